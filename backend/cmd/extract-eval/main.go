@@ -0,0 +1,213 @@
+// Command extract-eval menjalankan dataset gambar berlabel melalui provider ekstraksi (Gemini, DeepSeek, Ollama)
+// dan menulis laporan precision/recall per field, galat jam/hari (MAE) dan galat tanggal, opsional dibandingkan
+// dengan laporan baseline dari run sebelumnya (mis. model lain).
+//
+// Dataset: folder berisi <nama>.jpg|.png + <nama>.json (field yang diharapkan, format sama dengan respons ekstraksi).
+//
+// Mode:
+//   - live:   panggil provider.
+//   - record: panggil provider lalu simpan respons ke -recordings (untuk replay).
+//   - replay: baca respons dari -recordings tanpa jaringan (stub); tidak butuh API key.
+//
+// Contoh:
+//
+//	go run ./cmd/extract-eval -dir ./testdata/oneday -use-case one_day -provider gemini -model gemini-2.5-flash -mode record -out reports/flash
+//	go run ./cmd/extract-eval -dir ./testdata/oneday -use-case one_day -provider gemini -model gemini-2.5-flash-lite -mode record -out reports/lite -baseline reports/flash.json
+//	go run ./cmd/extract-eval -dir ./testdata/oneday -use-case one_day -provider gemini -model gemini-2.5-flash -mode replay -out reports/flash-replay
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/extracteval"
+	"dashboardadminimb/internal/extraction"
+	"dashboardadminimb/internal/imagepreprocess"
+	"dashboardadminimb/internal/prompt"
+)
+
+func main() {
+	dir := flag.String("dir", "", "folder dataset (gambar + JSON expected)")
+	useCase := flag.String("use-case", prompt.UseCaseOneDay, "invoice | one_day | row_only")
+	provider := flag.String("provider", "", "gemini | deepseek | ollama (kosong = dari config EXTRACT_PROVIDER)")
+	model := flag.String("model", "", "override GEMINI_MODEL / DEEPSEEK_MODEL")
+	baseURL := flag.String("base-url", "", "override DEEPSEEK_BASE_URL (deepseek/ollama)")
+	mode := flag.String("mode", extracteval.ModeLive, "live | record | replay")
+	recordings := flag.String("recordings", "", "folder rekaman respons (default <dir>/recordings)")
+	out := flag.String("out", "", "prefix file laporan: <out>.json dan <out>.md (kosong = markdown ke stdout)")
+	baselinePath := flag.String("baseline", "", "laporan JSON run sebelumnya untuk perbandingan")
+	quantityUnit := flag.String("quantity-unit", "hari", "hari | jam (one_day)")
+	useBBM := flag.Bool("use-bbm", false, "kolom BBM aktif (one_day)")
+	columns := flag.String("columns", "", "deskripsi kolom dipisah koma (row_only)")
+	flag.Parse()
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch *mode {
+	case extracteval.ModeLive, extracteval.ModeRecord, extracteval.ModeReplay:
+	default:
+		log.Fatalf("mode tidak valid: %s", *mode)
+	}
+	switch *useCase {
+	case prompt.UseCaseInvoice, prompt.UseCaseOneDay, prompt.UseCaseRowOnly:
+	default:
+		log.Fatalf("use case %s tidak didukung extract-eval", *useCase)
+	}
+	if *recordings == "" {
+		*recordings = filepath.Join(*dir, "recordings")
+	}
+
+	// Mode replay tidak butuh config (tanpa API key/jaringan); provider dan model wajib lewat flag.
+	cfg, err := config.LoadConfig()
+	if err != nil && *mode != extracteval.ModeReplay {
+		log.Fatalf("config: %v", err)
+	}
+	applyOverrides(&cfg, *provider, *model, *baseURL)
+	client := extraction.NewClient(cfg)
+	if *mode == extracteval.ModeReplay && client.Model == "" {
+		log.Fatalf("mode replay butuh -model (nama folder rekaman)")
+	}
+
+	cases, err := extracteval.LoadDataset(*dir)
+	if err != nil {
+		log.Fatalf("dataset: %v", err)
+	}
+	if len(cases) == 0 {
+		log.Fatalf("dataset %s kosong (butuh pasangan gambar + .json)", *dir)
+	}
+
+	var cols []string
+	for _, c := range strings.Split(*columns, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, c)
+		}
+	}
+
+	eval := extracteval.NewScorer()
+	report := &extracteval.Report{
+		GeneratedAt: time.Now(),
+		Dataset:     *dir,
+		UseCase:     *useCase,
+		Provider:    client.Provider,
+		Model:       client.Model,
+		Mode:        *mode,
+	}
+	for _, c := range cases {
+		recPath := extracteval.RecordingPath(*recordings, client.Provider, client.Model, *useCase, c.Name)
+		var actual map[string]interface{}
+		if *mode == extracteval.ModeReplay {
+			actual, err = extracteval.LoadRecording(recPath)
+		} else {
+			var raw interface{}
+			raw, err = extractOne(client, *useCase, c, *quantityUnit, *useBBM, cols)
+			if err == nil {
+				actual, err = extracteval.ToMap(raw)
+			}
+			if *mode == extracteval.ModeRecord {
+				if rerr := record(recPath, c.Name, client, *useCase, raw, err); rerr != nil {
+					log.Fatalf("simpan rekaman %s: %v", c.Name, rerr)
+				}
+			}
+		}
+		if err != nil {
+			log.Printf("%s: %v", c.Name, err)
+			actual = nil
+		}
+		eval.Add(c.Expected, actual)
+		report.Results = append(report.Results, extracteval.NewCaseResult(c.Name, c.Expected, actual, err))
+	}
+	report.Fill(eval)
+
+	var baseline *extracteval.Report
+	if *baselinePath != "" {
+		if baseline, err = extracteval.LoadReport(*baselinePath); err != nil {
+			log.Fatalf("baseline: %v", err)
+		}
+	}
+	if *out == "" {
+		report.WriteMarkdown(os.Stdout, baseline)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		log.Fatalf("laporan: %v", err)
+	}
+	if err := report.SaveJSON(*out + ".json"); err != nil {
+		log.Fatalf("laporan: %v", err)
+	}
+	f, err := os.Create(*out + ".md")
+	if err != nil {
+		log.Fatalf("laporan: %v", err)
+	}
+	defer f.Close()
+	report.WriteMarkdown(f, baseline)
+	fmt.Printf("Laporan: %s.json, %s.md (precision %.1f%%, recall %.1f%%)\n", *out, *out, report.Precision*100, report.Recall*100)
+}
+
+// applyOverrides memetakan flag -provider/-model/-base-url ke field config yang dibaca extraction.NewClient.
+func applyOverrides(cfg *config.Config, provider, model, baseURL string) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	switch provider {
+	case "":
+	case prompt.ProviderGemini:
+		cfg.ExtractProvider = "gemini"
+	case prompt.ProviderDeepSeek, prompt.ProviderOllama:
+		cfg.ExtractProvider = "deepseek"
+		// DEEPSEEK_BASE_URL dari env biasanya menunjuk API DeepSeek, jadi ollama selalu ke lokal kecuali -base-url diisi.
+		if baseURL == "" && provider == prompt.ProviderOllama {
+			baseURL = "http://localhost:11434"
+		}
+	default:
+		log.Fatalf("provider tidak valid: %s", provider)
+	}
+	if baseURL != "" {
+		cfg.DeepSeekBaseURL = baseURL
+	}
+	if model != "" {
+		if prompt.ProviderFor(cfg.ExtractProvider, cfg.DeepSeekBaseURL) == prompt.ProviderGemini {
+			cfg.GeminiModel = model
+		} else {
+			cfg.DeepSeekModel = model
+		}
+	}
+}
+
+func extractOne(client *extraction.Client, useCase string, c extracteval.Case, quantityUnit string, useBBM bool, cols []string) (interface{}, error) {
+	img, err := os.ReadFile(c.ImagePath)
+	if err != nil {
+		return nil, err
+	}
+	img, mime, err := imagepreprocess.RotatePortraitToLandscape(img, c.MimeType)
+	if err != nil {
+		return nil, err
+	}
+	switch useCase {
+	case prompt.UseCaseInvoice:
+		return client.Invoice(img, mime, "")
+	case prompt.UseCaseOneDay:
+		return client.OneDay(img, mime, quantityUnit, useBBM, "")
+	default:
+		return client.Row(img, mime, cols, "")
+	}
+}
+
+func record(path, name string, client *extraction.Client, useCase string, out interface{}, extractErr error) error {
+	rec := extracteval.Recording{Case: name, Provider: client.Provider, Model: client.Model, UseCase: useCase}
+	if extractErr != nil {
+		rec.Error = extractErr.Error()
+	} else {
+		b, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+		rec.Output = b
+	}
+	return extracteval.SaveRecording(path, rec)
+}
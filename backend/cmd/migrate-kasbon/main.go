@@ -0,0 +1,47 @@
+// Command migrate-kasbon menyalin baris kasbons (terikat ke satu gaji) ke ledger member_advances.
+//
+// Setiap kasbon lama menjadi kasbon satu kali cicilan yang sudah lunas lewat potongan gaji bulan itu,
+// jadi saldo tidak berubah. Aman dijalankan berkali-kali: kasbon yang sudah dimigrasi dilewati, yang
+// jumlahnya berubah dibuat ulang, dan yang sudah dihapus dari kasbons ikut dihapus dari ledger.
+//
+//	go run ./cmd/migrate-kasbon -dry-run   # lihat apa yang akan dipindahkan
+//	go run ./cmd/migrate-kasbon            # semua user
+//	go run ./cmd/migrate-kasbon -user 3    # satu user
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/database"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "hanya tampilkan, tanpa menulis")
+	userID := flag.Uint("user", 0, "ID user (0 = semua)")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	db, err := database.NewMySQLDB(&cfg)
+	if err != nil {
+		log.Fatalf("db: %v", err)
+	}
+	advanceService := service.NewAdvanceService(repository.NewAdvanceRepository(db), repository.NewMemberRepository(db))
+	res, err := advanceService.MigrateLegacyKasbons(*userID, *dryRun)
+	if err != nil {
+		log.Fatalf("migrasi kasbon: %v", err)
+	}
+	mode := ""
+	if *dryRun {
+		mode = " (dry-run, tidak ada yang ditulis)"
+	}
+	fmt.Printf("Selesai%s: %d kasbon, %d dibuat, %d diperbarui, %d dihapus, %d sudah ada\n",
+		mode, res.Kasbons, res.Created, res.Updated, res.Removed, res.Skipped)
+}
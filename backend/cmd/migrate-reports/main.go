@@ -0,0 +1,93 @@
+// Command migrate-reports memindahkan projects.reports.daily (JSON) ke tabel daily_reports / daily_report_images.
+//
+// Aman dijalankan berkali-kali: tanggal yang sudah ada di tabel tidak ditimpa, dan "daily" dihapus dari JSON
+// hanya setelah semua baris proyek tersimpan. weekly/monthly/_smartNota tetap di kolom reports.
+//
+//	go run ./cmd/migrate-reports -dry-run        # lihat apa yang akan dipindahkan
+//	go run ./cmd/migrate-reports                 # semua proyek
+//	go run ./cmd/migrate-reports -project 12     # satu proyek
+//	go run ./cmd/migrate-reports -rollback       # tulis balik baris tabel ke reports.daily (sebelum migrasi down)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/database"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "hanya tampilkan, tanpa menulis")
+	projectID := flag.Uint("project", 0, "ID proyek (0 = semua)")
+	rollback := flag.Bool("rollback", false, "kembalikan baris tabel ke projects.reports.daily")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	db, err := database.NewMySQLDB(&cfg)
+	if err != nil {
+		log.Fatalf("db: %v", err)
+	}
+	projectRepo := repository.NewProjectRepository(db)
+	projectService := service.NewProjectService(projectRepo, repository.NewDailyReportRepository(db), repository.NewCustomerRepository(db))
+
+	var ids []uint
+	if *projectID != 0 {
+		ids = []uint{*projectID}
+	} else if err := db.Model(&entity.Project{}).Order("id ASC").Pluck("id", &ids).Error; err != nil {
+		log.Fatalf("daftar proyek: %v", err)
+	}
+
+	var moved, skipped, failed int
+	for _, id := range ids {
+		if *rollback {
+			// View gabungan (kolom + tabel) ditulis balik utuh ke kolom reports.
+			p, err := projectService.GetProjectByIDAdmin(id)
+			if err != nil {
+				log.Printf("proyek %d: %v", id, err)
+				failed++
+				continue
+			}
+			if *dryRun {
+				fmt.Printf("proyek %d: reports.daily akan ditulis ulang dari tabel\n", id)
+				continue
+			}
+			if err := projectRepo.UpdateReports(id, p.Reports); err != nil {
+				log.Printf("proyek %d: %v", id, err)
+				failed++
+				continue
+			}
+			fmt.Printf("proyek %d: reports.daily ditulis ulang dari tabel\n", id)
+			continue
+		}
+		res, err := projectService.MigrateDailyReports(id, *dryRun)
+		if err != nil {
+			log.Printf("proyek %d: %v", id, err)
+			failed++
+			continue
+		}
+		if res.InBlob == 0 && !res.Stripped {
+			continue
+		}
+		moved += res.Created
+		skipped += res.Skipped
+		fmt.Printf("proyek %d: %d entri JSON, %d dipindahkan, %d sudah ada di tabel, daily dihapus dari JSON: %v\n",
+			id, res.InBlob, res.Created, res.Skipped, res.Stripped)
+	}
+	if *rollback {
+		fmt.Printf("Selesai: %d proyek, %d gagal\n", len(ids), failed)
+		return
+	}
+	mode := ""
+	if *dryRun {
+		mode = " (dry-run, tidak ada yang ditulis)"
+	}
+	fmt.Printf("Selesai%s: %d proyek, %d baris dipindahkan, %d dilewati, %d gagal\n", mode, len(ids), moved, skipped, failed)
+}
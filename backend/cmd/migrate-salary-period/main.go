@@ -0,0 +1,48 @@
+// Command migrate-salary-period mengisi salaries.period_year/period_month untuk gaji lama.
+//
+// Bulan teks bebas ("Januari 2026", "01/2026", "januari-2026") dinormalkan ke YYYY-MM sehingga filter
+// total gaji tahunan/kuartalan memakai rentang periode. Aman dijalankan berkali-kali: hanya gaji yang
+// periodenya belum terisi yang diproses. Bulan yang tidak dikenali dicetak untuk diperbaiki manual.
+//
+//	go run ./cmd/migrate-salary-period -dry-run   # lihat apa yang akan diubah
+//	go run ./cmd/migrate-salary-period
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/database"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "hanya tampilkan, tanpa menulis")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	db, err := database.NewMySQLDB(&cfg)
+	if err != nil {
+		log.Fatalf("db: %v", err)
+	}
+	salaryService := service.NewSalaryService(repository.NewSalaryRepository(db), repository.NewMemberRepository(db), nil, nil, nil, nil, nil)
+	res, err := salaryService.MigrateSalaryPeriods(*dryRun)
+	if err != nil {
+		log.Fatalf("migrasi periode gaji: %v", err)
+	}
+	for _, u := range res.Unrecognized {
+		fmt.Printf("  bulan tidak dikenali, gaji %s\n", u)
+	}
+	mode := ""
+	if *dryRun {
+		mode = " (dry-run, tidak ada yang ditulis)"
+	}
+	fmt.Printf("Selesai%s: %d gaji tanpa periode, %d diisi, %d tidak dikenali\n",
+		mode, res.Salaries, res.Updated, len(res.Unrecognized))
+}
@@ -0,0 +1,145 @@
+// Command prompt-eval memutar ulang dataset gambar berlabel terhadap dua versi prompt dan melaporkan akurasi per field.
+//
+// Dataset: folder berisi <nama>.jpg|.png + <nama>.json (field yang diharapkan, format sama dengan respons ekstraksi).
+// Versi prompt diambil dari tabel prompt_templates (ID); 0 = prompt bawaan di kode.
+//
+//	go run ./cmd/prompt-eval -dir ./testdata/timesheets -use-case row_only -a 0 -b 12 -columns "Tanggal,Jam,Keterangan"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/extracteval"
+	"dashboardadminimb/internal/extraction"
+	"dashboardadminimb/internal/imagepreprocess"
+	"dashboardadminimb/internal/prompt"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/pkg/database"
+)
+
+func main() {
+	dir := flag.String("dir", "", "folder dataset (gambar + JSON expected)")
+	useCase := flag.String("use-case", prompt.UseCaseOneDay, "invoice | one_day | row_only")
+	idA := flag.Uint("a", 0, "ID prompt_templates versi A (0 = prompt bawaan)")
+	idB := flag.Uint("b", 0, "ID prompt_templates versi B (0 = prompt bawaan)")
+	quantityUnit := flag.String("quantity-unit", "hari", "hari | jam (one_day)")
+	useBBM := flag.Bool("use-bbm", false, "kolom BBM aktif (one_day)")
+	columns := flag.String("columns", "", "deskripsi kolom dipisah koma (row_only)")
+	flag.Parse()
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	cases, err := extracteval.LoadDataset(*dir)
+	if err != nil {
+		log.Fatalf("dataset: %v", err)
+	}
+	if len(cases) == 0 {
+		log.Fatalf("dataset %s kosong (butuh pasangan gambar + .json)", *dir)
+	}
+
+	var cols []string
+	for _, c := range strings.Split(*columns, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, c)
+		}
+	}
+	data := prompt.Data{QuantityUnit: *quantityUnit, UseBBM: *useBBM, ColumnDescriptions: cols}
+
+	var repo repository.PromptTemplateRepository
+	if *idA != 0 || *idB != 0 {
+		db, err := database.NewMySQLDB(&cfg)
+		if err != nil {
+			log.Fatalf("db: %v", err)
+		}
+		repo = repository.NewPromptTemplateRepository(db)
+	}
+	loadPrompt := func(id uint) (string, string) {
+		if id == 0 {
+			return "", "bawaan"
+		}
+		tpl, err := repo.FindByID(id)
+		if err != nil {
+			log.Fatalf("prompt %d: %v", id, err)
+		}
+		if tpl.UseCase != *useCase {
+			log.Fatalf("prompt %d untuk use case %s, bukan %s", id, tpl.UseCase, *useCase)
+		}
+		text, err := prompt.Render(tpl.Body, data)
+		if err != nil {
+			log.Fatalf("prompt %d: %v", id, err)
+		}
+		return text, fmt.Sprintf("v%d (#%d)", tpl.Version, tpl.ID)
+	}
+	promptA, labelA := loadPrompt(*idA)
+	promptB, labelB := loadPrompt(*idB)
+
+	client := extraction.NewClient(cfg)
+	scoreA, scoreB := extracteval.NewScorer(), extracteval.NewScorer()
+	for _, c := range cases {
+		img, err := os.ReadFile(c.ImagePath)
+		if err != nil {
+			log.Fatalf("%s: %v", c.Name, err)
+		}
+		img, mime, err := imagepreprocess.RotatePortraitToLandscape(img, c.MimeType)
+		if err != nil {
+			log.Fatalf("%s: %v", c.Name, err)
+		}
+		for _, run := range []struct {
+			prompt string
+			scorer *extracteval.Scorer
+			label  string
+		}{{promptA, scoreA, "A"}, {promptB, scoreB, "B"}} {
+			actual, err := extractOne(client, *useCase, img, mime, data, run.prompt)
+			if err != nil {
+				log.Printf("%s [%s]: %v", c.Name, run.label, err)
+			}
+			run.scorer.Add(c.Expected, actual)
+		}
+	}
+
+	fmt.Printf("Dataset: %s (%d gambar), use case %s, provider %s, model %s\n\n", *dir, len(cases), *useCase, client.Provider, client.Model)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "field\tA: %s\tB: %s\tdelta\n", labelA, labelB)
+	byField := map[string]extracteval.FieldScore{}
+	for _, fs := range scoreB.Scores() {
+		byField[fs.Field] = fs
+	}
+	for _, a := range scoreA.Scores() {
+		b := byField[a.Field]
+		fmt.Fprintf(w, "%s\t%d/%d (%.0f%%)\t%d/%d (%.0f%%)\t%+.0f%%\n", a.Field, a.Correct, a.Total, a.Acc*100, b.Correct, b.Total, b.Acc*100, (b.Acc-a.Acc)*100)
+	}
+	fmt.Fprintf(w, "TOTAL\t%.1f%%\t%.1f%%\t%+.1f%%\n", scoreA.Overall()*100, scoreB.Overall()*100, (scoreB.Overall()-scoreA.Overall())*100)
+	fmt.Fprintf(w, "gagal ekstrak\t%d\t%d\t\n", scoreA.Errors, scoreB.Errors)
+	w.Flush()
+}
+
+func extractOne(client *extraction.Client, useCase string, img []byte, mime string, data prompt.Data, promptText string) (map[string]interface{}, error) {
+	var out interface{}
+	var err error
+	switch useCase {
+	case prompt.UseCaseInvoice:
+		out, err = client.Invoice(img, mime, promptText)
+	case prompt.UseCaseOneDay:
+		out, err = client.OneDay(img, mime, data.QuantityUnit, data.UseBBM, promptText)
+	case prompt.UseCaseRowOnly:
+		out, err = client.Row(img, mime, data.ColumnDescriptions, promptText)
+	default:
+		return nil, fmt.Errorf("use case %s tidak didukung prompt-eval", useCase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return extracteval.ToMap(out)
+}
@@ -26,6 +26,16 @@ type Config struct {
 	// OCR untuk pipeline timesheet: gambar → OCR → LLM. Kosong = OCR dinonaktifkan untuk from-image.
 	TesseractPath string `mapstructure:"TESSERACT_PATH"` // e.g. "tesseract" atau path ke tesseract.exe. Pakai untuk OCR teks dari gambar.
 	OCRScriptPath string `mapstructure:"OCR_SCRIPT_PATH"` // opsional: script (python dll) baca gambar dari stdin, print teks ke stdout. Prioritas di atas Tesseract jika diisi.
+	// Preprocessing sebelum OCR, per runner: daftar step dipisah koma (exif,grayscale,contrast,threshold,deskew,crop,denoise), "none" atau "default".
+	OCRPreprocess       string `mapstructure:"OCR_PREPROCESS"`        // untuk Tesseract. Kosong = default (semua step)
+	OCRScriptPreprocess string `mapstructure:"OCR_SCRIPT_PREPROCESS"` // untuk OCR_SCRIPT_PATH. Kosong = exif saja (PaddleOCR/EasyOCR punya preprocessing sendiri)
+	OCRLang             string `mapstructure:"OCR_LANG"` // bahasa tesseract default, e.g. "ind+eng" (kosong = ind+eng). Bisa di-override per request (form field "lang")
+	OCRPSM              int    `mapstructure:"OCR_PSM"`  // page segmentation mode default (0 = default tesseract; 6 = satu blok teks, 4 = kolom). Override: form field "psm"
+	// Upload PDF (timesheet scan): dipecah per halaman. Kosong semua = ekstraksi gambar scan bawaan (pure Go, tanpa render teks/vektor).
+	PDFToPPMPath string `mapstructure:"PDFTOPPM_PATH"`  // e.g. "pdftoppm" (poppler-utils). Wajib untuk PDF digital
+	PDFScriptPath string `mapstructure:"PDF_SCRIPT_PATH"` // opsional: script <in.pdf> <out_dir> menulis PNG/JPEG per halaman. Prioritas di atas pdftoppm
+	PDFDPI        int    `mapstructure:"PDF_DPI"`         // resolusi rasterisasi (kosong = 150)
+	PDFMaxPages   int    `mapstructure:"PDF_MAX_PAGES"`   // batas halaman per PDF (kosong = 31)
 }
 
 func LoadConfig() (config Config, err error) {
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS extraction_logs;
+DROP TABLE IF EXISTS prompt_templates;
@@ -0,0 +1,35 @@
+-- Prompt ekstraksi (Go text/template) per use case + provider dengan versi, dan log ekstraksi (versi prompt yang dipakai)
+CREATE TABLE IF NOT EXISTS prompt_templates (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id BIGINT UNSIGNED NOT NULL DEFAULT 1,
+  use_case VARCHAR(50) NOT NULL,
+  provider VARCHAR(20) NOT NULL,
+  version INT NOT NULL,
+  body TEXT NOT NULL,
+  notes TEXT NULL,
+  is_active TINYINT(1) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_prompt_templates_version (user_id, use_case, provider, version),
+  KEY idx_prompt_templates_is_active (is_active)
+);
+
+CREATE TABLE IF NOT EXISTS extraction_logs (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id BIGINT UNSIGNED NOT NULL DEFAULT 1,
+  use_case VARCHAR(50) NOT NULL,
+  provider VARCHAR(20) NOT NULL,
+  model VARCHAR(100) NULL,
+  prompt_template_id BIGINT UNSIGNED NULL,
+  prompt_version INT NOT NULL DEFAULT 0,
+  file_name VARCHAR(255) NULL,
+  output LONGTEXT NULL,
+  error TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_extraction_logs_user_id (user_id),
+  KEY idx_extraction_logs_use_case (use_case),
+  KEY idx_extraction_logs_prompt_template_id (prompt_template_id),
+  CONSTRAINT fk_extraction_logs_prompt_template FOREIGN KEY (prompt_template_id) REFERENCES prompt_templates(id) ON DELETE SET NULL
+);
@@ -0,0 +1,3 @@
+-- Jalankan `go run ./cmd/migrate-reports -rollback` dulu agar baris harian kembali ke projects.reports.daily.
+DROP TABLE IF EXISTS daily_report_images;
+DROP TABLE IF EXISTS daily_reports;
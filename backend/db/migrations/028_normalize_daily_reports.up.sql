@@ -0,0 +1,36 @@
+-- Laporan harian proyek keluar dari projects.reports (JSON) ke tabel sendiri; satu baris per proyek per tanggal.
+-- Data lama dipindahkan dengan: go run ./cmd/migrate-reports
+CREATE TABLE IF NOT EXISTS daily_reports (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  project_id BIGINT UNSIGNED NOT NULL,
+  date VARCHAR(10) NOT NULL,
+  revenue DOUBLE NOT NULL DEFAULT 0,
+  paid DOUBLE NOT NULL DEFAULT 0,
+  volume DOUBLE NOT NULL DEFAULT 0,
+  target_volume DOUBLE NOT NULL DEFAULT 0,
+  plan DOUBLE NOT NULL DEFAULT 0,
+  aktual DOUBLE NOT NULL DEFAULT 0,
+  workers JSON NULL,
+  equipment JSON NULL,
+  total_workers BIGINT NOT NULL DEFAULT 0,
+  total_equipment BIGINT NOT NULL DEFAULT 0,
+  ritase DOUBLE NOT NULL DEFAULT 0,
+  cuaca VARCHAR(255) NULL,
+  catatan TEXT NULL,
+  created_at BIGINT NULL,
+  updated_at BIGINT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_daily_reports_project_date (project_id, date),
+  KEY idx_daily_reports_project_id (project_id)
+);
+
+CREATE TABLE IF NOT EXISTS daily_report_images (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  report_daily_id BIGINT UNSIGNED NOT NULL,
+  image_path VARCHAR(512) NULL,
+  description TEXT NULL,
+  created_at BIGINT NULL,
+  updated_at BIGINT NULL,
+  PRIMARY KEY (id),
+  KEY idx_daily_report_images_report_daily_id (report_daily_id)
+);
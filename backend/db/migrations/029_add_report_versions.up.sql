@@ -0,0 +1,3 @@
+-- Versi untuk optimistic concurrency (ETag / If-Match) pada proyek dan laporan harian.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS version INT UNSIGNED NOT NULL DEFAULT 1;
+ALTER TABLE daily_reports ADD COLUMN IF NOT EXISTS version INT UNSIGNED NOT NULL DEFAULT 1;
@@ -0,0 +1,25 @@
+-- Termin pembayaran proyek; tiap termin terhubung ke satu project_incomes (Planned) dan opsional ke invoice draft.
+CREATE TABLE IF NOT EXISTS project_milestones (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  project_id BIGINT UNSIGNED NOT NULL,
+  sequence BIGINT NOT NULL DEFAULT 0,
+  name VARCHAR(200) NOT NULL,
+  amount_type VARCHAR(20) NOT NULL DEFAULT 'percent',
+  percent DECIMAL(6,2) NOT NULL DEFAULT 0,
+  amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+  trigger_progress DECIMAL(6,2) NOT NULL DEFAULT 0,
+  hold_days BIGINT NOT NULL DEFAULT 0,
+  planned_date DATE NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  reached_date DATE NULL,
+  income_id BIGINT NULL,
+  invoice_id BIGINT UNSIGNED NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_project_milestones_project_id (project_id),
+  KEY idx_project_milestones_status (status),
+  KEY idx_project_milestones_income_id (income_id),
+  KEY idx_project_milestones_invoice_id (invoice_id)
+);
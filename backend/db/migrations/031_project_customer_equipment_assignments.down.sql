@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS equipment_assignments;
+ALTER TABLE projects DROP INDEX IF EXISTS idx_projects_customer_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS customer_id;
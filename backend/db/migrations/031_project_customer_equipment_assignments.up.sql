@@ -0,0 +1,21 @@
+-- Proyek terhubung ke customer; penempatan alat per proyek dengan rentang tanggal.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS customer_id BIGINT UNSIGNED NULL;
+ALTER TABLE projects ADD INDEX IF NOT EXISTS idx_projects_customer_id (customer_id);
+
+CREATE TABLE IF NOT EXISTS equipment_assignments (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  equipment_id BIGINT UNSIGNED NOT NULL,
+  project_id BIGINT UNSIGNED NOT NULL,
+  from_date VARCHAR(10) NOT NULL,
+  to_date VARCHAR(10) NULL,
+  rate DECIMAL(15,2) DEFAULT 0,
+  rate_unit VARCHAR(20) DEFAULT 'hari',
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_equipment_assignments_user_id (user_id),
+  KEY idx_equipment_assignments_project_id (project_id),
+  KEY idx_equipment_assignments_equipment_dates (equipment_id, from_date)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE equipment DROP COLUMN IF EXISTS purchase_date;
+ALTER TABLE equipment DROP COLUMN IF EXISTS purchase_price;
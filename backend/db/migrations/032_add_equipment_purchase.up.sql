@@ -0,0 +1,3 @@
+-- Harga & tanggal beli alat untuk analisis payback.
+ALTER TABLE equipment ADD COLUMN IF NOT EXISTS purchase_price DECIMAL(15,2) DEFAULT 0;
+ALTER TABLE equipment ADD COLUMN IF NOT EXISTS purchase_date VARCHAR(10) NULL;
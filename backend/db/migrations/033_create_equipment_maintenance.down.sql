@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS service_records;
+DROP TABLE IF EXISTS maintenance_plans;
+DROP TABLE IF EXISTS equipment_meter_readings;
@@ -0,0 +1,52 @@
+-- Hour meter / odometer, rencana perawatan berkala dan riwayat servis alat.
+CREATE TABLE IF NOT EXISTS equipment_meter_readings (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  equipment_id BIGINT UNSIGNED NOT NULL,
+  date VARCHAR(10) NOT NULL,
+  reading DECIMAL(12,1) NOT NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_equipment_meter_readings_user_id (user_id),
+  KEY idx_equipment_meter_readings_equipment_id (equipment_id)
+);
+
+CREATE TABLE IF NOT EXISTS maintenance_plans (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  equipment_id BIGINT UNSIGNED NOT NULL,
+  name VARCHAR(200) NOT NULL,
+  interval_meter DECIMAL(12,1) DEFAULT 0,
+  interval_days INT DEFAULT 0,
+  due_soon_meter DECIMAL(12,1) DEFAULT 0,
+  last_service_meter DECIMAL(12,1) DEFAULT 0,
+  last_service_date VARCHAR(10) NULL,
+  active TINYINT(1) DEFAULT 1,
+  notified_due VARCHAR(50) NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_maintenance_plans_user_id (user_id),
+  KEY idx_maintenance_plans_equipment_id (equipment_id)
+);
+
+CREATE TABLE IF NOT EXISTS service_records (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  equipment_id BIGINT UNSIGNED NOT NULL,
+  plan_id BIGINT UNSIGNED NULL,
+  date VARCHAR(10) NOT NULL,
+  meter DECIMAL(12,1) DEFAULT 0,
+  description TEXT NULL,
+  mechanic VARCHAR(100) NULL,
+  cost DECIMAL(15,2) DEFAULT 0,
+  finance_id BIGINT UNSIGNED NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_service_records_user_id (user_id),
+  KEY idx_service_records_equipment_id (equipment_id),
+  KEY idx_service_records_plan_id (plan_id),
+  KEY idx_service_records_finance_id (finance_id)
+);
@@ -0,0 +1,19 @@
+-- Dokumen/izin alat (STNK, KIR, asuransi, SIA, SILO) dengan masa berlaku.
+CREATE TABLE IF NOT EXISTS equipment_documents (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  equipment_id BIGINT UNSIGNED NOT NULL,
+  doc_type VARCHAR(20) NOT NULL,
+  number VARCHAR(100) NULL,
+  issue_date VARCHAR(10) NULL,
+  expiry_date VARCHAR(10) NULL,
+  file_url VARCHAR(500) NULL,
+  notes TEXT NULL,
+  notified_stage VARCHAR(20) NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_equipment_documents_user_id (user_id),
+  KEY idx_equipment_documents_equipment_id (equipment_id),
+  KEY idx_equipment_documents_expiry_date (expiry_date)
+);
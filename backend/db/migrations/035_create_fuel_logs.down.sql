@@ -0,0 +1 @@
+DROP TABLE IF EXISTS fuel_logs;
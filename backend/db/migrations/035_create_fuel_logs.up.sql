@@ -0,0 +1,24 @@
+-- Log pengeluaran BBM per alat.
+CREATE TABLE IF NOT EXISTS fuel_logs (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  equipment_id BIGINT UNSIGNED NOT NULL,
+  project_id BIGINT UNSIGNED NULL,
+  date VARCHAR(10) NOT NULL,
+  liters DECIMAL(10,2) NOT NULL,
+  price_per_liter DECIMAL(15,2) DEFAULT 0,
+  total DECIMAL(15,2) DEFAULT 0,
+  meter_reading DECIMAL(12,1) DEFAULT 0,
+  operator_id VARCHAR(255) NULL,
+  finance_id BIGINT UNSIGNED NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_fuel_logs_user_id (user_id),
+  KEY idx_fuel_logs_equipment_id (equipment_id),
+  KEY idx_fuel_logs_project_id (project_id),
+  KEY idx_fuel_logs_date (date),
+  KEY idx_fuel_logs_operator_id (operator_id),
+  KEY idx_fuel_logs_finance_id (finance_id)
+);
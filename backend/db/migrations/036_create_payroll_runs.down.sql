@@ -0,0 +1,4 @@
+ALTER TABLE salaries DROP INDEX IF EXISTS idx_salaries_payroll_run_id;
+ALTER TABLE salaries DROP COLUMN IF EXISTS locked;
+ALTER TABLE salaries DROP COLUMN IF EXISTS payroll_run_id;
+DROP TABLE IF EXISTS payroll_runs;
@@ -0,0 +1,24 @@
+-- Payroll run bulanan; gaji terhubung ke run dan terkunci setelah finalisasi.
+CREATE TABLE IF NOT EXISTS payroll_runs (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  month VARCHAR(7) NOT NULL,
+  status VARCHAR(20) DEFAULT 'draft',
+  expense_mode VARCHAR(20) NULL,
+  payment_date VARCHAR(10) NULL,
+  member_count BIGINT DEFAULT 0,
+  total_gross DECIMAL(15,2) DEFAULT 0,
+  total_loan DECIMAL(15,2) DEFAULT 0,
+  total_net DECIMAL(15,2) DEFAULT 0,
+  finance_ids JSON NULL,
+  notes TEXT NULL,
+  finalized_at DATETIME(3) NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY idx_payroll_runs_user_month (user_id, month)
+);
+
+ALTER TABLE salaries ADD COLUMN IF NOT EXISTS payroll_run_id BIGINT UNSIGNED NULL;
+ALTER TABLE salaries ADD COLUMN IF NOT EXISTS locked TINYINT(1) DEFAULT 0;
+ALTER TABLE salaries ADD INDEX IF NOT EXISTS idx_salaries_payroll_run_id (payroll_run_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS advance_repayments;
+DROP TABLE IF EXISTS member_advances;
@@ -0,0 +1,42 @@
+-- Ledger kasbon per anggota (lepas dari satu gaji) dan cicilannya.
+-- Baris kasbons lama dipindahkan dengan: go run ./cmd/migrate-kasbon
+CREATE TABLE IF NOT EXISTS member_advances (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  member_id VARCHAR(255) NOT NULL,
+  tanggal VARCHAR(10) NOT NULL,
+  amount DECIMAL(15,2) NOT NULL,
+  installments BIGINT DEFAULT 1,
+  installment_amount DECIMAL(15,2) DEFAULT 0,
+  start_month VARCHAR(7) NOT NULL,
+  keterangan TEXT NULL,
+  status VARCHAR(20) DEFAULT 'open',
+  legacy_kasbon_id BIGINT UNSIGNED NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_member_advances_user_id (user_id),
+  KEY idx_member_advances_member_id (member_id),
+  UNIQUE KEY idx_member_advances_legacy_kasbon_id (legacy_kasbon_id)
+);
+
+CREATE TABLE IF NOT EXISTS advance_repayments (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  advance_id BIGINT UNSIGNED NOT NULL,
+  member_id VARCHAR(255) NOT NULL,
+  month VARCHAR(7) NOT NULL,
+  tanggal VARCHAR(10) NULL,
+  amount DECIMAL(15,2) NOT NULL,
+  source VARCHAR(20) DEFAULT 'manual',
+  salary_id BIGINT UNSIGNED NULL,
+  payroll_run_id BIGINT UNSIGNED NULL,
+  keterangan TEXT NULL,
+  created_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_advance_repayments_user_id (user_id),
+  KEY idx_advance_repayments_advance_id (advance_id),
+  KEY idx_advance_repayments_member_id (member_id),
+  KEY idx_advance_repayments_salary_id (salary_id),
+  KEY idx_advance_repayments_payroll_run_id (payroll_run_id)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE payroll_runs DROP COLUMN IF EXISTS total_statutory;
+ALTER TABLE salaries DROP COLUMN IF EXISTS statutory_deduction;
+ALTER TABLE members DROP COLUMN IF EXISTS bpjs_kesehatan;
+ALTER TABLE members DROP COLUMN IF EXISTS bpjs_ketenagakerjaan;
+ALTER TABLE members DROP COLUMN IF EXISTS npwp;
+ALTER TABLE members DROP COLUMN IF EXISTS ptkp_status;
+DROP TABLE IF EXISTS salary_deductions;
+DROP TABLE IF EXISTS payroll_tax_settings;
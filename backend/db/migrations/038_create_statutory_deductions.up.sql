@@ -0,0 +1,49 @@
+-- PPh 21 (TER) dan BPJS: konfigurasi per user, status PTKP anggota, baris potongan per gaji.
+CREATE TABLE IF NOT EXISTS payroll_tax_settings (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  pph21_enabled TINYINT(1) DEFAULT 0,
+  bpjs_tk_enabled TINYINT(1) DEFAULT 0,
+  bpjs_kes_enabled TINYINT(1) DEFAULT 0,
+  jht_employee_rate DECIMAL(6,3) NULL,
+  jht_employer_rate DECIMAL(6,3) NULL,
+  jp_employee_rate DECIMAL(6,3) NULL,
+  jp_employer_rate DECIMAL(6,3) NULL,
+  jp_wage_cap DECIMAL(15,2) NULL,
+  jkk_rate DECIMAL(6,3) NULL,
+  jkm_rate DECIMAL(6,3) NULL,
+  kes_employee_rate DECIMAL(6,3) NULL,
+  kes_employer_rate DECIMAL(6,3) NULL,
+  kes_wage_cap DECIMAL(15,2) NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY idx_payroll_tax_settings_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS salary_deductions (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  salary_id BIGINT UNSIGNED NOT NULL,
+  member_id VARCHAR(255) NOT NULL,
+  month VARCHAR(7) NULL,
+  code VARCHAR(20) NULL,
+  name VARCHAR(100) NULL,
+  party VARCHAR(10) NULL,
+  base DECIMAL(15,2) NULL,
+  rate DECIMAL(6,3) NULL,
+  amount DECIMAL(15,2) NULL,
+  created_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_salary_deductions_user_id (user_id),
+  KEY idx_salary_deductions_salary_id (salary_id),
+  KEY idx_salary_deductions_member_id (member_id),
+  KEY idx_salary_deductions_month (month)
+);
+
+ALTER TABLE members ADD COLUMN IF NOT EXISTS ptkp_status VARCHAR(10) DEFAULT 'TK/0';
+ALTER TABLE members ADD COLUMN IF NOT EXISTS npwp VARCHAR(30) NULL;
+ALTER TABLE members ADD COLUMN IF NOT EXISTS bpjs_ketenagakerjaan TINYINT(1) DEFAULT 1;
+ALTER TABLE members ADD COLUMN IF NOT EXISTS bpjs_kesehatan TINYINT(1) DEFAULT 1;
+ALTER TABLE salaries ADD COLUMN IF NOT EXISTS statutory_deduction DOUBLE DEFAULT 0;
+ALTER TABLE payroll_runs ADD COLUMN IF NOT EXISTS total_statutory DECIMAL(15,2) DEFAULT 0;
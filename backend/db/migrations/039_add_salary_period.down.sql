@@ -0,0 +1,5 @@
+ALTER TABLE salaries
+  DROP INDEX idx_salaries_period,
+  DROP INDEX idx_salaries_member_period,
+  DROP COLUMN period_month,
+  DROP COLUMN period_year;
@@ -0,0 +1,13 @@
+-- Periode gaji terstruktur (tahun, bulan) menggantikan filter teks bebas pada salaries.month.
+ALTER TABLE salaries
+  ADD COLUMN period_year SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN period_month TINYINT NOT NULL DEFAULT 0,
+  ADD INDEX idx_salaries_member_period (member_id, period_year, period_month),
+  ADD INDEX idx_salaries_period (period_year, period_month);
+
+-- Bulan yang sudah YYYY-MM langsung diisi; format lama ("Januari 2026", "01/2026")
+-- dinormalisasi oleh `go run ./cmd/migrate-salary-period`.
+UPDATE salaries
+SET period_year = CAST(SUBSTRING(month, 1, 4) AS UNSIGNED),
+    period_month = CAST(SUBSTRING(month, 6, 2) AS UNSIGNED)
+WHERE month REGEXP '^[0-9]{4}-(0[1-9]|1[0-2])$';
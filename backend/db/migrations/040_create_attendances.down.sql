@@ -0,0 +1,3 @@
+DELETE FROM salary_details WHERE absensi_id IS NOT NULL;
+ALTER TABLE salary_details DROP INDEX idx_salary_details_absensi_id, DROP COLUMN absensi_id;
+DROP TABLE IF EXISTS attendances;
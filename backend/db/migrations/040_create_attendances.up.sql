@@ -0,0 +1,36 @@
+-- Absensi harian anggota; absensi hadir yang disetujui dijadikan baris salary_details saat gaji dihitung ulang.
+CREATE TABLE IF NOT EXISTS attendances (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  member_id VARCHAR(255) NOT NULL,
+  date VARCHAR(10) NOT NULL,
+  shift VARCHAR(20) NOT NULL DEFAULT '',
+  status VARCHAR(10) NOT NULL DEFAULT 'hadir',
+  project_id BIGINT UNSIGNED NULL,
+  site VARCHAR(200) NULL,
+  equipment_id BIGINT UNSIGNED NULL,
+  check_in VARCHAR(5) NULL,
+  check_out VARCHAR(5) NULL,
+  hours DECIMAL(5,2) DEFAULT 0,
+  overtime_hours DECIMAL(5,2) DEFAULT 0,
+  trips DECIMAL(6,1) DEFAULT 0,
+  pay_basis VARCHAR(10) NOT NULL DEFAULT 'hour',
+  rate DECIMAL(15,2) DEFAULT 0,
+  overtime_rate DECIMAL(15,2) DEFAULT 0,
+  approved TINYINT(1) DEFAULT 0,
+  approved_at DATETIME(3) NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY idx_attendance_member_day (member_id, date, shift),
+  KEY idx_attendances_user_id (user_id),
+  KEY idx_attendances_date (date),
+  KEY idx_attendances_project_id (project_id),
+  KEY idx_attendances_equipment_id (equipment_id),
+  KEY idx_attendances_approved (approved)
+);
+
+ALTER TABLE salary_details
+  ADD COLUMN absensi_id BIGINT UNSIGNED NULL,
+  ADD INDEX idx_salary_details_absensi_id (absensi_id);
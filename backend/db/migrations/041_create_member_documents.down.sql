@@ -0,0 +1,14 @@
+ALTER TABLE equipment_assignments
+  DROP INDEX idx_equipment_assignments_operator_id,
+  DROP COLUMN operator_id;
+
+ALTER TABLE members ADD COLUMN documents JSON NULL;
+
+UPDATE members m
+SET m.documents = (
+  SELECT JSON_ARRAYAGG(d.file_url)
+  FROM member_documents d
+  WHERE d.member_id = m.id AND d.file_url <> '' AND d.file_url NOT LIKE '%/%'
+);
+
+DROP TABLE IF EXISTS member_documents;
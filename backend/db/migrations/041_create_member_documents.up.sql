@@ -0,0 +1,33 @@
+-- Dokumen anggota bertipe (KTP, SIM B2, SIO, medical checkup) dengan masa berlaku, menggantikan members.documents (array JSON nama file).
+CREATE TABLE IF NOT EXISTS member_documents (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  member_id VARCHAR(255) NOT NULL,
+  doc_type VARCHAR(20) NOT NULL,
+  number VARCHAR(100) NULL,
+  issue_date VARCHAR(10) NULL,
+  expiry_date VARCHAR(10) NULL,
+  file_url VARCHAR(500) NULL,
+  notes TEXT NULL,
+  notified_stage VARCHAR(20) NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_member_documents_user_id (user_id),
+  KEY idx_member_documents_member_id (member_id),
+  KEY idx_member_documents_expiry_date (expiry_date)
+);
+
+-- File lama dipindahkan sebagai jenis "lainnya"; jenis dan masa berlaku dilengkapi lewat /api/member-documents.
+INSERT INTO member_documents (user_id, member_id, doc_type, file_url, created_at, updated_at)
+SELECT m.user_id, m.id, 'lainnya', d.file_name, NOW(3), NOW(3)
+FROM members m,
+  JSON_TABLE(m.documents, '$[*]' COLUMNS (file_name VARCHAR(500) PATH '$')) d
+WHERE m.documents IS NOT NULL AND JSON_VALID(m.documents) AND d.file_name IS NOT NULL AND d.file_name <> '';
+
+ALTER TABLE members DROP COLUMN documents;
+
+-- Operator yang mengoperasikan unit selama penempatan (dicek terhadap lisensi SIO/SIM B2).
+ALTER TABLE equipment_assignments
+  ADD COLUMN operator_id VARCHAR(255) NULL,
+  ADD INDEX idx_equipment_assignments_operator_id (operator_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS member_employment_events;
+DROP TABLE IF EXISTS member_contracts;
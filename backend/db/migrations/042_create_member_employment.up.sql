@@ -0,0 +1,52 @@
+-- Kontrak kerja (PKWT/PKWTT) dan riwayat jabatan/tarif/status anggota; payroll memakai tarif yang berlaku pada tanggal tiap detail gaji.
+CREATE TABLE IF NOT EXISTS member_contracts (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  member_id VARCHAR(255) NOT NULL,
+  contract_type VARCHAR(10) NOT NULL,
+  number VARCHAR(100) NULL,
+  start_date VARCHAR(10) NOT NULL,
+  end_date VARCHAR(10) NULL,
+  role VARCHAR(100) NULL,
+  hourly_rate DECIMAL(15,2) DEFAULT 0,
+  trip_rate DECIMAL(15,2) DEFAULT 0,
+  file_url VARCHAR(500) NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_member_contracts_user_id (user_id),
+  KEY idx_member_contracts_member_id (member_id)
+);
+
+CREATE TABLE IF NOT EXISTS member_employment_events (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  member_id VARCHAR(255) NOT NULL,
+  event_type VARCHAR(20) NOT NULL,
+  effective_date VARCHAR(10) NOT NULL,
+  role VARCHAR(100) NULL,
+  hourly_rate DECIMAL(15,2) DEFAULT 0,
+  trip_rate DECIMAL(15,2) DEFAULT 0,
+  contract_id BIGINT UNSIGNED NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_member_employment_events_user_id (user_id),
+  KEY idx_employment_member_date (member_id, effective_date),
+  KEY idx_member_employment_events_contract_id (contract_id)
+);
+
+-- Jabatan saat ini menjadi titik awal riwayat; anggota nonaktif mendapat peristiwa penonaktifan.
+INSERT INTO member_employment_events (user_id, member_id, event_type, effective_date, role, created_at)
+SELECT user_id, id, 'joined',
+  CASE WHEN join_date REGEXP '^[0-9]{4}-[0-9]{2}-[0-9]{2}' THEN LEFT(join_date, 10) ELSE DATE_FORMAT(NOW(), '%Y-%m-%d') END,
+  role, NOW(3)
+FROM members;
+
+INSERT INTO member_employment_events (user_id, member_id, event_type, effective_date, notes, created_at)
+SELECT user_id, id, 'deactivated',
+  CASE WHEN deactivated_at REGEXP '^[0-9]{4}-[0-9]{2}-[0-9]{2}' THEN LEFT(deactivated_at, 10) ELSE DATE_FORMAT(NOW(), '%Y-%m-%d') END,
+  deactivation_reason, NOW(3)
+FROM members
+WHERE is_active = 0;
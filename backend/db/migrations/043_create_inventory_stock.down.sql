@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS stock_locations;
+ALTER TABLE inventory_categories
+  DROP COLUMN unit_header,
+  DROP COLUMN quantity_header;
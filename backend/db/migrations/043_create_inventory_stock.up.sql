@@ -0,0 +1,42 @@
+-- Buku stok inventori: lokasi (gudang/site), mutasi per item, dan kolom jumlah/satuan yang ditetapkan per kategori.
+ALTER TABLE inventory_categories
+  ADD COLUMN quantity_header VARCHAR(100) NULL,
+  ADD COLUMN unit_header VARCHAR(100) NULL;
+
+CREATE TABLE IF NOT EXISTS stock_locations (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  name VARCHAR(255) NOT NULL,
+  kind VARCHAR(20) DEFAULT 'warehouse',
+  project_id BIGINT UNSIGNED NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  updated_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_stock_locations_user_id (user_id),
+  KEY idx_stock_locations_project_id (project_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT UNSIGNED NOT NULL DEFAULT 1,
+  item_id VARCHAR(255) NOT NULL,
+  category_id VARCHAR(36) NULL,
+  movement_type VARCHAR(20) NOT NULL,
+  date VARCHAR(10) NOT NULL,
+  quantity DECIMAL(15,3) NOT NULL,
+  unit VARCHAR(30) NULL,
+  from_location_id BIGINT UNSIGNED NULL,
+  to_location_id BIGINT UNSIGNED NULL,
+  project_id BIGINT UNSIGNED NULL,
+  reference VARCHAR(100) NULL,
+  notes TEXT NULL,
+  created_at DATETIME(3) NULL,
+  PRIMARY KEY (id),
+  KEY idx_stock_movements_user_id (user_id),
+  KEY idx_stock_movements_item_date (item_id, date),
+  KEY idx_stock_movements_category_id (category_id),
+  KEY idx_stock_movements_from_location_id (from_location_id),
+  KEY idx_stock_movements_to_location_id (to_location_id),
+  KEY idx_stock_movements_project_id (project_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE salaries
+  DROP COLUMN loan_adjustment;
@@ -0,0 +1,22 @@
+-- Potongan manual di luar kasbon: Loan kini selalu dihitung ulang dari kasbon + cicilan ledger + loan_adjustment.
+ALTER TABLE salaries
+  ADD COLUMN loan_adjustment DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- Gaji yang belum dikunci: sisa loan di luar kasbon gaji dipindah menjadi penyesuaian manual, agar hitung ulang
+-- berikutnya tidak menghapus potongan yang dulu diisi tangan. Salinan kasbon di ledger (source 'salary') sama dengan
+-- baris kasbons sehingga tidak dikurangi lagi; gaji di payroll run draft sudah memuat cicilan terjadwal bulan run,
+-- jadi cicilan itu (aturan yang sama dengan scheduledInstallment) ikut dikurangi.
+UPDATE salaries s
+LEFT JOIN payroll_runs pr ON pr.id = s.payroll_run_id AND pr.status = 'draft'
+SET s.loan_adjustment = GREATEST(0, ROUND(s.loan
+    - COALESCE((SELECT SUM(k.jumlah) FROM kasbons k WHERE k.salary_id = s.id), 0)
+    - CASE WHEN pr.id IS NULL THEN 0 ELSE COALESCE((
+        SELECT SUM(LEAST(a.installment_amount, a.amount - COALESCE(
+          (SELECT SUM(r.amount) FROM advance_repayments r WHERE r.advance_id = a.id), 0)))
+        FROM member_advances a
+        WHERE a.member_id = s.member_id AND a.user_id = pr.user_id AND a.legacy_kasbon_id IS NULL
+          AND a.start_month <= pr.month
+          AND a.amount - COALESCE((SELECT SUM(r.amount) FROM advance_repayments r WHERE r.advance_id = a.id), 0) > 0
+          AND NOT EXISTS (SELECT 1 FROM advance_repayments r WHERE r.advance_id = a.id AND r.month = pr.month)
+      ), 0) END, 2))
+WHERE s.locked = 0;
@@ -0,0 +1,2 @@
+ALTER TABLE salary_details
+  DROP COLUMN rate_source;
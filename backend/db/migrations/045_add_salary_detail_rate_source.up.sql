@@ -0,0 +1,4 @@
+-- Sumber harga_per_jam detail gaji: 'contract' diselesaikan ulang dari kontrak/riwayat tarif setiap gaji dihitung ulang.
+-- Baris lama tetap 'manual'; baris manual tanpa harga diisi kontrak saat hitung ulang jika tarifnya ada.
+ALTER TABLE salary_details
+  ADD COLUMN rate_source VARCHAR(20) NOT NULL DEFAULT 'manual';
@@ -177,7 +177,12 @@ func extractJSONFromText(text string) string {
 
 // ExtractInvoiceFromImage memanggil model vision dan mengembalikan data invoice (format sama dengan Gemini).
 func ExtractInvoiceFromImage(baseURL, apiKey, model string, imageData []byte, mimeType string) (*gemini.ExtractInvoiceResponse, error) {
-	text, err := callVision(baseURL, apiKey, model, imageData, mimeType, gemini.ExtractPromptForDeepSeek())
+	return ExtractInvoiceFromImageWithPrompt(baseURL, apiKey, model, imageData, mimeType, gemini.ExtractPromptForDeepSeek())
+}
+
+// ExtractInvoiceFromImageWithPrompt sama seperti ExtractInvoiceFromImage dengan prompt dari luar (template versi di database).
+func ExtractInvoiceFromImageWithPrompt(baseURL, apiKey, model string, imageData []byte, mimeType string, prompt string) (*gemini.ExtractInvoiceResponse, error) {
+	text, err := callVision(baseURL, apiKey, model, imageData, mimeType, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +203,11 @@ func ExtractOneDayFromImage(baseURL, apiKey, model string, imageData []byte, mim
 	if isOllama(baseURL) {
 		prompt = gemini.BuildOneDayPromptForOllama(quantityUnit, useBBM)
 	}
+	return ExtractOneDayFromImageWithPrompt(baseURL, apiKey, model, imageData, mimeType, prompt)
+}
+
+// ExtractOneDayFromImageWithPrompt sama seperti ExtractOneDayFromImage dengan prompt dari luar.
+func ExtractOneDayFromImageWithPrompt(baseURL, apiKey, model string, imageData []byte, mimeType string, prompt string) (*gemini.ExtractOneDayResponse, error) {
 	text, err := callVision(baseURL, apiKey, model, imageData, mimeType, prompt)
 	if err != nil {
 		return nil, err
@@ -222,6 +232,11 @@ func ExtractDateAndDaysFromImage(baseURL, apiKey, model string, imageData []byte
 	if isOllama(baseURL) {
 		prompt = gemini.BuildRowOnlyPromptForOllama(columnDescriptions)
 	}
+	return ExtractDateAndDaysFromImageWithPrompt(baseURL, apiKey, model, imageData, mimeType, prompt)
+}
+
+// ExtractDateAndDaysFromImageWithPrompt sama seperti ExtractDateAndDaysFromImage dengan prompt dari luar.
+func ExtractDateAndDaysFromImageWithPrompt(baseURL, apiKey, model string, imageData []byte, mimeType string, prompt string) (*gemini.ExtractRowResponse, error) {
 	text, err := callVision(baseURL, apiKey, model, imageData, mimeType, prompt)
 	if err != nil {
 		return nil, err
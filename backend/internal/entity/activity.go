@@ -10,6 +10,10 @@ const (
 	ActivityExpense ActivityType = "expense"
 	ActivityMember  ActivityType = "member"
 	ActivityUpdate  ActivityType = "update"
+	// ActivityMaintenance servis alat yang jatuh tempo/terlambat (dicatat oleh job latar belakang).
+	ActivityMaintenance ActivityType = "maintenance"
+	// ActivityCompliance dokumen/izin yang akan atau sudah kedaluwarsa.
+	ActivityCompliance ActivityType = "compliance"
 	// ActivityProject   ActivityType = "project"
 	// ActivitySalary    ActivityType = "salary"
 	// ActivityInventory ActivityType = "inventory"
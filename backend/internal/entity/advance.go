@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+// Status kasbon (advance) di ledger anggota.
+const (
+	AdvanceOpen    = "open"
+	AdvancePaidOff = "paid_off"
+)
+
+// Sumber pembayaran cicilan kasbon.
+const (
+	RepaymentPayroll = "payroll" // dipotong otomatis saat payroll run difinalisasi
+	RepaymentManual  = "manual"  // dibayar langsung oleh anggota
+	RepaymentSalary  = "salary"  // kasbon lama (per gaji) yang sudah dipotong dari gaji bulan itu
+)
+
+// MemberAdvance pencairan kasbon ke anggota, tidak terikat ke satu gaji. Dicicil InstallmentAmount per bulan
+// mulai StartMonth sampai lunas.
+type MemberAdvance struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	UserID            uint      `gorm:"not null;index;default:1" json:"user_id"`
+	MemberID          string    `gorm:"type:varchar(255);not null;index" json:"member_id"`
+	Tanggal           string    `gorm:"size:10;not null" json:"tanggal"` // YYYY-MM-DD pencairan
+	Amount            float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Installments      int       `gorm:"default:1" json:"installments"`
+	InstallmentAmount float64   `gorm:"type:decimal(15,2);default:0" json:"installment_amount"`
+	StartMonth        string    `gorm:"size:7;not null" json:"start_month"` // YYYY-MM potongan pertama
+	Keterangan        string    `gorm:"type:text" json:"keterangan"`
+	Status            string    `gorm:"type:varchar(20);default:'open'" json:"status"`
+	LegacyKasbonID    *uint     `gorm:"uniqueIndex" json:"legacy_kasbon_id,omitempty"` // asal baris kasbons yang dimigrasi
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	MemberName  string  `gorm:"-" json:"member_name,omitempty"`
+	Repaid      float64 `gorm:"-" json:"repaid"`
+	Outstanding float64 `gorm:"-" json:"outstanding"`
+}
+
+func (MemberAdvance) TableName() string {
+	return "member_advances"
+}
+
+// AdvanceRepayment satu pembayaran/potongan cicilan kasbon.
+type AdvanceRepayment struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index;default:1" json:"user_id"`
+	AdvanceID    uint      `gorm:"not null;index" json:"advance_id"`
+	MemberID     string    `gorm:"type:varchar(255);not null;index" json:"member_id"`
+	Month        string    `gorm:"size:7;not null" json:"month"` // bulan gaji yang dipotong
+	Tanggal      string    `gorm:"size:10" json:"tanggal"`
+	Amount       float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Source       string    `gorm:"type:varchar(20);default:'manual'" json:"source"`
+	SalaryID     *uint     `gorm:"index" json:"salary_id,omitempty"`
+	PayrollRunID *uint     `gorm:"index" json:"payroll_run_id,omitempty"`
+	Keterangan   string    `gorm:"type:text" json:"keterangan"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (AdvanceRepayment) TableName() string {
+	return "advance_repayments"
+}
+
+// AdvanceBalance saldo kasbon per anggota.
+type AdvanceBalance struct {
+	MemberID      string  `json:"member_id"`
+	MemberName    string  `json:"member_name"`
+	Disbursed     float64 `json:"disbursed"`
+	Repaid        float64 `json:"repaid"`
+	Outstanding   float64 `json:"outstanding"`
+	OpenAdvances  int     `json:"open_advances"`
+	NextDeduction float64 `json:"next_deduction"` // potongan terjadwal bulan berjalan
+}
+
+// AdvanceHistoryEntry baris riwayat pelunasan: pencairan (+) dan pembayaran (-) beserta saldo berjalan.
+type AdvanceHistoryEntry struct {
+	Date      string  `json:"date"`
+	Kind      string  `json:"kind"` // disbursement | repayment
+	AdvanceID uint    `json:"advance_id"`
+	Month     string  `json:"month,omitempty"`
+	Source    string  `json:"source,omitempty"`
+	Amount    float64 `json:"amount"`
+	Balance   float64 `json:"balance"`
+	Note      string  `json:"note,omitempty"`
+}
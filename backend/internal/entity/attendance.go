@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+// Status kehadiran harian.
+const (
+	AttendancePresent = "hadir"
+	AttendanceLeave   = "izin"
+	AttendanceSick    = "sakit"
+	AttendanceAbsent  = "alpa"
+	AttendanceOff     = "cuti"
+)
+
+// Dasar pembayaran kehadiran: per jam kerja atau per trip (dump truck).
+const (
+	PayPerHour = "hour"
+	PayPerTrip = "trip"
+)
+
+// DefaultOvertimeMultiplier pengali tarif untuk jam lembur bila OvertimeRate kosong.
+const DefaultOvertimeMultiplier = 1.5
+
+// Attendance absensi satu anggota pada satu tanggal dan shift. Hanya absensi hadir yang sudah disetujui
+// yang dijadikan baris SalaryDetail saat gaji bulan itu dihitung ulang.
+type Attendance struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"not null;index;default:1" json:"user_id"`
+	MemberID      string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_attendance_member_day" json:"member_id"`
+	Date          string     `gorm:"size:10;not null;index;uniqueIndex:idx_attendance_member_day" json:"date"` // YYYY-MM-DD
+	Shift         string     `gorm:"type:varchar(20);not null;default:'';uniqueIndex:idx_attendance_member_day" json:"shift"`
+	Status        string     `gorm:"type:varchar(10);not null;default:hadir" json:"status"`
+	ProjectID     *uint      `gorm:"index" json:"project_id"`
+	Site          string     `gorm:"type:varchar(200)" json:"site"`
+	EquipmentID   *uint      `gorm:"index" json:"equipment_id"`                // alat yang dioperasikan
+	CheckIn       string     `gorm:"size:5" json:"check_in"`                   // HH:MM
+	CheckOut      string     `gorm:"size:5" json:"check_out"`                  // HH:MM; lebih kecil dari CheckIn = lewat tengah malam
+	Hours         float64    `gorm:"type:decimal(5,2);default:0" json:"hours"` // jam kerja normal; kosong = durasi CheckIn–CheckOut dikurangi lembur
+	OvertimeHours float64    `gorm:"type:decimal(5,2);default:0" json:"overtime_hours"`
+	Trips         float64    `gorm:"type:decimal(6,1);default:0" json:"trips"`
+	PayBasis      string     `gorm:"type:varchar(10);not null;default:hour" json:"pay_basis"` // hour | trip
+	Rate          float64    `gorm:"type:decimal(15,2);default:0" json:"rate"`                // per jam atau per trip; 0 = tarif kontrak pada tanggal itu
+	OvertimeRate  float64    `gorm:"type:decimal(15,2);default:0" json:"overtime_rate"`       // per jam; 0 = Rate × 1,5 (wajib untuk dasar trip)
+	Approved      bool       `gorm:"default:false;index" json:"approved"`
+	ApprovedAt    *time.Time `json:"approved_at"`
+	Notes         string     `gorm:"type:text" json:"notes"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	MemberName    string `gorm:"-" json:"member_name,omitempty"`
+	EquipmentName string `gorm:"-" json:"equipment_name,omitempty"`
+
+	// Peringatan lisensi operator untuk EquipmentID saat disimpan (tidak memblokir).
+	Warnings []LicenceWarning `gorm:"-" json:"warnings,omitempty"`
+}
+
+func (Attendance) TableName() string {
+	return "attendances"
+}
+
+// AttendanceMatrixCell ringkasan satu anggota pada satu tanggal (gabungan semua shift).
+type AttendanceMatrixCell struct {
+	Date     string  `json:"date"`
+	Status   string  `json:"status,omitempty"` // kosong = tidak ada absensi
+	Hours    float64 `json:"hours"`
+	Overtime float64 `json:"overtime"`
+	Trips    float64 `json:"trips"`
+	Approved bool    `json:"approved"`
+}
+
+// AttendanceMatrixRow satu baris matriks absensi bulanan.
+type AttendanceMatrixRow struct {
+	MemberID      string                 `json:"member_id"`
+	MemberName    string                 `json:"member_name"`
+	Days          []AttendanceMatrixCell `json:"days"`
+	PresentDays   int                    `json:"present_days"`
+	StatusCounts  map[string]int         `json:"status_counts"`
+	TotalHours    float64                `json:"total_hours"`
+	TotalOvertime float64                `json:"total_overtime"`
+	TotalTrips    float64                `json:"total_trips"`
+	Pending       int                    `json:"pending"` // absensi hadir yang belum disetujui
+}
+
+// AttendanceMatrix rekap absensi bulanan per anggota.
+type AttendanceMatrix struct {
+	Month string                `json:"month"`
+	Days  int                   `json:"days"`
+	Rows  []AttendanceMatrixRow `json:"rows"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// EquipmentAssignment penempatan satu unit alat di proyek untuk rentang tanggal (inklusif).
+// ToDate kosong = masih di lokasi (tanpa tanggal selesai). Satu unit tidak boleh punya dua penempatan yang tumpang tindih.
+type EquipmentAssignment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index;default:1" json:"user_id"`
+	EquipmentID uint      `gorm:"not null;index:idx_equipment_assignments_equipment_dates,priority:1" json:"equipment_id"`
+	ProjectID   uint      `gorm:"not null;index" json:"project_id"`
+	OperatorID  *string   `gorm:"type:varchar(255);index" json:"operator_id"`                                                   // anggota yang mengoperasikan unit
+	FromDate    string    `gorm:"size:10;not null;index:idx_equipment_assignments_equipment_dates,priority:2" json:"from_date"` // YYYY-MM-DD
+	ToDate      *string   `gorm:"size:10" json:"to_date"`                                                                       // YYYY-MM-DD, NULL = terbuka
+	Rate        float64   `gorm:"type:decimal(15,2);default:0" json:"rate"`
+	RateUnit    string    `gorm:"type:varchar(20);default:'hari'" json:"rate_unit"` // hari | jam | bulan
+	Notes       string    `gorm:"type:text" json:"notes"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Peringatan lisensi operator saat disimpan (tidak memblokir).
+	Warnings []LicenceWarning `gorm:"-" json:"warnings,omitempty"`
+}
+
+func (EquipmentAssignment) TableName() string {
+	return "equipment_assignments"
+}
+
+// EquipmentDeployment penempatan lengkap dengan nama alat dan proyek (untuk daftar "alat di mana pada tanggal X").
+type EquipmentDeployment struct {
+	EquipmentAssignment
+	EquipmentName string `json:"equipment_name"`
+	EquipmentType string `json:"equipment_type"`
+	LicensePlate  string `json:"license_plate"`
+	ProjectName   string `json:"project_name"`
+	OperatorName  string `json:"operator_name"`
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+// Jenis dokumen alat.
+const (
+	DocSTNK      = "stnk"
+	DocKIR       = "kir"
+	DocInsurance = "asuransi"
+	DocSIA       = "sia"  // Surat Izin Alat
+	DocSILO      = "silo" // Surat Izin Layak Operasi
+	DocOther     = "lainnya"
+)
+
+// Status masa berlaku dokumen.
+const (
+	DocStatusValid    = "valid"
+	DocStatusNoExpiry = "no_expiry"
+	DocStatus30       = "expiring_30"
+	DocStatus14       = "expiring_14"
+	DocStatus7        = "expiring_7"
+	DocStatusExpired  = "expired"
+)
+
+// RequiredDocuments dokumen wajib per jenis alat.
+var RequiredDocuments = map[string][]string{
+	"dump_truck": {DocSTNK, DocKIR, DocInsurance},
+	"alat_berat": {DocSIA, DocSILO},
+}
+
+// EquipmentDocument dokumen/izin alat (STNK, KIR, asuransi, SIA, SILO) dengan masa berlaku dan scan.
+type EquipmentDocument struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index;default:1" json:"user_id"`
+	EquipmentID   uint      `gorm:"not null;index" json:"equipment_id"`
+	DocType       string    `gorm:"type:varchar(20);not null" json:"doc_type"`
+	Number        string    `gorm:"type:varchar(100)" json:"number"`
+	IssueDate     *string   `gorm:"size:10" json:"issue_date"`         // YYYY-MM-DD
+	ExpiryDate    *string   `gorm:"size:10;index" json:"expiry_date"`  // YYYY-MM-DD, NULL = tidak kedaluwarsa
+	FileURL       string    `gorm:"type:varchar(500)" json:"file_url"` // hasil POST /api/uploads/file (folder equipment-documents)
+	Notes         string    `gorm:"type:text" json:"notes"`
+	NotifiedStage string    `gorm:"type:varchar(20)" json:"-"` // status terakhir yang sudah dicatat sebagai aktivitas
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Dihitung saat dibaca.
+	DaysLeft *int   `gorm:"-" json:"days_left"`
+	Status   string `gorm:"-" json:"status"`
+}
+
+func (EquipmentDocument) TableName() string {
+	return "equipment_documents"
+}
+
+// EquipmentCompliance kelengkapan dokumen satu alat.
+type EquipmentCompliance struct {
+	EquipmentID   uint                `json:"equipment_id"`
+	EquipmentName string              `json:"equipment_name"`
+	EquipmentType string              `json:"equipment_type"`
+	LicensePlate  string              `json:"license_plate"`
+	Compliant     bool                `json:"compliant"` // semua dokumen wajib ada dan belum kedaluwarsa
+	Missing       []string            `json:"missing"`
+	Expired       []EquipmentDocument `json:"expired"`
+	Expiring      []EquipmentDocument `json:"expiring"` // kedaluwarsa dalam 30 hari
+	Documents     []EquipmentDocument `json:"documents"`
+}
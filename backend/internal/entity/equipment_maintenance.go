@@ -0,0 +1,97 @@
+package entity
+
+import "time"
+
+// Satuan meter alat: hour meter untuk alat berat, odometer (km) untuk dump truck.
+const (
+	MeterUnitHM = "hm"
+	MeterUnitKM = "km"
+)
+
+// Status jatuh tempo rencana perawatan.
+const (
+	MaintenanceOK      = "ok"
+	MaintenanceDueSoon = "due_soon"
+	MaintenanceOverdue = "overdue"
+)
+
+// MeterUnitOf satuan meter sesuai jenis alat.
+func MeterUnitOf(e Equipment) string {
+	if e.Type == "dump_truck" {
+		return MeterUnitKM
+	}
+	return MeterUnitHM
+}
+
+// EquipmentMeterReading pembacaan hour meter / odometer pada satu tanggal.
+type EquipmentMeterReading struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index;default:1" json:"user_id"`
+	EquipmentID uint      `gorm:"not null;index" json:"equipment_id"`
+	Date        string    `gorm:"size:10;not null" json:"date"` // YYYY-MM-DD
+	Reading     float64   `gorm:"type:decimal(12,1);not null" json:"reading"`
+	Notes       string    `gorm:"type:text" json:"notes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (EquipmentMeterReading) TableName() string {
+	return "equipment_meter_readings"
+}
+
+// MaintenancePlan perawatan berkala, mis. ganti oli tiap 250 HM atau inspeksi track tiap 500 HM.
+// Interval meter dan/atau hari; jatuh tempo dihitung dari servis terakhir.
+type MaintenancePlan struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"not null;index;default:1" json:"user_id"`
+	EquipmentID      uint      `gorm:"not null;index" json:"equipment_id"`
+	Name             string    `gorm:"type:varchar(200);not null" json:"name"`
+	IntervalMeter    float64   `gorm:"type:decimal(12,1);default:0" json:"interval_meter"` // 0 = tanpa interval meter
+	IntervalDays     int       `gorm:"default:0" json:"interval_days"`                     // 0 = tanpa interval kalender
+	DueSoonMeter     float64   `gorm:"type:decimal(12,1);default:0" json:"due_soon_meter"` // peringatan dini; 0 = 10% interval
+	LastServiceMeter float64   `gorm:"type:decimal(12,1);default:0" json:"last_service_meter"`
+	LastServiceDate  *string   `gorm:"size:10" json:"last_service_date"`
+	Active           bool      `json:"active"`                    // tanpa default gorm agar false ikut tersimpan; default true diisi handler
+	NotifiedDue      string    `gorm:"type:varchar(50)" json:"-"` // jatuh tempo terakhir yang sudah dicatat sebagai aktivitas
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Dihitung saat dibaca.
+	MeterUnit      string   `gorm:"-" json:"meter_unit"`
+	CurrentMeter   float64  `gorm:"-" json:"current_meter"`
+	NextDueMeter   *float64 `gorm:"-" json:"next_due_meter"`
+	RemainingMeter *float64 `gorm:"-" json:"remaining_meter"`
+	NextDueDate    *string  `gorm:"-" json:"next_due_date"`
+	RemainingDays  *int     `gorm:"-" json:"remaining_days"`
+	Status         string   `gorm:"-" json:"status"`
+}
+
+func (MaintenancePlan) TableName() string {
+	return "maintenance_plans"
+}
+
+// ServiceRecord riwayat servis. Biaya dicatat sebagai Finance expense (FinanceID) dengan equipment_id alat.
+type ServiceRecord struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index;default:1" json:"user_id"`
+	EquipmentID uint      `gorm:"not null;index" json:"equipment_id"`
+	PlanID      *uint     `gorm:"index" json:"plan_id"`
+	Date        string    `gorm:"size:10;not null" json:"date"` // YYYY-MM-DD
+	Meter       float64   `gorm:"type:decimal(12,1);default:0" json:"meter"`
+	Description string    `gorm:"type:text" json:"description"`
+	Mechanic    string    `gorm:"type:varchar(100)" json:"mechanic"`
+	Cost        float64   `gorm:"type:decimal(15,2);default:0" json:"cost"`
+	FinanceID   *uint     `gorm:"index" json:"finance_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (ServiceRecord) TableName() string {
+	return "service_records"
+}
+
+// MaintenanceDue rencana perawatan yang jatuh tempo/terlambat, untuk daftar armada.
+type MaintenanceDue struct {
+	MaintenancePlan
+	EquipmentName string `json:"equipment_name"`
+	LicensePlate  string `json:"license_plate"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+// EquipmentUtilization utilisasi & profitabilitas satu unit alat dalam periode From–To (inklusif).
+// Hari kerja = tanggal yang tercatat di baris invoice (RowDate) atau di laporan harian proyek tempat alat ditempatkan.
+type EquipmentUtilization struct {
+	Rank               int      `json:"rank"`
+	EquipmentID        uint     `json:"equipment_id"`
+	EquipmentName      string   `json:"equipment_name"`
+	EquipmentType      string   `json:"equipment_type"`
+	LicensePlate       string   `json:"license_plate"`
+	From               string   `json:"from"`
+	To                 string   `json:"to"`
+	PeriodDays         int      `json:"period_days"`
+	AssignedDays       int      `json:"assigned_days"` // hari dengan penempatan aktif di proyek
+	WorkingDays        int      `json:"working_days"`
+	InvoicedDays       int      `json:"invoiced_days"` // hari kerja yang berasal dari baris invoice
+	ReportedDays       int      `json:"reported_days"` // hari kerja yang berasal dari laporan harian
+	IdleDays           int      `json:"idle_days"`
+	UtilizationPercent float64  `json:"utilization_percent"`
+	Hours              float64  `json:"hours"`
+	BilledRevenue      float64  `json:"billed_revenue"` // total baris invoice
+	FinanceIncome      float64  `json:"finance_income"` // pemasukan Finance dengan equipment_id
+	Revenue            float64  `json:"revenue"`        // BilledRevenue, atau FinanceIncome jika belum ada invoice
+	Cost               float64  `json:"cost"`           // pengeluaran Finance (BBM, perbaikan, dll.)
+	Profit             float64  `json:"profit"`
+	RevenuePerHour     float64  `json:"revenue_per_hour"`
+	CostPerHour        float64  `json:"cost_per_hour"`
+	PurchasePrice      float64  `json:"purchase_price"`
+	LifetimeRevenue    float64  `json:"lifetime_revenue"`
+	LifetimeCost       float64  `json:"lifetime_cost"`
+	LifetimeProfit     float64  `json:"lifetime_profit"`
+	PaybackPercent     float64  `json:"payback_percent"`          // LifetimeProfit / PurchasePrice
+	PaybackMonths      *float64 `json:"payback_months,omitempty"` // perkiraan sisa bulan sampai balik modal dari laju profit periode; 0 = sudah balik modal
+}
@@ -13,6 +13,7 @@ const (
 	CategoryJasa          FinanceCategory = "Jasa"
 	CategorySewaAlatBerat FinanceCategory = "Sewa Alat Berat"
 	CategoryOther         FinanceCategory = "Other"
+	CategoryGaji          FinanceCategory = "Gaji"
 )
 
 type MonthlyComparison struct {
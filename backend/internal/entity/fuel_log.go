@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// FuelLog pengeluaran BBM ke satu unit alat. MeterReading = hour meter/odometer saat pengisian;
+// konsumsi per jam dihitung dari selisih meter antar pengisian (metode isi penuh ke isi penuh).
+type FuelLog struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index;default:1" json:"user_id"`
+	EquipmentID   uint      `gorm:"not null;index" json:"equipment_id"`
+	ProjectID     *uint     `gorm:"index" json:"project_id"`
+	Date          string    `gorm:"size:10;not null;index" json:"date"` // YYYY-MM-DD
+	Liters        float64   `gorm:"type:decimal(10,2);not null" json:"liters"`
+	PricePerLiter float64   `gorm:"type:decimal(15,2);default:0" json:"price_per_liter"`
+	Total         float64   `gorm:"type:decimal(15,2);default:0" json:"total"`
+	MeterReading  float64   `gorm:"type:decimal(12,1);default:0" json:"meter_reading"`
+	OperatorID    *string   `gorm:"type:varchar(255);index" json:"operator_id"` // Member.ID
+	FinanceID     *uint     `gorm:"index" json:"finance_id"`                    // expense pembelian BBM (opsional)
+	Notes         string    `gorm:"type:text" json:"notes"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Dihitung saat dibaca.
+	OperatorName  string   `gorm:"-" json:"operator_name,omitempty"`
+	MeterDelta    *float64 `gorm:"-" json:"meter_delta"`     // selisih meter dari pengisian sebelumnya
+	LitersPerHour *float64 `gorm:"-" json:"liters_per_hour"` // per km untuk dump truck
+	Anomaly       bool     `gorm:"-" json:"anomaly"`
+	AnomalyReason string   `gorm:"-" json:"anomaly_reason,omitempty"`
+}
+
+func (FuelLog) TableName() string {
+	return "fuel_logs"
+}
+
+// FuelConsumption ringkasan BBM satu unit: konsumsi rata-rata dan rekonsiliasi BBM ditagih vs dikeluarkan.
+type FuelConsumption struct {
+	EquipmentID       uint      `json:"equipment_id"`
+	EquipmentName     string    `json:"equipment_name"`
+	LicensePlate      string    `json:"license_plate"`
+	MeterUnit         string    `json:"meter_unit"`
+	From              string    `json:"from"`
+	To                string    `json:"to"`
+	IssuedLiters      float64   `json:"issued_liters"`
+	IssuedCost        float64   `json:"issued_cost"`
+	MeterHours        float64   `json:"meter_hours"`
+	AvgLitersPerHour  float64   `json:"avg_liters_per_hour"`  // periode ini
+	HistLitersPerHour float64   `json:"hist_liters_per_hour"` // seluruh riwayat, acuan anomali
+	Anomalies         int       `json:"anomalies"`
+	BilledLiters      float64   `json:"billed_liters"` // BbmQuantity di baris invoice
+	BilledAmount      float64   `json:"billed_amount"`
+	LitersVariance    float64   `json:"liters_variance"` // BilledLiters - IssuedLiters; negatif = BBM belum ditagih
+	AmountVariance    float64   `json:"amount_variance"`
+	Logs              []FuelLog `json:"logs,omitempty"`
+}
@@ -11,6 +11,11 @@ type InventoryCategory struct {
 	Description string          `gorm:"type:text" json:"description"`
 	Headers     datatypes.JSON  `gorm:"type:json" json:"headers"`
 	Data        []InventoryData `gorm:"foreignKey:CategoryID" json:"data"`
+
+	// Kolom (ID header) yang ditetapkan sebagai jumlah stok dan satuan. Jika QuantityHeader diisi,
+	// nilainya dihitung dari buku stok (StockMovement) dan tidak bisa diubah manual.
+	QuantityHeader string `gorm:"type:varchar(100)" json:"quantity_header"`
+	UnitHeader     string `gorm:"type:varchar(100)" json:"unit_header"`
 }
 
 type InventoryData struct {
@@ -19,3 +24,29 @@ type InventoryData struct {
 	Values     datatypes.JSON `gorm:"type:json" json:"values"`
 	Images     datatypes.JSON `gorm:"type:json" json:"images"`
 }
+
+const (
+	HeaderText     = "text"
+	HeaderNumber   = "number"
+	HeaderDate     = "date" // YYYY-MM-DD
+	HeaderEnum     = "enum"
+	HeaderCurrency = "currency" // rupiah, dibulatkan 2 desimal
+	HeaderImage    = "image"
+)
+
+// InventoryHeader definisi satu kolom dinamis di InventoryCategory.Headers.
+// Tipe lama string/integer/float dinormalisasi menjadi text/number saat kategori disimpan.
+type InventoryHeader struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Required bool        `json:"required"`
+	Optional bool        `json:"optional"` // kebalikan Required, dipertahankan untuk klien lama
+	Unique   bool        `json:"unique"`
+	Default  interface{} `json:"default,omitempty"`
+	Options  []string    `json:"options,omitempty"` // pilihan untuk enum, juga urutan sorting
+	Integer  bool        `json:"integer,omitempty"` // number tanpa desimal
+
+	// RenamedFrom hanya dikirim saat update kategori: ID kolom lama yang nilainya dipindah ke kolom ini.
+	RenamedFrom string `json:"renamed_from,omitempty"`
+}
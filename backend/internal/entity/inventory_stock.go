@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+const (
+	StockLocationWarehouse = "warehouse"
+	StockLocationSite      = "site"
+)
+
+const (
+	StockReceipt    = "receipt"    // masuk ke ToLocationID
+	StockIssue      = "issue"      // keluar dari FromLocationID (dipakai/dikirim ke proyek)
+	StockTransfer   = "transfer"   // pindah FromLocationID -> ToLocationID
+	StockAdjustment = "adjustment" // koreksi stok opname di ToLocationID, Quantity boleh negatif
+)
+
+// StockLocation gudang atau lokasi proyek (site) tempat barang inventori disimpan.
+type StockLocation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index;default:1" json:"user_id"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	Kind      string    `gorm:"type:varchar(20);default:'warehouse'" json:"kind"` // warehouse | site
+	ProjectID *uint     `gorm:"index" json:"project_id"`                          // proyek untuk lokasi site
+	Notes     string    `gorm:"type:text" json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (StockLocation) TableName() string {
+	return "stock_locations"
+}
+
+// StockMovement satu baris buku stok untuk satu item inventori (InventoryData).
+// Stok on-hand per lokasi = jumlah mutasi masuk dikurangi mutasi keluar; tidak disimpan terpisah.
+type StockMovement struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"not null;index;default:1" json:"user_id"`
+	ItemID         string    `gorm:"type:varchar(255);not null;index:idx_stock_movements_item_date,priority:1" json:"item_id"`
+	CategoryID     string    `gorm:"type:varchar(36);index" json:"category_id"`
+	MovementType   string    `gorm:"type:varchar(20);not null" json:"movement_type"`
+	Date           string    `gorm:"size:10;not null;index:idx_stock_movements_item_date,priority:2" json:"date"` // YYYY-MM-DD
+	Quantity       float64   `gorm:"type:decimal(15,3);not null" json:"quantity"`
+	Unit           string    `gorm:"type:varchar(30)" json:"unit"`
+	FromLocationID *uint     `gorm:"index" json:"from_location_id"`
+	ToLocationID   *uint     `gorm:"index" json:"to_location_id"`
+	ProjectID      *uint     `gorm:"index" json:"project_id"`
+	Reference      string    `gorm:"type:varchar(100)" json:"reference"` // no. surat jalan / PO / nota
+	Notes          string    `gorm:"type:text" json:"notes"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// StockMovementEntry mutasi lengkap dengan nama lokasi dan proyek (untuk daftar/riwayat).
+type StockMovementEntry struct {
+	StockMovement
+	FromLocationName string  `json:"from_location_name"`
+	ToLocationName   string  `json:"to_location_name"`
+	ProjectName      string  `json:"project_name"`
+	Balance          float64 `gorm:"-" json:"balance"` // total stok item setelah mutasi ini (hanya di riwayat per item)
+}
+
+// StockBalance stok on-hand satu item di satu lokasi.
+type StockBalance struct {
+	ItemID       string  `json:"item_id"`
+	LocationID   uint    `json:"location_id"`
+	LocationName string  `json:"location_name"`
+	Quantity     float64 `json:"quantity"`
+	Date         string  `gorm:"-" json:"date,omitempty"` // diisi saat mutasi ditolak: tanggal saldo pertama kali negatif
+}
+
+// ItemStock stok satu item: total dan rincian per lokasi.
+type ItemStock struct {
+	ItemID     string         `json:"item_id"`
+	CategoryID string         `json:"category_id"`
+	Name       string         `json:"name"`
+	Unit       string         `json:"unit"`
+	Total      float64        `json:"total"`
+	Locations  []StockBalance `json:"locations"`
+}
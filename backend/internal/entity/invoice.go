@@ -80,3 +80,17 @@ type InvoiceItem struct {
 func (InvoiceItem) TableName() string {
 	return "invoice_items"
 }
+
+// EquipmentInvoiceRow baris invoice (non-cancelled) untuk analisis utilisasi alat; ItemName = nama alat.
+type EquipmentInvoiceRow struct {
+	InvoiceID    uint    `gorm:"column:invoice_id"`
+	InvoiceDate  string  `gorm:"column:invoice_date"`
+	QuantityUnit string  `gorm:"column:quantity_unit"`
+	ItemName     string  `gorm:"column:item_name"`
+	Quantity     float64 `gorm:"column:quantity"`
+	Days         float64 `gorm:"column:days"`
+	Total        float64 `gorm:"column:total"`
+	RowDate      string  `gorm:"column:row_date"`
+	BbmQuantity  float64 `gorm:"column:bbm_quantity"`
+	BbmUnitPrice float64 `gorm:"column:bbm_unit_price"`
+}
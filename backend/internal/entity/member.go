@@ -30,10 +30,14 @@ type Member struct {
 	PhoneNumber        string         `gorm:"size:50" json:"phoneNumber"`
 	Address            string         `gorm:"type:text" json:"address"`
 	JoinDate           string         `json:"joinDate"`
-	ProfileImage       string         `json:"profileImage"`                // Hanya menyimpan nama file
-	Documents          datatypes.JSON `gorm:"type:jsonb" json:"documents"` // Menyimpan array string
-	Files              datatypes.JSON `gorm:"type:jsonb" json:"files"`     // Menyimpan array string
+	ProfileImage       string         `json:"profileImage"`            // Hanya menyimpan nama file
+	Documents          datatypes.JSON `gorm:"-" json:"documents"`      // nama file dari member_documents, untuk tampilan lama
+	Files              datatypes.JSON `gorm:"type:jsonb" json:"files"` // Menyimpan array string
 	IsActive           bool           `gorm:"default:true" json:"isActive"`
+	PTKPStatus         string         `gorm:"type:varchar(10);default:'TK/0'" json:"ptkpStatus"` // TK/0..TK/3, K/0..K/3 untuk PPh 21
+	NPWP               string         `gorm:"type:varchar(30)" json:"npwp"`
+	BPJSEmployment     bool           `gorm:"column:bpjs_ketenagakerjaan" json:"bpjsKetenagakerjaan"` // tanpa default gorm agar false ikut tersimpan; default true diisi handler
+	BPJSHealth         bool           `gorm:"column:bpjs_kesehatan" json:"bpjsKesehatan"`
 	DeactivationReason string         `gorm:"type:text" json:"deactivationReason,omitempty"`
 	DeactivatedAt      *string        `json:"deactivatedAt,omitempty"`
 	Salaries           []Salary       `gorm:"foreignKey:MemberID" json:"salaries"`
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// Jenis dokumen anggota.
+const (
+	MemberDocKTP     = "ktp"
+	MemberDocSIMB2   = "sim_b2"
+	MemberDocSIO     = "sio" // Surat Izin Operator alat berat
+	MemberDocMedical = "mcu" // medical checkup
+	MemberDocOther   = "lainnya"
+)
+
+// OperatorLicences lisensi yang harus berlaku bagi operator per jenis alat.
+var OperatorLicences = map[string][]string{
+	"alat_berat": {MemberDocSIO},
+	"dump_truck": {MemberDocSIMB2},
+}
+
+// MemberDocument dokumen/sertifikat anggota dengan nomor dan masa berlaku. Status memakai konstanta DocStatus*.
+type MemberDocument struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index;default:1" json:"user_id"`
+	MemberID      string    `gorm:"type:varchar(255);not null;index" json:"member_id"`
+	DocType       string    `gorm:"type:varchar(20);not null" json:"doc_type"`
+	Number        string    `gorm:"type:varchar(100)" json:"number"`
+	IssueDate     *string   `gorm:"size:10" json:"issue_date"`         // YYYY-MM-DD
+	ExpiryDate    *string   `gorm:"size:10;index" json:"expiry_date"`  // YYYY-MM-DD, NULL = tidak kedaluwarsa
+	FileURL       string    `gorm:"type:varchar(500)" json:"file_url"` // nama file di uploads (unggahan lama) atau URL POST /api/uploads/file
+	Notes         string    `gorm:"type:text" json:"notes"`
+	NotifiedStage string    `gorm:"type:varchar(20)" json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Dihitung saat dibaca.
+	DaysLeft *int   `gorm:"-" json:"days_left"`
+	Status   string `gorm:"-" json:"status"`
+}
+
+func (MemberDocument) TableName() string {
+	return "member_documents"
+}
+
+// MemberDocumentAlert anggota yang dokumennya sudah atau akan kedaluwarsa.
+type MemberDocumentAlert struct {
+	MemberID   string           `json:"member_id"`
+	MemberName string           `json:"member_name"`
+	Role       string           `json:"role"`
+	Expired    []MemberDocument `json:"expired"`
+	Expiring   []MemberDocument `json:"expiring"`
+}
+
+// Masalah lisensi operator.
+const (
+	LicenceMissing  = "missing"
+	LicenceExpired  = "expired"
+	LicenceExpiring = "expires_during" // berakhir sebelum penempatan selesai
+)
+
+// LicenceWarning peringatan (tidak memblokir) saat operator tanpa lisensi berlaku ditugaskan ke alat.
+type LicenceWarning struct {
+	MemberID   string  `json:"member_id"`
+	MemberName string  `json:"member_name"`
+	DocType    string  `json:"doc_type"`
+	Problem    string  `json:"problem"`
+	ExpiryDate *string `json:"expiry_date,omitempty"`
+	Message    string  `json:"message"`
+}
@@ -0,0 +1,89 @@
+package entity
+
+import "time"
+
+// Jenis kontrak kerja.
+const (
+	ContractPKWT  = "pkwt"  // waktu tertentu, wajib tanggal selesai
+	ContractPKWTT = "pkwtt" // waktu tidak tertentu (karyawan tetap)
+)
+
+// Status kontrak terhadap hari ini.
+const (
+	ContractActive   = "active"
+	ContractUpcoming = "upcoming"
+	ContractEnded    = "ended"
+)
+
+// Jenis peristiwa riwayat kepegawaian. joined, contract dan change membawa jabatan + tarif yang berlaku
+// mulai EffectiveDate; deactivated/reactivated hanya mengubah status aktif.
+const (
+	EmploymentJoined      = "joined"
+	EmploymentContract    = "contract"
+	EmploymentChange      = "change"
+	EmploymentDeactivated = "deactivated"
+	EmploymentReactivated = "reactivated"
+)
+
+// MemberContract kontrak kerja anggota (PKWT/PKWTT) dengan jabatan dan tarif dasar.
+type MemberContract struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index;default:1" json:"user_id"`
+	MemberID     string    `gorm:"type:varchar(255);not null;index" json:"member_id"`
+	ContractType string    `gorm:"type:varchar(10);not null" json:"contract_type"` // pkwt | pkwtt
+	Number       string    `gorm:"type:varchar(100)" json:"number"`
+	StartDate    string    `gorm:"size:10;not null" json:"start_date"` // YYYY-MM-DD
+	EndDate      *string   `gorm:"size:10" json:"end_date"`            // YYYY-MM-DD, NULL untuk PKWTT
+	Role         string    `gorm:"size:100" json:"role"`
+	HourlyRate   float64   `gorm:"type:decimal(15,2);default:0" json:"hourly_rate"`
+	TripRate     float64   `gorm:"type:decimal(15,2);default:0" json:"trip_rate"` // untuk dasar bayar per trip
+	FileURL      string    `gorm:"type:varchar(500)" json:"file_url"`
+	Notes        string    `gorm:"type:text" json:"notes"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Status string `gorm:"-" json:"status"` // dihitung saat dibaca
+}
+
+func (MemberContract) TableName() string {
+	return "member_contracts"
+}
+
+// MemberEmploymentEvent satu baris riwayat kepegawaian. Untuk jenis yang membawa syarat kerja, Role dan
+// tarif adalah nilai lengkap setelah perubahan (bukan selisih).
+type MemberEmploymentEvent struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index;default:1" json:"user_id"`
+	MemberID      string    `gorm:"type:varchar(255);not null;index:idx_employment_member_date,priority:1" json:"member_id"`
+	EventType     string    `gorm:"type:varchar(20);not null" json:"event_type"`
+	EffectiveDate string    `gorm:"size:10;not null;index:idx_employment_member_date,priority:2" json:"effective_date"` // YYYY-MM-DD
+	Role          string    `gorm:"size:100" json:"role"`
+	HourlyRate    float64   `gorm:"type:decimal(15,2);default:0" json:"hourly_rate"`
+	TripRate      float64   `gorm:"type:decimal(15,2);default:0" json:"trip_rate"`
+	ContractID    *uint     `gorm:"index" json:"contract_id"`
+	Notes         string    `gorm:"type:text" json:"notes"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (MemberEmploymentEvent) TableName() string {
+	return "member_employment_events"
+}
+
+// EmploymentTerms jabatan, tarif, kontrak dan status aktif anggota pada satu tanggal.
+type EmploymentTerms struct {
+	MemberID     string  `json:"member_id"`
+	Date         string  `json:"date"`
+	Role         string  `json:"role"`
+	HourlyRate   float64 `json:"hourly_rate"`
+	TripRate     float64 `json:"trip_rate"`
+	Active       bool    `json:"active"`
+	ContractID   *uint   `json:"contract_id"`
+	ContractType string  `json:"contract_type,omitempty"`
+}
+
+// EmploymentHistory kontrak dan riwayat kepegawaian satu anggota, plus syarat kerja yang berlaku hari ini.
+type EmploymentHistory struct {
+	Contracts []MemberContract        `json:"contracts"`
+	Events    []MemberEmploymentEvent `json:"events"`
+	Current   EmploymentTerms         `json:"current"`
+}
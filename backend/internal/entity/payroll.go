@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Status payroll run.
+const (
+	PayrollDraft     = "draft"
+	PayrollFinalized = "finalized"
+)
+
+// Cara pencatatan pengeluaran gaji saat finalisasi.
+const (
+	PayrollExpenseSingle    = "single"     // satu Finance expense untuk total gaji bersih
+	PayrollExpensePerMember = "per_member" // satu Finance expense per anggota
+)
+
+// PayrollRun penggajian satu bulan untuk semua anggota aktif. Draft bisa direview dan dihitung ulang;
+// setelah difinalisasi gaji terkunci, berstatus Paid, dan pengeluarannya tercatat di Finance.
+type PayrollRun struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	UserID         uint           `gorm:"not null;uniqueIndex:idx_payroll_runs_user_month;default:1" json:"user_id"`
+	Month          string         `gorm:"size:7;not null;uniqueIndex:idx_payroll_runs_user_month" json:"month"` // YYYY-MM
+	Status         string         `gorm:"type:varchar(20);default:'draft'" json:"status"`
+	ExpenseMode    string         `gorm:"type:varchar(20)" json:"expense_mode"`
+	PaymentDate    *string        `gorm:"size:10" json:"payment_date"`
+	MemberCount    int            `json:"member_count"`
+	TotalGross     float64        `gorm:"type:decimal(15,2);default:0" json:"total_gross"`
+	TotalLoan      float64        `gorm:"type:decimal(15,2);default:0" json:"total_loan"`
+	TotalStatutory float64        `gorm:"type:decimal(15,2);default:0" json:"total_statutory"` // PPh 21 + BPJS karyawan
+	TotalNet       float64        `gorm:"type:decimal(15,2);default:0" json:"total_net"`
+	FinanceIDs     datatypes.JSON `gorm:"type:json" json:"finance_ids"` // Finance expense yang dibuat saat finalisasi
+	Notes          string         `gorm:"type:text" json:"notes"`
+	FinalizedAt    *time.Time     `json:"finalized_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+
+	Items []PayrollItem `gorm:"-" json:"items,omitempty"`
+	// Warnings tarif kontrak yang belum bisa diisi saat sinkron; gajinya tetap dihitung dengan harga yang ada.
+	Warnings []string `gorm:"-" json:"warnings,omitempty"`
+}
+
+func (PayrollRun) TableName() string {
+	return "payroll_runs"
+}
+
+// PayrollItem baris review payroll: gaji satu anggota beserta jam kerja dari SalaryDetail.
+type PayrollItem struct {
+	SalaryID    uint    `json:"salary_id"`
+	MemberID    string  `json:"member_id"`
+	MemberName  string  `json:"member_name"`
+	Role        string  `json:"role"`
+	Hours       float64 `json:"hours"`
+	DetailCount int     `json:"detail_count"`
+	KasbonCount int     `json:"kasbon_count"`
+	GrossSalary float64 `json:"gross_salary"`
+	Loan        float64 `json:"loan"`
+	Installment float64 `json:"installment"` // bagian Loan dari cicilan kasbon ledger
+	Statutory   float64 `json:"statutory"`   // PPh 21 + BPJS bagian karyawan
+	NetSalary   float64 `json:"net_salary"`
+	Status      string  `json:"status"`
+	Locked      bool    `json:"locked"`
+}
@@ -4,10 +4,12 @@ import (
 	"gorm.io/datatypes"
 )
 
+// ReportDaily satu baris laporan harian proyek (satu tanggal per proyek). Dulu disimpan di Project.Reports.daily;
+// sekarang tabel sendiri agar bisa di-query dan diedit per baris tanpa menimpa seluruh dokumen.
 type ReportDaily struct {
 	ID           uint    `gorm:"primaryKey" json:"id"`
-	ProjectID    uint    `gorm:"index" json:"projectId"`
-	Date         string  `json:"date"`
+	ProjectID    uint    `gorm:"index;uniqueIndex:uq_daily_reports_project_date" json:"projectId"`
+	Date         string  `gorm:"size:10;uniqueIndex:uq_daily_reports_project_date" json:"date"` // YYYY-MM-DD
 	Revenue      float64 `json:"revenue"`
 	Paid         float64 `json:"paid"`
 	Volume       float64 `json:"volume"`
@@ -22,36 +24,83 @@ type ReportDaily struct {
 	Ritase  float64 `json:"ritase"`
 	Cuaca   string  `gorm:"size:255" json:"cuaca"`
 	Catatan string  `gorm:"type:text" json:"catatan"`
+	// Version naik setiap baris berubah; dipakai untuk If-Match / merge per tanggal.
+	Version uint `gorm:"not null;default:1" json:"version"`
 	// Images will be stored in separate table
 	Images    []DailyReportImage `gorm:"foreignKey:ReportDailyID" json:"images"`
 	CreatedAt int64              `json:"createdAt"`
 	UpdatedAt int64              `json:"updatedAt"`
 }
 
+func (ReportDaily) TableName() string {
+	return "daily_reports"
+}
+
 // New table for storing daily report images
 type DailyReportImage struct {
 	ID            uint   `gorm:"primaryKey" json:"id"`
 	ReportDailyID uint   `gorm:"index" json:"reportDailyId"`
-	ImagePath     string `json:"imagePath"`
-	Description   string `json:"description"`
+	ImagePath     string `gorm:"size:512" json:"imagePath"`
+	Description   string `gorm:"type:text" json:"description"`
 	CreatedAt     int64  `json:"createdAt"`
 	UpdatedAt     int64  `json:"updatedAt"`
 }
 
+func (DailyReportImage) TableName() string {
+	return "daily_report_images"
+}
+
+// ReportWeekly rekap satu minggu ISO (Senin–Minggu) yang dihitung dari baris daily_reports.
 type ReportWeekly struct {
-	Week         string  `json:"week"`
+	Week         string  `json:"week"`  // YYYY-Www (ISO 8601)
+	Label        string  `json:"label"` // tanggal pertama s/d terakhir yang ada datanya
+	StartDate    string  `json:"startDate"`
+	EndDate      string  `json:"endDate"`
 	TargetPlan   float64 `json:"targetPlan"`
 	TargetAktual float64 `json:"targetAktual"`
 	Volume       float64 `json:"volume"`
 	TargetVolume float64 `json:"targetVolume"`
+	ReportRollup
 }
 
+// ReportMonthly rekap satu bulan kalender yang dihitung dari baris daily_reports.
 type ReportMonthly struct {
-	Month        string  `json:"month"`
+	Month        string  `json:"month"` // YYYY-MM
+	StartDate    string  `json:"startDate"`
+	EndDate      string  `json:"endDate"`
 	TargetPlan   float64 `json:"targetPlan"`
 	TargetAktual float64 `json:"targetAktual"`
 	Volume       float64 `json:"volume"`
 	TargetVolume float64 `json:"targetVolume"`
+	ReportRollup
+}
+
+// ReportRollup field agregat yang sama untuk rekap mingguan dan bulanan (bentuk sama dengan form laporan frontend).
+type ReportRollup struct {
+	Days               int                `json:"days"`
+	TotalWorkers       int                `json:"totalWorkers"`
+	AvgWorkers         float64            `json:"avgWorkers"`
+	TotalEquipment     int                `json:"totalEquipment"`
+	AvgEquipment       float64            `json:"avgEquipment"`
+	Workers            map[string]float64 `json:"workers"`
+	AvgWorkersByType   map[string]float64 `json:"avgWorkersByType"`
+	Equipment          map[string]float64 `json:"equipment"`
+	AvgEquipmentByType map[string]float64 `json:"avgEquipmentByType"`
+	CumulativePlan     float64            `json:"cumulativePlan"`
+	CumulativeAktual   float64            `json:"cumulativeAktual"`
+}
+
+// SCurvePoint satu titik kurva S: rencana vs aktual kumulatif sampai Date (akhir periode).
+type SCurvePoint struct {
+	Period           string  `json:"period"` // tanggal, YYYY-Www atau YYYY-MM sesuai interval
+	Date             string  `json:"date"`
+	Plan             float64 `json:"plan"`
+	Aktual           float64 `json:"aktual"`
+	CumulativePlan   float64 `json:"cumulativePlan"`
+	CumulativeAktual float64 `json:"cumulativeAktual"`
+	PlanPercent      float64 `json:"planPercent"`
+	AktualPercent    float64 `json:"aktualPercent"`
+	Deviation        float64 `json:"deviation"` // AktualPercent - PlanPercent
 }
 
 type Project struct {
@@ -68,5 +117,11 @@ type Project struct {
 	UnitPrice    float64        `json:"unitPrice"`
 	TotalVolume  float64        `json:"totalVolume"`
 	Unit         string         `gorm:"size:50" json:"unit"`
-	Reports      datatypes.JSON `gorm:"type:jsonb" json:"reports"`
+	// CustomerID pemberi kerja; dipakai sebagai default customer invoice termin.
+	CustomerID *uint `gorm:"index" json:"customerId"`
+	// Version naik setiap proyek atau laporan hariannya berubah; dikirim sebagai ETag dan wajib di If-Match saat update.
+	Version uint `gorm:"not null;default:1" json:"version"`
+	// Reports berisi weekly/monthly dan metadata (_smartNota). Baris daily ada di tabel daily_reports;
+	// respons API tetap menggabungkannya ke reports.daily (lihat service.ProjectService).
+	Reports datatypes.JSON `gorm:"type:jsonb" json:"reports"`
 }
@@ -0,0 +1,41 @@
+package entity
+
+// EarnedValue analisis earned value management (EVM) satu proyek per tanggal AsOf.
+// Nilai uang dalam rupiah; PV/EV dari volume × UnitPrice proyek, AC dari ProjectExpense.
+type EarnedValue struct {
+	ProjectID   uint   `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	AsOf        string `json:"asOf"`
+	PlanSource  string `json:"planSource"` // "daily" (plan harian) atau "linear" (StartDate–EndDate)
+
+	BAC float64 `json:"bac"` // budget at completion: TotalVolume × UnitPrice (atau TotalRevenue)
+	PV  float64 `json:"pv"`  // planned value
+	EV  float64 `json:"ev"`  // earned value
+	AC  float64 `json:"ac"`  // actual cost
+
+	SV  float64 `json:"sv"`  // schedule variance EV - PV
+	CV  float64 `json:"cv"`  // cost variance EV - AC
+	SPI float64 `json:"spi"` // EV / PV
+	CPI float64 `json:"cpi"` // EV / AC
+	EAC float64 `json:"eac"` // estimate at completion AC + (BAC - EV) / CPI
+	ETC float64 `json:"etc"` // estimate to complete EAC - AC
+	VAC float64 `json:"vac"` // variance at completion BAC - EAC
+
+	PlannedPercent  float64 `json:"plannedPercent"`
+	ProgressPercent float64 `json:"progressPercent"` // volume aktual / TotalVolume
+	ActualVolume    float64 `json:"actualVolume"`
+
+	PlannedCompletionDate   string `json:"plannedCompletionDate"`
+	ProjectedCompletionDate string `json:"projectedCompletionDate"` // kosong jika belum bisa diproyeksikan
+	DelayDays               int    `json:"delayDays"`               // positif = terlambat dari rencana
+
+	Curve []EarnedValuePoint `json:"curve,omitempty"`
+}
+
+// EarnedValuePoint nilai kumulatif PV/EV/AC sampai Date.
+type EarnedValuePoint struct {
+	Date string  `json:"date"`
+	PV   float64 `json:"pv"`
+	EV   float64 `json:"ev"`
+	AC   float64 `json:"ac"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// Jenis nilai termin.
+const (
+	MilestoneAmountPercent = "percent" // persen dari nilai kontrak
+	MilestoneAmountFixed   = "fixed"   // nominal tetap
+)
+
+// Status termin: pending → reached (progress tercapai, income jadi Pending) → invoiced (draft invoice dibuat).
+const (
+	MilestoneStatusPending  = "pending"
+	MilestoneStatusReached  = "reached"
+	MilestoneStatusInvoiced = "invoiced"
+)
+
+// ProjectMilestone satu termin pembayaran proyek (mis. DP 20%, progress 50%, serah terima 100%, retensi 5%).
+// Setiap termin punya satu ProjectIncome (status Planned) yang dibuat otomatis.
+type ProjectMilestone struct {
+	ID         uint    `json:"id" gorm:"primaryKey"`
+	ProjectID  uint    `json:"projectId" gorm:"not null;index"`
+	Sequence   int     `json:"sequence" gorm:"not null;default:0"`
+	Name       string  `json:"name" gorm:"type:varchar(200);not null"`
+	AmountType string  `json:"amountType" gorm:"type:varchar(20);not null;default:'percent'"`
+	Percent    float64 `json:"percent" gorm:"type:decimal(6,2);not null;default:0"`
+	Amount     float64 `json:"amount" gorm:"type:decimal(15,2);not null;default:0"` // nominal; dihitung dari Percent jika AmountType percent
+	// TriggerProgress progress volume (%) yang harus tercapai; 0 = langsung (mis. DP).
+	TriggerProgress float64 `json:"triggerProgress" gorm:"type:decimal(6,2);not null;default:0"`
+	// HoldDays jeda setelah progress tercapai sebelum termin bisa ditagih (mis. retensi 180 hari masa pemeliharaan).
+	HoldDays    int       `json:"holdDays" gorm:"not null;default:0"`
+	PlannedDate string    `json:"plannedDate" gorm:"type:date"` // tanggal rencana tagih (tanggal ProjectIncome)
+	Status      string    `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	ReachedDate *string   `json:"reachedDate,omitempty" gorm:"type:date"`
+	IncomeID    *int      `json:"incomeId,omitempty" gorm:"index"`
+	InvoiceID   *uint     `json:"invoiceId,omitempty" gorm:"index"`
+	Notes       string    `json:"notes" gorm:"type:text"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Dihitung saat dibaca (tidak disimpan).
+	CurrentProgress float64 `json:"currentProgress" gorm:"-"`
+	ClaimableDate   string  `json:"claimableDate,omitempty" gorm:"-"` // tanggal progress tercapai + HoldDays
+}
+
+func (ProjectMilestone) TableName() string {
+	return "project_milestones"
+}
+
+// MilestoneInvoiceRequest body konversi termin ke draft invoice. Kosong = default (customer proyek, template pertama,
+// nomor otomatis, tanggal hari ini).
+type MilestoneInvoiceRequest struct {
+	CustomerID    *uint   `json:"customerId"`
+	TemplateID    uint    `json:"templateId"`
+	InvoiceNumber string  `json:"invoiceNumber"`
+	InvoiceDate   string  `json:"invoiceDate"`
+	TaxPercent    float64 `json:"taxPercent"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// PromptTemplate satu versi prompt ekstraksi (Go text/template) per use case dan provider.
+// Versi bersifat immutable: perubahan isi = versi baru; hanya satu versi aktif per (user, use case, provider).
+type PromptTemplate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;default:1;uniqueIndex:uq_prompt_templates_version" json:"user_id"`
+	UseCase   string    `gorm:"type:varchar(50);not null;uniqueIndex:uq_prompt_templates_version" json:"use_case"`   // invoice | one_day | row_only | timesheet | timesheet_repair
+	Provider  string    `gorm:"type:varchar(20);not null;uniqueIndex:uq_prompt_templates_version" json:"provider"`   // gemini | deepseek | ollama | *
+	Version   int       `gorm:"not null;uniqueIndex:uq_prompt_templates_version" json:"version"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	Notes     string    `gorm:"type:text" json:"notes"`
+	IsActive  bool      `gorm:"not null;default:false;index" json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
+
+// ExtractionLog mencatat prompt (versi) dan model yang menghasilkan satu ekstraksi, untuk audit dan perbandingan.
+type ExtractionLog struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"not null;index;default:1" json:"user_id"`
+	UseCase          string    `gorm:"type:varchar(50);not null;index" json:"use_case"`
+	Provider         string    `gorm:"type:varchar(20);not null" json:"provider"`
+	Model            string    `gorm:"type:varchar(100)" json:"model"`
+	PromptTemplateID *uint     `gorm:"index" json:"prompt_template_id,omitempty"` // NULL = prompt bawaan (kode)
+	PromptVersion    int       `gorm:"not null;default:0" json:"prompt_version"`   // 0 = prompt bawaan
+	FileName         string    `gorm:"type:varchar(255)" json:"file_name"`
+	Output           string    `gorm:"type:longtext" json:"output"` // JSON hasil ekstraksi
+	Error            string    `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (ExtractionLog) TableName() string {
+	return "extraction_logs"
+}
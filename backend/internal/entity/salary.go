@@ -8,16 +8,26 @@ import (
 )
 
 type Salary struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	MemberID    string         `gorm:"type:varchar(255);index" json:"member_id"`
-	Month       string         `gorm:"size(7)" json:"month"` // Format: YYYY-MM
-	Salary      float64        `json:"salary"`
-	Loan        float64        `json:"loan"`
-	NetSalary   float64        `json:"net_salary"`
-	GrossSalary float64        `json:"gross_salary"`
-	Status      string         `gorm:"size(20)" json:"status"`
-	Documents   datatypes.JSON `gorm:"type:json" json:"documents"` // Menyimpan array nama file
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	Member      Member         `gorm:"foreignKey:MemberID" json:"-"`
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	MemberID    string  `gorm:"type:varchar(255);index;index:idx_salaries_member_period,priority:1" json:"member_id"`
+	Month       string  `gorm:"size(7)" json:"month"` // Format: YYYY-MM
+	PeriodYear  int     `gorm:"index:idx_salaries_member_period,priority:2;index:idx_salaries_period,priority:1" json:"period_year"`
+	PeriodMonth int     `gorm:"index:idx_salaries_member_period,priority:3;index:idx_salaries_period,priority:2" json:"period_month"`
+	Salary      float64 `json:"salary"`
+	Loan        float64 `json:"loan"`
+	// LoanAdjustment potongan manual di luar kasbon; Loan = kasbon + cicilan ledger + LoanAdjustment setiap dihitung ulang.
+	LoanAdjustment float64        `gorm:"default:0" json:"loan_adjustment"`
+	NetSalary      float64        `json:"net_salary"`
+	GrossSalary    float64        `json:"gross_salary"`
+	Statutory      float64        `gorm:"column:statutory_deduction;default:0" json:"statutory_deduction"` // PPh 21 + BPJS bagian karyawan
+	Status         string         `gorm:"size(20)" json:"status"`
+	Documents      datatypes.JSON `gorm:"type:json" json:"documents"`  // Menyimpan array nama file
+	PayrollRunID   *uint          `gorm:"index" json:"payroll_run_id"` // payroll run bulanan yang memuat gaji ini
+	Locked         bool           `gorm:"default:false" json:"locked"` // true setelah payroll run difinalisasi: tidak bisa diubah
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Member         Member         `gorm:"foreignKey:MemberID" json:"-"`
+
+	// RateWarnings baris bersumber kontrak yang tarifnya belum ada saat dihitung ulang (harganya dibiarkan).
+	RateWarnings []string `gorm:"-" json:"rate_warnings,omitempty"`
 }
@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+// Sumber harga_per_jam detail gaji.
+const (
+	RateSourceManual   = "manual"   // diisi pengguna atau tarif absensi
+	RateSourceContract = "contract" // tarif kontrak/riwayat pada Tanggal; diselesaikan ulang setiap gaji dihitung ulang
+)
+
 type SalaryDetail struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	SalaryID    uint      `json:"salary_id"`
@@ -12,6 +18,8 @@ type SalaryDetail struct {
 	JamTrip     float32       `json:"jam_trip"`      // Pastikan tag json benar
 	HargaPerJam float64   `json:"harga_per_jam"` // Pastikan tag json benar
 	Keterangan  string    `json:"keterangan"`
+	AbsensiID   *uint     `gorm:"index" json:"absensi_id"` // diisi untuk baris yang dibuat dari absensi yang disetujui
+	RateSource  string    `gorm:"size:20;default:'manual'" json:"rate_source"`
 }
 
 type Kasbon struct {
@@ -0,0 +1,106 @@
+package entity
+
+import "time"
+
+// Kode potongan wajib per gaji.
+const (
+	DeductionPPh21   = "pph21"
+	DeductionJHT     = "jht"      // BPJS TK Jaminan Hari Tua
+	DeductionJP      = "jp"       // BPJS TK Jaminan Pensiun
+	DeductionJKK     = "jkk"      // BPJS TK Jaminan Kecelakaan Kerja (perusahaan)
+	DeductionJKM     = "jkm"      // BPJS TK Jaminan Kematian (perusahaan)
+	DeductionBPJSKes = "bpjs_kes" // BPJS Kesehatan
+)
+
+// Pihak yang menanggung potongan.
+const (
+	PartyEmployee = "employee" // mengurangi gaji bersih
+	PartyEmployer = "employer" // beban perusahaan, tidak mengurangi gaji
+)
+
+// PayrollTaxSetting konfigurasi PPh 21 & BPJS per user. Tarif dalam persen; 0 pada batas upah = tanpa batas.
+// Tanpa baris tersimpan dipakai DefaultPayrollTaxSetting (semua nonaktif).
+type PayrollTaxSetting struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	UserID          uint      `gorm:"not null;uniqueIndex;default:1" json:"user_id"`
+	PPh21Enabled    bool      `gorm:"column:pph21_enabled" json:"pph21_enabled"`
+	BPJSTKEnabled   bool      `gorm:"column:bpjs_tk_enabled" json:"bpjs_tk_enabled"`
+	BPJSKesEnabled  bool      `gorm:"column:bpjs_kes_enabled" json:"bpjs_kes_enabled"`
+	JHTEmployeeRate float64   `gorm:"type:decimal(6,3)" json:"jht_employee_rate"`
+	JHTEmployerRate float64   `gorm:"type:decimal(6,3)" json:"jht_employer_rate"`
+	JPEmployeeRate  float64   `gorm:"type:decimal(6,3)" json:"jp_employee_rate"`
+	JPEmployerRate  float64   `gorm:"type:decimal(6,3)" json:"jp_employer_rate"`
+	JPWageCap       float64   `gorm:"type:decimal(15,2)" json:"jp_wage_cap"`
+	JKKRate         float64   `gorm:"type:decimal(6,3)" json:"jkk_rate"` // sesuai kelompok risiko (0,24–1,74)
+	JKMRate         float64   `gorm:"type:decimal(6,3)" json:"jkm_rate"`
+	KesEmployeeRate float64   `gorm:"type:decimal(6,3)" json:"kes_employee_rate"`
+	KesEmployerRate float64   `gorm:"type:decimal(6,3)" json:"kes_employer_rate"`
+	KesWageCap      float64   `gorm:"type:decimal(15,2)" json:"kes_wage_cap"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (PayrollTaxSetting) TableName() string {
+	return "payroll_tax_settings"
+}
+
+// DefaultPayrollTaxSetting tarif umum BPJS; admin mengaktifkan dan menyesuaikan batas upah tahunan.
+func DefaultPayrollTaxSetting(userID uint) PayrollTaxSetting {
+	return PayrollTaxSetting{
+		UserID:          userID,
+		JHTEmployeeRate: 2,
+		JHTEmployerRate: 3.7,
+		JPEmployeeRate:  1,
+		JPEmployerRate:  2,
+		JPWageCap:       10547400,
+		JKKRate:         0.24,
+		JKMRate:         0.3,
+		KesEmployeeRate: 1,
+		KesEmployerRate: 4,
+		KesWageCap:      12000000,
+	}
+}
+
+// SalaryDeduction satu baris potongan wajib hasil perhitungan gaji.
+type SalaryDeduction struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index;default:1" json:"user_id"`
+	SalaryID  uint      `gorm:"not null;index" json:"salary_id"`
+	MemberID  string    `gorm:"type:varchar(255);not null;index" json:"member_id"`
+	Month     string    `gorm:"size:7;index" json:"month"` // YYYY-MM
+	Code      string    `gorm:"type:varchar(20)" json:"code"`
+	Name      string    `gorm:"size:100" json:"name"`
+	Party     string    `gorm:"type:varchar(10)" json:"party"`
+	Base      float64   `gorm:"type:decimal(15,2)" json:"base"` // dasar perhitungan (bruto untuk PPh 21)
+	Rate      float64   `gorm:"type:decimal(6,3)" json:"rate"`  // persen
+	Amount    float64   `gorm:"type:decimal(15,2)" json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (SalaryDeduction) TableName() string {
+	return "salary_deductions"
+}
+
+// StatutoryRecapRow rekap satu anggota dalam satu bulan untuk pelaporan SPT Masa PPh 21 & iuran BPJS.
+type StatutoryRecapRow struct {
+	SalaryID    uint               `json:"salary_id"`
+	MemberID    string             `json:"member_id"`
+	MemberName  string             `json:"member_name"`
+	NPWP        string             `json:"npwp"`
+	PTKPStatus  string             `json:"ptkp_status"`
+	Gross       float64            `json:"gross"`        // gaji kotor
+	TaxableBase float64            `json:"taxable_base"` // bruto PPh 21 (gaji + premi ditanggung perusahaan)
+	Employee    map[string]float64 `json:"employee"`     // per kode potongan
+	Employer    map[string]float64 `json:"employer"`
+}
+
+// StatutoryRecap rekap bulanan potongan wajib.
+type StatutoryRecap struct {
+	Month         string              `json:"month"`
+	Rows          []StatutoryRecapRow `json:"rows"`
+	TotalGross    float64             `json:"total_gross"`
+	TotalEmployee map[string]float64  `json:"total_employee"`
+	TotalEmployer map[string]float64  `json:"total_employer"`
+	// TotalRemit iuran BPJS (karyawan + perusahaan) dan PPh 21 yang harus disetor.
+	TotalRemit float64 `json:"total_remit"`
+}
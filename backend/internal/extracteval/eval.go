@@ -0,0 +1,176 @@
+// Package extracteval membaca dataset berlabel (gambar + JSON expected) dan menilai hasil ekstraksi per field.
+package extracteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Case satu gambar berlabel: <nama>.jpg|.jpeg|.png + <nama>.json berisi field yang diharapkan.
+type Case struct {
+	Name      string
+	ImagePath string
+	MimeType  string
+	Expected  map[string]interface{}
+}
+
+var imageMime = map[string]string{".jpg": "image/jpeg", ".jpeg": "image/jpeg", ".png": "image/png"}
+
+// LoadDataset membaca semua pasangan gambar + JSON di dir (tidak rekursif). Gambar tanpa JSON dilewati.
+func LoadDataset(dir string) ([]Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var cases []Case
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		mime, ok := imageMime[ext]
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		raw, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var expected map[string]interface{}
+		if err := json.Unmarshal(raw, &expected); err != nil {
+			return nil, fmt.Errorf("%s.json: %w", name, err)
+		}
+		cases = append(cases, Case{Name: name, ImagePath: filepath.Join(dir, e.Name()), MimeType: mime, Expected: Flatten(expected)})
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// ToMap mengubah struct hasil ekstraksi menjadi map datar (lewat JSON) untuk dibandingkan.
+func ToMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return Flatten(m), nil
+}
+
+// Flatten meratakan objek/array bersarang: {"items":[{"days":1}]} → {"items[0].days":1}.
+func Flatten(m map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, vv := range t {
+				key := k
+				if prefix != "" {
+					key = prefix + "." + k
+				}
+				walk(key, vv)
+			}
+		case []interface{}:
+			for i, vv := range t {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), vv)
+			}
+		default:
+			out[prefix] = v
+		}
+	}
+	walk("", m)
+	return out
+}
+
+// Match membandingkan satu nilai: angka dengan toleransi 0.01, string case-insensitive dan spasi dinormalisasi.
+func Match(expected, actual interface{}) bool {
+	if ef, ok := expected.(float64); ok {
+		af, ok := actual.(float64)
+		return ok && math.Abs(ef-af) <= 0.01
+	}
+	if expected == nil {
+		return actual == nil || actual == ""
+	}
+	return NormalizeString(fmt.Sprint(expected)) == NormalizeString(fmt.Sprint(actual))
+}
+
+// NormalizeString huruf kecil + spasi ganda jadi satu.
+func NormalizeString(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// FieldScore akurasi satu field di seluruh dataset.
+type FieldScore struct {
+	Field   string  `json:"field"`
+	Correct int     `json:"correct"`
+	Total   int     `json:"total"`
+	Acc     float64 `json:"accuracy"`
+}
+
+// Scorer mengakumulasi penilaian seluruh dataset: akurasi per field (hanya field yang ada di expected)
+// untuk prompt-eval, dan FieldMetric (precision/recall, MAE, galat tanggal) untuk laporan extract-eval.
+type Scorer struct {
+	correct map[string]int
+	total   map[string]int
+	fields  map[string]*FieldMetric
+	Cases   int
+	Errors  int // case yang gagal diekstrak: semua field dihitung salah/FN
+}
+
+func NewScorer() *Scorer {
+	return &Scorer{correct: map[string]int{}, total: map[string]int{}, fields: map[string]*FieldMetric{}}
+}
+
+// Add menilai satu case (expected dan actual sudah di-Flatten bila berisi array). actual nil = ekstraksi gagal.
+func (s *Scorer) Add(expected, actual map[string]interface{}) {
+	s.Cases++
+	if actual == nil {
+		s.Errors++
+	}
+	for field, ev := range expected {
+		s.total[field]++
+		if actual != nil && Match(ev, actual[field]) {
+			s.correct[field]++
+		}
+	}
+	s.addMetrics(expected, actual)
+}
+
+// Scores akurasi per field diurutkan berdasarkan nama field.
+func (s *Scorer) Scores() []FieldScore {
+	out := make([]FieldScore, 0, len(s.total))
+	for f, t := range s.total {
+		fs := FieldScore{Field: f, Correct: s.correct[f], Total: t}
+		if t > 0 {
+			fs.Acc = float64(fs.Correct) / float64(t)
+		}
+		out = append(out, fs)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+// Overall akurasi gabungan semua field.
+func (s *Scorer) Overall() float64 {
+	var c, t int
+	for f, n := range s.total {
+		t += n
+		c += s.correct[f]
+	}
+	if t == 0 {
+		return 0
+	}
+	return float64(c) / float64(t)
+}
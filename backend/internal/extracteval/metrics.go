@@ -0,0 +1,152 @@
+package extracteval
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var indexRe = regexp.MustCompile(`\[\d+\]`)
+
+// FieldKey menyatukan field array lintas indeks: "items[3].days" → "items[].days".
+func FieldKey(path string) string {
+	return indexRe.ReplaceAllString(path, "[]")
+}
+
+// FieldMetric precision/recall dan galat numerik/tanggal satu field (teragregasi lintas case dan indeks array).
+//
+//	TP: nilai ada di expected dan hasil sama.
+//	FP: hasil berisi nilai tapi salah, atau field tidak ada di expected.
+//	FN: expected berisi nilai tapi hasil kosong atau salah.
+type FieldMetric struct {
+	Field     string  `json:"field"`
+	TP        int     `json:"tp"`
+	FP        int     `json:"fp"`
+	FN        int     `json:"fn"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+
+	// MAE rata-rata |expected-actual| untuk field angka (jam/hari/harga); DateErrorDays untuk field tanggal YYYY-MM-DD.
+	NumericN      int     `json:"numeric_n,omitempty"`
+	MAE           float64 `json:"mae,omitempty"`
+	DateN         int     `json:"date_n,omitempty"`
+	DateErrorDays float64 `json:"date_error_days,omitempty"`
+
+	absSum  float64
+	daysSum float64
+}
+
+func (s *Scorer) field(path string) *FieldMetric {
+	k := FieldKey(path)
+	m, ok := s.fields[k]
+	if !ok {
+		m = &FieldMetric{Field: k}
+		s.fields[k] = m
+	}
+	return m
+}
+
+// addMetrics mengakumulasi TP/FP/FN dan galat numerik/tanggal satu case ke FieldMetric.
+func (s *Scorer) addMetrics(expected, actual map[string]interface{}) {
+	for path, ev := range expected {
+		m := s.field(path)
+		if isEmpty(ev) {
+			if av, ok := actual[path]; ok && !isEmpty(av) && !isZero(av) {
+				m.FP++
+			}
+			continue
+		}
+		av, ok := actual[path]
+		if !ok || isEmpty(av) {
+			m.FN++
+			continue
+		}
+		if Match(ev, av) {
+			m.TP++
+		} else {
+			m.FP++
+			m.FN++
+		}
+		if ef, ok := ev.(float64); ok {
+			if af, ok := av.(float64); ok {
+				m.NumericN++
+				m.absSum += math.Abs(ef - af)
+			}
+		}
+		if ed, ok := parseDate(ev); ok {
+			if ad, ok := parseDate(av); ok {
+				m.DateN++
+				m.daysSum += math.Abs(ed.Sub(ad).Hours() / 24)
+			}
+		}
+	}
+	// Field yang dikarang model (tidak ada di label) = FP, kecuali nilai kosong/nol bawaan struct.
+	for path, av := range actual {
+		if _, ok := expected[path]; ok || isEmpty(av) || isZero(av) {
+			continue
+		}
+		s.field(path).FP++
+	}
+}
+
+// Metrics hasil akhir per field, diurutkan berdasarkan nama.
+func (s *Scorer) Metrics() []FieldMetric {
+	out := make([]FieldMetric, 0, len(s.fields))
+	for _, m := range s.fields {
+		fm := *m
+		fm.Precision = ratio(fm.TP, fm.TP+fm.FP)
+		fm.Recall = ratio(fm.TP, fm.TP+fm.FN)
+		if fm.Precision+fm.Recall > 0 {
+			fm.F1 = 2 * fm.Precision * fm.Recall / (fm.Precision + fm.Recall)
+		}
+		if fm.NumericN > 0 {
+			fm.MAE = fm.absSum / float64(fm.NumericN)
+		}
+		if fm.DateN > 0 {
+			fm.DateErrorDays = fm.daysSum / float64(fm.DateN)
+		}
+		out = append(out, fm)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+// Totals micro-average precision/recall seluruh field.
+func (s *Scorer) Totals() (precision, recall float64) {
+	var tp, fp, fn int
+	for _, m := range s.fields {
+		tp, fp, fn = tp+m.TP, fp+m.FP, fn+m.FN
+	}
+	return ratio(tp, tp+fp), ratio(tp, tp+fn)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && NormalizeString(s) == ""
+}
+
+func isZero(v interface{}) bool {
+	f, ok := v.(float64)
+	return ok && f == 0
+}
+
+func parseDate(v interface{}) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok || len(s) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", s[:10])
+	return t, err == nil
+}
@@ -0,0 +1,72 @@
+package extracteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Mode cara harness mendapatkan hasil ekstraksi.
+const (
+	ModeLive   = "live"   // panggil provider
+	ModeRecord = "record" // panggil provider lalu simpan respons ke folder rekaman
+	ModeReplay = "replay" // baca respons rekaman tanpa jaringan (stub)
+)
+
+// Recording respons provider yang disimpan untuk replay.
+type Recording struct {
+	Case     string          `json:"case"`
+	Provider string          `json:"provider"`
+	Model    string          `json:"model"`
+	UseCase  string          `json:"use_case"`
+	Output   json.RawMessage `json:"output,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+var unsafePathRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// RecordingPath <dir>/<provider>/<model>/<use_case>/<case>.json (karakter aneh di nama model diganti "_").
+func RecordingPath(dir, provider, model, useCase, name string) string {
+	clean := func(s string) string {
+		if s == "" {
+			return "_"
+		}
+		return unsafePathRe.ReplaceAllString(s, "_")
+	}
+	return filepath.Join(dir, clean(provider), clean(model), clean(useCase), clean(name)+".json")
+}
+
+// SaveRecording menulis hasil (atau error) satu case.
+func SaveRecording(path string, rec Recording) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// LoadRecording membaca rekaman dan mengembalikan output yang sudah di-Flatten.
+// Rekaman berisi error dikembalikan sebagai error agar dihitung sama seperti kegagalan live.
+func LoadRecording(path string) (map[string]interface{}, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("rekaman tidak ditemukan: %w", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if rec.Error != "" {
+		return nil, fmt.Errorf("%s", rec.Error)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(rec.Output, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return Flatten(m), nil
+}
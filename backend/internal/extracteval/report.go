@@ -0,0 +1,155 @@
+package extracteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// CaseResult ringkasan per gambar di laporan.
+type CaseResult struct {
+	Name    string   `json:"name"`
+	Error   string   `json:"error,omitempty"`
+	Wrong   []string `json:"wrong,omitempty"` // field yang salah/kosong
+	Correct int      `json:"correct"`
+	Total   int      `json:"total"`
+}
+
+// Report laporan satu run evaluasi; disimpan JSON agar bisa jadi baseline perbandingan run berikutnya.
+type Report struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Dataset     string        `json:"dataset"`
+	UseCase     string        `json:"use_case"`
+	Provider    string        `json:"provider"`
+	Model       string        `json:"model"`
+	Mode        string        `json:"mode"`
+	Cases       int           `json:"cases"`
+	Errors      int           `json:"errors"`
+	Precision   float64       `json:"precision"`
+	Recall      float64       `json:"recall"`
+	Fields      []FieldMetric `json:"fields"`
+	Results     []CaseResult  `json:"results"`
+}
+
+// Fill mengisi metrik dari Scorer.
+func (r *Report) Fill(s *Scorer) {
+	r.Cases, r.Errors = s.Cases, s.Errors
+	r.Precision, r.Recall = s.Totals()
+	r.Fields = s.Metrics()
+}
+
+// NewCaseResult mencatat field yang salah untuk satu case.
+func NewCaseResult(name string, expected, actual map[string]interface{}, err error) CaseResult {
+	cr := CaseResult{Name: name}
+	if err != nil {
+		cr.Error = err.Error()
+	}
+	for path, ev := range expected {
+		if isEmpty(ev) {
+			continue
+		}
+		cr.Total++
+		if actual != nil && Match(ev, actual[path]) {
+			cr.Correct++
+		} else {
+			cr.Wrong = append(cr.Wrong, fmt.Sprintf("%s: expected %v, got %v", path, ev, actual[path]))
+		}
+	}
+	sort.Strings(cr.Wrong)
+	return cr
+}
+
+// LoadReport membaca laporan JSON (baseline).
+func LoadReport(path string) (*Report, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// SaveJSON menulis laporan ke file JSON.
+func (r *Report) SaveJSON(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// WriteMarkdown menulis laporan yang bisa dibaca manusia. baseline opsional: jika ada, tiap metrik diberi kolom delta.
+func (r *Report) WriteMarkdown(w io.Writer, baseline *Report) {
+	fmt.Fprintf(w, "# Evaluasi ekstraksi %s\n\n", r.UseCase)
+	fmt.Fprintf(w, "- Dataset: `%s` (%d gambar, %d gagal)\n", r.Dataset, r.Cases, r.Errors)
+	fmt.Fprintf(w, "- Provider: %s, model: %s, mode: %s\n", r.Provider, r.Model, r.Mode)
+	fmt.Fprintf(w, "- Precision %.1f%%, recall %.1f%%\n", r.Precision*100, r.Recall*100)
+	if baseline != nil {
+		fmt.Fprintf(w, "- Baseline: %s / %s (%s) — precision %+.1f%%, recall %+.1f%%\n",
+			baseline.Provider, baseline.Model, baseline.GeneratedAt.Format("2006-01-02 15:04"),
+			(r.Precision-baseline.Precision)*100, (r.Recall-baseline.Recall)*100)
+	}
+	fmt.Fprintln(w)
+
+	base := map[string]FieldMetric{}
+	if baseline != nil {
+		for _, f := range baseline.Fields {
+			base[f.Field] = f
+		}
+		fmt.Fprintln(w, "| Field | Precision | Recall | F1 | MAE | Galat tanggal (hari) | Δ F1 | Δ MAE | Δ galat tanggal |")
+		fmt.Fprintln(w, "|---|---|---|---|---|---|---|---|---|")
+	} else {
+		fmt.Fprintln(w, "| Field | Precision | Recall | F1 | MAE | Galat tanggal (hari) |")
+		fmt.Fprintln(w, "|---|---|---|---|---|---|")
+	}
+	for _, f := range r.Fields {
+		fmt.Fprintf(w, "| %s | %.1f%% | %.1f%% | %.1f%% | %s | %s |", f.Field, f.Precision*100, f.Recall*100, f.F1*100,
+			optFloat(f.MAE, f.NumericN), optFloat(f.DateErrorDays, f.DateN))
+		if baseline != nil {
+			b, ok := base[f.Field]
+			if !ok {
+				fmt.Fprint(w, " baru | | |")
+			} else {
+				fmt.Fprintf(w, " %+.1f%% | %s | %s |", (f.F1-b.F1)*100,
+					optDelta(f.MAE-b.MAE, f.NumericN, b.NumericN), optDelta(f.DateErrorDays-b.DateErrorDays, f.DateN, b.DateN))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "\n## Per gambar")
+	fmt.Fprintln(w)
+	for _, c := range r.Results {
+		switch {
+		case c.Error != "":
+			fmt.Fprintf(w, "- **%s**: gagal — %s\n", c.Name, c.Error)
+		case len(c.Wrong) == 0:
+			fmt.Fprintf(w, "- %s: %d/%d benar\n", c.Name, c.Correct, c.Total)
+		default:
+			fmt.Fprintf(w, "- %s: %d/%d benar\n", c.Name, c.Correct, c.Total)
+			for _, s := range c.Wrong {
+				fmt.Fprintf(w, "  - %s\n", s)
+			}
+		}
+	}
+}
+
+func optFloat(v float64, n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func optDelta(v float64, n, baseN int) string {
+	if n == 0 || baseN == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f", v)
+}
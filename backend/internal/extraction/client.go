@@ -0,0 +1,82 @@
+// Package extraction memilih provider vision (Gemini, DeepSeek/OpenAI-compatible, Ollama) sesuai config
+// dan menjalankan ekstraksi invoice/timesheet dengan prompt bawaan atau prompt override (template versi di database).
+package extraction
+
+import (
+	"strings"
+
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/deepseek"
+	"dashboardadminimb/internal/gemini"
+	"dashboardadminimb/internal/prompt"
+)
+
+// Client konfigurasi provider untuk satu rangkaian ekstraksi.
+type Client struct {
+	Provider string // prompt.ProviderGemini | ProviderDeepSeek | ProviderOllama
+	Model    string
+
+	geminiAPIKey    string
+	deepSeekBaseURL string
+	deepSeekAPIKey  string
+}
+
+// NewClient membuat Client dari config (EXTRACT_PROVIDER, GEMINI_*, DEEPSEEK_*).
+func NewClient(cfg config.Config) *Client {
+	c := &Client{
+		Provider:        prompt.ProviderFor(cfg.ExtractProvider, cfg.DeepSeekBaseURL),
+		geminiAPIKey:    cfg.GeminiAPIKey,
+		deepSeekBaseURL: cfg.DeepSeekBaseURL,
+		deepSeekAPIKey:  cfg.DeepSeekAPIKey,
+	}
+	if c.Provider == prompt.ProviderGemini {
+		c.Model = cfg.GeminiModel
+	} else {
+		c.Model = cfg.DeepSeekModel
+	}
+	return c
+}
+
+func (c *Client) isGemini() bool { return c.Provider == prompt.ProviderGemini }
+
+// Invoice satu gambar = banyak item. promptText kosong = prompt bawaan.
+func (c *Client) Invoice(imageData []byte, mimeType, promptText string) (*gemini.ExtractInvoiceResponse, error) {
+	if c.isGemini() {
+		if strings.TrimSpace(promptText) == "" {
+			return gemini.ExtractInvoiceFromImage(c.geminiAPIKey, c.Model, imageData, mimeType)
+		}
+		return gemini.ExtractInvoiceFromImageWithPrompt(c.geminiAPIKey, c.Model, imageData, mimeType, promptText)
+	}
+	if strings.TrimSpace(promptText) == "" {
+		return deepseek.ExtractInvoiceFromImage(c.deepSeekBaseURL, c.deepSeekAPIKey, c.Model, imageData, mimeType)
+	}
+	return deepseek.ExtractInvoiceFromImageWithPrompt(c.deepSeekBaseURL, c.deepSeekAPIKey, c.Model, imageData, mimeType, promptText)
+}
+
+// OneDay satu gambar = satu hari (satu baris item). promptText kosong = prompt bawaan.
+func (c *Client) OneDay(imageData []byte, mimeType, quantityUnit string, useBBM bool, promptText string) (*gemini.ExtractOneDayResponse, error) {
+	if c.isGemini() {
+		if strings.TrimSpace(promptText) == "" {
+			return gemini.ExtractOneDayFromImage(c.geminiAPIKey, c.Model, imageData, mimeType, quantityUnit, useBBM)
+		}
+		return gemini.ExtractOneDayFromImageWithPrompt(c.geminiAPIKey, c.Model, imageData, mimeType, promptText)
+	}
+	if strings.TrimSpace(promptText) == "" {
+		return deepseek.ExtractOneDayFromImage(c.deepSeekBaseURL, c.deepSeekAPIKey, c.Model, imageData, mimeType, quantityUnit, useBBM)
+	}
+	return deepseek.ExtractOneDayFromImageWithPrompt(c.deepSeekBaseURL, c.deepSeekAPIKey, c.Model, imageData, mimeType, promptText)
+}
+
+// Row tanggal + quantity + unit untuk satu baris. promptText kosong = prompt bawaan.
+func (c *Client) Row(imageData []byte, mimeType string, columnDescriptions []string, promptText string) (*gemini.ExtractRowResponse, error) {
+	if c.isGemini() {
+		if strings.TrimSpace(promptText) == "" {
+			return gemini.ExtractDateAndDaysFromImage(c.geminiAPIKey, c.Model, imageData, mimeType, columnDescriptions)
+		}
+		return gemini.ExtractDateAndDaysFromImageWithPrompt(c.geminiAPIKey, c.Model, imageData, mimeType, promptText)
+	}
+	if strings.TrimSpace(promptText) == "" {
+		return deepseek.ExtractDateAndDaysFromImage(c.deepSeekBaseURL, c.deepSeekAPIKey, c.Model, imageData, mimeType, columnDescriptions)
+	}
+	return deepseek.ExtractDateAndDaysFromImageWithPrompt(c.deepSeekBaseURL, c.deepSeekAPIKey, c.Model, imageData, mimeType, promptText)
+}
@@ -360,7 +360,12 @@ func NormalizeRowDate(s string) string {
 
 // ExtractDateAndDaysFromImage ekstrak tanggal + quantity dan unit (hari|jam). Pemanggil konversi jam↔hari.
 func ExtractDateAndDaysFromImage(apiKey, model string, imageData []byte, mimeType string, columnDescriptions []string) (*ExtractRowResponse, error) {
-	text, err := callGemini(apiKey, model, imageData, mimeType, buildRowOnlyPrompt(columnDescriptions))
+	return ExtractDateAndDaysFromImageWithPrompt(apiKey, model, imageData, mimeType, buildRowOnlyPrompt(columnDescriptions))
+}
+
+// ExtractDateAndDaysFromImageWithPrompt sama seperti ExtractDateAndDaysFromImage dengan prompt dari luar (template versi di database).
+func ExtractDateAndDaysFromImageWithPrompt(apiKey, model string, imageData []byte, mimeType string, prompt string) (*ExtractRowResponse, error) {
+	text, err := callGemini(apiKey, model, imageData, mimeType, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -446,7 +451,12 @@ func callGemini(apiKey, model string, imageData []byte, mimeType string, prompt
 
 // ExtractInvoiceFromImage mengirim gambar ke Gemini dan mengembalikan data terstruktur untuk pre-fill invoice.
 func ExtractInvoiceFromImage(apiKey, model string, imageData []byte, mimeType string) (*ExtractInvoiceResponse, error) {
-	text, err := callGemini(apiKey, model, imageData, mimeType, extractPrompt)
+	return ExtractInvoiceFromImageWithPrompt(apiKey, model, imageData, mimeType, extractPrompt)
+}
+
+// ExtractInvoiceFromImageWithPrompt sama seperti ExtractInvoiceFromImage dengan prompt dari luar.
+func ExtractInvoiceFromImageWithPrompt(apiKey, model string, imageData []byte, mimeType string, prompt string) (*ExtractInvoiceResponse, error) {
+	text, err := callGemini(apiKey, model, imageData, mimeType, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -466,7 +476,11 @@ func ExtractOneDayFromImage(apiKey, model string, imageData []byte, mimeType str
 	if quantityUnit == "" {
 		quantityUnit = "hari"
 	}
-	prompt := buildOneDayPrompt(quantityUnit, useBBM)
+	return ExtractOneDayFromImageWithPrompt(apiKey, model, imageData, mimeType, buildOneDayPrompt(quantityUnit, useBBM))
+}
+
+// ExtractOneDayFromImageWithPrompt sama seperti ExtractOneDayFromImage dengan prompt dari luar.
+func ExtractOneDayFromImageWithPrompt(apiKey, model string, imageData []byte, mimeType string, prompt string) (*ExtractOneDayResponse, error) {
 	text, err := callGemini(apiKey, model, imageData, mimeType, prompt)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdvanceHandler ledger kasbon anggota: pencairan, cicilan, saldo dan riwayat pelunasan.
+type AdvanceHandler struct {
+	service         service.AdvanceService
+	activityService service.ActivityService
+}
+
+func NewAdvanceHandler(service service.AdvanceService, activityService service.ActivityService) *AdvanceHandler {
+	return &AdvanceHandler{service, activityService}
+}
+
+func advanceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAdvanceInvalid), errors.Is(err, service.ErrRepaymentInvalid):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrAdvanceLegacy), errors.Is(err, service.ErrAdvanceRepaid):
+		return response.Error(c, http.StatusConflict, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
+// List GET /api/advances?member_id=
+func (h *AdvanceHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.List(userID, c.QueryParam("member_id"))
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// Balances GET /api/advances/balances — sisa kasbon per anggota.
+func (h *AdvanceHandler) Balances(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.Balances(userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// History GET /api/advances/members/:memberId/history
+func (h *AdvanceHandler) History(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.History(userID, c.Param("memberId"))
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+func (h *AdvanceHandler) Get(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	advance, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, advance)
+}
+
+func (h *AdvanceHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.MemberAdvance
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.Save(userID, &body); err != nil {
+		return advanceError(c, err)
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityExpense, "Pencairan Kasbon",
+		fmt.Sprintf("Kasbon Rp %.0f untuk anggota %s, %d kali cicilan", body.Amount, body.MemberID, body.Installments))
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *AdvanceHandler) Update(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	existing, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = existing.ID
+	if err := h.service.Save(userID, &body); err != nil {
+		return advanceError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *AdvanceHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.Get(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		return advanceError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// Repay POST /api/advances/:id/repayments {amount, tanggal, month, keterangan} — pembayaran di luar payroll.
+func (h *AdvanceHandler) Repay(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.Get(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	var body entity.AdvanceRepayment
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.Repay(userID, uint(id), &body); err != nil {
+		return advanceError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AttendanceHandler absensi harian anggota: input per lokasi, persetujuan dan matriks bulanan.
+type AttendanceHandler struct {
+	service         service.AttendanceService
+	activityService service.ActivityService
+}
+
+func NewAttendanceHandler(service service.AttendanceService, activityService service.ActivityService) *AttendanceHandler {
+	return &AttendanceHandler{service, activityService}
+}
+
+func attendanceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAttendanceInvalid), errors.Is(err, service.ErrAttendanceLink):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrAttendanceLocked):
+		return response.Error(c, http.StatusConflict, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
+// List GET /api/attendance?member_id=&project_id=&from=&to=&approved=true
+func (h *AttendanceHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	projectID, _ := strconv.Atoi(c.QueryParam("project_id"))
+	list, err := h.service.List(userID, repository.AttendanceFilter{
+		MemberID:     c.QueryParam("member_id"),
+		ProjectID:    uint(projectID),
+		From:         c.QueryParam("from"),
+		To:           c.QueryParam("to"),
+		ApprovedOnly: c.QueryParam("approved") == "true",
+	})
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// Matrix GET /api/attendance/matrix?month=YYYY-MM&project_id=
+func (h *AttendanceHandler) Matrix(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	projectID, _ := strconv.Atoi(c.QueryParam("project_id"))
+	matrix, err := h.service.Matrix(userID, c.QueryParam("month"), uint(projectID))
+	if err != nil {
+		if errors.Is(err, service.ErrPayrollMonth) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, matrix)
+}
+
+func (h *AttendanceHandler) Get(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	attendance, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, attendance)
+}
+
+func (h *AttendanceHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.Attendance
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.Save(userID, &body); err != nil {
+		return attendanceError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+// Bulk POST /api/attendance/bulk — absensi semua anggota di satu lokasi untuk satu tanggal.
+func (h *AttendanceHandler) Bulk(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body service.BulkAttendance
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	list, err := h.service.SaveBulk(userID, &body)
+	if err != nil {
+		return attendanceError(c, err)
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityMember, "Input Absensi",
+		fmt.Sprintf("Absensi %s %s: %d anggota", body.Date, body.Site, len(list)))
+	return response.Success(c, http.StatusCreated, list)
+}
+
+// Approve POST /api/attendance/approve {ids, approved} — absensi yang disetujui masuk ke detail gaji
+// saat gaji bulan itu dihitung ulang.
+func (h *AttendanceHandler) Approve(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body struct {
+		IDs      []uint `json:"ids"`
+		Approved *bool  `json:"approved"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	approved := body.Approved == nil || *body.Approved
+	count, err := h.service.SetApproval(userID, body.IDs, approved)
+	if err != nil {
+		return attendanceError(c, err)
+	}
+	action := "disetujui"
+	if !approved {
+		action = "dibatalkan persetujuannya"
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityMember, "Persetujuan Absensi",
+		fmt.Sprintf("%d absensi %s", count, action))
+	return response.Success(c, http.StatusOK, map[string]interface{}{"updated": count, "approved": approved})
+}
+
+func (h *AttendanceHandler) Update(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	existing, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.service.Save(userID, &body); err != nil {
+		return attendanceError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *AttendanceHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.Get(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		return attendanceError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
@@ -3,32 +3,88 @@ package http
 import (
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
 	"dashboardadminimb/pkg/response"
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 type DailyReportHandler struct {
 	dailyReportService service.DailyReportService
+	projectService     service.ProjectService
 }
 
-func NewDailyReportHandler(dailyReportService service.DailyReportService) *DailyReportHandler {
+func NewDailyReportHandler(dailyReportService service.DailyReportService, projectService service.ProjectService) *DailyReportHandler {
 	return &DailyReportHandler{
 		dailyReportService: dailyReportService,
+		projectService:     projectService,
 	}
 }
 
+// authorizeProject memastikan proyek milik user yang login. Return status HTTP + error jika tidak.
+func (h *DailyReportHandler) authorizeProject(c echo.Context, projectID uint) (int, error) {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+	if _, err := h.projectService.GetProjectByID(projectID, userID); err != nil {
+		return http.StatusNotFound, errors.New("Project not found")
+	}
+	return 0, nil
+}
+
+// reportForUser mengambil laporan berdasarkan :id dan memastikan proyeknya milik user.
+func (h *DailyReportHandler) reportForUser(c echo.Context) (*entity.ReportDaily, int, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("Invalid report ID")
+	}
+	report, err := h.dailyReportService.GetDailyReportByID(uint(id))
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	if code, err := h.authorizeProject(c, report.ProjectID); err != nil {
+		return nil, code, err
+	}
+	return report, 0, nil
+}
+
+// migrateLegacy memindahkan reports.daily lama (JSON) ke tabel sebelum edit per baris, agar tanggal yang dihapus
+// tidak muncul lagi dari JSON dan tanggal baru tidak bentrok dengan entri JSON.
+func (h *DailyReportHandler) migrateLegacy(projectID uint) error {
+	_, err := h.projectService.MigrateDailyReports(projectID, false)
+	return err
+}
+
+func validReportDate(date string) bool {
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
 // CreateDailyReport creates a new daily report
 func (h *DailyReportHandler) CreateDailyReport(c echo.Context) error {
 	var report entity.ReportDaily
 	if err := c.Bind(&report); err != nil {
 		return response.Error(c, http.StatusBadRequest, errors.New("Invalid request body"))
 	}
+	if !validReportDate(report.Date) {
+		return response.Error(c, http.StatusBadRequest, errors.New("date must be YYYY-MM-DD"))
+	}
+	if code, err := h.authorizeProject(c, report.ProjectID); err != nil {
+		return response.Error(c, code, err)
+	}
+	if err := h.migrateLegacy(report.ProjectID); err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
 
 	if err := h.dailyReportService.CreateDailyReport(&report); err != nil {
+		if errors.Is(err, service.ErrDailyReportExists) {
+			return response.Error(c, http.StatusConflict, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 
@@ -42,6 +98,9 @@ func (h *DailyReportHandler) GetDailyReportsByProject(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, errors.New("Invalid project ID"))
 	}
+	if code, err := h.authorizeProject(c, uint(projectID)); err != nil {
+		return response.Error(c, code, err)
+	}
 
 	reports, err := h.dailyReportService.GetDailyReportsByProject(uint(projectID))
 	if err != nil {
@@ -53,50 +112,68 @@ func (h *DailyReportHandler) GetDailyReportsByProject(c echo.Context) error {
 
 // GetDailyReportByID gets a specific daily report by ID
 func (h *DailyReportHandler) GetDailyReportByID(c echo.Context) error {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	report, code, err := h.reportForUser(c)
 	if err != nil {
-		return response.Error(c, http.StatusBadRequest, errors.New("Invalid report ID"))
-	}
-
-	report, err := h.dailyReportService.GetDailyReportByID(uint(id))
-	if err != nil {
-		return response.Error(c, http.StatusNotFound, err)
+		return response.Error(c, code, err)
 	}
 
+	setVersionETag(c, report.Version)
 	return response.Success(c, http.StatusOK, report)
 }
 
 // UpdateDailyReport updates a daily report
 func (h *DailyReportHandler) UpdateDailyReport(c echo.Context) error {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	existing, code, err := h.reportForUser(c)
 	if err != nil {
-		return response.Error(c, http.StatusBadRequest, errors.New("Invalid report ID"))
+		return response.Error(c, code, err)
 	}
 
 	var report entity.ReportDaily
 	if err := c.Bind(&report); err != nil {
 		return response.Error(c, http.StatusBadRequest, errors.New("Invalid request body"))
 	}
+	if !validReportDate(report.Date) {
+		return response.Error(c, http.StatusBadRequest, errors.New("date must be YYYY-MM-DD"))
+	}
 
-	report.ID = uint(id)
-	if err := h.dailyReportService.UpdateDailyReport(&report); err != nil {
+	expected, code, err := expectedVersion(c, report.Version)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+
+	if err := h.migrateLegacy(existing.ProjectID); err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	report.ID = existing.ID
+	if err := h.dailyReportService.UpdateDailyReport(&report, expected); err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			current, err := h.dailyReportService.GetDailyReportByID(existing.ID)
+			if err != nil {
+				return response.Error(c, http.StatusInternalServerError, err)
+			}
+			return versionConflict(c, current.Version, current)
+		}
+		if errors.Is(err, service.ErrDailyReportExists) {
+			return response.Error(c, http.StatusConflict, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 
+	setVersionETag(c, report.Version)
 	return response.Success(c, http.StatusOK, report)
 }
 
 // DeleteDailyReport deletes a daily report
 func (h *DailyReportHandler) DeleteDailyReport(c echo.Context) error {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	existing, code, err := h.reportForUser(c)
 	if err != nil {
-		return response.Error(c, http.StatusBadRequest, errors.New("Invalid report ID"))
+		return response.Error(c, code, err)
 	}
 
-	if err := h.dailyReportService.DeleteDailyReport(uint(id)); err != nil {
+	if err := h.migrateLegacy(existing.ProjectID); err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	if err := h.dailyReportService.DeleteDailyReport(existing.ID); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 
@@ -110,6 +187,9 @@ func (h *DailyReportHandler) GetDailyReportsByDateRange(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, errors.New("Invalid project ID"))
 	}
+	if code, err := h.authorizeProject(c, uint(projectID)); err != nil {
+		return response.Error(c, code, err)
+	}
 
 	startDate := c.QueryParam("startDate")
 	endDate := c.QueryParam("endDate")
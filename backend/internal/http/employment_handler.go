@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EmploymentHandler kontrak kerja dan riwayat jabatan/tarif anggota.
+type EmploymentHandler struct {
+	service service.EmploymentService
+}
+
+func NewEmploymentHandler(service service.EmploymentService) *EmploymentHandler {
+	return &EmploymentHandler{service}
+}
+
+func employmentError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrEmploymentInvalid):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrContractOverlap), errors.Is(err, service.ErrEmploymentEvent):
+		return response.Error(c, http.StatusConflict, err)
+	}
+	return response.Error(c, http.StatusNotFound, err)
+}
+
+// History GET /api/members/:id/employment — kontrak, riwayat dan syarat kerja yang berlaku hari ini.
+func (h *EmploymentHandler) History(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	history, err := h.service.History(c.Param("id"), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, history)
+}
+
+// Terms GET /api/members/:id/employment/terms?date=YYYY-MM-DD (default hari ini).
+func (h *EmploymentHandler) Terms(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	date := c.QueryParam("date")
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	terms, err := h.service.TermsFor(c.Param("id"), userID, date)
+	if err != nil {
+		return employmentError(c, err)
+	}
+	return response.Success(c, http.StatusOK, terms)
+}
+
+// CreateContract POST /api/members/:id/employment/contracts
+func (h *EmploymentHandler) CreateContract(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.MemberContract
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.SaveContract(c.Param("id"), userID, &body); err != nil {
+		return employmentError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *EmploymentHandler) UpdateContract(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("contractId"))
+	existing, err := h.service.GetContract(uint(id), c.Param("id"), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.service.SaveContract(c.Param("id"), userID, &body); err != nil {
+		return employmentError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *EmploymentHandler) DeleteContract(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("contractId"))
+	if err := h.service.DeleteContract(uint(id), c.Param("id"), userID); err != nil {
+		return employmentError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// RecordChange POST /api/members/:id/employment/changes — perubahan jabatan/tarif mulai effective_date.
+func (h *EmploymentHandler) RecordChange(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body service.EmploymentChange
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	event, err := h.service.RecordChange(c.Param("id"), userID, &body)
+	if err != nil {
+		return employmentError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, event)
+}
+
+func (h *EmploymentHandler) DeleteEvent(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("eventId"))
+	if err := h.service.DeleteEvent(uint(id), c.Param("id"), userID); err != nil {
+		return employmentError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
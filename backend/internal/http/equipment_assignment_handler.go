@@ -0,0 +1,125 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EquipmentAssignmentHandler penempatan alat ke proyek.
+type EquipmentAssignmentHandler struct {
+	service service.EquipmentAssignmentService
+}
+
+func NewEquipmentAssignmentHandler(service service.EquipmentAssignmentService) *EquipmentAssignmentHandler {
+	return &EquipmentAssignmentHandler{service}
+}
+
+// assignmentSaveError 400 untuk input tidak valid, 409 + daftar penempatan yang bentrok untuk tumpang tindih.
+func assignmentSaveError(c echo.Context, conflicts []entity.EquipmentDeployment, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAssignmentInvalid):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrAssignmentOverlap):
+		return response.ErrorWithData(c, http.StatusConflict, err, map[string]interface{}{"conflicts": conflicts})
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
+// List GET /api/equipment-assignments?equipment_id=&project_id=&date=
+func (h *EquipmentAssignmentHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	equipmentID, _ := strconv.Atoi(c.QueryParam("equipment_id"))
+	projectID, _ := strconv.Atoi(c.QueryParam("project_id"))
+	list, err := h.service.List(userID, repository.EquipmentAssignmentFilter{
+		EquipmentID: uint(equipmentID),
+		ProjectID:   uint(projectID),
+		Date:        c.QueryParam("date"),
+	})
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// Deployed GET /api/equipment-assignments/deployed?date=YYYY-MM-DD (default hari ini) — alat per proyek + unit menganggur.
+func (h *EquipmentAssignmentHandler) Deployed(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	date := c.QueryParam("date")
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	snap, err := h.service.DeployedOn(userID, date)
+	if err != nil {
+		if errors.Is(err, service.ErrAssignmentInvalid) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, snap)
+}
+
+func (h *EquipmentAssignmentHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.EquipmentAssignment
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if conflicts, err := h.service.Save(userID, &body); err != nil {
+		return assignmentSaveError(c, conflicts, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *EquipmentAssignmentHandler) Update(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	existing, err := h.service.GetByID(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	var body entity.EquipmentAssignment
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = existing.ID
+	body.CreatedAt = existing.CreatedAt
+	if conflicts, err := h.service.Save(userID, &body); err != nil {
+		return assignmentSaveError(c, conflicts, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *EquipmentAssignmentHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
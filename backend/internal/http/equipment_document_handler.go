@@ -0,0 +1,103 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EquipmentDocumentHandler dokumen & izin alat (STNK, KIR, asuransi, SIA, SILO).
+type EquipmentDocumentHandler struct {
+	service service.EquipmentDocumentService
+}
+
+func NewEquipmentDocumentHandler(service service.EquipmentDocumentService) *EquipmentDocumentHandler {
+	return &EquipmentDocumentHandler{service}
+}
+
+func documentError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrDocumentInvalid) {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Error(c, http.StatusNotFound, err)
+}
+
+// List GET /api/equipment/:id/documents
+func (h *EquipmentDocumentHandler) List(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.List(equipmentID, userID)
+	if err != nil {
+		return documentError(c, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// Create POST /api/equipment/:id/documents — file_url dari POST /api/uploads/file (folder=equipment-documents).
+func (h *EquipmentDocumentHandler) Create(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.EquipmentDocument
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.Save(equipmentID, userID, &body); err != nil {
+		return documentError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *EquipmentDocumentHandler) Update(c echo.Context) error {
+	equipmentID, userID, docID, err := equipmentParams(c, "docId")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	existing, err := h.service.Get(docID, equipmentID, userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.service.Save(equipmentID, userID, &body); err != nil {
+		return documentError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *EquipmentDocumentHandler) Delete(c echo.Context) error {
+	equipmentID, userID, docID, err := equipmentParams(c, "docId")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	if err := h.service.Delete(docID, equipmentID, userID); err != nil {
+		return documentError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// Compliance GET /api/equipment/documents/compliance?issues_only=true — kelengkapan dokumen seluruh armada.
+func (h *EquipmentDocumentHandler) Compliance(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.Compliance(userID, c.QueryParam("issues_only") == "true")
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
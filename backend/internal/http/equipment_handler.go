@@ -66,6 +66,9 @@ func (h *EquipmentHandler) Create(c echo.Context) error {
 	if body.PricePerHour < 0 {
 		body.PricePerHour = 0
 	}
+	if body.PurchasePrice < 0 {
+		body.PurchasePrice = 0
+	}
 	if err := h.service.Create(userID, &body); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
@@ -86,6 +89,10 @@ func (h *EquipmentHandler) Update(c echo.Context) error {
 	existing.LicensePlate = body.LicensePlate
 	existing.PricePerDay = body.PricePerDay
 	existing.PricePerHour = body.PricePerHour
+	if body.PurchasePrice >= 0 {
+		existing.PurchasePrice = body.PurchasePrice
+	}
+	existing.PurchaseDate = body.PurchaseDate
 	if body.Type != "" {
 		if body.Type == "alat_berat" || body.Type == "dump_truck" {
 			existing.Type = body.Type
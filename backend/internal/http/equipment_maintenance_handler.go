@@ -0,0 +1,190 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EquipmentMaintenanceHandler hour meter, rencana perawatan dan riwayat servis alat.
+type EquipmentMaintenanceHandler struct {
+	service service.EquipmentMaintenanceService
+}
+
+func NewEquipmentMaintenanceHandler(service service.EquipmentMaintenanceService) *EquipmentMaintenanceHandler {
+	return &EquipmentMaintenanceHandler{service}
+}
+
+// maintenanceError 400 untuk validasi, selain itu 404 (alat/rencana/servis tidak ditemukan).
+func maintenanceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrMeterInvalid), errors.Is(err, service.ErrPlanInvalid),
+		errors.Is(err, service.ErrServiceInvalid), errors.Is(err, service.ErrServiceFinanceLink):
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Error(c, http.StatusNotFound, err)
+}
+
+// equipmentParams :id alat dan user login; param adalah nama path param tambahan (planId/recordId) atau "".
+func equipmentParams(c echo.Context, param string) (equipmentID, userID, id uint, err error) {
+	if userID, err = appmiddleware.CurrentUserID(c); err != nil {
+		return
+	}
+	eid, _ := strconv.Atoi(c.Param("id"))
+	equipmentID = uint(eid)
+	if param != "" {
+		n, _ := strconv.Atoi(c.Param(param))
+		id = uint(n)
+	}
+	return
+}
+
+// ListReadings GET /api/equipment/:id/meter-readings
+func (h *EquipmentMaintenanceHandler) ListReadings(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.ListReadings(equipmentID, userID)
+	if err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// AddReading POST /api/equipment/:id/meter-readings
+func (h *EquipmentMaintenanceHandler) AddReading(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.EquipmentMeterReading
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.AddReading(equipmentID, userID, &body); err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+// ListPlans GET /api/equipment/:id/maintenance-plans — dengan status jatuh tempo.
+func (h *EquipmentMaintenanceHandler) ListPlans(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.ListPlans(equipmentID, userID)
+	if err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+func (h *EquipmentMaintenanceHandler) CreatePlan(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	body := entity.MaintenancePlan{Active: true}
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.SavePlan(equipmentID, userID, &body); err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *EquipmentMaintenanceHandler) UpdatePlan(c echo.Context) error {
+	equipmentID, userID, planID, err := equipmentParams(c, "planId")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	existing, err := h.service.GetPlan(planID, equipmentID, userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt, body.NotifiedDue = existing.ID, existing.CreatedAt, existing.NotifiedDue
+	if err := h.service.SavePlan(equipmentID, userID, &body); err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *EquipmentMaintenanceHandler) DeletePlan(c echo.Context) error {
+	equipmentID, userID, planID, err := equipmentParams(c, "planId")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	if err := h.service.DeletePlan(planID, equipmentID, userID); err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// ListRecords GET /api/equipment/:id/service-records
+func (h *EquipmentMaintenanceHandler) ListRecords(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.ListRecords(equipmentID, userID)
+	if err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// CreateRecord POST /api/equipment/:id/service-records — cost > 0 tanpa finance_id membuat expense Finance.
+func (h *EquipmentMaintenanceHandler) CreateRecord(c echo.Context) error {
+	equipmentID, userID, _, err := equipmentParams(c, "")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.ServiceRecord
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.CreateRecord(equipmentID, userID, &body); err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *EquipmentMaintenanceHandler) DeleteRecord(c echo.Context) error {
+	equipmentID, userID, recordID, err := equipmentParams(c, "recordId")
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	if err := h.service.DeleteRecord(recordID, equipmentID, userID); err != nil {
+		return maintenanceError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// Due GET /api/equipment/maintenance/due?include_due_soon=true — rencana armada yang terlambat/segera jatuh tempo.
+func (h *EquipmentMaintenanceHandler) Due(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	includeDueSoon := c.QueryParam("include_due_soon") != "false"
+	list, err := h.service.DueList(userID, includeDueSoon)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
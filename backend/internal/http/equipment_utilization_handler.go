@@ -0,0 +1,95 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EquipmentUtilizationHandler utilisasi & profitabilitas alat.
+type EquipmentUtilizationHandler struct {
+	service service.EquipmentUtilizationService
+}
+
+func NewEquipmentUtilizationHandler(service service.EquipmentUtilizationService) *EquipmentUtilizationHandler {
+	return &EquipmentUtilizationHandler{service}
+}
+
+// utilizationQuery ?from=&to= (default 30 hari terakhir s/d hari ini), ?hours_per_day= (default 8), ?sort=.
+func utilizationQuery(c echo.Context) (service.UtilizationQuery, error) {
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	q := service.UtilizationQuery{From: today.AddDate(0, 0, -29), To: today, SortBy: c.QueryParam("sort")}
+	if v := c.QueryParam("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return q, service.ErrUtilizationQuery
+		}
+		q.From = t
+	}
+	if v := c.QueryParam("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return q, service.ErrUtilizationQuery
+		}
+		q.To = t
+	}
+	if !q.ValidPeriod() {
+		return q, service.ErrUtilizationQuery
+	}
+	if v := c.QueryParam("hours_per_day"); v != "" {
+		h, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return q, service.ErrUtilizationQuery
+		}
+		q.HoursPerDay = h
+	}
+	return q, nil
+}
+
+// Ranking GET /api/equipment/utilization — ranking armada.
+func (h *EquipmentUtilizationHandler) Ranking(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	q, err := utilizationQuery(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	list, err := h.service.Ranking(userID, q)
+	if err != nil {
+		if errors.Is(err, service.ErrUtilizationQuery) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// GetByEquipment GET /api/equipment/:id/utilization
+func (h *EquipmentUtilizationHandler) GetByEquipment(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	q, err := utilizationQuery(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	u, err := h.service.GetByEquipment(uint(id), userID, q)
+	if err != nil {
+		if errors.Is(err, service.ErrUtilizationQuery) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, u)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	errVersionRequired = errors.New("header If-Match (atau field version) wajib diisi dengan versi terakhir yang dibaca")
+	errInvalidIfMatch  = errors.New("If-Match tidak valid")
+)
+
+// versionETag ETag dari kolom version: "3".
+func versionETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+func setVersionETag(c echo.Context, version uint) {
+	c.Response().Header().Set("ETag", versionETag(version))
+}
+
+// expectedVersion versi yang diharapkan client: dari If-Match, atau field version di body jika header kosong.
+// If-Match "*" berarti tanpa cek (0). Return status 428/400 jika tidak ada atau tidak valid.
+func expectedVersion(c echo.Context, bodyVersion uint) (uint, int, error) {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" {
+		if bodyVersion == 0 {
+			return 0, http.StatusPreconditionRequired, errVersionRequired
+		}
+		return bodyVersion, 0, nil
+	}
+	if header == "*" {
+		return 0, 0, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	v, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil || v == 0 {
+		return 0, http.StatusBadRequest, errInvalidIfMatch
+	}
+	return uint(v), 0, nil
+}
+
+// versionConflict 409 dengan versi terbaru dan datanya agar client bisa merge lalu kirim ulang.
+func versionConflict(c echo.Context, version uint, current interface{}) error {
+	setVersionETag(c, version)
+	return response.ErrorWithData(c, http.StatusConflict, service.ErrVersionConflict, map[string]interface{}{
+		"version": version,
+		"current": current,
+	})
+}
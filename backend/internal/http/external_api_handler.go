@@ -12,20 +12,21 @@ import (
 	"dashboardadminimb/pkg/response"
 
 	"github.com/labstack/echo/v4"
-	"gorm.io/datatypes"
 )
 
 type ExternalAPIHandler struct {
-	projectService service.ProjectService
-	financeService service.FinanceService
-	memberService  service.MemberService
+	projectService     service.ProjectService
+	financeService     service.FinanceService
+	memberService      service.MemberService
+	dailyReportService service.DailyReportService
 }
 
-func NewExternalAPIHandler(projectService service.ProjectService, financeService service.FinanceService, memberService service.MemberService) *ExternalAPIHandler {
+func NewExternalAPIHandler(projectService service.ProjectService, financeService service.FinanceService, memberService service.MemberService, dailyReportService service.DailyReportService) *ExternalAPIHandler {
 	return &ExternalAPIHandler{
-		projectService: projectService,
-		financeService: financeService,
-		memberService:  memberService,
+		projectService:     projectService,
+		financeService:     financeService,
+		memberService:      memberService,
+		dailyReportService: dailyReportService,
 	}
 }
 
@@ -135,7 +136,7 @@ func (h *ExternalAPIHandler) GetDailyReportsByProject(c echo.Context) error {
 }
 
 // PushCutFillReports receives cut-fill daily data from Smart Nota and merges it
-// into the project's reports.daily JSON array.
+// into the project's daily report rows (only ritase/aktual/cuaca/catatan are touched).
 // POST /api/external/daily-reports
 func (h *ExternalAPIHandler) PushCutFillReports(c echo.Context) error {
 	userID, err := appmiddleware.IntegrationTokenUserID(c)
@@ -158,35 +159,11 @@ func (h *ExternalAPIHandler) PushCutFillReports(c echo.Context) error {
 		return response.Error(c, http.StatusNotFound, errors.New("project not found"))
 	}
 
-	var reports map[string]interface{}
-	if len(project.Reports) > 0 {
-		_ = json.Unmarshal(project.Reports, &reports)
-	}
-	if reports == nil {
-		reports = map[string]interface{}{}
-	}
-
-	var daily []map[string]interface{}
-	if d, ok := reports["daily"]; ok {
-		if arr, ok := d.([]interface{}); ok {
-			for _, item := range arr {
-				if m, ok := item.(map[string]interface{}); ok {
-					daily = append(daily, m)
-				}
-			}
-		}
-	}
-
-	dateIndex := make(map[string]int, len(daily))
-	for i, dr := range daily {
-		if date, ok := dr["date"].(string); ok {
-			dateIndex[date] = i
-		}
-	}
-
-	now := time.Now().Unix()
-	updated, created := 0, 0
+	entries := make([]service.CutFillEntry, 0, len(req.Entries))
 	for _, e := range req.Entries {
+		if _, err := time.Parse("2006-01-02", e.Date); err != nil {
+			return response.Error(c, http.StatusBadRequest, errors.New("invalid date: "+e.Date))
+		}
 		catatan := e.Catatan
 		if e.DisruptionHours > 0 {
 			if catatan != "" {
@@ -194,61 +171,15 @@ func (h *ExternalAPIHandler) PushCutFillReports(c echo.Context) error {
 			}
 			catatan += "Jam gangguan: " + strconv.FormatFloat(e.DisruptionHours, 'f', -1, 64) + " jam"
 		}
-
-		if idx, exists := dateIndex[e.Date]; exists {
-			daily[idx]["ritase"] = e.Ritase
-			daily[idx]["aktual"] = e.Aktual
-			if e.Cuaca != "" {
-				daily[idx]["cuaca"] = e.Cuaca
-			}
-			if catatan != "" {
-				daily[idx]["catatan"] = catatan
-			}
-			daily[idx]["updatedAt"] = now
-			updated++
-		} else {
-			newEntry := map[string]interface{}{
-				"id":             0,
-				"projectId":      req.ProjectID,
-				"date":           e.Date,
-				"revenue":        0,
-				"paid":           0,
-				"volume":         0,
-				"targetVolume":   0,
-				"plan":           0,
-				"aktual":         e.Aktual,
-				"ritase":         e.Ritase,
-				"cuaca":          e.Cuaca,
-				"catatan":        catatan,
-				"workers":        map[string]interface{}{},
-				"equipment":      map[string]interface{}{},
-				"totalWorkers":   0,
-				"totalEquipment": 0,
-				"images":         []interface{}{},
-				"createdAt":      now,
-				"updatedAt":      now,
-			}
-			dateIndex[e.Date] = len(daily)
-			daily = append(daily, newEntry)
-			created++
-		}
-	}
-
-	reports["daily"] = daily
-	if reports["weekly"] == nil {
-		reports["weekly"] = []interface{}{}
-	}
-	if reports["monthly"] == nil {
-		reports["monthly"] = []interface{}{}
+		entries = append(entries, service.CutFillEntry{Date: e.Date, Ritase: e.Ritase, Aktual: e.Aktual, Cuaca: e.Cuaca, Catatan: catatan})
 	}
 
-	reportsJSON, err := json.Marshal(reports)
-	if err != nil {
+	// Proyek lama: pindahkan dulu reports.daily ke tabel agar volume/pekerja di JSON tidak tertutup baris baru.
+	if _, err := h.projectService.MigrateDailyReports(project.ID, false); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
-	project.Reports = datatypes.JSON(reportsJSON)
-
-	if err := h.projectService.UpdateProject(project); err != nil {
+	updated, created, err := h.dailyReportService.MergeCutFill(project.ID, entries)
+	if err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FuelHandler log pengeluaran BBM alat.
+type FuelHandler struct {
+	service service.FuelService
+}
+
+func NewFuelHandler(service service.FuelService) *FuelHandler {
+	return &FuelHandler{service}
+}
+
+func fuelError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrFuelInvalid) || errors.Is(err, service.ErrFuelLink) {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
+// fuelQuery ?equipment_id=&project_id=&from=&to=&threshold= (persen deviasi anomali, default 30).
+func fuelQuery(c echo.Context) (repository.FuelLogFilter, float64) {
+	equipmentID, _ := strconv.Atoi(c.QueryParam("equipment_id"))
+	projectID, _ := strconv.Atoi(c.QueryParam("project_id"))
+	threshold, _ := strconv.ParseFloat(c.QueryParam("threshold"), 64)
+	return repository.FuelLogFilter{
+		EquipmentID: uint(equipmentID),
+		ProjectID:   uint(projectID),
+		From:        c.QueryParam("from"),
+		To:          c.QueryParam("to"),
+	}, threshold
+}
+
+// List GET /api/fuel-logs
+func (h *FuelHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	filter, threshold := fuelQuery(c)
+	list, err := h.service.List(userID, filter, threshold)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// Consumption GET /api/fuel-logs/consumption — liter/jam per unit dan rekonsiliasi BBM ditagih vs dikeluarkan.
+func (h *FuelHandler) Consumption(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	filter, threshold := fuelQuery(c)
+	list, err := h.service.Consumption(userID, filter, threshold)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+func (h *FuelHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.FuelLog
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.Save(userID, &body); err != nil {
+		return fuelError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *FuelHandler) Update(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	existing, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.service.Save(userID, &body); err != nil {
+		return fuelError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *FuelHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
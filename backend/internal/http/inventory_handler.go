@@ -6,15 +6,18 @@ import (
 	appmiddleware "dashboardadminimb/pkg/middleware"
 	"dashboardadminimb/pkg/response"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type InventoryHandler struct {
@@ -31,13 +34,6 @@ type InventoryDataResponse struct {
 	Images     []string       `json:"images"`
 }
 
-type InventoryHeader struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	Optional bool   `json:"optional"`
-}
-
 func NewInventoryHandler(service service.InventoryService, uploadDir string, baseURL string, activityService service.ActivityService) *InventoryHandler {
 	return &InventoryHandler{
 		service:         service,
@@ -47,6 +43,32 @@ func NewInventoryHandler(service service.InventoryService, uploadDir string, bas
 	}
 }
 
+// inventoryError 400 untuk skema/nilai tidak valid, 409 untuk nilai kolom unik yang sudah dipakai.
+func inventoryError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInventorySchema), errors.Is(err, service.ErrInventoryValue):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrInventoryDuplicate):
+		return response.Error(c, http.StatusConflict, err)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
+// bindInventoryData menerima JSON maupun multipart; pada multipart, values dikirim sebagai string JSON.
+func bindInventoryData(c echo.Context, data *entity.InventoryData) error {
+	if err := c.Bind(data); err != nil {
+		return err
+	}
+	if len(data.Values) == 0 {
+		if raw := c.FormValue("values"); raw != "" {
+			data.Values = datatypes.JSON(raw)
+		}
+	}
+	return nil
+}
+
 func (h *InventoryHandler) CreateCategory(c echo.Context) error {
 	userID, err := appmiddleware.CurrentUserID(c)
 	if err != nil {
@@ -57,7 +79,7 @@ func (h *InventoryHandler) CreateCategory(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
 	if err := h.service.CreateCategory(userID, &category); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return inventoryError(c, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityIncome, "Create Category Baru",
 		fmt.Sprintf("Berhasil membuat category baru dengan judul: %s", category.Title))
@@ -97,7 +119,7 @@ func (h *InventoryHandler) UpdateCategory(c echo.Context) error {
 	}
 	category.ID = id
 	if err := h.service.UpdateCategory(&category); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return inventoryError(c, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityUpdate, "Berhasil mengupdate category",
 		fmt.Sprintf("Berhasil mengupdate category dengan judul: %s", category.Title))
@@ -129,7 +151,7 @@ func (h *InventoryHandler) CreateData(c echo.Context) error {
 	}
 	categoryID := c.Param("categoryId")
 	var data entity.InventoryData
-	if err := c.Bind(&data); err != nil {
+	if err := bindInventoryData(c, &data); err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
 	data.CategoryID = categoryID
@@ -137,36 +159,26 @@ func (h *InventoryHandler) CreateData(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusNotFound, err)
 	}
-	var headers []InventoryHeader
-	if err := json.Unmarshal(category.Headers, &headers); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
-	}
-	var values map[string]interface{}
-	json.Unmarshal(data.Values, &values)
-	dataName := ""
-	for _, header := range headers {
-		if header.Type == "string" {
-			if val, ok := values[header.ID]; ok {
-				dataName = val.(string)
-				break
-			}
-		}
-	}
-	index := len(category.Data)
-	data.ID = h.service.GenerateDataID(category.Title, dataName, index)
 	if err := h.service.CreateData(&data); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return inventoryError(c, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityIncome, "Create Data Baru",
 		fmt.Sprintf("Berhasil membuat Data baru dengan category : %s", category.Title))
 	return response.Success(c, http.StatusCreated, data)
 }
 
+// GetCategoryData GET /api/inventory/categories/:categoryId/data?sort=<id kolom>&order=asc|desc
 func (h *InventoryHandler) GetCategoryData(c echo.Context) error {
 	categoryID := c.Param("categoryId")
-	data, err := h.service.GetDataByCategory(categoryID)
+	var data []entity.InventoryData
+	var err error
+	if sortBy := c.QueryParam("sort"); sortBy != "" {
+		data, err = h.service.SortedData(categoryID, sortBy, strings.EqualFold(c.QueryParam("order"), "desc"))
+	} else {
+		data, err = h.service.GetDataByCategory(categoryID)
+	}
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return inventoryError(c, err)
 	}
 	responseData := make([]InventoryDataResponse, 0)
 	for _, d := range data {
@@ -193,12 +205,12 @@ func (h *InventoryHandler) UpdateData(c echo.Context) error {
 	}
 	id := c.Param("id")
 	var data entity.InventoryData
-	if err := c.Bind(&data); err != nil {
+	if err := bindInventoryData(c, &data); err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
 	data.ID = id
 	if err := h.service.UpdateData(&data); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return inventoryError(c, err)
 	}
 	category, err := h.service.GetCategoryByID(data.CategoryID)
 	if err != nil {
@@ -264,7 +276,7 @@ func (h *InventoryHandler) UploadImage(c echo.Context) error {
 	images = append(images, filename)
 	imagesJSON, _ := json.Marshal(images)
 	data.Images = imagesJSON
-	if err := h.service.UpdateData(data); err != nil {
+	if err := h.service.UpdateImages(data); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	return response.Success(c, http.StatusOK, map[string]string{
@@ -289,7 +301,7 @@ func (h *InventoryHandler) DeleteImage(c echo.Context) error {
 	}
 	imagesJSON, _ := json.Marshal(newImages)
 	data.Images = imagesJSON
-	if err := h.service.UpdateData(data); err != nil {
+	if err := h.service.UpdateImages(data); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	filePath := filepath.Join(h.uploadDir, imageName)
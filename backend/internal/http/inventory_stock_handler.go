@@ -0,0 +1,204 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// InventoryStockHandler lokasi stok, mutasi (buku stok) dan saldo on-hand inventori.
+type InventoryStockHandler struct {
+	service         service.InventoryStockService
+	activityService service.ActivityService
+}
+
+func NewInventoryStockHandler(service service.InventoryStockService, activityService service.ActivityService) *InventoryStockHandler {
+	return &InventoryStockHandler{service, activityService}
+}
+
+// stockError 400 untuk input tidak valid, 409 (+ saldo lokasi yang kurang) untuk stok tidak mencukupi / lokasi terpakai.
+func stockError(c echo.Context, short *entity.StockBalance, err error) error {
+	switch {
+	case errors.Is(err, service.ErrStockInvalid), errors.Is(err, service.ErrStockLocationInvalid), errors.Is(err, service.ErrStockSettingsInvalid):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrStockInsufficient):
+		return response.ErrorWithData(c, http.StatusConflict, err, map[string]interface{}{"balance": short})
+	case errors.Is(err, service.ErrStockLocationInUse):
+		return response.Error(c, http.StatusConflict, err)
+	}
+	return response.Error(c, http.StatusNotFound, err)
+}
+
+func (h *InventoryStockHandler) ListLocations(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.ListLocations(userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+func (h *InventoryStockHandler) CreateLocation(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.StockLocation
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.SaveLocation(userID, &body); err != nil {
+		return stockError(c, nil, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *InventoryStockHandler) UpdateLocation(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	existing, err := h.service.GetLocation(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.service.SaveLocation(userID, &body); err != nil {
+		return stockError(c, nil, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *InventoryStockHandler) DeleteLocation(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.service.DeleteLocation(uint(id), userID); err != nil {
+		return stockError(c, nil, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// ListMovements GET /api/inventory/movements?item_id=&category_id=&location_id=&project_id=&from=&to=
+func (h *InventoryStockHandler) ListMovements(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	locationID, _ := strconv.Atoi(c.QueryParam("location_id"))
+	projectID, _ := strconv.Atoi(c.QueryParam("project_id"))
+	list, err := h.service.ListMovements(userID, repository.StockFilter{
+		ItemID:     c.QueryParam("item_id"),
+		CategoryID: c.QueryParam("category_id"),
+		LocationID: uint(locationID),
+		ProjectID:  uint(projectID),
+		From:       c.QueryParam("from"),
+		To:         c.QueryParam("to"),
+	})
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// CreateMovement POST /api/inventory/movements — mutasi tidak diubah; koreksi lewat hapus atau adjustment.
+func (h *InventoryStockHandler) CreateMovement(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.StockMovement
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	short, err := h.service.SaveMovement(userID, &body)
+	if err != nil {
+		return stockError(c, short, err)
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityUpdate, "Mutasi Stok",
+		fmt.Sprintf("Mutasi %s %s sebanyak %g %s", body.MovementType, body.ItemID, body.Quantity, body.Unit))
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *InventoryStockHandler) DeleteMovement(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	short, err := h.service.DeleteMovement(uint(id), userID)
+	if err != nil {
+		return stockError(c, short, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// ItemHistory GET /api/inventory/data/:id/movements — buku stok item dengan saldo berjalan.
+func (h *InventoryStockHandler) ItemHistory(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.ItemHistory(userID, c.Param("id"))
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// OnHand GET /api/inventory/stock?category_id=&location_id=&item_id= — saldo per item per lokasi.
+func (h *InventoryStockHandler) OnHand(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	locationID, _ := strconv.Atoi(c.QueryParam("location_id"))
+	list, err := h.service.OnHand(userID, repository.StockFilter{
+		ItemID:     c.QueryParam("item_id"),
+		CategoryID: c.QueryParam("category_id"),
+		LocationID: uint(locationID),
+	})
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// ConfigureCategory PUT /api/inventory/categories/:id/stock-settings — tetapkan kolom jumlah/satuan.
+func (h *InventoryStockHandler) ConfigureCategory(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body service.StockSettings
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	category, err := h.service.ConfigureCategory(userID, c.Param("id"), body)
+	if err != nil {
+		return stockError(c, nil, err)
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityUpdate, "Pengaturan Stok",
+		fmt.Sprintf("Kolom jumlah/satuan category %s diperbarui", category.Title))
+	return response.Success(c, http.StatusOK, category)
+}
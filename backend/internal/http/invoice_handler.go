@@ -4,9 +4,11 @@ import (
 	"dashboardadminimb/config"
 	"dashboardadminimb/internal/deepseek"
 	"dashboardadminimb/internal/entity"
-	"dashboardadminimb/internal/gemini"
+	"dashboardadminimb/internal/extraction"
 	"dashboardadminimb/internal/imagepreprocess"
 	"dashboardadminimb/internal/ocr"
+	"dashboardadminimb/internal/pdfpages"
+	"dashboardadminimb/internal/prompt"
 	"dashboardadminimb/internal/service"
 	appmiddleware "dashboardadminimb/pkg/middleware"
 	"encoding/json"
@@ -44,8 +46,9 @@ func normalizeDueDate(s *string) *string {
 }
 
 type InvoiceHandler struct {
-	service service.InvoiceService
-	cfg     config.Config
+	service       service.InvoiceService
+	promptService service.PromptTemplateService
+	cfg           config.Config
 }
 
 type invoiceAttachmentPayload struct {
@@ -54,8 +57,8 @@ type invoiceAttachmentPayload struct {
 	FileName string `json:"file_name,omitempty"`
 }
 
-func NewInvoiceHandler(service service.InvoiceService, cfg config.Config) *InvoiceHandler {
-	return &InvoiceHandler{service: service, cfg: cfg}
+func NewInvoiceHandler(service service.InvoiceService, promptService service.PromptTemplateService, cfg config.Config) *InvoiceHandler {
+	return &InvoiceHandler{service: service, promptService: promptService, cfg: cfg}
 }
 
 func hydrateCustomerFields(inv *entity.Invoice) {
@@ -309,8 +312,8 @@ func (h *InvoiceHandler) GetCustomerSuggestions(c echo.Context) error {
 	return response.Success(c, http.StatusOK, list)
 }
 
-// ExtractFromImage menerima upload satu atau banyak gambar (nota/timesheet). Banyak gambar = 1 gambar 1 hari, digabung.
-// Form: image (atau image[]) untuk file; quantity_unit (hari|jam), use_bbm_columns (true|false), bbm_unit_price, location, equipment_name untuk config.
+// ExtractFromImage menerima upload satu atau banyak gambar/PDF (nota/timesheet). Banyak halaman = 1 halaman 1 hari, digabung
+// (PDF 7 halaman = 7 baris item). Form: image (atau image[]) untuk file (JPEG/PNG/PDF); quantity_unit (hari|jam), use_bbm_columns (true|false), bbm_unit_price, location, equipment_name untuk config.
 func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -320,6 +323,18 @@ func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 	if len(files) == 0 {
 		return response.Error(c, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "minimal 1 gambar (field 'image')"))
 	}
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var pages []uploadPage
+	for _, file := range files {
+		p, err := h.readUploadPages(file)
+		if err != nil {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		pages = append(pages, p...)
+	}
 	quantityUnit := strings.TrimSpace(c.FormValue("quantity_unit"))
 	if quantityUnit == "" {
 		quantityUnit = "hari"
@@ -343,6 +358,7 @@ func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 		RowDate      string  `json:"row_date"`
 		BbmQuantity  float64 `json:"bbm_quantity"`
 		BbmUnitPrice float64 `json:"bbm_unit_price"`
+		Source       string  `json:"source,omitempty"` // file (dan halaman PDF) asal baris
 	}
 	type extractResp struct {
 		CustomerName    string    `json:"customer_name"`
@@ -356,34 +372,32 @@ func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 		Notes           string    `json:"notes"`
 		Items           []itemRow `json:"items"`
 		Total           float64   `json:"total"`
+		PromptVersion   int       `json:"prompt_version"` // 0 = prompt bawaan
+		Pages           int       `json:"pages"`          // jumlah halaman yang diproses
 	}
 	resp := extractResp{
 		Location:      location,
 		EquipmentName: equipmentName,
 		QuantityUnit:  quantityUnit,
 		UseBbmColumns: useBBM,
+		Pages:         len(pages),
 	}
 
-	// Satu gambar: mode lama (satu dokumen bisa banyak baris)
-	if len(files) == 1 {
-		file := files[0]
-		imageData, mimeType, err := readImageFile(file)
+	// Satu halaman: mode lama (satu dokumen bisa banyak baris)
+	if len(pages) == 1 {
+		page := pages[0]
+		imageData, mimeType, err := imagepreprocess.RotatePortraitToLandscape(page.Data, page.MimeType)
 		if err != nil {
 			return response.Error(c, http.StatusBadRequest, err)
 		}
-		imageData, mimeType, err = imagepreprocess.RotatePortraitToLandscape(imageData, mimeType)
-		if err != nil {
-			return response.Error(c, http.StatusBadRequest, err)
-		}
-		var out *gemini.ExtractInvoiceResponse
-		if strings.TrimSpace(strings.ToLower(h.cfg.ExtractProvider)) == "deepseek" {
-			out, err = deepseek.ExtractInvoiceFromImage(h.cfg.DeepSeekBaseURL, h.cfg.DeepSeekAPIKey, h.cfg.DeepSeekModel, imageData, mimeType)
-		} else {
-			out, err = gemini.ExtractInvoiceFromImage(h.cfg.GeminiAPIKey, h.cfg.GeminiModel, imageData, mimeType)
-		}
+		client := extraction.NewClient(h.cfg)
+		promptText, tpl := h.resolvePrompt(c, userID, prompt.UseCaseInvoice, client.Provider, prompt.Data{})
+		out, err := client.Invoice(imageData, mimeType, promptText)
+		h.recordExtraction(c, userID, prompt.UseCaseInvoice, client, tpl, page.Label, out, err)
 		if err != nil {
 			return response.Error(c, http.StatusUnprocessableEntity, err)
 		}
+		resp.PromptVersion = promptVersion(tpl)
 		resp.CustomerName = out.CustomerName
 		resp.CustomerPhone = out.CustomerPhone
 		resp.CustomerAddress = out.CustomerAddress
@@ -399,6 +413,7 @@ func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 				RowDate:      it.RowDate,
 				BbmQuantity:  0,
 				BbmUnitPrice: 0,
+				Source:       page.Label,
 			})
 		}
 		if resp.Location == "" {
@@ -407,24 +422,19 @@ func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 		return response.Success(c, http.StatusOK, resp)
 	}
 
-	// Banyak gambar: 1 gambar = 1 hari (1 baris)
-	for i, file := range files {
-		imageData, mimeType, err := readImageFile(file)
+	// Banyak halaman (gambar atau halaman PDF): 1 halaman = 1 hari (1 baris)
+	client := extraction.NewClient(h.cfg)
+	promptText, tpl := h.resolvePrompt(c, userID, prompt.UseCaseOneDay, client.Provider, prompt.Data{QuantityUnit: quantityUnit, UseBBM: useBBM})
+	resp.PromptVersion = promptVersion(tpl)
+	for i, page := range pages {
+		imageData, mimeType, err := imagepreprocess.RotatePortraitToLandscape(page.Data, page.MimeType)
 		if err != nil {
 			return response.Error(c, http.StatusBadRequest, err)
 		}
-		imageData, mimeType, err = imagepreprocess.RotatePortraitToLandscape(imageData, mimeType)
+		one, err := client.OneDay(imageData, mimeType, quantityUnit, useBBM, promptText)
+		h.recordExtraction(c, userID, prompt.UseCaseOneDay, client, tpl, page.Label, one, err)
 		if err != nil {
-			return response.Error(c, http.StatusBadRequest, err)
-		}
-		var one *gemini.ExtractOneDayResponse
-		if strings.TrimSpace(strings.ToLower(h.cfg.ExtractProvider)) == "deepseek" {
-			one, err = deepseek.ExtractOneDayFromImage(h.cfg.DeepSeekBaseURL, h.cfg.DeepSeekAPIKey, h.cfg.DeepSeekModel, imageData, mimeType, quantityUnit, useBBM)
-		} else {
-			one, err = gemini.ExtractOneDayFromImage(h.cfg.GeminiAPIKey, h.cfg.GeminiModel, imageData, mimeType, quantityUnit, useBBM)
-		}
-		if err != nil {
-			return response.Error(c, http.StatusUnprocessableEntity, echo.NewHTTPError(http.StatusUnprocessableEntity, "gambar ke-"+(strconv.Itoa(i+1))+": "+err.Error()))
+			return response.Error(c, http.StatusUnprocessableEntity, echo.NewHTTPError(http.StatusUnprocessableEntity, "gambar ke-"+(strconv.Itoa(i+1))+" ("+page.Label+"): "+err.Error()))
 		}
 		// Dari gambar pertama ambil customer & location jika belum dari config
 		if i == 0 {
@@ -456,35 +466,150 @@ func (h *InvoiceHandler) ExtractFromImage(c echo.Context) error {
 			RowDate:      strings.TrimSpace(one.RowDate),
 			BbmQuantity:  one.BbmQuantity,
 			BbmUnitPrice: bbmPrice,
+			Source:       page.Label,
 		})
 		resp.Total += one.Days*one.Price + one.BbmQuantity*bbmPrice
 	}
 	return response.Success(c, http.StatusOK, resp)
 }
 
+// resolvePrompt mengambil prompt versi aktif dari database untuk use case/provider. Return "" = pakai prompt bawaan;
+// error (DB atau template rusak) hanya di-log agar ekstraksi tetap jalan dengan prompt bawaan.
+func (h *InvoiceHandler) resolvePrompt(c echo.Context, userID uint, useCase, provider string, data prompt.Data) (string, *entity.PromptTemplate) {
+	if h.promptService == nil {
+		return "", nil
+	}
+	text, tpl, err := h.promptService.Resolve(userID, useCase, provider, data)
+	if err != nil {
+		c.Logger().Warnf("prompt %s/%s: %v, pakai prompt bawaan", useCase, provider, err)
+		return "", nil
+	}
+	return text, tpl
+}
+
+// recordExtraction mencatat versi prompt + model yang menghasilkan ekstraksi (gagal simpan log tidak menggagalkan request).
+func (h *InvoiceHandler) recordExtraction(c echo.Context, userID uint, useCase string, client *extraction.Client, tpl *entity.PromptTemplate, fileName string, out interface{}, extractErr error) {
+	if h.promptService == nil {
+		return
+	}
+	log := &entity.ExtractionLog{
+		UserID:        userID,
+		UseCase:       useCase,
+		Provider:      client.Provider,
+		Model:         client.Model,
+		PromptVersion: promptVersion(tpl),
+		FileName:      fileName,
+	}
+	if tpl != nil {
+		log.PromptTemplateID = &tpl.ID
+	}
+	if extractErr != nil {
+		log.Error = extractErr.Error()
+	} else if b, err := json.Marshal(out); err == nil {
+		log.Output = string(b)
+	}
+	if err := h.promptService.RecordExtraction(log); err != nil {
+		c.Logger().Warnf("extraction log: %v", err)
+	}
+}
+
+func promptVersion(tpl *entity.PromptTemplate) int {
+	if tpl == nil {
+		return 0
+	}
+	return tpl.Version
+}
+
 func readImageFile(file *multipart.FileHeader) ([]byte, string, error) {
+	data, mimeType, err := readUploadFile(file, 4*1024*1024, "gambar terlalu besar (maks 4MB)")
+	if err != nil {
+		return nil, "", err
+	}
+	if mimeType == "" {
+		mimeType = "image/jpeg"
+	}
+	return data, mimeType, nil
+}
+
+func readUploadFile(file *multipart.FileHeader, maxSize int64, tooLarge string) ([]byte, string, error) {
 	src, err := file.Open()
 	if err != nil {
 		return nil, "", err
 	}
 	defer src.Close()
-	data, err := io.ReadAll(src)
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
 	if err != nil {
 		return nil, "", err
 	}
-	if len(data) > 4*1024*1024 {
-		return nil, "", echo.NewHTTPError(http.StatusBadRequest, "gambar terlalu besar (maks 4MB)")
+	if int64(len(data)) > maxSize {
+		return nil, "", echo.NewHTTPError(http.StatusBadRequest, tooLarge)
 	}
-	mimeType := file.Header.Get("Content-Type")
-	if mimeType == "" {
-		mimeType = "image/jpeg"
+	return data, file.Header.Get("Content-Type"), nil
+}
+
+// uploadPage satu gambar siap ekstraksi: file gambar biasa atau satu halaman PDF.
+type uploadPage struct {
+	Data     []byte
+	MimeType string
+	Label    string // nama file; untuk PDF ditambah "#<halaman>"
+}
+
+// readUploadPages membaca file upload. Gambar = 1 halaman; PDF dipecah per halaman (PDF_SCRIPT_PATH, PDFTOPPM_PATH,
+// atau ekstraksi gambar scan bawaan). Halaman hasil rasterisasi > 4MB diperkecil agar sama dengan batas upload gambar.
+func (h *InvoiceHandler) readUploadPages(file *multipart.FileHeader) ([]uploadPage, error) {
+	if !pdfpages.IsPDF(nil, file.Header.Get("Content-Type"), file.Filename) {
+		data, mimeType, err := readImageFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pdfpages.IsPDF(data, "", "") {
+			return []uploadPage{{Data: data, MimeType: mimeType, Label: file.Filename}}, nil
+		}
 	}
-	return data, mimeType, nil
+	data, _, err := readUploadFile(file, 20*1024*1024, "PDF terlalu besar (maks 20MB)")
+	if err != nil {
+		return nil, err
+	}
+	maxPages := h.cfg.PDFMaxPages
+	if maxPages <= 0 {
+		maxPages = pdfpages.DefaultMaxPages
+	}
+	rasterizer := pdfpages.ResolveRasterizer(h.cfg.PDFToPPMPath, h.cfg.PDFScriptPath, h.cfg.PDFDPI)
+	pages, err := rasterizer.Rasterize(data, maxPages)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnprocessableEntity, fmt.Sprintf("%s: %v", file.Filename, err))
+	}
+	out := make([]uploadPage, 0, len(pages))
+	for i, p := range pages {
+		if len(p.Data) > 4*1024*1024 {
+			if p.Data, p.MimeType, err = imagepreprocess.ResizeMaxLongEdge(p.Data, p.MimeType); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, uploadPage{Data: p.Data, MimeType: p.MimeType, Label: fmt.Sprintf("%s#%d", file.Filename, i+1)})
+	}
+	return out, nil
+}
+
+// readSinglePage untuk endpoint yang hanya memproses satu halaman (PDF banyak halaman ditolak).
+func (h *InvoiceHandler) readSinglePage(file *multipart.FileHeader) (uploadPage, error) {
+	pages, err := h.readUploadPages(file)
+	if err != nil {
+		return uploadPage{}, err
+	}
+	if len(pages) != 1 {
+		return uploadPage{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("PDF berisi %d halaman; endpoint ini hanya menerima 1 halaman (pakai extract-from-image untuk banyak halaman)", len(pages)))
+	}
+	return pages[0], nil
 }
 
 // ExtractRowFromImage upload satu gambar untuk satu baris item: ekstrak hanya tanggal dan hari/jam. Harga diambil dari alat berat (bisa diedit di form).
 func (h *InvoiceHandler) ExtractRowFromImage(c echo.Context) error {
 	c.Logger().Info("extract-row-from-image: request received")
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
 	file, err := c.FormFile("image")
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
@@ -500,20 +625,18 @@ func (h *InvoiceHandler) ExtractRowFromImage(c echo.Context) error {
 			c.Logger().Warnf("extract-row-from-image: gagal parse column_descriptions: %v", err)
 		}
 	}
-	imageData, mimeType, err := readImageFile(file)
+	page, err := h.readSinglePage(file)
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
-	imageData, mimeType, err = imagepreprocess.RotatePortraitToLandscape(imageData, mimeType)
+	imageData, mimeType, err := imagepreprocess.RotatePortraitToLandscape(page.Data, page.MimeType)
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
-	var out *gemini.ExtractRowResponse
-	if strings.TrimSpace(strings.ToLower(h.cfg.ExtractProvider)) == "deepseek" {
-		out, err = deepseek.ExtractDateAndDaysFromImage(h.cfg.DeepSeekBaseURL, h.cfg.DeepSeekAPIKey, h.cfg.DeepSeekModel, imageData, mimeType, columnDescriptions)
-	} else {
-		out, err = gemini.ExtractDateAndDaysFromImage(h.cfg.GeminiAPIKey, h.cfg.GeminiModel, imageData, mimeType, columnDescriptions)
-	}
+	client := extraction.NewClient(h.cfg)
+	promptText, tpl := h.resolvePrompt(c, userID, prompt.UseCaseRowOnly, client.Provider, prompt.Data{ColumnDescriptions: columnDescriptions})
+	out, err := client.Row(imageData, mimeType, columnDescriptions, promptText)
+	h.recordExtraction(c, userID, prompt.UseCaseRowOnly, client, tpl, page.Label, out, err)
 	if err != nil {
 		c.Logger().Errorf("extract-row-from-image: ekstraksi gagal: %v", err)
 		return response.Error(c, http.StatusUnprocessableEntity, err)
@@ -554,6 +677,7 @@ func (h *InvoiceHandler) ExtractRowFromImage(c echo.Context) error {
 		"days":      days,
 		"unit":      targetUnit, // "hari" atau "jam" — satuan dari nilai days
 		"item_name": strings.TrimSpace(out.ItemName),
+		"prompt_version": promptVersion(tpl),
 	}
 	return response.Success(c, http.StatusOK, resp)
 }
@@ -567,9 +691,10 @@ type TimesheetJamKerja struct {
 	Jam   *float64 `json:"jam"`
 }
 
-// TimesheetRawOCR untuk audit (teks OCR mentah).
+// TimesheetRawOCR untuk audit (teks OCR mentah + layout per baris jika runner mendukung).
 type TimesheetRawOCR struct {
-	Text string `json:"text"`
+	Text  string     `json:"text"`
+	Lines []ocr.Line `json:"lines,omitempty"`
 }
 
 // TimesheetParsedResponse schema output parser timesheet (strict JSON, ada raw_ocr untuk audit).
@@ -590,7 +715,16 @@ type TimesheetParsedResponse struct {
 }
 
 // parseTimesheetPrompt strict: OCR → JSON, normalisasi tanggal/jam, raw_ocr.text = OCR_TEXT.
-func parseTimesheetPrompt(ocrText string) string {
+// layout (opsional) = teks OCR berkolom dari ocr.Result.Layout agar model melihat kolom tabel, bukan satu blok teks.
+func parseTimesheetPrompt(ocrText, layout string) string {
+	layoutPart := ""
+	if strings.TrimSpace(layout) != "" {
+		layoutPart = `
+Layout OCR (satu baris = satu baris visual di kertas, kolom tabel dipisah " | ", kata berakhiran "?" = confidence OCR rendah).
+Gunakan layout ini untuk memasangkan jam awal/akhir/jam per baris tabel jam_kerja:
+` + layout + `
+`
+	}
 	return `Kamu adalah parser dokumen timesheet tulisan tangan.
 Output HARUS berupa JSON valid saja, tanpa teks tambahan, tanpa markdown.
 
@@ -623,7 +757,7 @@ Schema JSON:
 
 Input OCR mentah:
 ` + ocrText + `
-
+` + layoutPart + `
 Tambahkan raw_ocr.text = Input OCR mentah di atas (apa adanya).
 Keluarkan JSON sesuai schema.`
 }
@@ -676,23 +810,33 @@ func validateTimesheetJSON(raw string) error {
 }
 
 // parseTimesheetWithRetry panggil Ollama dengan prompt, bersihkan JSON, validasi; jika gagal sekali retry dengan repair prompt.
-func (h *InvoiceHandler) parseTimesheetWithRetry(baseURL, model, ocrText string) (TimesheetParsedResponse, error) {
-	prompt := parseTimesheetPrompt(ocrText)
-	raw, err := deepseek.CallOllamaGenerate(baseURL, model, prompt, true)
+// lines (opsional) = layout OCR terstruktur; dirender ke prompt dan disimpan di raw_ocr.lines untuk audit.
+// Prompt memakai versi aktif di database (use case timesheet / timesheet_repair, provider ollama) jika ada.
+func (h *InvoiceHandler) parseTimesheetWithRetry(c echo.Context, userID uint, baseURL, model, ocrText string, lines []ocr.Line) (out TimesheetParsedResponse, err error) {
+	layout := (&ocr.Result{Text: ocrText, Lines: lines}).Layout()
+	promptText, tpl := h.resolvePrompt(c, userID, prompt.UseCaseTimesheet, prompt.ProviderOllama, prompt.Data{OCRText: ocrText, Layout: layout})
+	if promptText == "" {
+		promptText = parseTimesheetPrompt(ocrText, layout)
+	}
+	client := &extraction.Client{Provider: prompt.ProviderOllama, Model: model}
+	defer func() { h.recordExtraction(c, userID, prompt.UseCaseTimesheet, client, tpl, "", out, err) }()
+	raw, err := deepseek.CallOllamaGenerate(baseURL, model, promptText, true)
 	if err != nil {
 		return TimesheetParsedResponse{}, err
 	}
 	raw = cleanTimesheetJSON(raw)
 	if err := validateTimesheetJSON(raw); err != nil {
 		// Retry sekali dengan repair prompt
-		repairPrompt := parseTimesheetRepairPrompt(raw)
+		repairPrompt, _ := h.resolvePrompt(c, userID, prompt.UseCaseTimesheetRepair, prompt.ProviderOllama, prompt.Data{BadJSON: raw})
+		if repairPrompt == "" {
+			repairPrompt = parseTimesheetRepairPrompt(raw)
+		}
 		raw, err = deepseek.CallOllamaGenerate(baseURL, model, repairPrompt, true)
 		if err != nil {
 			return TimesheetParsedResponse{}, err
 		}
 		raw = cleanTimesheetJSON(raw)
 	}
-	var out TimesheetParsedResponse
 	if err := json.Unmarshal([]byte(raw), &out); err != nil {
 		return TimesheetParsedResponse{}, fmt.Errorf("parse json: %w", err)
 	}
@@ -702,12 +846,17 @@ func (h *InvoiceHandler) parseTimesheetWithRetry(baseURL, model, ocrText string)
 	} else if out.RawOCR.Text == "" {
 		out.RawOCR.Text = ocrText
 	}
+	out.RawOCR.Lines = lines
 	return out, nil
 }
 
 // ParseTimesheetText menerima teks OCR, memanggil Ollama /api/generate, mengembalikan JSON timesheet.
 // Body: { "text": "..." } atau { "ocr_text": "..." }. Memakai DEEPSEEK_BASE_URL + DEEPSEEK_MODEL (Ollama).
 func (h *InvoiceHandler) ParseTimesheetText(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
 	var body struct {
 		Text    string `json:"text"`
 		OcrText string `json:"ocr_text"`
@@ -735,29 +884,79 @@ func (h *InvoiceHandler) ParseTimesheetText(c echo.Context) error {
 		model = "qwen2.5:7b"
 	}
 
-	out, err := h.parseTimesheetWithRetry(baseURL, model, ocrText)
+	out, err := h.parseTimesheetWithRetry(c, userID, baseURL, model, ocrText, nil)
 	if err != nil {
 		return response.Error(c, http.StatusUnprocessableEntity, err)
 	}
 	return response.Success(c, http.StatusOK, out)
 }
 
-// ParseTimesheetFromImage pipeline: preprocess (resize max 1024) → OCR → LLM (Ollama text) → validasi + retry repair.
+// ocrOptions bahasa dan page segmentation mode untuk OCR: form field "lang" / "psm", fallback OCR_LANG / OCR_PSM.
+func (h *InvoiceHandler) ocrOptions(c echo.Context) (ocr.Options, error) {
+	opts := ocr.Options{Lang: h.cfg.OCRLang, PSM: h.cfg.OCRPSM}
+	if lang := strings.TrimSpace(c.FormValue("lang")); lang != "" {
+		opts.Lang = lang
+	}
+	if psm := strings.TrimSpace(c.FormValue("psm")); psm != "" {
+		n, err := strconv.Atoi(psm)
+		if err != nil {
+			return opts, echo.NewHTTPError(http.StatusBadRequest, "psm harus angka 0..13")
+		}
+		opts.PSM = n
+	}
+	return opts, nil
+}
+
+// ocrPreprocessPipeline pipeline preprocessing untuk OCR: form field "preprocess" (mis. "exif,grayscale,threshold" atau "none")
+// meng-override default per runner dari config (OCR_PREPROCESS / OCR_SCRIPT_PREPROCESS).
+func (h *InvoiceHandler) ocrPreprocessPipeline(c echo.Context) (imagepreprocess.Pipeline, error) {
+	var pipeline imagepreprocess.Pipeline
+	if raw := strings.TrimSpace(c.FormValue("preprocess")); raw != "" {
+		steps, err := imagepreprocess.ParseSteps(raw)
+		if err != nil {
+			return pipeline, err
+		}
+		pipeline.Steps = steps
+	} else {
+		var err error
+		pipeline, err = ocr.ResolvePreprocess(h.cfg.TesseractPath, h.cfg.OCRScriptPath, h.cfg.OCRPreprocess, h.cfg.OCRScriptPreprocess)
+		if err != nil {
+			return pipeline, err
+		}
+	}
+	pipeline.MaxLongEdge = imagepreprocess.MaxLongEdge
+	return pipeline, nil
+}
+
+// ParseTimesheetFromImage pipeline: preprocess (EXIF, grayscale, threshold, deskew, dll; resize max 1024) → OCR (kata + bbox) → LLM (Ollama text) → validasi + retry repair.
 // Hindari vision model; pakai OCR→LLM agar stabil untuk tulisan tangan.
 func (h *InvoiceHandler) ParseTimesheetFromImage(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
 	file, err := c.FormFile("image")
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
-	imageData, mimeType, err := readImageFile(file)
+	page, err := h.readSinglePage(file)
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
-	imageData, mimeType, err = imagepreprocess.RotatePortraitToLandscape(imageData, mimeType)
+	imageData, mimeType := page.Data, page.MimeType
+	// 1) Preprocess: pipeline step (EXIF harus sebelum rotate karena rotate membuang metadata), lalu resize sisi terpanjang max 1024px
+	pipeline, err := h.ocrPreprocessPipeline(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	imageData, mimeType, err = pipeline.Run(imageData, mimeType)
+	if err != nil {
+		return response.Error(c, http.StatusUnprocessableEntity, fmt.Errorf("preprocess: %w", err))
+	}
+	imageData, mimeType, err = imagepreprocess.RotatePortraitToLandscapeLossless(imageData, mimeType)
 	if err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
-	// 1) Preprocess: resize sisi terpanjang max 1024px
 	preprocessed, outMime, err := imagepreprocess.ResizeMaxLongEdge(imageData, mimeType)
 	if err != nil {
 		return response.Error(c, http.StatusUnprocessableEntity, fmt.Errorf("preprocess: %w", err))
@@ -767,10 +966,15 @@ func (h *InvoiceHandler) ParseTimesheetFromImage(c echo.Context) error {
 	if err != nil || ocrRunner == nil {
 		return response.Error(c, http.StatusServiceUnavailable, echo.NewHTTPError(http.StatusServiceUnavailable, "OCR tidak dikonfigurasi. Set TESSERACT_PATH (atau OCR_SCRIPT_PATH) di config."))
 	}
-	ocrText, err := ocrRunner.Run(preprocessed, outMime)
+	opts, err := h.ocrOptions(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	ocrResult, err := ocr.RunStructured(ocrRunner, preprocessed, outMime, opts)
 	if err != nil {
 		return response.Error(c, http.StatusUnprocessableEntity, fmt.Errorf("ocr: %w", err))
 	}
+	ocrText := ocrResult.Text
 	if strings.TrimSpace(ocrText) == "" {
 		return response.Error(c, http.StatusUnprocessableEntity, echo.NewHTTPError(http.StatusUnprocessableEntity, "OCR tidak menghasilkan teks. Coba gambar lebih jelas atau periksa Tesseract."))
 	}
@@ -783,7 +987,7 @@ func (h *InvoiceHandler) ParseTimesheetFromImage(c echo.Context) error {
 	if model == "" {
 		model = "qwen2.5:7b"
 	}
-	out, err := h.parseTimesheetWithRetry(baseURL, model, ocrText)
+	out, err := h.parseTimesheetWithRetry(c, userID, baseURL, model, ocrText, ocrResult.Lines)
 	if err != nil {
 		return response.Error(c, http.StatusUnprocessableEntity, err)
 	}
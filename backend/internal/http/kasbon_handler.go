@@ -39,6 +39,9 @@ func (h *KasbonHandler) CreateKasbon(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, 500, err)
 	}
+	if salary.Locked {
+		return response.Error(c, http.StatusConflict, service.ErrSalaryLocked)
+	}
 	var kasbon entity.Kasbon
 	if err := c.Bind(&kasbon); err != nil {
 		return response.Error(c, 400, err)
@@ -66,6 +69,14 @@ func (h *KasbonHandler) UpdateKasbon(c echo.Context) error {
 		return response.Error(c, 400, err)
 	}
 	kasbon.ID = uint(id)
+	if existing, err := h.kasbonService.GetKasbonByID(kasbon.ID); err == nil {
+		if err := h.salaryService.EnsureEditable(existing.SalaryID); err != nil {
+			return response.Error(c, http.StatusConflict, err)
+		}
+	}
+	if err := h.salaryService.EnsureEditable(kasbon.SalaryID); err != nil {
+		return response.Error(c, http.StatusConflict, err)
+	}
 	if err := h.kasbonService.UpdateKasbon(&kasbon); err != nil {
 		return response.Error(c, 500, err)
 	}
@@ -90,6 +101,9 @@ func (h *KasbonHandler) DeleteKasbon(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, 404, err)
 	}
+	if err := h.salaryService.EnsureEditable(kasbon.SalaryID); err != nil {
+		return response.Error(c, http.StatusConflict, err)
+	}
 	if err := h.kasbonService.DeleteKasbon(uint(kasbonID)); err != nil {
 		return response.Error(c, 500, err)
 	}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MemberDocumentHandler dokumen anggota (KTP, SIM B2, SIO, medical checkup) dengan masa berlaku.
+type MemberDocumentHandler struct {
+	service service.MemberDocumentService
+}
+
+func NewMemberDocumentHandler(service service.MemberDocumentService) *MemberDocumentHandler {
+	return &MemberDocumentHandler{service}
+}
+
+func memberDocumentError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrMemberDocumentInvalid) {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Error(c, http.StatusNotFound, err)
+}
+
+// List GET /api/member-documents?member_id=
+func (h *MemberDocumentHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.List(c.QueryParam("member_id"), userID)
+	if err != nil {
+		return memberDocumentError(c, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+// Expiring GET /api/member-documents/expiring?days=30 — anggota dengan dokumen kedaluwarsa atau hampir habis.
+func (h *MemberDocumentHandler) Expiring(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	days, err := strconv.Atoi(c.QueryParam("days"))
+	if err != nil || days < 0 {
+		days = 30
+	}
+	list, err := h.service.Expiring(userID, days)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+func (h *MemberDocumentHandler) Get(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	doc, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, doc)
+}
+
+// Create POST /api/member-documents — file_url dari POST /api/uploads/file (folder=member-documents).
+func (h *MemberDocumentHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body entity.MemberDocument
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID = 0
+	if err := h.service.Save(userID, &body); err != nil {
+		return memberDocumentError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, body)
+}
+
+func (h *MemberDocumentHandler) Update(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	existing, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	body := *existing
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	body.ID, body.CreatedAt = existing.ID, existing.CreatedAt
+	if err := h.service.Save(userID, &body); err != nil {
+		return memberDocumentError(c, err)
+	}
+	return response.Success(c, http.StatusOK, body)
+}
+
+func (h *MemberDocumentHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		return memberDocumentError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
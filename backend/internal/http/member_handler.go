@@ -22,14 +22,16 @@ import (
 type MemberHandler struct {
 	service         service.MemberService
 	salaryService   service.SalaryService
+	documentService service.MemberDocumentService
 	uploadDir       string
 	activityService service.ActivityService
 }
 
-func NewMemberHandler(service service.MemberService, salaryService service.SalaryService, uploadDir string, activityService service.ActivityService) *MemberHandler {
+func NewMemberHandler(service service.MemberService, salaryService service.SalaryService, documentService service.MemberDocumentService, uploadDir string, activityService service.ActivityService) *MemberHandler {
 	return &MemberHandler{
 		service:         service,
 		salaryService:   salaryService,
+		documentService: documentService,
 		uploadDir:       uploadDir,
 		activityService: activityService,
 	}
@@ -66,7 +68,8 @@ func (h *MemberHandler) CreateMember(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusUnauthorized, err)
 	}
-	var member entity.Member
+	// BPJS default ikut; false dari body tetap tersimpan.
+	member := entity.Member{BPJSEmployment: true, BPJSHealth: true}
 
 	contentType := c.Request().Header.Get("Content-Type")
 	if contentType != "" && len(contentType) >= 19 && contentType[:19] == "multipart/form-data" {
@@ -236,7 +239,13 @@ func (h *MemberHandler) saveUploadedFile(file *multipart.FileHeader, dstPath str
 	return err
 }
 
+// AddDocument POST /api/members/:id/documents (multipart "files"). Field opsional doc_type, number,
+// issue_date, expiry_date dan notes berlaku untuk semua file yang diunggah.
 func (h *MemberHandler) AddDocument(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
 	id := c.Param("id")
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -246,47 +255,52 @@ func (h *MemberHandler) AddDocument(c echo.Context) error {
 	if len(files) == 0 {
 		return response.Error(c, http.StatusBadRequest, errors.New("no files uploaded"))
 	}
-	var fileNames []string
+	optional := func(name string) *string {
+		if v := c.FormValue(name); v != "" {
+			return &v
+		}
+		return nil
+	}
+	fileNames := []string{}
+	docs := []entity.MemberDocument{}
 	for _, file := range files {
 		fileExt := filepath.Ext(file.Filename)
 		fileName := uuid.New().String() + fileExt
+		doc := entity.MemberDocument{
+			MemberID:   id,
+			DocType:    c.FormValue("doc_type"),
+			Number:     c.FormValue("number"),
+			IssueDate:  optional("issue_date"),
+			ExpiryDate: optional("expiry_date"),
+			FileURL:    fileName,
+			Notes:      c.FormValue("notes"),
+		}
 		dstPath := filepath.Join(h.uploadDir, fileName)
 		if err := h.saveUploadedFile(file, dstPath); err != nil {
 			return response.Error(c, http.StatusInternalServerError, err)
 		}
+		if err := h.documentService.Save(userID, &doc); err != nil {
+			os.Remove(dstPath)
+			return memberDocumentError(c, err)
+		}
 		fileNames = append(fileNames, fileName)
+		docs = append(docs, doc)
 	}
-	member, err := h.service.GetMemberByID(id)
-	if err != nil {
-		return response.Error(c, http.StatusNotFound, err)
-	}
-	var documents []string
-	json.Unmarshal(member.Documents, &documents)
-	documents = append(documents, fileNames...)
-	documentsJSON, _ := json.Marshal(documents)
-	member.Documents = documentsJSON
-	return h.service.UpdateMember(member)
+	return response.Success(c, http.StatusCreated, map[string]interface{}{"files": fileNames, "documents": docs})
 }
 
 func (h *MemberHandler) DeleteDocument(c echo.Context) error {
-	id := c.Param("id")
-	fileName := c.Param("fileName")
-	member, err := h.service.GetMemberByID(id)
+	userID, err := appmiddleware.CurrentUserID(c)
 	if err != nil {
-		return response.Error(c, http.StatusNotFound, err)
+		return response.Error(c, http.StatusUnauthorized, err)
 	}
-	var documents []string
-	json.Unmarshal(member.Documents, &documents)
-	newDocs := make([]string, 0)
-	for _, doc := range documents {
-		if doc != fileName {
-			newDocs = append(newDocs, doc)
-		}
+	id := c.Param("id")
+	fileName := filepath.Base(c.Param("fileName"))
+	if err := h.documentService.DeleteFile(id, fileName, userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
 	}
-	documentsJSON, _ := json.Marshal(newDocs)
-	member.Documents = documentsJSON
 	os.Remove(filepath.Join(h.uploadDir, fileName))
-	return h.service.UpdateMember(member)
+	return response.Success(c, http.StatusNoContent, nil)
 }
 
 func (h *MemberHandler) DeactivateMember(c echo.Context) error {
@@ -356,16 +370,16 @@ func (h *MemberHandler) GetAllMembersTotalSalary(c echo.Context) error {
 
 func (h *MemberHandler) GetMemberTotalSalaryWithFilter(c echo.Context) error {
 	memberID := c.Param("id")
-	year := c.QueryParam("year")
-	month := c.QueryParam("month")
-	total, err := h.service.GetMemberTotalSalaryWithFilter(memberID, year, month)
+	year, month, quarter := c.QueryParam("year"), c.QueryParam("month"), c.QueryParam("quarter")
+	total, err := h.service.GetMemberTotalSalaryWithFilter(memberID, year, month, quarter)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryPeriodError(c, err)
 	}
 	return response.Success(c, http.StatusOK, map[string]interface{}{
 		"member_id":    memberID,
 		"year":         year,
 		"month":        month,
+		"quarter":      quarter,
 		"total_salary": total,
 	})
 }
@@ -375,15 +389,15 @@ func (h *MemberHandler) GetAllMembersTotalSalaryWithFilter(c echo.Context) error
 	if err != nil {
 		return response.Error(c, http.StatusUnauthorized, err)
 	}
-	year := c.QueryParam("year")
-	month := c.QueryParam("month")
-	total, err := h.service.GetAllMembersTotalSalaryWithFilter(userID, year, month)
+	year, month, quarter := c.QueryParam("year"), c.QueryParam("month"), c.QueryParam("quarter")
+	total, err := h.service.GetAllMembersTotalSalaryWithFilter(userID, year, month, quarter)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryPeriodError(c, err)
 	}
 	return response.Success(c, http.StatusOK, map[string]interface{}{
 		"year":         year,
 		"month":        month,
+		"quarter":      quarter,
 		"total_salary": total,
 	})
 }
@@ -393,25 +407,32 @@ func (h *MemberHandler) GetAllMembersWithSalaryInfo(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusUnauthorized, err)
 	}
-	year := c.QueryParam("year")
-	month := c.QueryParam("month")
+	year, month, quarter := c.QueryParam("year"), c.QueryParam("month"), c.QueryParam("quarter")
 	orderBy := c.QueryParam("order")
 	if orderBy != "asc" && orderBy != "desc" {
 		orderBy = "desc"
 	}
-	members, err := h.service.GetAllMembersWithSalaryInfo(userID, year, month, orderBy)
+	members, err := h.service.GetAllMembersWithSalaryInfo(userID, year, month, quarter, orderBy)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryPeriodError(c, err)
 	}
 	return response.Success(c, http.StatusOK, members)
 }
 
 func (h *MemberHandler) GetMemberMonthlySalaryDetails(c echo.Context) error {
 	memberID := c.Param("id")
-	year := c.QueryParam("year")
-	details, err := h.service.GetMemberMonthlySalaryDetails(memberID, year)
+	details, err := h.service.GetMemberMonthlySalaryDetails(memberID,
+		c.QueryParam("year"), c.QueryParam("month"), c.QueryParam("quarter"))
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryPeriodError(c, err)
 	}
 	return response.Success(c, http.StatusOK, details)
 }
+
+// salaryPeriodError 400 untuk filter periode yang tidak valid, selain itu 500.
+func salaryPeriodError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrSalaryPeriod) {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PayrollHandler payroll run bulanan: draft, review, finalisasi.
+type PayrollHandler struct {
+	service         service.PayrollService
+	activityService service.ActivityService
+}
+
+func NewPayrollHandler(service service.PayrollService, activityService service.ActivityService) *PayrollHandler {
+	return &PayrollHandler{service, activityService}
+}
+
+func payrollError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrPayrollMonth), errors.Is(err, service.ErrPayrollExpenseMode), errors.Is(err, service.ErrSalaryRateMissing):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrPayrollExists), errors.Is(err, service.ErrPayrollNotDraft):
+		return response.Error(c, http.StatusConflict, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
+func (h *PayrollHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	runs, err := h.service.List(userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, runs)
+}
+
+func (h *PayrollHandler) Get(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	run, err := h.service.Get(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, run)
+}
+
+// Create POST /api/payroll-runs {month, notes} — membuat draft untuk semua anggota aktif.
+func (h *PayrollHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body struct {
+		Month string `json:"month"`
+		Notes string `json:"notes"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	run, err := h.service.CreateDraft(userID, body.Month, body.Notes)
+	if err != nil {
+		return payrollError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, run)
+}
+
+// Refresh POST /api/payroll-runs/:id/refresh — hitung ulang draft setelah detail jam/kasbon berubah.
+func (h *PayrollHandler) Refresh(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.Get(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	run, err := h.service.Refresh(uint(id), userID)
+	if err != nil {
+		return payrollError(c, err)
+	}
+	return response.Success(c, http.StatusOK, run)
+}
+
+// Finalize POST /api/payroll-runs/:id/finalize {expense_mode: single|per_member, payment_date}.
+func (h *PayrollHandler) Finalize(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.Get(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	var body struct {
+		ExpenseMode string `json:"expense_mode"`
+		PaymentDate string `json:"payment_date"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	run, err := h.service.Finalize(uint(id), userID, body.ExpenseMode, body.PaymentDate)
+	if err != nil {
+		return payrollError(c, err)
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityExpense, "Finalisasi Payroll",
+		fmt.Sprintf("Payroll %s difinalisasi: %d anggota, total Rp %.0f", run.Month, run.MemberCount, run.TotalNet))
+	return response.Success(c, http.StatusOK, run)
+}
+
+func (h *PayrollHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.Get(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		return payrollError(c, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PayslipHandler slip gaji PDF per Salary dan ekspor ZIP per bulan.
+type PayslipHandler struct {
+	service         service.PayslipService
+	activityService service.ActivityService
+}
+
+func NewPayslipHandler(service service.PayslipService, activityService service.ActivityService) *PayslipHandler {
+	return &PayslipHandler{service, activityService}
+}
+
+func sendFile(c echo.Context, contentType string, slip *service.Payslip) error {
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", slip.FileName))
+	return c.Blob(http.StatusOK, contentType, slip.Data)
+}
+
+// Download GET /api/salaries/:id/payslip — unduh PDF tanpa menyimpan.
+func (h *PayslipHandler) Download(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	slip, err := h.service.Render(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return sendFile(c, "application/pdf", slip)
+}
+
+// Store POST /api/salaries/:id/payslip — buat PDF dan lampirkan ke dokumen gaji.
+func (h *PayslipHandler) Store(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	salary, slip, err := h.service.Store(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	_ = h.activityService.LogActivity(userID, entity.ActivityUpdate, "Slip Gaji",
+		fmt.Sprintf("Slip gaji %s dibuat", slip.FileName))
+	return response.Success(c, http.StatusOK, map[string]interface{}{
+		"file_name": slip.FileName,
+		"salary":    salary,
+	})
+}
+
+// Export GET /api/salaries/payslips/export?month=YYYY-MM — ZIP slip gaji semua anggota.
+func (h *PayslipHandler) Export(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	archive, err := h.service.ExportMonth(userID, c.QueryParam("month"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPayrollMonth):
+			return response.Error(c, http.StatusBadRequest, err)
+		case errors.Is(err, service.ErrPayslipEmpty):
+			return response.Error(c, http.StatusNotFound, err)
+		}
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return sendFile(c, "application/zip", archive)
+}
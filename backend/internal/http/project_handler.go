@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"dashboardadminimb/pkg/response"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type ProjectHandler struct {
@@ -34,11 +36,19 @@ func (h *ProjectHandler) CreateProject(c echo.Context) error {
 	}
 
 	if err := h.service.CreateProject(userID, &project); err != nil {
+		if errors.Is(err, service.ErrProjectCustomerNotFound) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityIncome, "Proyek Baru",
 		fmt.Sprintf("Proyek %s dimulai", project.Name))
-	return response.Success(c, http.StatusCreated, project)
+	created, err := h.service.GetProjectByID(project.ID, userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	setVersionETag(c, created.Version)
+	return response.Success(c, http.StatusCreated, created)
 }
 
 func (h *ProjectHandler) GetAllProjects(c echo.Context) error {
@@ -77,6 +87,7 @@ func (h *ProjectHandler) GetProjectByID(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusNotFound, err)
 	}
+	setVersionETag(c, project.Version)
 	return response.Success(c, http.StatusOK, project)
 }
 
@@ -93,12 +104,67 @@ func (h *ProjectHandler) UpdateProject(c echo.Context) error {
 	}
 	project.ID = uint(id)
 	project.UserID = userID
-	if err := h.service.UpdateProject(&project); err != nil {
+	expected, code, err := expectedVersion(c, project.Version)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	if err := h.service.UpdateProject(&project, expected); err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			current, err := h.service.GetProjectByID(project.ID, userID)
+			if err != nil {
+				return response.Error(c, http.StatusInternalServerError, err)
+			}
+			return versionConflict(c, current.Version, current)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Error(c, http.StatusNotFound, err)
+		}
+		if errors.Is(err, service.ErrProjectCustomerNotFound) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityUpdate, "Update Project",
 		fmt.Sprintf("Update Project : %s", project.Name))
-	return response.Success(c, http.StatusOK, project)
+	// Sinkronisasi laporan harian ikut menaikkan versi proyek, jadi versi di struct yang disimpan sudah usang.
+	updated, err := h.service.GetProjectByID(project.ID, userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	setVersionETag(c, updated.Version)
+	return response.Success(c, http.StatusOK, updated)
+}
+
+// MergeDailyReports PATCH /api/projects/:id/reports/daily — body {"entries": [{"date", "version"?, field yang diubah...}]}.
+// Entri dengan version usang tidak disimpan dan dikembalikan sebagai conflict beserta baris terbaru.
+func (h *ProjectHandler) MergeDailyReports(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := h.service.GetProjectByID(uint(id), userID); err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	var req struct {
+		Entries []service.DailyReportPatch `json:"entries"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	results, err := h.service.MergeDailyReports(uint(id), req.Entries)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	project, err := h.service.GetProjectByID(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	setVersionETag(c, project.Version)
+	return response.Success(c, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"project": project,
+	})
 }
 
 func (h *ProjectHandler) DeleteProject(c echo.Context) error {
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ProjectMilestoneHandler termin pembayaran proyek.
+type ProjectMilestoneHandler struct {
+	service service.ProjectMilestoneService
+}
+
+func NewProjectMilestoneHandler(service service.ProjectMilestoneService) *ProjectMilestoneHandler {
+	return &ProjectMilestoneHandler{service}
+}
+
+// milestoneError memetakan error validasi termin ke 400/409, sisanya 404 (proyek/termin tidak ada) atau 500.
+func milestoneError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrMilestoneInvalid), errors.Is(err, service.ErrMilestonePercentExceeded),
+		errors.Is(err, service.ErrMilestoneCustomerRequired), errors.Is(err, service.ErrMilestoneTemplateRequired):
+		return response.Error(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrMilestoneNotReached), errors.Is(err, service.ErrMilestoneAlreadyInvoiced):
+		return response.Error(c, http.StatusConflict, err)
+	}
+	return response.Error(c, http.StatusNotFound, err)
+}
+
+func milestoneID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("milestoneId"), 10, 32)
+	if err != nil {
+		return 0, errors.New("Invalid milestone ID")
+	}
+	return uint(id), nil
+}
+
+// List GET /api/projects/:id/milestones — termin dengan status terbaru (reached dievaluasi dari progress).
+func (h *ProjectMilestoneHandler) List(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	milestones, err := h.service.List(projectID, userID)
+	if err != nil {
+		return milestoneError(c, err)
+	}
+	return response.Success(c, http.StatusOK, milestones)
+}
+
+// Create POST /api/projects/:id/milestones — otomatis membuat ProjectIncome berstatus Planned.
+func (h *ProjectMilestoneHandler) Create(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	var m entity.ProjectMilestone
+	if err := c.Bind(&m); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.Create(projectID, userID, &m); err != nil {
+		return milestoneError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, m)
+}
+
+func (h *ProjectMilestoneHandler) Update(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	id, err := milestoneID(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	var m entity.ProjectMilestone
+	if err := c.Bind(&m); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.Update(projectID, userID, id, &m); err != nil {
+		return milestoneError(c, err)
+	}
+	return response.Success(c, http.StatusOK, m)
+}
+
+func (h *ProjectMilestoneHandler) Delete(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	id, err := milestoneID(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.Delete(projectID, userID, id); err != nil {
+		return milestoneError(c, err)
+	}
+	return response.Success(c, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+// CreateInvoice POST /api/projects/:id/milestones/:milestoneId/invoice — draft invoice untuk termin yang tercapai.
+func (h *ProjectMilestoneHandler) CreateInvoice(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	id, err := milestoneID(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	var req entity.MilestoneInvoiceRequest
+	_ = c.Bind(&req)
+	inv, err := h.service.CreateInvoiceDraft(projectID, userID, id, req)
+	if err != nil {
+		return milestoneError(c, err)
+	}
+	return response.Success(c, http.StatusCreated, inv)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ProjectReportHandler rekap mingguan/bulanan dan kurva S yang dihitung dari laporan harian.
+type ProjectReportHandler struct {
+	service service.ProjectReportService
+}
+
+func NewProjectReportHandler(service service.ProjectReportService) *ProjectReportHandler {
+	return &ProjectReportHandler{service}
+}
+
+func projectParams(c echo.Context) (projectID, userID uint, code int, err error) {
+	userID, err = appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return 0, 0, http.StatusUnauthorized, err
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, 0, http.StatusBadRequest, errors.New("Invalid project ID")
+	}
+	return uint(id), userID, 0, nil
+}
+
+func (h *ProjectReportHandler) GetWeekly(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	reports, err := h.service.GetWeeklyReports(projectID, userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, reports)
+}
+
+func (h *ProjectReportHandler) GetMonthly(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	reports, err := h.service.GetMonthlyReports(projectID, userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, reports)
+}
+
+// GetSCurve ?interval=daily|weekly|monthly (default daily).
+func (h *ProjectReportHandler) GetSCurve(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	curve, err := h.service.GetSCurve(projectID, userID, c.QueryParam("interval"))
+	if errors.Is(err, service.ErrInvalidInterval) {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, curve)
+}
+
+// parseAsOf ?asOf=YYYY-MM-DD, default hari ini.
+func parseAsOf(c echo.Context) (time.Time, error) {
+	if v := c.QueryParam("asOf"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return t, errors.New("asOf harus YYYY-MM-DD")
+		}
+		return t, nil
+	}
+	return time.Now(), nil
+}
+
+// GetEarnedValue PV/EV/AC, SPI/CPI/EAC/ETC dan proyeksi tanggal selesai. ?asOf=YYYY-MM-DD (default hari ini).
+func (h *ProjectReportHandler) GetEarnedValue(c echo.Context) error {
+	projectID, userID, code, err := projectParams(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	ev, err := h.service.GetEarnedValue(projectID, userID, asOf)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, ev)
+}
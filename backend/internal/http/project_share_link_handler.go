@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/service"
 	"dashboardadminimb/pkg/response"
 
@@ -68,6 +69,7 @@ func (h *ProjectShareLinkHandler) GetSharedProject(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusNotFound, err)
 	}
+	setVersionETag(c, project.Version)
 	return response.Success(c, http.StatusOK, map[string]interface{}{
 		"project":  project,
 		"settings": json.RawMessage(link.Settings),
@@ -94,12 +96,31 @@ func (h *ProjectShareLinkHandler) UpdateSharedSettings(c echo.Context) error {
 	})
 }
 
+// editableLink link dari :token yang boleh dipakai untuk edit (edit_token cocok atau settings.allowEdit).
+func (h *ProjectShareLinkHandler) editableLink(c echo.Context) (*entity.ProjectShareLink, int, error) {
+	link, err := h.shareService.GetByToken(c.Param("token"))
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	allowEdit := false
+	var settingsMap map[string]interface{}
+	if err := json.Unmarshal([]byte(link.Settings), &settingsMap); err == nil {
+		if v, ok := settingsMap["allowEdit"].(bool); ok {
+			allowEdit = v
+		}
+	}
+	if link.EditToken != c.QueryParam("edit_token") && !allowEdit {
+		return nil, http.StatusForbidden, errors.New("edit is not allowed")
+	}
+	return link, 0, nil
+}
+
 // PUT /api/public/projects/shared/:token/reports  (public with edit_token or allowEdit)
+// Wajib If-Match atau field version (versi proyek terakhir dibaca); versi usang → 409 dengan proyek terbaru.
 func (h *ProjectShareLinkHandler) UpdateSharedReports(c echo.Context) error {
-	token := c.Param("token")
-	editToken := c.QueryParam("edit_token")
 	var req struct {
 		Reports json.RawMessage `json:"reports"`
+		Version uint            `json:"version"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
@@ -107,20 +128,13 @@ func (h *ProjectShareLinkHandler) UpdateSharedReports(c echo.Context) error {
 	if len(req.Reports) == 0 {
 		return response.Error(c, http.StatusBadRequest, errors.New("reports is required"))
 	}
-	link, err := h.shareService.GetByToken(token)
+	link, code, err := h.editableLink(c)
 	if err != nil {
-		return response.Error(c, http.StatusNotFound, err)
+		return response.Error(c, code, err)
 	}
-
-	allowEdit := false
-	var settingsMap map[string]interface{}
-	if err := json.Unmarshal([]byte(link.Settings), &settingsMap); err == nil {
-		if v, ok := settingsMap["allowEdit"].(bool); ok {
-			allowEdit = v
-		}
-	}
-	if link.EditToken != editToken && !allowEdit {
-		return response.Error(c, http.StatusForbidden, errors.New("edit is not allowed"))
+	expected, code, err := expectedVersion(c, req.Version)
+	if err != nil {
+		return response.Error(c, code, err)
 	}
 
 	project, err := h.projectService.GetProjectByIDAdmin(link.ProjectID)
@@ -128,14 +142,50 @@ func (h *ProjectShareLinkHandler) UpdateSharedReports(c echo.Context) error {
 		return response.Error(c, http.StatusNotFound, err)
 	}
 	project.Reports = datatypes.JSON(req.Reports)
-	if err := h.projectService.UpdateProject(project); err != nil {
+	if err := h.projectService.UpdateProject(project, expected); err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			current, err := h.projectService.GetProjectByIDAdmin(link.ProjectID)
+			if err != nil {
+				return response.Error(c, http.StatusInternalServerError, err)
+			}
+			return versionConflict(c, current.Version, current)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	updated, err := h.projectService.GetProjectByIDAdmin(link.ProjectID)
 	if err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
+	setVersionETag(c, updated.Version)
+	return response.Success(c, http.StatusOK, map[string]interface{}{
+		"project": updated,
+	})
+}
+
+// PATCH /api/public/projects/shared/:token/reports/daily  (public with edit_token or allowEdit)
+// Merge per tanggal seperti PATCH /api/projects/:id/reports/daily.
+func (h *ProjectShareLinkHandler) MergeSharedDailyReports(c echo.Context) error {
+	var req struct {
+		Entries []service.DailyReportPatch `json:"entries"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	link, code, err := h.editableLink(c)
+	if err != nil {
+		return response.Error(c, code, err)
+	}
+	results, err := h.projectService.MergeDailyReports(link.ProjectID, req.Entries)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	updated, err := h.projectService.GetProjectByIDAdmin(link.ProjectID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	setVersionETag(c, updated.Version)
 	return response.Success(c, http.StatusOK, map[string]interface{}{
+		"results": results,
 		"project": updated,
 	})
 }
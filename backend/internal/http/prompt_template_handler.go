@@ -0,0 +1,143 @@
+package http
+
+import (
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/prompt"
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PromptTemplateHandler struct {
+	service service.PromptTemplateService
+}
+
+func NewPromptTemplateHandler(service service.PromptTemplateService) *PromptTemplateHandler {
+	return &PromptTemplateHandler{service: service}
+}
+
+// List versi prompt. Query: use_case, provider.
+func (h *PromptTemplateHandler) List(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	list, err := h.service.GetAll(userID, c.QueryParam("use_case"), c.QueryParam("provider"))
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
+
+func (h *PromptTemplateHandler) GetByID(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	item, err := h.service.GetByID(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, item)
+}
+
+// Create menyimpan versi baru. Body: use_case, provider (default "*"), body (Go template), notes, activate (bool).
+func (h *PromptTemplateHandler) Create(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	var body struct {
+		UseCase  string `json:"use_case"`
+		Provider string `json:"provider"`
+		Body     string `json:"body"`
+		Notes    string `json:"notes"`
+		Activate bool   `json:"activate"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	tpl := entity.PromptTemplate{UseCase: body.UseCase, Provider: body.Provider, Body: body.Body, Notes: body.Notes}
+	if err := h.service.Create(userID, &tpl, body.Activate); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Success(c, http.StatusCreated, tpl)
+}
+
+// Activate menjadikan versi ini aktif untuk use case/provider-nya.
+func (h *PromptTemplateHandler) Activate(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	tpl, err := h.service.Activate(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, tpl)
+}
+
+// Render preview prompt dengan data contoh dari body (quantity_unit, use_bbm, column_descriptions, ocr_text, layout, bad_json).
+func (h *PromptTemplateHandler) Render(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	tpl, err := h.service.GetByID(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	var body struct {
+		QuantityUnit       string   `json:"quantity_unit"`
+		UseBBM             bool     `json:"use_bbm"`
+		ColumnDescriptions []string `json:"column_descriptions"`
+		OCRText            string   `json:"ocr_text"`
+		Layout             string   `json:"layout"`
+		BadJSON            string   `json:"bad_json"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	text, err := prompt.Render(tpl.Body, prompt.Data(body))
+	if err != nil {
+		return response.Error(c, http.StatusUnprocessableEntity, err)
+	}
+	return response.Success(c, http.StatusOK, map[string]interface{}{"prompt": text, "version": tpl.Version})
+}
+
+func (h *PromptTemplateHandler) Delete(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.service.Delete(uint(id), userID); err != nil {
+		if errors.Is(err, service.ErrPromptTemplateNotFound) {
+			return response.Error(c, http.StatusNotFound, err)
+		}
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Success(c, http.StatusNoContent, nil)
+}
+
+// ExtractionLogs riwayat ekstraksi beserta versi prompt yang dipakai. Query: use_case, limit (maks 200).
+func (h *PromptTemplateHandler) ExtractionLogs(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	list, err := h.service.GetExtractionLogs(userID, c.QueryParam("use_case"), limit)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, list)
+}
@@ -27,6 +27,14 @@ type SalaryHandler struct {
 	activityService service.ActivityService
 }
 
+// salaryCalcError ErrSalaryRateMissing = 400 (tarif perlu diisi dulu), selain itu 500.
+func salaryCalcError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrSalaryRateMissing) {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	return response.Error(c, http.StatusInternalServerError, err)
+}
+
 func NewSalaryHandler(service service.SalaryService, memberService service.MemberService, uploadDir string, detailService service.SalaryDetailService, activityService service.ActivityService) *SalaryHandler {
 	return &SalaryHandler{
 		service:         service,
@@ -53,6 +61,9 @@ func (h *SalaryHandler) CreateSalary(c echo.Context) error {
 	}
 	salary.MemberID = memberID
 	if err := h.service.CreateSalary(&salary); err != nil {
+		if errors.Is(err, service.ErrSalaryMonth) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityIncome, "Update Gaji",
@@ -104,13 +115,20 @@ func (h *SalaryHandler) UpdateSalary(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusNotFound, err)
 	}
-	originalMemberID := existingSalary.MemberID
+	if existingSalary.Locked {
+		return response.Error(c, http.StatusConflict, service.ErrSalaryLocked)
+	}
+	originalMemberID, runID := existingSalary.MemberID, existingSalary.PayrollRunID
 	if err := c.Bind(existingSalary); err != nil {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
 	existingSalary.MemberID = originalMemberID
 	existingSalary.ID = uint(salaryID)
+	existingSalary.PayrollRunID, existingSalary.Locked = runID, false
 	if err := h.service.UpdateSalary(existingSalary); err != nil {
+		if errors.Is(err, service.ErrSalaryMonth) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	member, err := h.memberService.GetMemberByID(originalMemberID)
@@ -122,6 +140,28 @@ func (h *SalaryHandler) UpdateSalary(c echo.Context) error {
 	return response.Success(c, http.StatusOK, existingSalary)
 }
 
+// RecalculateSalary POST /api/salaries/:id/recalculate — hitung ulang gaji, termasuk detail dari absensi yang disetujui.
+func (h *SalaryHandler) RecalculateSalary(c echo.Context) error {
+	salaryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errors.New("invalid salary ID"))
+	}
+	if err := h.service.EnsureEditable(uint(salaryID)); err != nil {
+		if errors.Is(err, service.ErrSalaryLocked) {
+			return response.Error(c, http.StatusConflict, err)
+		}
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	if err := h.service.RecalculateSalary(uint(salaryID)); err != nil {
+		return salaryCalcError(c, err)
+	}
+	salary, err := h.service.GetSalaryByID(uint(salaryID))
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, salary)
+}
+
 func (h *SalaryHandler) DeleteSalary(c echo.Context) error {
 	userID, err := appmiddleware.CurrentUserID(c)
 	if err != nil {
@@ -133,6 +173,9 @@ func (h *SalaryHandler) DeleteSalary(c echo.Context) error {
 		return response.Error(c, http.StatusNotFound, err)
 	}
 	if err := h.service.DeleteSalary(uint(id)); err != nil {
+		if errors.Is(err, service.ErrSalaryLocked) {
+			return response.Error(c, http.StatusConflict, err)
+		}
 		return response.Error(c, http.StatusNotFound, err)
 	}
 	_ = h.activityService.LogActivity(userID, entity.ActivityExpense, "Hapus Gaji",
@@ -170,9 +213,7 @@ func (h *SalaryHandler) UploadDocuments(c echo.Context) error {
 	var documents []string
 	json.Unmarshal(salary.Documents, &documents)
 	documents = append(documents, fileNames...)
-	documentsJSON, _ := json.Marshal(documents)
-	salary.Documents = documentsJSON
-	if err := h.service.UpdateSalary(salary); err != nil {
+	if err := h.service.UpdateDocuments(salary, documents); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	return response.Success(c, http.StatusOK, salary)
@@ -200,11 +241,9 @@ func (h *SalaryHandler) DeleteDocument(c echo.Context) error {
 			newDocs = append(newDocs, doc)
 		}
 	}
-	documentsJSON, _ := json.Marshal(newDocs)
-	salary.Documents = documentsJSON
 	filePath := filepath.Join(h.uploadDir, fileName)
 	os.Remove(filePath)
-	if err := h.service.UpdateSalary(salary); err != nil {
+	if err := h.service.UpdateDocuments(salary, newDocs); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	member, err := h.memberService.GetMemberByID(salary.MemberID)
@@ -247,11 +286,14 @@ func (h *SalaryHandler) CreateSalaryDetail(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
 	detail.SalaryID = uint(salaryID)
+	if err := h.service.EnsureEditable(detail.SalaryID); err != nil {
+		return response.Error(c, http.StatusConflict, err)
+	}
 	if err := h.detailService.CreateDetail(&detail); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryCalcError(c, err)
 	}
 	if err := h.service.RecalculateSalary(detail.SalaryID); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryCalcError(c, err)
 	}
 	salary, err := h.service.GetSalaryByID(detail.SalaryID)
 	if err == nil {
@@ -290,11 +332,17 @@ func (h *SalaryHandler) UpdateSalaryDetail(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err)
 	}
 	detail.SalaryID = uint(salaryID)
+	if err := h.service.EnsureEditable(detail.SalaryID); err != nil {
+		return response.Error(c, http.StatusConflict, err)
+	}
 	if err := h.detailService.UpdateDetail(&detail); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		if errors.Is(err, service.ErrSalaryDetailGenerated) {
+			return response.Error(c, http.StatusConflict, err)
+		}
+		return salaryCalcError(c, err)
 	}
 	if err := h.service.RecalculateSalary(detail.SalaryID); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryCalcError(c, err)
 	}
 	salary, err := h.service.GetSalaryByID(detail.SalaryID)
 	if err == nil {
@@ -320,11 +368,17 @@ func (h *SalaryHandler) DeleteSalaryDetail(c echo.Context) error {
 	if err != nil {
 		return response.Error(c, http.StatusNotFound, err)
 	}
+	if err := h.service.EnsureEditable(detail.SalaryID); err != nil {
+		return response.Error(c, http.StatusConflict, err)
+	}
 	if err := h.detailService.DeleteDetail(uint(detailID)); err != nil {
+		if errors.Is(err, service.ErrSalaryDetailGenerated) {
+			return response.Error(c, http.StatusConflict, err)
+		}
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 	if err := h.service.RecalculateSalary(detail.SalaryID); err != nil {
-		return response.Error(c, http.StatusInternalServerError, err)
+		return salaryCalcError(c, err)
 	}
 	salary, err := h.service.GetSalaryByID(detail.SalaryID)
 	if err == nil {
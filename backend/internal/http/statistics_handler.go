@@ -5,6 +5,7 @@ import (
 	appmiddleware "dashboardadminimb/pkg/middleware"
 	"dashboardadminimb/pkg/response"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -13,10 +14,11 @@ type StatisticsHandler struct {
 	financeSvc service.FinanceService
 	memberSvc  service.MemberService
 	projectSvc service.ProjectService
+	reportSvc  service.ProjectReportService
 }
 
-func NewStatisticsHandler(f service.FinanceService, m service.MemberService, p service.ProjectService) *StatisticsHandler {
-	return &StatisticsHandler{f, m, p}
+func NewStatisticsHandler(f service.FinanceService, m service.MemberService, p service.ProjectService, r service.ProjectReportService) *StatisticsHandler {
+	return &StatisticsHandler{f, m, p, r}
 }
 
 func (h *StatisticsHandler) GetDashboardStats(c echo.Context) error {
@@ -40,10 +42,16 @@ func (h *StatisticsHandler) GetDashboardStats(c echo.Context) error {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 
+	earnedValue, err := h.reportSvc.GetEarnedValueSummaries(userID, time.Now())
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+
 	return response.Success(c, http.StatusOK, map[string]interface{}{
-		"total_income":  income,
-		"total_expense": expense,
-		"member_count":  memberCount,
-		"project_count": projectCount,
+		"total_income":   income,
+		"total_expense":  expense,
+		"member_count":   memberCount,
+		"project_count":  projectCount,
+		"project_health": earnedValue,
 	})
 }
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"dashboardadminimb/internal/service"
+	appmiddleware "dashboardadminimb/pkg/middleware"
+	"dashboardadminimb/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StatutoryHandler pengaturan PPh 21 / BPJS, rincian potongan per gaji dan rekap bulanan.
+type StatutoryHandler struct {
+	service service.StatutoryService
+}
+
+func NewStatutoryHandler(service service.StatutoryService) *StatutoryHandler {
+	return &StatutoryHandler{service}
+}
+
+func (h *StatutoryHandler) GetSetting(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	setting, err := h.service.GetSetting(userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, setting)
+}
+
+// SaveSetting PUT /api/statutory/settings — berlaku untuk perhitungan ulang gaji berikutnya.
+func (h *StatutoryHandler) SaveSetting(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	setting, err := h.service.GetSetting(userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	if err := c.Bind(setting); err != nil {
+		return response.Error(c, http.StatusBadRequest, err)
+	}
+	if err := h.service.SaveSetting(userID, setting); err != nil {
+		if errors.Is(err, service.ErrTaxSettingInvalid) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, setting)
+}
+
+// Lines GET /api/statutory/salaries/:id — baris PPh 21 & BPJS satu gaji.
+func (h *StatutoryHandler) Lines(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	lines, err := h.service.Lines(uint(id), userID)
+	if err != nil {
+		return response.Error(c, http.StatusNotFound, err)
+	}
+	return response.Success(c, http.StatusOK, lines)
+}
+
+// Recap GET /api/statutory/recap?month=YYYY-MM
+func (h *StatutoryHandler) Recap(c echo.Context) error {
+	userID, err := appmiddleware.CurrentUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, err)
+	}
+	recap, err := h.service.Recap(userID, c.QueryParam("month"))
+	if err != nil {
+		if errors.Is(err, service.ErrPayrollMonth) {
+			return response.Error(c, http.StatusBadRequest, err)
+		}
+		return response.Error(c, http.StatusInternalServerError, err)
+	}
+	return response.Success(c, http.StatusOK, recap)
+}
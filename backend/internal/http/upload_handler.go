@@ -37,8 +37,14 @@ var allowedExtensions = map[string]bool{
 	".xlsx": true,
 }
 
+// uploadFolders subfolder yang boleh dipilih lewat form field "folder".
+var uploadFolders = map[string]bool{
+	"finance":             true,
+	"equipment-documents": true,
+}
+
 // UploadFile accepts a multipart file (field name: "file"), saves it under
-// <uploadDir>/finance/, and returns the public URL.
+// <uploadDir>/<folder>/ (form field "folder", default finance), and returns the public URL.
 //
 // POST /api/uploads/file
 func (h *UploadHandler) UploadFile(c echo.Context) error {
@@ -54,8 +60,16 @@ func (h *UploadHandler) UploadFile(c echo.Context) error {
 			fmt.Errorf("unsupported file type: %s", ext))
 	}
 
+	folder := c.FormValue("folder")
+	if folder == "" {
+		folder = "finance"
+	}
+	if !uploadFolders[folder] {
+		return response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported folder: %s", folder))
+	}
+
 	// Create subdirectory
-	subDir := filepath.Join(h.uploadDir, "finance")
+	subDir := filepath.Join(h.uploadDir, folder)
 	if err := os.MkdirAll(subDir, os.ModePerm); err != nil {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
@@ -82,9 +96,9 @@ func (h *UploadHandler) UploadFile(c echo.Context) error {
 		return response.Error(c, http.StatusInternalServerError, err)
 	}
 
-	// Build public URL: <baseURL>/api/uploads/finance/<filename>
+	// Build public URL: <baseURL>/api/uploads/<folder>/<filename>
 	baseURL := strings.TrimRight(h.baseURL, "/")
-	publicURL := fmt.Sprintf("%s/api/uploads/finance/%s", baseURL, fileName)
+	publicURL := fmt.Sprintf("%s/api/uploads/%s/%s", baseURL, folder, fileName)
 
 	return response.Success(c, http.StatusOK, map[string]string{
 		"url":      publicURL,
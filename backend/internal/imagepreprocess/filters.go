@@ -0,0 +1,301 @@
+package imagepreprocess
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// toGray konversi ke *image.Gray dengan origin (0,0). Jika sudah Gray, dikembalikan apa adanya.
+func toGray(img image.Image) *image.Gray {
+	if g, ok := img.(*image.Gray); ok && g.Bounds().Min == (image.Point{}) {
+		return g
+	}
+	b := img.Bounds()
+	dst := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// contrastStretch memetakan ulang intensitas secara linear sehingga percentile clip..1-clip jadi 0..255.
+func contrastStretch(g *image.Gray, clip float64) *image.Gray {
+	var hist [256]int
+	for _, v := range g.Pix {
+		hist[v]++
+	}
+	total := len(g.Pix)
+	if total == 0 {
+		return g
+	}
+	cut := int(float64(total) * clip)
+	lo, hi := 0, 255
+	for acc := 0; lo < 255; lo++ {
+		acc += hist[lo]
+		if acc > cut {
+			break
+		}
+	}
+	for acc := 0; hi > 0; hi-- {
+		acc += hist[hi]
+		if acc > cut {
+			break
+		}
+	}
+	if hi <= lo {
+		return g
+	}
+	var lut [256]uint8
+	scale := 255.0 / float64(hi-lo)
+	for i := 0; i < 256; i++ {
+		v := float64(i-lo) * scale
+		lut[i] = clampByte(v)
+	}
+	dst := image.NewGray(g.Rect)
+	for i, v := range g.Pix {
+		dst.Pix[i] = lut[v]
+	}
+	return dst
+}
+
+// adaptiveThreshold binarisasi Bradley-Roth: piksel hitam jika < (1-t) × rata-rata jendela sekitarnya.
+// Tahan bayangan dan pencahayaan tidak rata pada foto kertas kusut.
+func adaptiveThreshold(g *image.Gray, t float64) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	if w == 0 || h == 0 {
+		return g
+	}
+	win := w / 16
+	if win < 15 {
+		win = 15
+	}
+	half := win / 2
+	// integral image (w+1)x(h+1)
+	integ := make([]int64, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		var row int64
+		for x := 0; x < w; x++ {
+			row += int64(g.Pix[y*g.Stride+x])
+			integ[(y+1)*(w+1)+x+1] = integ[y*(w+1)+x+1] + row
+		}
+	}
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := maxInt(0, y-half), minInt(h, y+half+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := maxInt(0, x-half), minInt(w, x+half+1)
+			count := int64((x1 - x0) * (y1 - y0))
+			sum := integ[y1*(w+1)+x1] - integ[y0*(w+1)+x1] - integ[y1*(w+1)+x0] + integ[y0*(w+1)+x0]
+			if float64(int64(g.Pix[y*g.Stride+x])*count) < float64(sum)*(1-t) {
+				dst.Pix[y*dst.Stride+x] = 0
+			} else {
+				dst.Pix[y*dst.Stride+x] = 255
+			}
+		}
+	}
+	return dst
+}
+
+// medianFilter3 median 3x3: buang noise bintik (salt & pepper) tanpa mengaburkan tepi huruf.
+func medianFilter3(g *image.Gray) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	var win [9]uint8
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			n := 0
+			for dy := -1; dy <= 1; dy++ {
+				yy := clampInt(y+dy, 0, h-1)
+				for dx := -1; dx <= 1; dx++ {
+					xx := clampInt(x+dx, 0, w-1)
+					win[n] = g.Pix[yy*g.Stride+xx]
+					n++
+				}
+			}
+			// insertion sort 9 elemen
+			for i := 1; i < 9; i++ {
+				for j := i; j > 0 && win[j-1] > win[j]; j-- {
+					win[j-1], win[j] = win[j], win[j-1]
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = win[4]
+		}
+	}
+	return dst
+}
+
+// deskew mencari sudut kemiringan dalam ±maxDeg (langkah stepDeg) yang memaksimalkan variasi
+// projection profile horizontal piksel gelap (baris teks lurus = profil paling "tajam"), lalu memutar balik.
+func deskew(g *image.Gray, maxDeg, stepDeg float64) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	if w < 16 || h < 16 {
+		return g
+	}
+	// Kumpulkan koordinat piksel gelap (subsample agar cepat di gambar besar).
+	stride := 1
+	if w*h > 1_500_000 {
+		stride = 2
+	}
+	var xs, ys []float64
+	for y := 0; y < h; y += stride {
+		for x := 0; x < w; x += stride {
+			if g.Pix[y*g.Stride+x] < 128 {
+				xs = append(xs, float64(x))
+				ys = append(ys, float64(y))
+			}
+		}
+	}
+	if len(xs) < 100 {
+		return g
+	}
+	diag := int(math.Hypot(float64(w), float64(h))) + 2
+	bins := make([]float64, 2*diag)
+	bestScore, bestDeg := -1.0, 0.0
+	for deg := -maxDeg; deg <= maxDeg+1e-9; deg += stepDeg {
+		rad := deg * math.Pi / 180
+		sin, cos := math.Sin(rad), math.Cos(rad)
+		for i := range bins {
+			bins[i] = 0
+		}
+		for i := range xs {
+			yy := int(-xs[i]*sin+ys[i]*cos) + diag
+			if yy >= 0 && yy < len(bins) {
+				bins[yy]++
+			}
+		}
+		var score float64
+		for i := 1; i < len(bins); i++ {
+			d := bins[i] - bins[i-1]
+			score += d * d
+		}
+		if score > bestScore {
+			bestScore, bestDeg = score, deg
+		}
+	}
+	if math.Abs(bestDeg) < stepDeg/2 {
+		return g
+	}
+	return rotateGray(g, bestDeg)
+}
+
+// rotateGray memutar gambar sebesar deg derajat (positif = berlawanan jarum jam di koordinat gambar)
+// terhadap titik tengah, ukuran kanvas tetap; area kosong diisi putih.
+func rotateGray(g *image.Gray, deg float64) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	rad := deg * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	cx, cy := float64(w)/2, float64(h)/2
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// Inverse mapping: titik tujuan (x,y) berasal dari sumber yang diputar +deg.
+			fx, fy := float64(x)-cx, float64(y)-cy
+			sx := fx*cos - fy*sin + cx
+			sy := fx*sin + fy*cos + cy
+			ix, iy := int(math.Round(sx)), int(math.Round(sy))
+			if ix < 0 || iy < 0 || ix >= w || iy >= h {
+				dst.Pix[y*dst.Stride+x] = 255
+				continue
+			}
+			dst.Pix[y*dst.Stride+x] = g.Pix[iy*g.Stride+ix]
+		}
+	}
+	return dst
+}
+
+// cropBorders membuang pinggiran gelap (bayangan/meja di luar kertas, >60% piksel gelap per baris/kolom)
+// lalu margin putih kosong, menyisakan pad piksel di sekitar konten.
+func cropBorders(g *image.Gray, pad int) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	if w == 0 || h == 0 {
+		return g
+	}
+	rowDark := func(y, x0, x1 int) float64 {
+		n := 0
+		for x := x0; x < x1; x++ {
+			if g.Pix[y*g.Stride+x] < 128 {
+				n++
+			}
+		}
+		return float64(n) / float64(maxInt(1, x1-x0))
+	}
+	colDark := func(x, y0, y1 int) float64 {
+		n := 0
+		for y := y0; y < y1; y++ {
+			if g.Pix[y*g.Stride+x] < 128 {
+				n++
+			}
+		}
+		return float64(n) / float64(maxInt(1, y1-y0))
+	}
+	top, bottom, left, right := 0, h, 0, w
+	// 1) border gelap
+	for top < bottom-1 && rowDark(top, left, right) > 0.6 {
+		top++
+	}
+	for bottom-1 > top && rowDark(bottom-1, left, right) > 0.6 {
+		bottom--
+	}
+	for left < right-1 && colDark(left, top, bottom) > 0.6 {
+		left++
+	}
+	for right-1 > left && colDark(right-1, top, bottom) > 0.6 {
+		right--
+	}
+	// 2) margin putih (tanpa piksel gelap sama sekali)
+	for top < bottom-1 && rowDark(top, left, right) == 0 {
+		top++
+	}
+	for bottom-1 > top && rowDark(bottom-1, left, right) == 0 {
+		bottom--
+	}
+	for left < right-1 && colDark(left, top, bottom) == 0 {
+		left++
+	}
+	for right-1 > left && colDark(right-1, top, bottom) == 0 {
+		right--
+	}
+	top, left = maxInt(0, top-pad), maxInt(0, left-pad)
+	bottom, right = minInt(h, bottom+pad), minInt(w, right+pad)
+	if right-left < 8 || bottom-top < 8 {
+		return g
+	}
+	dst := image.NewGray(image.Rect(0, 0, right-left, bottom-top))
+	for y := top; y < bottom; y++ {
+		copy(dst.Pix[(y-top)*dst.Stride:(y-top)*dst.Stride+(right-left)], g.Pix[y*g.Stride+left:y*g.Stride+right])
+	}
+	return dst
+}
+
+func clampByte(v float64) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+package imagepreprocess
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Step satu tahap pipeline preprocessing sebelum OCR.
+type Step string
+
+const (
+	StepEXIF      Step = "exif"      // koreksi orientasi dari tag EXIF (foto HP)
+	StepGrayscale Step = "grayscale" // konversi ke grayscale
+	StepContrast  Step = "contrast"  // contrast stretch (percentile 1%..99%)
+	StepThreshold Step = "threshold" // binarisasi adaptif (Bradley, integral image)
+	StepDeskew    Step = "deskew"    // luruskan kemiringan via projection profile
+	StepCrop      Step = "crop"      // buang border gelap + margin putih
+	StepDenoise   Step = "denoise"   // median filter 3x3
+)
+
+// DefaultOCRSteps pipeline default untuk Tesseract: cocok untuk timesheet tulisan tangan yang kusut/berbayang.
+var DefaultOCRSteps = []Step{StepEXIF, StepGrayscale, StepContrast, StepDeskew, StepThreshold, StepDenoise, StepCrop}
+
+var knownSteps = map[Step]bool{
+	StepEXIF: true, StepGrayscale: true, StepContrast: true, StepThreshold: true,
+	StepDeskew: true, StepCrop: true, StepDenoise: true,
+}
+
+// Pipeline urutan step yang dijalankan pada satu gambar. Pipeline kosong = gambar dikembalikan apa adanya.
+type Pipeline struct {
+	Steps       []Step
+	MaxLongEdge int // >0: downscale dulu sebelum step lain agar deskew/threshold cepat
+}
+
+// ParseSteps membaca daftar step dipisah koma, mis. "exif,grayscale,threshold".
+// "" mengembalikan nil (pakai default pemanggil); "none" mengembalikan slice kosong (tanpa preprocessing);
+// "default" mengembalikan DefaultOCRSteps.
+func ParseSteps(s string) ([]Step, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return nil, nil
+	case "none", "off":
+		return []Step{}, nil
+	case "default":
+		return append([]Step{}, DefaultOCRSteps...), nil
+	}
+	var steps []Step
+	for _, part := range strings.Split(s, ",") {
+		st := Step(strings.TrimSpace(part))
+		if st == "" {
+			continue
+		}
+		if !knownSteps[st] {
+			return nil, fmt.Errorf("step preprocess tidak dikenal: %q", st)
+		}
+		steps = append(steps, st)
+	}
+	return steps, nil
+}
+
+// Has true jika pipeline memuat step tersebut.
+func (p Pipeline) Has(step Step) bool {
+	for _, s := range p.Steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Run menjalankan pipeline pada bytes gambar. Output PNG (lossless, penting untuk hasil binarisasi).
+func (p Pipeline) Run(data []byte, mimeType string) ([]byte, string, error) {
+	if len(p.Steps) == 0 {
+		return data, mimeType, nil
+	}
+	img, _, err := decodeImage(data, mimeType)
+	if err != nil {
+		return nil, "", err
+	}
+	out := p.Apply(img, exifOrientation(data))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// Apply menjalankan pipeline pada gambar yang sudah di-decode. orientation = nilai tag EXIF (1..8), 0/1 = normal.
+func (p Pipeline) Apply(img image.Image, orientation int) image.Image {
+	if p.Has(StepEXIF) {
+		img = applyOrientation(img, orientation)
+	}
+	if p.MaxLongEdge > 0 {
+		img = downscale(img, p.MaxLongEdge)
+	}
+	var cur image.Image = img
+	for _, st := range p.Steps {
+		switch st {
+		case StepGrayscale:
+			cur = toGray(cur)
+		case StepContrast:
+			cur = contrastStretch(toGray(cur), 0.01)
+		case StepThreshold:
+			cur = adaptiveThreshold(toGray(cur), 0.15)
+		case StepDeskew:
+			cur = deskew(toGray(cur), 5, 0.25)
+		case StepCrop:
+			cur = cropBorders(toGray(cur), 8)
+		case StepDenoise:
+			cur = medianFilter3(toGray(cur))
+		}
+	}
+	return cur
+}
+
+func downscale(img image.Image, maxEdge int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	long := w
+	if h > long {
+		long = h
+	}
+	if long <= maxEdge {
+		return img
+	}
+	ratio := float64(maxEdge) / float64(long)
+	newW, newH := int(float64(w)*ratio), int(float64(h)*ratio)
+	if newW < 1 {
+		newW = 1
+	}
+	if newH < 1 {
+		newH = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, newW, newH))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
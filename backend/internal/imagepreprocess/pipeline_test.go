@@ -0,0 +1,292 @@
+package imagepreprocess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// go test ./internal/imagepreprocess -update menulis ulang fixture dan golden output di testdata/.
+var update = flag.Bool("update", false, "tulis ulang fixture dan golden image di testdata")
+
+func TestPipelineGolden(t *testing.T) {
+	if *update {
+		writeFixtures(t)
+	}
+	cases := []struct {
+		step    Step
+		fixture string
+		check   func(t *testing.T, in, out image.Image)
+	}{
+		{StepEXIF, "exif6.jpg", func(t *testing.T, in, out image.Image) {
+			// Orientation 6: gambar 60x40 tersimpan menyamping, tampil tegak 40x60 dengan blok merah di kanan atas.
+			if out.Bounds().Dx() != in.Bounds().Dy() || out.Bounds().Dy() != in.Bounds().Dx() {
+				t.Fatalf("ukuran %v, want sisi tertukar dari %v", out.Bounds(), in.Bounds())
+			}
+			if r, g, _, _ := out.At(out.Bounds().Dx()-3, 2).RGBA(); r>>8 < 180 || g>>8 > 80 {
+				t.Fatalf("pojok kanan atas bukan merah setelah rotasi")
+			}
+		}},
+		{StepGrayscale, "document.png", func(t *testing.T, in, out image.Image) {
+			if _, ok := out.(*image.Gray); !ok {
+				t.Fatalf("output %T, want *image.Gray", out)
+			}
+		}},
+		{StepContrast, "document.png", func(t *testing.T, in, out image.Image) {
+			lo, hi := grayRange(out)
+			if lo > 5 || hi < 250 {
+				t.Fatalf("rentang %d..%d, want direntang mendekati 0..255", lo, hi)
+			}
+		}},
+		{StepThreshold, "document.png", func(t *testing.T, in, out image.Image) {
+			g := out.(*image.Gray)
+			for _, v := range g.Pix {
+				if v != 0 && v != 255 {
+					t.Fatalf("nilai %d, want biner 0/255", v)
+				}
+			}
+		}},
+		{StepDeskew, "skewed.png", func(t *testing.T, in, out image.Image) {
+			if out.Bounds().Size() != in.Bounds().Size() {
+				t.Fatalf("deskew mengubah ukuran kanvas %v -> %v", in.Bounds(), out.Bounds())
+			}
+			// Garis lurus = piksel gelap terkumpul di sedikit baris = variansi profil horizontal naik.
+			if before, after := rowProfileVariance(toGray(in)), rowProfileVariance(out.(*image.Gray)); after <= before {
+				t.Fatalf("variansi profil %.1f -> %.1f, want naik setelah deskew", before, after)
+			}
+		}},
+		{StepCrop, "document.png", func(t *testing.T, in, out image.Image) {
+			if out.Bounds().Dx() >= in.Bounds().Dx() || out.Bounds().Dy() >= in.Bounds().Dy() {
+				t.Fatalf("crop %v tidak lebih kecil dari %v", out.Bounds(), in.Bounds())
+			}
+		}},
+		{StepDenoise, "document.png", func(t *testing.T, in, out image.Image) {
+			// Bintik noise tunggal di (30,60) hilang.
+			if v := out.(*image.Gray).GrayAt(30, 60).Y; v < 128 {
+				t.Fatalf("bintik noise masih ada (%d)", v)
+			}
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.step), func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			in, _, err := decodeImage(data, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			outData, mime, err := Pipeline{Steps: []Step{tc.step}}.Run(data, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mime != "image/png" {
+				t.Fatalf("mime %q, want image/png", mime)
+			}
+			out, err := png.Decode(bytes.NewReader(outData))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, in, out)
+
+			golden := filepath.Join("testdata", "golden", string(tc.step)+".png")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, outData, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			wantData, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("golden %s tidak ada (jalankan dengan -update): %v", golden, err)
+			}
+			want, err := png.Decode(bytes.NewReader(wantData))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := pixelDiff(want, out); diff != "" {
+				t.Fatalf("output berbeda dari %s: %s", golden, diff)
+			}
+		})
+	}
+}
+
+func TestRotatePortraitToLandscapeOutput(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 20, 40))); err != nil {
+		t.Fatal(err)
+	}
+	_, mime, err := RotatePortraitToLandscape(buf.Bytes(), "image/png")
+	if err != nil || mime != "image/jpeg" {
+		t.Fatalf("RotatePortraitToLandscape = %q, %v; want image/jpeg", mime, err)
+	}
+	out, mime, err := RotatePortraitToLandscapeLossless(buf.Bytes(), "image/png")
+	if err != nil || mime != "image/png" {
+		t.Fatalf("RotatePortraitToLandscapeLossless = %q, %v; want image/png", mime, err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil || img.Bounds().Dx() != 40 || img.Bounds().Dy() != 20 {
+		t.Fatalf("hasil rotasi %v, %v; want 40x20", img.Bounds(), err)
+	}
+}
+
+func TestParseSteps(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    int
+		wantNil bool
+		wantErr bool
+	}{
+		{"", 0, true, false},
+		{"none", 0, false, false},
+		{"default", len(DefaultOCRSteps), false, false},
+		{" exif, Grayscale ,threshold", 3, false, false},
+		{"exif,sharpen", 0, true, true},
+	}
+	for _, tc := range cases {
+		steps, err := ParseSteps(tc.in)
+		if (err != nil) != tc.wantErr || (steps == nil) != tc.wantNil || len(steps) != tc.want {
+			t.Errorf("ParseSteps(%q) = %v, %v", tc.in, steps, err)
+		}
+	}
+}
+
+func grayRange(img image.Image) (uint8, uint8) {
+	lo, hi := uint8(255), uint8(0)
+	for _, v := range img.(*image.Gray).Pix {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+func rowProfileVariance(g *image.Gray) float64 {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	rows := make([]float64, h)
+	mean := 0.0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if g.Pix[y*g.Stride+x] < 128 {
+				rows[y]++
+			}
+		}
+		mean += rows[y] / float64(h)
+	}
+	v := 0.0
+	for _, r := range rows {
+		v += (r - mean) * (r - mean)
+	}
+	return v / float64(h)
+}
+
+func pixelDiff(want, got image.Image) string {
+	if want.Bounds().Size() != got.Bounds().Size() {
+		return fmt.Sprintf("ukuran %v, want %v", got.Bounds(), want.Bounds())
+	}
+	wb, gb := want.Bounds(), got.Bounds()
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			w := color.GrayModel.Convert(want.At(wb.Min.X+x, wb.Min.Y+y)).(color.Gray)
+			g := color.GrayModel.Convert(got.At(gb.Min.X+x, gb.Min.Y+y)).(color.Gray)
+			if w != g {
+				return fmt.Sprintf("piksel (%d,%d) = %d, want %d", x, y, g.Y, w.Y)
+			}
+		}
+	}
+	return ""
+}
+
+// writeFixtures membuat gambar uji deterministik: dokumen berbayang dengan border gelap dan bintik noise,
+// garis teks miring 3°, dan JPEG dengan EXIF Orientation 6.
+func writeFixtures(t *testing.T) {
+	t.Helper()
+	if err := os.MkdirAll("testdata", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := image.NewRGBA(image.Rect(0, 0, 120, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 120; x++ {
+			// Latar kertas redup dengan bayangan dari kiri ke kanan.
+			v := uint8(150 + x*60/120)
+			doc.Set(x, y, color.RGBA{v, v, uint8(int(v) - 10), 255})
+		}
+	}
+	draw.Draw(doc, image.Rect(0, 0, 120, 6), image.NewUniform(color.RGBA{20, 20, 20, 255}), image.Point{}, draw.Src)
+	draw.Draw(doc, image.Rect(0, 0, 6, 80), image.NewUniform(color.RGBA{20, 20, 20, 255}), image.Point{}, draw.Src)
+	for i, y := range []int{20, 34, 48} {
+		draw.Draw(doc, image.Rect(20, y, 90-i*10, y+4), image.NewUniform(color.RGBA{60, 40, 40, 255}), image.Point{}, draw.Src)
+	}
+	doc.Set(30, 60, color.RGBA{0, 0, 0, 255})
+	writePNG(t, "document.png", doc)
+
+	skew := image.NewGray(image.Rect(0, 0, 120, 80))
+	draw.Draw(skew, skew.Bounds(), image.White, image.Point{}, draw.Src)
+	slope := math.Tan(3 * math.Pi / 180)
+	for _, base := range []int{20, 35, 50, 65} {
+		for x := 10; x < 110; x++ {
+			y := base + int(math.Round(float64(x-60)*slope))
+			skew.SetGray(x, y, color.Gray{})
+			skew.SetGray(x, y+1, color.Gray{})
+		}
+	}
+	writePNG(t, "skewed.png", skew)
+
+	// Tersimpan 60x40 dengan blok merah di kiri atas; Orientation 6 (putar 90° CW) membawanya ke kanan atas.
+	side := image.NewRGBA(image.Rect(0, 0, 60, 40))
+	draw.Draw(side, side.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(side, image.Rect(0, 0, 15, 15), image.NewUniform(color.RGBA{220, 0, 0, 255}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, side, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("testdata", "exif6.jpg"), withOrientation(buf.Bytes(), 6), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writePNG(t *testing.T, name string, img image.Image) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("testdata", name), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// withOrientation menyisipkan segmen APP1 Exif (TIFF big-endian, satu entri Orientation) setelah SOI.
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd[0:], 1)
+	binary.BigEndian.PutUint16(ifd[2:], 0x0112)
+	binary.BigEndian.PutUint16(ifd[4:], 3) // SHORT
+	binary.BigEndian.PutUint32(ifd[6:], 1)
+	binary.BigEndian.PutUint16(ifd[10:], orientation)
+	payload := append(append([]byte("Exif\x00\x00"), tiff...), ifd...)
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, seg...)
+	out = append(out, payload...)
+	return append(out, jpg[2:]...)
+}
@@ -15,6 +15,9 @@ import (
 
 const maxLongEdge = 1024
 
+// MaxLongEdge batas sisi terpanjang yang dipakai ResizeMaxLongEdge; dipakai juga sebagai Pipeline.MaxLongEdge untuk OCR.
+const MaxLongEdge = maxLongEdge
+
 // ResizeMaxLongEdge mengubah ukuran gambar sehingga sisi terpanjang = maxLongEdge (1024px).
 // Format output: JPEG (lebih kecil). Jika input PNG dengan alpha, konversi ke JPEG (background putih).
 func ResizeMaxLongEdge(data []byte, mimeType string) ([]byte, string, error) {
@@ -93,7 +96,26 @@ func DecodeAndResizeFromReader(r io.Reader, mimeType string) ([]byte, string, er
 // RotatePortraitToLandscape jika gambar portrait (tinggi > lebar), putar 90° searah jarum jam jadi landscape.
 // Model vision (Gemini/Qwen) lebih akurat baca dokumen landscape. Return JPEG bytes.
 func RotatePortraitToLandscape(data []byte, mimeType string) ([]byte, string, error) {
-	img, _, err := decodeImage(data, mimeType)
+	dst, _, err := rotatePortrait(data, mimeType)
+	if err != nil || dst == nil {
+		return data, mimeType, err
+	}
+	return reencode(dst, nil, "image/jpeg", "jpeg")
+}
+
+// RotatePortraitToLandscapeLossless sama seperti RotatePortraitToLandscape, tetapi PNG tetap PNG
+// agar hasil binarisasi pipeline OCR tidak rusak oleh kompresi JPEG.
+func RotatePortraitToLandscapeLossless(data []byte, mimeType string) ([]byte, string, error) {
+	dst, fmtName, err := rotatePortrait(data, mimeType)
+	if err != nil || dst == nil {
+		return data, mimeType, err
+	}
+	return reencode(dst, nil, mimeType, fmtName)
+}
+
+// rotatePortrait return nil jika gambar sudah landscape (tidak perlu diputar).
+func rotatePortrait(data []byte, mimeType string) (image.Image, string, error) {
+	img, fmtName, err := decodeImage(data, mimeType)
 	if err != nil {
 		return nil, "", err
 	}
@@ -103,7 +125,7 @@ func RotatePortraitToLandscape(data []byte, mimeType string) ([]byte, string, er
 		return nil, "", fmt.Errorf("invalid image size %dx%d", w, h)
 	}
 	if w >= h {
-		return data, mimeType, nil
+		return nil, fmtName, nil
 	}
 	// Portrait: rotate 90° clockwise. New size: width=h, height=w.
 	dst := image.NewRGBA(image.Rect(0, 0, h, w))
@@ -112,5 +134,5 @@ func RotatePortraitToLandscape(data []byte, mimeType string) ([]byte, string, er
 			dst.Set(x, y, img.At(w-1-y, x))
 		}
 	}
-	return reencode(dst, nil, "image/jpeg", "jpeg")
+	return dst, fmtName, nil
 }
@@ -0,0 +1,276 @@
+package ocr
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Options pengaturan per panggilan OCR.
+type Options struct {
+	Lang string // bahasa tesseract, mis. "ind+eng" (default), "ind", "eng"
+	PSM  int    // page segmentation mode tesseract (0..13); 0 = pakai default tesseract
+}
+
+// Box bounding box dalam piksel (koordinat gambar yang di-OCR).
+type Box struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Word satu kata hasil OCR dengan posisi dan confidence (0..100, -1 = tidak diketahui).
+type Word struct {
+	Text string  `json:"text"`
+	Box  Box     `json:"box"`
+	Conf float64 `json:"conf"`
+}
+
+// Line satu baris teks (gabungan kata dengan block/paragraph/line yang sama).
+type Line struct {
+	Text  string  `json:"text"`
+	Box   Box     `json:"box"`
+	Conf  float64 `json:"conf"`
+	Words []Word  `json:"words"`
+}
+
+// Result hasil OCR terstruktur. Text selalu terisi; Lines kosong jika runner hanya mengembalikan teks polos.
+type Result struct {
+	Text  string `json:"text"`
+	Lines []Line `json:"lines,omitempty"`
+}
+
+var langPattern = regexp.MustCompile(`^[a-z_]+(\+[a-z_]+)*$`)
+
+// normalize mengisi default dan memvalidasi opsi (bahasa hanya huruf kecil/underscore dipisah '+').
+func (o Options) normalize() (Options, error) {
+	o.Lang = strings.ToLower(strings.TrimSpace(o.Lang))
+	if o.Lang == "" {
+		o.Lang = "ind+eng"
+	}
+	if !langPattern.MatchString(o.Lang) {
+		return o, fmt.Errorf("bahasa OCR tidak valid: %q", o.Lang)
+	}
+	if o.PSM < 0 || o.PSM > 13 {
+		return o, fmt.Errorf("psm harus 0..13, dapat %d", o.PSM)
+	}
+	return o, nil
+}
+
+// ParseTSV membaca output `tesseract ... tsv` menjadi Result (kata dikelompokkan per baris).
+func ParseTSV(tsv string) (*Result, error) {
+	sc := bufio.NewScanner(strings.NewReader(tsv))
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	type key struct{ page, block, par, line int }
+	groups := map[key]*Line{}
+	var order []key
+	header := true
+	for sc.Scan() {
+		row := sc.Text()
+		if header {
+			header = false
+			if strings.HasPrefix(row, "level") {
+				continue
+			}
+		}
+		cols := strings.Split(row, "\t")
+		if len(cols) < 12 {
+			continue
+		}
+		if cols[0] != "5" { // level 5 = word
+			continue
+		}
+		text := strings.TrimSpace(cols[11])
+		if text == "" {
+			continue
+		}
+		n := make([]int, 10)
+		for i := 1; i <= 9; i++ {
+			v, err := strconv.Atoi(cols[i])
+			if err != nil {
+				return nil, fmt.Errorf("tsv kolom %d: %w", i, err)
+			}
+			n[i] = v
+		}
+		conf, _ := strconv.ParseFloat(cols[10], 64)
+		k := key{n[1], n[2], n[3], n[4]}
+		ln, ok := groups[k]
+		if !ok {
+			ln = &Line{}
+			groups[k] = ln
+			order = append(order, k)
+		}
+		ln.Words = append(ln.Words, Word{Text: text, Box: Box{Left: n[6], Top: n[7], Width: n[8], Height: n[9]}, Conf: conf})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	lines := make([]Line, 0, len(order))
+	for _, k := range order {
+		lines = append(lines, finishLine(*groups[k]))
+	}
+	return newResult(lines), nil
+}
+
+var (
+	hocrWordRe  = regexp.MustCompile(`(?s)<span[^>]*class=['"]ocrx_word['"][^>]*title=['"]([^'"]*)['"][^>]*>(.*?)</span>`)
+	hocrBboxRe  = regexp.MustCompile(`bbox (\d+) (\d+) (\d+) (\d+)`)
+	hocrConfRe  = regexp.MustCompile(`x_wconf (\d+(?:\.\d+)?)`)
+	hocrTagRe   = regexp.MustCompile(`<[^>]+>`)
+	hocrLineTag = regexp.MustCompile(`<span[^>]*class=['"]ocr(?:_line|_textfloat|_header|_caption)['"]`)
+)
+
+// ParseHOCR membaca output hOCR (tesseract `hocr` atau script OCR lain) menjadi Result.
+func ParseHOCR(doc string) (*Result, error) {
+	// Potong dokumen per elemen baris; kata-kata di antaranya milik baris tersebut.
+	idx := hocrLineTag.FindAllStringIndex(doc, -1)
+	if len(idx) == 0 {
+		return nil, fmt.Errorf("hocr: tidak ada elemen ocr_line")
+	}
+	var lines []Line
+	for i, loc := range idx {
+		end := len(doc)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+		var ln Line
+		for _, m := range hocrWordRe.FindAllStringSubmatch(doc[loc[0]:end], -1) {
+			text := strings.TrimSpace(html.UnescapeString(hocrTagRe.ReplaceAllString(m[2], "")))
+			if text == "" {
+				continue
+			}
+			w := Word{Text: text, Conf: -1}
+			if b := hocrBboxRe.FindStringSubmatch(m[1]); b != nil {
+				x0, _ := strconv.Atoi(b[1])
+				y0, _ := strconv.Atoi(b[2])
+				x1, _ := strconv.Atoi(b[3])
+				y1, _ := strconv.Atoi(b[4])
+				w.Box = Box{Left: x0, Top: y0, Width: x1 - x0, Height: y1 - y0}
+			}
+			if c := hocrConfRe.FindStringSubmatch(m[1]); c != nil {
+				w.Conf, _ = strconv.ParseFloat(c[1], 64)
+			}
+			ln.Words = append(ln.Words, w)
+		}
+		if len(ln.Words) > 0 {
+			lines = append(lines, finishLine(ln))
+		}
+	}
+	return newResult(lines), nil
+}
+
+// finishLine mengurutkan kata kiri→kanan dan menghitung teks, bbox gabungan, dan rata-rata confidence.
+func finishLine(ln Line) Line {
+	sort.SliceStable(ln.Words, func(i, j int) bool { return ln.Words[i].Box.Left < ln.Words[j].Box.Left })
+	texts := make([]string, 0, len(ln.Words))
+	x0, y0, x1, y1 := -1, -1, 0, 0
+	var confSum float64
+	confN := 0
+	for _, w := range ln.Words {
+		texts = append(texts, w.Text)
+		if x0 < 0 || w.Box.Left < x0 {
+			x0 = w.Box.Left
+		}
+		if y0 < 0 || w.Box.Top < y0 {
+			y0 = w.Box.Top
+		}
+		if r := w.Box.Left + w.Box.Width; r > x1 {
+			x1 = r
+		}
+		if b := w.Box.Top + w.Box.Height; b > y1 {
+			y1 = b
+		}
+		if w.Conf >= 0 {
+			confSum += w.Conf
+			confN++
+		}
+	}
+	ln.Text = strings.Join(texts, " ")
+	ln.Box = Box{Left: x0, Top: y0, Width: x1 - x0, Height: y1 - y0}
+	ln.Conf = -1
+	if confN > 0 {
+		ln.Conf = confSum / float64(confN)
+	}
+	return ln
+}
+
+// newResult mengurutkan baris atas→bawah dan menyusun Text polos.
+func newResult(lines []Line) *Result {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Box.Top < lines[j].Box.Top })
+	texts := make([]string, 0, len(lines))
+	for _, ln := range lines {
+		texts = append(texts, ln.Text)
+	}
+	return &Result{Text: strings.Join(texts, "\n"), Lines: lines}
+}
+
+// Layout merender hasil OCR sebagai teks berkolom untuk prompt LLM: baris yang posisinya sejajar (overlap vertikal)
+// digabung jadi satu baris tabel, dan jarak horizontal lebar antar kata ditandai " | " agar kolom tabel terbaca.
+// Kata dengan confidence rendah (< 50) diberi tanda "?" supaya model tahu kata itu meragukan.
+func (r *Result) Layout() string {
+	if r == nil || len(r.Lines) == 0 {
+		return ""
+	}
+	var words []Word
+	var heightSum int
+	for _, ln := range r.Lines {
+		words = append(words, ln.Words...)
+		heightSum += ln.Box.Height
+	}
+	avgH := heightSum / len(r.Lines)
+	if avgH < 1 {
+		avgH = 1
+	}
+	sort.SliceStable(words, func(i, j int) bool { return words[i].Box.Top < words[j].Box.Top })
+	// Kelompokkan kata per baris visual: pusat vertikal dalam setengah tinggi baris rata-rata.
+	var rows [][]Word
+	var rowCenter []int
+	for _, w := range words {
+		c := w.Box.Top + w.Box.Height/2
+		placed := false
+		for i := range rows {
+			if abs(rowCenter[i]-c) <= avgH/2 {
+				rows[i] = append(rows[i], w)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			rows = append(rows, []Word{w})
+			rowCenter = append(rowCenter, c)
+		}
+	}
+	var sb strings.Builder
+	for _, row := range rows {
+		sort.SliceStable(row, func(i, j int) bool { return row[i].Box.Left < row[j].Box.Left })
+		for i, w := range row {
+			if i > 0 {
+				prev := row[i-1]
+				gap := w.Box.Left - (prev.Box.Left + prev.Box.Width)
+				if gap > 2*avgH {
+					sb.WriteString(" | ")
+				} else {
+					sb.WriteByte(' ')
+				}
+			}
+			sb.WriteString(w.Text)
+			if w.Conf >= 0 && w.Conf < 50 {
+				sb.WriteByte('?')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
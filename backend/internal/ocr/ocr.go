@@ -3,10 +3,12 @@ package ocr
 
 import (
 	"bytes"
+	"dashboardadminimb/internal/imagepreprocess"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -15,6 +17,25 @@ type Runner interface {
 	Run(imageData []byte, mimeType string) (string, error)
 }
 
+// StructuredRunner runner yang bisa mengembalikan layout (kata, bounding box, baris, confidence)
+// dengan bahasa dan page segmentation mode per panggilan.
+type StructuredRunner interface {
+	Runner
+	RunStructured(imageData []byte, mimeType string, opts Options) (*Result, error)
+}
+
+// RunStructured memakai StructuredRunner jika didukung runner; jika tidak, fallback ke Run (Result hanya berisi Text).
+func RunStructured(r Runner, imageData []byte, mimeType string, opts Options) (*Result, error) {
+	if sr, ok := r.(StructuredRunner); ok {
+		return sr.RunStructured(imageData, mimeType, opts)
+	}
+	text, err := r.Run(imageData, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Text: text}, nil
+}
+
 // NewTesseractRunner membuat runner yang memanggil tesseract CLI.
 // tesseractPath = "tesseract" atau path penuh ke binary.
 func NewTesseractRunner(tesseractPath string) (Runner, error) {
@@ -30,6 +51,28 @@ type tesseractRunner struct {
 }
 
 func (t *tesseractRunner) Run(imageData []byte, mimeType string) (string, error) {
+	out, err := t.exec(imageData, mimeType, Options{}, "")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RunStructured memanggil tesseract dengan output TSV lalu mengelompokkan kata per baris.
+func (t *tesseractRunner) RunStructured(imageData []byte, mimeType string, opts Options) (*Result, error) {
+	out, err := t.exec(imageData, mimeType, opts, "tsv")
+	if err != nil {
+		return nil, err
+	}
+	return ParseTSV(out)
+}
+
+// exec menjalankan tesseract pada file sementara. configFile = "" (teks), "tsv" atau "hocr".
+func (t *tesseractRunner) exec(imageData []byte, mimeType string, opts Options, configFile string) (string, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
 	ext := ".png"
 	if strings.HasPrefix(mimeType, "image/jpeg") || strings.HasPrefix(mimeType, "image/jpg") {
 		ext = ".jpg"
@@ -46,15 +89,22 @@ func (t *tesseractRunner) Run(imageData []byte, mimeType string) (string, error)
 	if err := tmpFile.Sync(); err != nil {
 		return "", err
 	}
-	// tesseract input stdout -l ind+eng
-	cmd := exec.Command(t.path, tmpFile.Name(), "stdout", "-l", "ind+eng")
+	// tesseract input stdout -l ind+eng [--psm N] [tsv|hocr]
+	args := []string{tmpFile.Name(), "stdout", "-l", opts.Lang}
+	if opts.PSM > 0 {
+		args = append(args, "--psm", strconv.Itoa(opts.PSM))
+	}
+	if configFile != "" {
+		args = append(args, configFile)
+	}
+	cmd := exec.Command(t.path, args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("tesseract: %w; stderr: %s", err, stderr.String())
 	}
-	return strings.TrimSpace(stdout.String()), nil
+	return stdout.String(), nil
 }
 
 // NoOPRunner mengembalikan teks kosong (untuk uji tanpa OCR).
@@ -73,6 +123,29 @@ func ResolveRunner(tesseractPath, scriptPath string) (Runner, error) {
 	return nil, nil
 }
 
+// ResolvePreprocess mengembalikan pipeline preprocessing untuk runner yang dipilih ResolveRunner (urutan prioritas sama).
+// Tesseract butuh gambar bersih (default: semua step); script runner default hanya koreksi EXIF.
+func ResolvePreprocess(tesseractPath, scriptPath, tesseractSteps, scriptSteps string) (imagepreprocess.Pipeline, error) {
+	if strings.TrimSpace(scriptPath) != "" {
+		steps, err := imagepreprocess.ParseSteps(scriptSteps)
+		if err != nil {
+			return imagepreprocess.Pipeline{}, err
+		}
+		if steps == nil {
+			steps = []imagepreprocess.Step{imagepreprocess.StepEXIF}
+		}
+		return imagepreprocess.Pipeline{Steps: steps}, nil
+	}
+	steps, err := imagepreprocess.ParseSteps(tesseractSteps)
+	if err != nil {
+		return imagepreprocess.Pipeline{}, err
+	}
+	if steps == nil {
+		steps = append([]imagepreprocess.Step{}, imagepreprocess.DefaultOCRSteps...)
+	}
+	return imagepreprocess.Pipeline{Steps: steps}, nil
+}
+
 // ScriptRunner memanggil script eksternal: stdin = image bytes, stdout = teks. Untuk PaddleOCR/EasyOCR.
 func NewScriptRunner(scriptPath string) (Runner, error) {
 	p := strings.TrimSpace(scriptPath)
@@ -90,7 +163,39 @@ type scriptRunner struct {
 }
 
 func (s *scriptRunner) Run(imageData []byte, mimeType string) (string, error) {
+	return s.exec(imageData, nil)
+}
+
+// RunStructured meneruskan opsi ke script lewat env OCR_LANG, OCR_PSM dan OCR_FORMAT=hocr.
+// Script boleh mengeluarkan hOCR, TSV tesseract, atau teks polos (tanpa layout).
+func (s *scriptRunner) RunStructured(imageData []byte, mimeType string, opts Options) (*Result, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return nil, err
+	}
+	env := []string{"OCR_LANG=" + opts.Lang, "OCR_FORMAT=hocr"}
+	if opts.PSM > 0 {
+		env = append(env, "OCR_PSM="+strconv.Itoa(opts.PSM))
+	}
+	out, err := s.exec(imageData, env)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.Contains(out, "ocrx_word"):
+		return ParseHOCR(out)
+	case strings.HasPrefix(out, "level\tpage_num"):
+		return ParseTSV(out)
+	default:
+		return &Result{Text: out}, nil
+	}
+}
+
+func (s *scriptRunner) exec(imageData []byte, env []string) (string, error) {
 	cmd := exec.Command(s.path)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmd.Stdin = bytes.NewReader(imageData)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -0,0 +1,205 @@
+// Package pdfdoc penulis PDF minimal (pure Go) untuk dokumen sederhana seperti slip gaji: teks Helvetica,
+// garis, kotak berwarna dan gambar JPEG. Koordinat dalam point dengan titik (0,0) di kiri atas halaman.
+package pdfdoc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Ukuran A4 dalam point.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// helveticaWidths lebar glyph Helvetica (per 1000 unit) untuk ASCII 32..126.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// TextWidth perkiraan lebar teks Helvetica dalam point; tebal dianggap 5% lebih lebar.
+func TextWidth(s string, size float64, bold bool) float64 {
+	w := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			w += helveticaWidths[r-32]
+		} else {
+			w += 556
+		}
+	}
+	width := float64(w) * size / 1000
+	if bold {
+		width *= 1.05
+	}
+	return width
+}
+
+type pdfImage struct {
+	data          []byte
+	width, height int
+}
+
+// Document PDF berisi satu atau beberapa halaman A4.
+type Document struct {
+	pages  []*bytes.Buffer
+	images []pdfImage
+}
+
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+// AddPage memulai halaman baru; perintah gambar berikutnya masuk ke halaman ini.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// escape teks untuk string literal PDF; karakter di luar Latin-1 diganti '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
+
+// Text menulis teks dengan baseline di y.
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, A4Height-y, escape(s))
+}
+
+// TextRight menulis teks rata kanan dengan tepi kanan di x.
+func (d *Document) TextRight(x, y, size float64, bold bool, s string) {
+	d.Text(x-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// Line garis lurus tebal width.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, A4Height-y1, x2, A4Height-y2)
+}
+
+// FillRect kotak terisi warna c (kiri atas x,y).
+func (d *Document) FillRect(x, y, w, h float64, c color.Color) {
+	r, g, b, _ := c.RGBA()
+	fmt.Fprintf(d.page(), "q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n",
+		float64(r)/65535, float64(g)/65535, float64(b)/65535, x, A4Height-y-h, w, h)
+}
+
+// Image menggambar gambar (JPEG/PNG/GIF/WebP) pada kotak x,y,w,h dengan rasio dipertahankan.
+// Gambar dikonversi ke JPEG agar bisa disematkan langsung (DCTDecode).
+func (d *Document) Image(data []byte, x, y, w, h float64) error {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	bounds := img.Bounds()
+	// Latar putih untuk gambar transparan (JPEG tidak punya alpha).
+	rgba := image.NewRGBA(bounds)
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			r, g, b, a := img.At(px, py).RGBA()
+			inv := 0xffff - a
+			rgba.Set(px, py, color.RGBA64{uint16(r + inv), uint16(g + inv), uint16(b + inv), 0xffff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, rgba, &jpeg.Options{Quality: 90}); err != nil {
+		return err
+	}
+	iw, ih := float64(bounds.Dx()), float64(bounds.Dy())
+	scale := w / iw
+	if ih*scale > h {
+		scale = h / ih
+	}
+	d.images = append(d.images, pdfImage{buf.Bytes(), bounds.Dx(), bounds.Dy()})
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n",
+		iw*scale, ih*scale, x, A4Height-y-ih*scale, len(d.images))
+	return nil
+}
+
+// Bytes menyusun file PDF lengkap.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string, stream []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s", len(offsets), body)
+		if stream != nil {
+			out.WriteString("\nstream\n")
+			out.Write(stream)
+			out.WriteString("\nendstream")
+		}
+		out.WriteString("\nendobj\n")
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Nomor objek: 1 catalog, 2 pages, 3-4 font, lalu gambar, lalu (page, content) per halaman.
+	firstImage := 5
+	firstPage := firstImage + len(d.images)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)), nil)
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+	var xobjects strings.Builder
+	for i, img := range d.images {
+		obj(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			img.width, img.height, len(img.data)), img.data)
+		fmt.Fprintf(&xobjects, " /Im%d %d 0 R", i+1, firstImage+i)
+	}
+	resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+	if xobjects.Len() > 0 {
+		resources += " /XObject <<" + xobjects.String() + " >>"
+	}
+	for i, content := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>",
+			A4Width, A4Height, resources, firstPage+i*2+1), nil)
+		obj(fmt.Sprintf("<< /Length %d >>", content.Len()), content.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
@@ -0,0 +1,722 @@
+package pdfpages
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EmbeddedRasterizer rasterizer pure Go untuk PDF hasil scanner/aplikasi scan HP: tiap halaman berisi satu gambar
+// (JPEG/DCTDecode atau raw Flate 1/8 bit). Gambar terbesar per halaman diambil apa adanya, tanpa render teks/vektor.
+// PDF digital (teks asli) atau kompresi CCITT/JBIG2/JPX butuh PDFTOPPM_PATH atau PDF_SCRIPT_PATH.
+type EmbeddedRasterizer struct{}
+
+type pdfObject struct {
+	dict   string // teks objek (biasanya "<< ... >>")
+	stream []byte // data stream mentah (masih terkompresi), nil jika bukan stream
+}
+
+type pdfDoc struct {
+	objects map[int]*pdfObject
+	budget  int64 // sisa byte hasil dekompresi yang boleh dipakai seluruh dokumen
+}
+
+// Batas untuk PDF upload yang tidak tepercaya: ukuran gambar dicek sebelum alokasi dan hasil inflate dibatasi
+// per stream maupun per dokumen (flate bomb). Berupa var agar bisa diperkecil di test.
+var (
+	maxImagePixels         = 50_000_000 // 50 MP, jauh di atas scan A4 600 dpi
+	maxStreamBytes   int64 = 256 << 20  // cukup untuk 50 MP CMYK
+	maxDocumentBytes int64 = 1 << 30
+)
+
+var (
+	objHeaderRe = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	refRe       = regexp.MustCompile(`^(\d+)\s+(\d+)\s+R$`)
+	refAllRe    = regexp.MustCompile(`(\d+)\s+(\d+)\s+R\b`)
+	nameRe      = regexp.MustCompile(`/[A-Za-z0-9]+`)
+)
+
+func (EmbeddedRasterizer) Rasterize(pdf []byte, maxPages int) ([]Page, error) {
+	doc, err := parsePDF(pdf)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := doc.pages()
+	if err != nil {
+		return nil, err
+	}
+	if maxPages > 0 && len(pages) > maxPages {
+		pages = pages[:maxPages]
+	}
+	out := make([]Page, 0, len(pages))
+	for i, pg := range pages {
+		p, err := doc.pageImage(pg)
+		if err != nil {
+			return nil, fmt.Errorf("halaman %d: %w", i+1, err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// parsePDF membaca semua objek "n g obj ... endobj" (termasuk yang ada di object stream /ObjStm).
+// Objek yang muncul belakangan (incremental update) menimpa yang lama.
+func parsePDF(data []byte) (*pdfDoc, error) {
+	doc := &pdfDoc{objects: map[int]*pdfObject{}, budget: maxDocumentBytes}
+	var objStms []*pdfObject
+	skipUntil := 0
+	for _, m := range objHeaderRe.FindAllSubmatchIndex(data, -1) {
+		if m[0] < skipUntil {
+			continue // match palsu di dalam data stream biner
+		}
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		obj, end := readObject(data, m[1])
+		doc.objects[num] = obj
+		skipUntil = end
+		if obj.stream != nil && dictName(parseDict(obj.dict), "/Type") == "/ObjStm" {
+			objStms = append(objStms, obj)
+		}
+	}
+	for _, st := range objStms {
+		if err := doc.loadObjStm(st); err != nil {
+			return nil, fmt.Errorf("object stream: %w", err)
+		}
+	}
+	if len(doc.objects) == 0 {
+		return nil, fmt.Errorf("bukan PDF yang valid (tidak ada objek)")
+	}
+	return doc, nil
+}
+
+// readObject membaca isi objek mulai dari pos (setelah "obj"); return objek dan posisi akhir.
+func readObject(data []byte, pos int) (*pdfObject, int) {
+	obj := &pdfObject{}
+	i := skipSpace(data, pos)
+	if bytes.HasPrefix(data[i:], []byte("<<")) {
+		end := matchDict(data, i)
+		obj.dict = string(data[i:end])
+		j := skipSpace(data, end)
+		if bytes.HasPrefix(data[j:], []byte("stream")) {
+			start := j + len("stream")
+			if start < len(data) && data[start] == '\r' {
+				start++
+			}
+			if start < len(data) && data[start] == '\n' {
+				start++
+			}
+			stop := -1
+			if n, err := strconv.Atoi(parseDict(obj.dict)["/Length"]); err == nil && n >= 0 && start+n <= len(data) {
+				if bytes.HasPrefix(bytes.TrimLeft(data[start+n:minInt(len(data), start+n+32)], "\r\n\t "), []byte("endstream")) {
+					stop = start + n
+				}
+			}
+			if stop < 0 { // /Length indirect atau salah: cari endstream
+				k := bytes.Index(data[start:], []byte("endstream"))
+				if k < 0 {
+					obj.stream = data[start:]
+					return obj, len(data)
+				}
+				stop = start + k
+				for stop > start && (data[stop-1] == '\n' || data[stop-1] == '\r') {
+					stop--
+				}
+			}
+			obj.stream = data[start:stop]
+			return obj, stop
+		}
+		return obj, end
+	}
+	k := bytes.Index(data[i:], []byte("endobj"))
+	if k < 0 {
+		k = len(data) - i
+	}
+	obj.dict = strings.TrimSpace(string(data[i : i+k]))
+	return obj, i + k
+}
+
+func (d *pdfDoc) loadObjStm(st *pdfObject) error {
+	dict := parseDict(st.dict)
+	raw, err := d.decodeFilters(st.stream, dict)
+	if err != nil {
+		return err
+	}
+	n, _ := strconv.Atoi(dict["/N"])
+	first, _ := strconv.Atoi(dict["/First"])
+	if first < 0 || first > len(raw) {
+		return fmt.Errorf("/First di luar data")
+	}
+	fields := strings.Fields(string(raw[:first]))
+	for k := 0; k+1 < len(fields) && k/2 < n; k += 2 {
+		num, err1 := strconv.Atoi(fields[k])
+		off, err2 := strconv.Atoi(fields[k+1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if off < 0 || off > len(raw)-first {
+			return fmt.Errorf("offset objek %d di luar data", num)
+		}
+		start := first + off
+		end := len(raw)
+		if k+3 < len(fields) {
+			if next, err := strconv.Atoi(fields[k+3]); err == nil && next >= 0 && next <= len(raw)-first {
+				end = first + next
+			}
+		}
+		if start > end {
+			return fmt.Errorf("offset objek %d tidak urut", num)
+		}
+		if _, exists := d.objects[num]; exists {
+			continue
+		}
+		d.objects[num] = &pdfObject{dict: strings.TrimSpace(string(raw[start:end]))}
+	}
+	return nil
+}
+
+// resolve mengikuti referensi "n g R" ke isi objeknya.
+func (d *pdfDoc) resolve(v string) (string, *pdfObject) {
+	v = strings.TrimSpace(v)
+	if m := refRe.FindStringSubmatch(v); m != nil {
+		num, _ := strconv.Atoi(m[1])
+		if obj, ok := d.objects[num]; ok {
+			return obj.dict, obj
+		}
+		return "", nil
+	}
+	return v, nil
+}
+
+type pdfPage struct {
+	resources string
+	rotate    int
+}
+
+// pages menelusuri page tree dari /Catalog → /Pages → /Kids secara berurutan (Resources dan Rotate diwariskan).
+func (d *pdfDoc) pages() ([]pdfPage, error) {
+	var root string
+	for _, obj := range d.objects {
+		if dictName(parseDict(obj.dict), "/Type") == "/Catalog" {
+			root = parseDict(obj.dict)["/Pages"]
+			break
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("PDF tanpa /Catalog /Pages")
+	}
+	var out []pdfPage
+	seen := map[string]bool{}
+	var walk func(ref string, res string, rotate int) error
+	walk = func(ref string, res string, rotate int) error {
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		text, _ := d.resolve(ref)
+		dict := parseDict(text)
+		if r, ok := dict["/Resources"]; ok {
+			res = r
+		}
+		if r, err := strconv.Atoi(dict["/Rotate"]); err == nil {
+			rotate = r
+		}
+		if kids, ok := dict["/Kids"]; ok {
+			for _, k := range refAllRe.FindAllString(kids, -1) {
+				if err := walk(k, res, rotate); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if dictName(dict, "/Type") == "/Page" || dict["/Contents"] != "" {
+			out = append(out, pdfPage{resources: res, rotate: rotate})
+		}
+		return nil
+	}
+	if err := walk(strings.TrimSpace(root), "", 0); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("PDF tidak berisi halaman")
+	}
+	return out, nil
+}
+
+// pageImage mengambil gambar terbesar di /XObject halaman (termasuk di dalam Form XObject) dan mengubahnya ke JPEG/PNG.
+func (d *pdfDoc) pageImage(pg pdfPage) (Page, error) {
+	var best *pdfObject
+	var bestArea float64 // float64: /Width dan /Height dari file bisa overflow bila dikalikan sebagai int
+	var visit func(res string, depth int)
+	visit = func(res string, depth int) {
+		resText, _ := d.resolve(res)
+		xobjText, _ := d.resolve(parseDict(resText)["/XObject"])
+		for _, ref := range parseDict(xobjText) {
+			_, obj := d.resolve(ref)
+			if obj == nil || obj.stream == nil {
+				continue
+			}
+			dict := parseDict(obj.dict)
+			switch dictName(dict, "/Subtype") {
+			case "/Image":
+				w, _ := strconv.Atoi(dict["/Width"])
+				h, _ := strconv.Atoi(dict["/Height"])
+				// Gambar berdimensi tidak valid tetap kandidat agar decode melaporkan alasannya.
+				if area := float64(w) * float64(h); best == nil || area > bestArea {
+					best, bestArea = obj, area
+				}
+			case "/Form":
+				if depth < 3 {
+					visit(dict["/Resources"], depth+1)
+				}
+			}
+		}
+	}
+	visit(pg.resources, 0)
+	if best == nil {
+		return Page{}, fmt.Errorf("tidak ada gambar scan di halaman (PDF digital butuh PDFTOPPM_PATH)")
+	}
+	p, err := d.decodeImageObject(best)
+	if err != nil {
+		return Page{}, err
+	}
+	if r := ((pg.rotate % 360) + 360) % 360; r != 0 {
+		return rotatePage(p, r)
+	}
+	return p, nil
+}
+
+func (d *pdfDoc) decodeImageObject(obj *pdfObject) (Page, error) {
+	dict := parseDict(obj.dict)
+	filters := nameRe.FindAllString(dict["/Filter"], -1)
+	data := obj.stream
+	for i, f := range filters {
+		switch f {
+		case "/FlateDecode", "/Fl":
+			var err error
+			if data, err = d.inflate(data); err != nil {
+				return Page{}, err
+			}
+		case "/DCTDecode", "/DCT":
+			if i == len(filters)-1 {
+				return Page{Data: data, MimeType: "image/jpeg"}, nil
+			}
+			return Page{}, fmt.Errorf("filter %s di tengah rantai tidak didukung", f)
+		default:
+			return Page{}, fmt.Errorf("kompresi gambar %s tidak didukung (set PDFTOPPM_PATH)", f)
+		}
+	}
+	w, _ := strconv.Atoi(dict["/Width"])
+	h, _ := strconv.Atoi(dict["/Height"])
+	bpc, _ := strconv.Atoi(dict["/BitsPerComponent"])
+	if err := checkImageSize(w, h); err != nil {
+		return Page{}, err
+	}
+	if dict["/ImageMask"] == "true" {
+		bpc = 1
+	}
+	if bpc != 1 && bpc != 8 {
+		return Page{}, fmt.Errorf("format gambar %d bit tidak didukung (set PDFTOPPM_PATH)", bpc)
+	}
+	comps := 0
+	switch cs := dict["/ColorSpace"]; {
+	case strings.HasPrefix(cs, "/DeviceGray"), strings.HasPrefix(cs, "/G"):
+		comps = 1
+	case strings.HasPrefix(cs, "/DeviceRGB"), strings.HasPrefix(cs, "/RGB"):
+		comps = 3
+	case strings.HasPrefix(cs, "/DeviceCMYK"), strings.HasPrefix(cs, "/CMYK"):
+		comps = 4
+	}
+	parms := parseDict(dict["/DecodeParms"])
+	if pred, _ := strconv.Atoi(parms["/Predictor"]); pred >= 10 {
+		colors := comps
+		if c, err := strconv.Atoi(parms["/Colors"]); err == nil {
+			colors = c
+		}
+		if colors == 0 {
+			colors = 1
+		}
+		if colors < 1 || colors > 4 {
+			return Page{}, fmt.Errorf("predictor: /Colors %d tidak valid", colors)
+		}
+		var err error
+		if data, err = unpredictPNG(data, (w*colors*bpc+7)/8, (colors*bpc+7)/8); err != nil {
+			return Page{}, err
+		}
+		comps = colors
+	}
+	if bpc == 8 && comps == 0 {
+		comps = len(data) / (w * h) // ICCBased/Indexed tanpa palet: tebak dari ukuran data
+	}
+	img, err := rawToImage(data, w, h, bpc, comps)
+	if err != nil {
+		return Page{}, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return Page{}, err
+	}
+	return Page{Data: buf.Bytes(), MimeType: "image/png"}, nil
+}
+
+// checkImageSize menolak dimensi nol/negatif dan gambar di atas maxImagePixels sebelum ada alokasi;
+// setelah lolos, w*h*4 tidak overflow.
+func checkImageSize(w, h int) error {
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("ukuran gambar tidak valid %dx%d", w, h)
+	}
+	if w > maxImagePixels/h {
+		return fmt.Errorf("ukuran gambar %dx%d melebihi batas %d piksel", w, h, maxImagePixels)
+	}
+	return nil
+}
+
+// rawToImage memeriksa panjang data sebelum mengalokasikan gambar tujuan.
+func rawToImage(data []byte, w, h, bpc, comps int) (image.Image, error) {
+	if err := checkImageSize(w, h); err != nil {
+		return nil, err
+	}
+	switch {
+	case bpc == 1:
+		stride := (w + 7) / 8
+		if len(data) < stride*h {
+			return nil, fmt.Errorf("data gambar 1-bit terpotong")
+		}
+		g := image.NewGray(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if data[y*stride+x/8]&(0x80>>uint(x%8)) != 0 {
+					g.Pix[y*g.Stride+x] = 255
+				}
+			}
+		}
+		return g, nil
+	case bpc == 8 && comps == 1:
+		if len(data) < w*h {
+			return nil, fmt.Errorf("data gambar terpotong")
+		}
+		g := image.NewGray(image.Rect(0, 0, w, h))
+		copy(g.Pix, data[:w*h])
+		return g, nil
+	case bpc == 8 && (comps == 3 || comps == 4):
+		if len(data) < w*h*comps {
+			return nil, fmt.Errorf("data gambar terpotong")
+		}
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for i := 0; i < w*h; i++ {
+			p := data[i*comps:]
+			if comps == 3 {
+				img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2] = p[0], p[1], p[2]
+			} else {
+				r, g, b := color.CMYKToRGB(p[0], p[1], p[2], p[3])
+				img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2] = r, g, b
+			}
+			img.Pix[i*4+3] = 255
+		}
+		return img, nil
+	}
+	return nil, fmt.Errorf("format gambar %d bit × %d komponen tidak didukung (set PDFTOPPM_PATH)", bpc, comps)
+}
+
+// unpredictPNG membalik predictor PNG (DecodeParms /Predictor 10..15): tiap baris diawali byte jenis filter.
+func unpredictPNG(data []byte, rowLen, bpp int) ([]byte, error) {
+	if rowLen <= 0 {
+		return nil, fmt.Errorf("predictor: /Columns tidak valid")
+	}
+	if bpp < 1 {
+		bpp = 1
+	}
+	rows := len(data) / (rowLen + 1)
+	out := make([]byte, rows*rowLen)
+	prev := make([]byte, rowLen)
+	for r := 0; r < rows; r++ {
+		ft := data[r*(rowLen+1)]
+		src := data[r*(rowLen+1)+1 : (r+1)*(rowLen+1)]
+		cur := out[r*rowLen : (r+1)*rowLen]
+		for i := 0; i < rowLen; i++ {
+			var left, up, upLeft byte
+			if i >= bpp {
+				left = cur[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up = prev[i]
+			switch ft {
+			case 0:
+				cur[i] = src[i]
+			case 1:
+				cur[i] = src[i] + left
+			case 2:
+				cur[i] = src[i] + up
+			case 3:
+				cur[i] = src[i] + byte((int(left)+int(up))/2)
+			case 4:
+				cur[i] = src[i] + paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("predictor: filter baris %d tidak dikenal", ft)
+			}
+		}
+		prev = cur
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := absInt(p-int(a)), absInt(p-int(b)), absInt(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func (d *pdfDoc) decodeFilters(data []byte, dict map[string]string) ([]byte, error) {
+	for _, f := range nameRe.FindAllString(dict["/Filter"], -1) {
+		if f != "/FlateDecode" && f != "/Fl" {
+			return nil, fmt.Errorf("filter %s tidak didukung", f)
+		}
+		var err error
+		if data, err = d.inflate(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate membuka zlib dengan batas maxStreamBytes per stream dan sisa budget dokumen; melewati batas = error.
+func (d *pdfDoc) inflate(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("flate: %w", err)
+	}
+	defer zr.Close()
+	limit := maxStreamBytes
+	if d.budget < limit {
+		limit = d.budget
+	}
+	out, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if int64(len(out)) > limit {
+		return nil, fmt.Errorf("flate: hasil dekompresi melebihi batas %d byte", limit)
+	}
+	d.budget -= int64(len(out))
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("flate: %w", err)
+	}
+	return out, nil // stream terpotong di akhir masih dipakai (umum di PDF scanner murah)
+}
+
+// rotatePage menerapkan /Rotate halaman (kelipatan 90 searah jarum jam) dan encode ulang sebagai PNG.
+func rotatePage(p Page, deg int) (Page, error) {
+	var img image.Image
+	var err error
+	if p.MimeType == "image/jpeg" {
+		img, err = jpeg.Decode(bytes.NewReader(p.Data))
+	} else {
+		img, err = png.Decode(bytes.NewReader(p.Data))
+	}
+	if err != nil {
+		return Page{}, err
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if deg == 90 || deg == 270 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch deg {
+			case 90:
+				dx, dy = h-1-y, x
+			case 180:
+				dx, dy = w-1-x, h-1-y
+			case 270:
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return Page{}, err
+	}
+	return Page{Data: buf.Bytes(), MimeType: "image/png"}, nil
+}
+
+// parseDict memecah dictionary PDF level teratas menjadi map key → teks nilai mentah ("/Name", "12 0 R", "<<...>>", "[...]").
+func parseDict(s string) map[string]string {
+	out := map[string]string{}
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "<<") {
+		return out
+	}
+	b := []byte(s)
+	end := matchDict(b, 0)
+	i := 2
+	for {
+		i = skipSpace(b, i)
+		if i >= end-2 || b[i] != '/' {
+			return out
+		}
+		kEnd := scanName(b, i)
+		key := string(b[i:kEnd])
+		vStart := skipSpace(b, kEnd)
+		vEnd := scanValue(b, vStart)
+		// referensi tidak langsung: "12 0 R"
+		if j := skipSpace(b, vEnd); isDigit(b, vStart) && isDigit(b, j) {
+			k := skipSpace(b, scanValue(b, j))
+			if k < len(b) && b[k] == 'R' {
+				vEnd = k + 1
+			}
+		}
+		out[key] = strings.TrimSpace(string(b[vStart:vEnd]))
+		if vEnd <= i {
+			return out
+		}
+		i = vEnd
+	}
+}
+
+func dictName(dict map[string]string, key string) string {
+	v := strings.TrimSpace(dict[key])
+	if strings.HasPrefix(v, "/") {
+		return v
+	}
+	return ""
+}
+
+// scanValue mengembalikan posisi akhir satu nilai PDF mulai dari i.
+func scanValue(b []byte, i int) int {
+	if i >= len(b) {
+		return i
+	}
+	switch b[i] {
+	case '<':
+		if i+1 < len(b) && b[i+1] == '<' {
+			return matchDict(b, i)
+		}
+		k := bytes.IndexByte(b[i:], '>')
+		if k < 0 {
+			return len(b)
+		}
+		return i + k + 1
+	case '[':
+		depth := 0
+		for j := i; j < len(b); j++ {
+			switch b[j] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			case '(':
+				j = scanString(b, j) - 1
+			}
+		}
+		return len(b)
+	case '(':
+		return scanString(b, i)
+	case '/':
+		return scanName(b, i)
+	}
+	j := i
+	for j < len(b) && !isDelim(b[j]) {
+		j++
+	}
+	if j == i {
+		j++
+	}
+	return j
+}
+
+func scanName(b []byte, i int) int {
+	j := i + 1
+	for j < len(b) && !isDelim(b[j]) {
+		j++
+	}
+	return j
+}
+
+func scanString(b []byte, i int) int {
+	depth := 0
+	for j := i; j < len(b); j++ {
+		switch b[j] {
+		case '\\':
+			j++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(b)
+}
+
+// matchDict posisi setelah ">>" penutup dictionary yang dibuka di i.
+func matchDict(b []byte, i int) int {
+	depth := 0
+	for j := i; j+1 < len(b); j++ {
+		switch {
+		case b[j] == '(':
+			j = scanString(b, j) - 1
+		case b[j] == '<' && b[j+1] == '<':
+			depth++
+			j++
+		case b[j] == '>' && b[j+1] == '>':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(b)
+}
+
+func skipSpace(b []byte, i int) int {
+	for i < len(b) {
+		switch b[i] {
+		case ' ', '\t', '\r', '\n', '\f', 0:
+			i++
+		case '%': // komentar sampai akhir baris
+			for i < len(b) && b[i] != '\n' && b[i] != '\r' {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func isDelim(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00()<>[]{}/%", c) >= 0
+}
+
+func isDigit(b []byte, i int) bool {
+	return i < len(b) && b[i] >= '0' && b[i] <= '9'
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
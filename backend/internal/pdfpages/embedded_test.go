@@ -0,0 +1,174 @@
+package pdfpages
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// pdfObj satu objek untuk buildPDF; stream nil = objek tanpa stream.
+type pdfObj struct {
+	dict   string
+	stream []byte
+}
+
+// buildPDF menyusun PDF minimal dengan objek bernomor 1..n (tanpa xref; parser tidak membutuhkannya).
+func buildPDF(objs ...pdfObj) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+	for i, o := range objs {
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		if o.stream == nil {
+			b.WriteString(o.dict)
+		} else {
+			fmt.Fprintf(&b, "%s\nstream\n", strings.Replace(o.dict, ">>", fmt.Sprintf(" /Length %d >>", len(o.stream)), 1))
+			b.Write(o.stream)
+			b.WriteString("\nendstream")
+		}
+		b.WriteString("\nendobj\n")
+	}
+	b.WriteString("%%EOF\n")
+	return b.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	zw.Write(data)
+	zw.Close()
+	return b.Bytes()
+}
+
+// imagePDF satu halaman berisi satu image XObject (objek 4).
+func imagePDF(imageDict string, stream []byte) []byte {
+	return buildPDF(
+		pdfObj{dict: "<< /Type /Catalog /Pages 2 0 R >>"},
+		pdfObj{dict: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"},
+		pdfObj{dict: "<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>"},
+		pdfObj{dict: "<< /Type /XObject /Subtype /Image " + imageDict + " >>", stream: stream},
+		pdfObj{dict: "<< >>", stream: []byte("q 10 0 0 10 0 0 cm /Im0 Do Q")},
+	)
+}
+
+// objStmPDF catalog dan page tree disimpan di object stream dengan header dan /First yang diberikan.
+func objStmPDF(header string, first int, filter string, body []byte) []byte {
+	content := append([]byte(header), body...)
+	if filter == "/FlateDecode" {
+		content = deflate(content)
+	}
+	dict := fmt.Sprintf("<< /Type /ObjStm /N 2 /First %d", first)
+	if filter != "" {
+		dict += " /Filter " + filter
+	}
+	return buildPDF(pdfObj{dict: dict + " >>", stream: content})
+}
+
+func TestEmbeddedRasterizer(t *testing.T) {
+	gray := bytes.Repeat([]byte{0x80}, 4*3)
+	objBody := "<< /Type /Catalog /Pages 11 0 R >> << /Type /Pages /Kids [] /Count 0 >>"
+	validHeader := fmt.Sprintf("10 0 11 %d ", len("<< /Type /Catalog /Pages 11 0 R >> "))
+
+	tests := []struct {
+		name    string
+		pdf     []byte
+		wantErr string // "" = sukses
+		wantW   int
+		wantH   int
+	}{
+		{name: "flate gray 8-bit", pdf: imagePDF("/Width 4 /Height 3 /BitsPerComponent 8 /ColorSpace /DeviceGray /Filter /FlateDecode", deflate(gray)), wantW: 4, wantH: 3},
+		{name: "1-bit mask", pdf: imagePDF("/Width 9 /Height 2 /ImageMask true", []byte{0xff, 0x80, 0x00, 0x00}), wantW: 9, wantH: 2},
+		{name: "jpeg apa adanya", pdf: imagePDF("/Width 4 /Height 3 /Filter /DCTDecode", []byte("\xff\xd8jpeg")), wantW: -1},
+		{name: "lebar nol", pdf: imagePDF("/Width 0 /Height 3 /BitsPerComponent 8 /ColorSpace /DeviceGray", gray), wantErr: "tidak valid"},
+		{name: "lebar negatif", pdf: imagePDF("/Width -4 /Height 3 /BitsPerComponent 8 /ColorSpace /DeviceGray", gray), wantErr: "tidak valid"},
+		{name: "dimensi raksasa", pdf: imagePDF("/Width 1000000 /Height 1000000 /BitsPerComponent 8 /ColorSpace /DeviceGray", gray), wantErr: "melebihi batas"},
+		{name: "dimensi overflow int", pdf: imagePDF("/Width 4294967296 /Height 4294967296 /BitsPerComponent 8 /ColorSpace /DeviceRGB", gray), wantErr: "melebihi batas"},
+		{name: "1-bit terpotong", pdf: imagePDF("/Width 5000 /Height 5000 /ImageMask true", []byte{0xff}), wantErr: "1-bit terpotong"},
+		{name: "gray terpotong", pdf: imagePDF("/Width 5000 /Height 5000 /BitsPerComponent 8 /ColorSpace /DeviceGray", gray), wantErr: "terpotong"},
+		{name: "cmyk terpotong", pdf: imagePDF("/Width 4 /Height 3 /BitsPerComponent 8 /ColorSpace /DeviceCMYK", gray), wantErr: "terpotong"},
+		{name: "bit depth tidak didukung", pdf: imagePDF("/Width 4 /Height 3 /BitsPerComponent 16 /ColorSpace /DeviceGray", gray), wantErr: "16 bit"},
+		{name: "predictor colors tidak valid", pdf: imagePDF("/Width 4 /Height 3 /BitsPerComponent 8 /DecodeParms << /Predictor 12 /Colors 1000000 >>", gray), wantErr: "/Colors"},
+		{name: "filter tidak didukung", pdf: imagePDF("/Width 4 /Height 3 /BitsPerComponent 1 /Filter /CCITTFaxDecode", gray), wantErr: "CCITTFaxDecode"},
+		{name: "DCT di tengah rantai", pdf: imagePDF("/Width 4 /Height 3 /Filter [/DCTDecode /FlateDecode]", gray), wantErr: "di tengah rantai"},
+		{name: "flate rusak", pdf: imagePDF("/Width 4 /Height 3 /BitsPerComponent 8 /ColorSpace /DeviceGray /Filter /FlateDecode", []byte("bukan zlib")), wantErr: "flate"},
+		{name: "objstm valid tanpa halaman", pdf: objStmPDF(validHeader, len(validHeader), "/FlateDecode", []byte(objBody)), wantErr: "tidak berisi halaman"},
+		{name: "objstm /First negatif", pdf: objStmPDF(validHeader, -5, "", []byte(objBody)), wantErr: "/First"},
+		{name: "objstm /First lewat data", pdf: objStmPDF(validHeader, 10000, "", []byte(objBody)), wantErr: "/First"},
+		{name: "objstm offset negatif", pdf: objStmPDF("10 -3 11 10 ", 10, "", []byte(objBody)), wantErr: "di luar data"},
+		{name: "objstm offset lewat data", pdf: objStmPDF("10 0 11 90000 ", 12, "", []byte(objBody)), wantErr: "di luar data"},
+		{name: "objstm offset tidak urut", pdf: objStmPDF("10 30 11 5 ", 10, "", []byte(objBody)), wantErr: "tidak urut"},
+		{name: "objstm filter tidak didukung", pdf: objStmPDF(validHeader, len(validHeader), "/LZWDecode", []byte(objBody)), wantErr: "LZWDecode"},
+		{name: "bukan PDF", pdf: []byte("hello"), wantErr: "tidak ada objek"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := EmbeddedRasterizer{}.Rasterize(tt.pdf, 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, ingin mengandung %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if len(pages) != 1 {
+				t.Fatalf("halaman = %d, ingin 1", len(pages))
+			}
+			if tt.wantW < 0 {
+				return
+			}
+			img, err := png.Decode(bytes.NewReader(pages[0].Data))
+			if err != nil {
+				t.Fatalf("decode png: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Fatalf("ukuran = %dx%d, ingin %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestInflateLimits(t *testing.T) {
+	defer func(stream, doc int64) { maxStreamBytes, maxDocumentBytes = stream, doc }(maxStreamBytes, maxDocumentBytes)
+	maxStreamBytes, maxDocumentBytes = 1000, 1500
+	small, big := deflate(make([]byte, 800)), deflate(make([]byte, 5000))
+
+	tests := []struct {
+		name    string
+		streams [][]byte
+		wantErr bool
+	}{
+		{name: "di bawah batas", streams: [][]byte{small}},
+		{name: "tepat batas stream", streams: [][]byte{deflate(make([]byte, 1000))}},
+		{name: "melebihi batas stream", streams: [][]byte{big}, wantErr: true},
+		{name: "melebihi budget dokumen", streams: [][]byte{small, small}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &pdfDoc{budget: maxDocumentBytes}
+			var err error
+			for _, s := range tt.streams {
+				if _, err = doc.inflate(s); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageSizeCap(t *testing.T) {
+	defer func(n int) { maxImagePixels = n }(maxImagePixels)
+	maxImagePixels = 100
+	if _, err := rawToImage(make([]byte, 121), 11, 11, 8, 1); err == nil {
+		t.Fatal("gambar 121 piksel harus ditolak dengan batas 100")
+	}
+	if _, err := rawToImage(make([]byte, 100), 10, 10, 8, 1); err != nil {
+		t.Fatalf("gambar 100 piksel: %v", err)
+	}
+}
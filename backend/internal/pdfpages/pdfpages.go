@@ -0,0 +1,173 @@
+// Package pdfpages memecah dokumen PDF (timesheet hasil scan) menjadi gambar per halaman untuk ekstraksi.
+// Urutan prioritas seperti ocr.ResolveRunner: script eksternal → pdftoppm (poppler) → ekstraksi gambar scan bawaan (pure Go).
+package pdfpages
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultDPI resolusi rasterisasi jika PDF_DPI kosong (cukup untuk tulisan tangan, file tetap kecil).
+const DefaultDPI = 150
+
+// DefaultMaxPages batas halaman per PDF (satu bulan timesheet harian).
+const DefaultMaxPages = 31
+
+// Page satu halaman PDF sebagai gambar.
+type Page struct {
+	Data     []byte
+	MimeType string
+}
+
+// Rasterizer mengubah PDF menjadi gambar per halaman (maks maxPages, urut halaman).
+type Rasterizer interface {
+	Rasterize(pdf []byte, maxPages int) ([]Page, error)
+}
+
+// IsPDF cek dari magic bytes, Content-Type, atau ekstensi file.
+func IsPDF(data []byte, mimeType, fileName string) bool {
+	if bytes.HasPrefix(bytes.TrimLeft(data[:minInt(len(data), 1024)], "\x00\r\n\t "), []byte("%PDF-")) {
+		return true
+	}
+	return strings.HasPrefix(mimeType, "application/pdf") || strings.EqualFold(filepath.Ext(fileName), ".pdf")
+}
+
+// ResolveRasterizer mengembalikan Rasterizer dari config. PDF_SCRIPT_PATH diisi → script; PDFTOPPM_PATH diisi → pdftoppm;
+// else ekstraksi gambar bawaan (hanya untuk PDF hasil scan: satu gambar JPEG/Flate per halaman). Tidak pernah nil.
+func ResolveRasterizer(pdftoppmPath, scriptPath string, dpi int) Rasterizer {
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+	if p := strings.TrimSpace(scriptPath); p != "" {
+		return &scriptRasterizer{path: p, dpi: dpi}
+	}
+	if p := strings.TrimSpace(pdftoppmPath); p != "" {
+		return &pdftoppmRasterizer{path: p, dpi: dpi}
+	}
+	return EmbeddedRasterizer{}
+}
+
+// pdftoppmRasterizer memanggil `pdftoppm -r DPI -png -l N in.pdf out/page`.
+type pdftoppmRasterizer struct {
+	path string
+	dpi  int
+}
+
+func (r *pdftoppmRasterizer) Rasterize(pdf []byte, maxPages int) ([]Page, error) {
+	return runInTempDir(pdf, func(in, outDir string) *exec.Cmd {
+		args := []string{"-r", strconv.Itoa(r.dpi), "-png"}
+		if maxPages > 0 {
+			args = append(args, "-l", strconv.Itoa(maxPages))
+		}
+		args = append(args, in, filepath.Join(outDir, "page"))
+		return exec.Command(r.path, args...)
+	}, maxPages, "pdftoppm")
+}
+
+// scriptRasterizer memanggil script eksternal: `script <in.pdf> <out_dir>` dengan env PDF_DPI dan PDF_MAX_PAGES;
+// script menulis satu PNG/JPEG per halaman ke out_dir (nama file diurutkan sebagai urutan halaman).
+type scriptRasterizer struct {
+	path string
+	dpi  int
+}
+
+func (r *scriptRasterizer) Rasterize(pdf []byte, maxPages int) ([]Page, error) {
+	return runInTempDir(pdf, func(in, outDir string) *exec.Cmd {
+		cmd := exec.Command(r.path, in, outDir)
+		cmd.Env = append(os.Environ(), "PDF_DPI="+strconv.Itoa(r.dpi), "PDF_MAX_PAGES="+strconv.Itoa(maxPages))
+		return cmd
+	}, maxPages, "pdf script")
+}
+
+func runInTempDir(pdf []byte, build func(in, outDir string) *exec.Cmd, maxPages int, name string) ([]Page, error) {
+	dir, err := os.MkdirTemp("", "pdfpages-*")
+	if err != nil {
+		return nil, fmt.Errorf("%s temp dir: %w", name, err)
+	}
+	defer os.RemoveAll(dir)
+	in := filepath.Join(dir, "in.pdf")
+	if err := os.WriteFile(in, pdf, 0600); err != nil {
+		return nil, fmt.Errorf("%s write temp: %w", name, err)
+	}
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0700); err != nil {
+		return nil, err
+	}
+	cmd := build(in, outDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w; stderr: %s", name, err, stderr.String())
+	}
+	return readPageDir(outDir, maxPages)
+}
+
+var pageNumRe = regexp.MustCompile(`(\d+)\.[A-Za-z]+$`)
+
+// readPageDir membaca gambar hasil rasterisasi, urut berdasarkan nomor di akhir nama file (page-2 < page-10).
+func readPageDir(dir string, maxPages int) ([]Page, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type file struct {
+		name string
+		num  int
+		mime string
+	}
+	var files []file
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		var mime string
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".png":
+			mime = "image/png"
+		case ".jpg", ".jpeg":
+			mime = "image/jpeg"
+		default:
+			continue
+		}
+		num := -1
+		if m := pageNumRe.FindStringSubmatch(e.Name()); m != nil {
+			num, _ = strconv.Atoi(m[1])
+		}
+		files = append(files, file{e.Name(), num, mime})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].num != files[j].num {
+			return files[i].num < files[j].num
+		}
+		return files[i].name < files[j].name
+	})
+	if len(files) == 0 {
+		return nil, fmt.Errorf("PDF tidak menghasilkan halaman")
+	}
+	if maxPages > 0 && len(files) > maxPages {
+		files = files[:maxPages]
+	}
+	pages := make([]Page, 0, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f.name))
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, Page{Data: data, MimeType: f.mime})
+	}
+	return pages, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
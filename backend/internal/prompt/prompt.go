@@ -0,0 +1,115 @@
+// Package prompt merender template prompt ekstraksi (Go text/template) yang disimpan di database
+// per use case dan provider, sehingga prompt bisa di-tuning tanpa redeploy.
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Use case ekstraksi yang prompt-nya bisa di-override dari database.
+const (
+	UseCaseInvoice         = "invoice"          // satu gambar = banyak item (ExtractFromImage, 1 file)
+	UseCaseOneDay          = "one_day"          // satu gambar = satu hari (ExtractFromImage, banyak file)
+	UseCaseRowOnly         = "row_only"         // tanggal + quantity (ExtractRowFromImage)
+	UseCaseTimesheet       = "timesheet"        // OCR → JSON timesheet (Ollama text)
+	UseCaseTimesheetRepair = "timesheet_repair" // retry perbaikan JSON timesheet
+)
+
+// Provider target prompt. ProviderAny berlaku untuk semua provider jika tidak ada versi khusus.
+const (
+	ProviderGemini   = "gemini"
+	ProviderDeepSeek = "deepseek" // OpenAI-compatible /v1/chat/completions
+	ProviderOllama   = "ollama"
+	ProviderAny      = "*"
+)
+
+// UseCases daftar use case yang valid.
+var UseCases = []string{UseCaseInvoice, UseCaseOneDay, UseCaseRowOnly, UseCaseTimesheet, UseCaseTimesheetRepair}
+
+// Providers daftar provider yang valid.
+var Providers = []string{ProviderGemini, ProviderDeepSeek, ProviderOllama, ProviderAny}
+
+// Data variabel yang tersedia di template, mis. {{.QuantityUnit}}, {{if .UseBBM}}...{{end}}, {{join .ColumnDescriptions ", "}}.
+type Data struct {
+	QuantityUnit       string   // "hari" | "jam"
+	UseBBM             bool     // kolom BBM aktif
+	ColumnDescriptions []string // deskripsi kolom dari form (row_only)
+	OCRText            string   // teks OCR mentah (timesheet)
+	Layout             string   // layout OCR berkolom (timesheet)
+	BadJSON            string   // JSON yang gagal validasi (timesheet_repair)
+}
+
+var funcs = template.FuncMap{
+	"join":     strings.Join,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"contains": strings.Contains,
+	"hasColumn": func(cols []string, word string) bool {
+		for _, c := range cols {
+			lc := strings.ToLower(c)
+			if strings.Contains(lc, word) && !strings.Contains(lc, "harga") {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// Render mengeksekusi body template dengan data.
+func Render(body string, data Data) (string, error) {
+	tpl, err := template.New("prompt").Funcs(funcs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Validate memastikan template bisa di-parse dan dirender dengan data contoh.
+func Validate(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("body template kosong")
+	}
+	_, err := Render(body, Data{
+		QuantityUnit:       "hari",
+		UseBBM:             true,
+		ColumnDescriptions: []string{"Tanggal", "Jam", "Keterangan"},
+		OCRText:            "contoh teks OCR",
+		Layout:             "Jam Awal | Akhir",
+		BadJSON:            "{}",
+	})
+	return err
+}
+
+// ValidUseCase true jika use case dikenal.
+func ValidUseCase(u string) bool { return contains(UseCases, u) }
+
+// ValidProvider true jika provider dikenal.
+func ValidProvider(p string) bool { return contains(Providers, p) }
+
+// ProviderFor menentukan provider prompt dari config EXTRACT_PROVIDER + DEEPSEEK_BASE_URL (Ollama dideteksi dari URL).
+func ProviderFor(extractProvider, deepSeekBaseURL string) string {
+	if strings.TrimSpace(strings.ToLower(extractProvider)) != "deepseek" {
+		return ProviderGemini
+	}
+	u := strings.ToLower(deepSeekBaseURL)
+	if strings.Contains(u, "11434") || strings.Contains(u, "ollama") {
+		return ProviderOllama
+	}
+	return ProviderDeepSeek
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
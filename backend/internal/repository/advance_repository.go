@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// LegacyKasbon baris kasbons lama beserta bulan & anggota dari gajinya.
+type LegacyKasbon struct {
+	ID         uint
+	UserID     uint
+	SalaryID   uint
+	MemberID   string
+	Month      string
+	Tanggal    string
+	Jumlah     float64
+	Keterangan string
+	Period     string // YYYY-MM dari periode gaji (atau tanggal kasbon jika periode belum diisi)
+}
+
+// LegacyAdvance kasbon ledger (lunas, satu cicilan) beserta potongan gajinya untuk satu baris kasbons lama pada bulan month.
+func LegacyAdvance(k LegacyKasbon, month string) (*entity.MemberAdvance, *entity.AdvanceRepayment) {
+	date := k.Tanggal
+	if len(date) != len("2006-01-02") {
+		date = month + "-01"
+	}
+	amount := math.Round(k.Jumlah*100) / 100
+	legacyID, salaryID := k.ID, k.SalaryID
+	advance := &entity.MemberAdvance{
+		UserID: k.UserID, MemberID: k.MemberID, Tanggal: date, Amount: amount,
+		Installments: 1, InstallmentAmount: amount, StartMonth: month,
+		Keterangan: k.Keterangan, Status: entity.AdvancePaidOff, LegacyKasbonID: &legacyID,
+	}
+	repayment := &entity.AdvanceRepayment{
+		UserID: k.UserID, MemberID: k.MemberID, Month: month, Tanggal: date, Amount: amount,
+		Source: entity.RepaymentSalary, SalaryID: &salaryID, Keterangan: fmt.Sprintf("Dipotong dari gaji %s", month),
+	}
+	return advance, repayment
+}
+
+type AdvanceRepository interface {
+	FindAll(userID uint, memberID string) ([]entity.MemberAdvance, error)
+	FindByID(id uint) (*entity.MemberAdvance, error)
+	Create(advance *entity.MemberAdvance) error
+	Update(advance *entity.MemberAdvance) error
+	// Delete menghapus kasbon beserta seluruh cicilannya.
+	Delete(advance *entity.MemberAdvance) error
+	FindRepayments(userID uint, memberID string) ([]entity.AdvanceRepayment, error)
+	FindRepaymentsBySalary(salaryID uint) ([]entity.AdvanceRepayment, error)
+	CountRepayments(advanceID uint) (int64, error)
+	// CreateRepayment menyimpan pembayaran dan memperbarui status lunas kasbonnya.
+	CreateRepayment(repayment *entity.AdvanceRepayment) error
+	// CreateLegacy menyimpan kasbon hasil migrasi beserta potongan gajinya dalam satu transaksi.
+	CreateLegacy(advance *entity.MemberAdvance, repayment *entity.AdvanceRepayment) error
+	FindLegacyKasbons(userID uint) ([]LegacyKasbon, error)
+	FindMigrated(userID uint) ([]entity.MemberAdvance, error)
+}
+
+type advanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAdvanceRepository(db *gorm.DB) AdvanceRepository {
+	return &advanceRepository{db}
+}
+
+func (r *advanceRepository) FindAll(userID uint, memberID string) ([]entity.MemberAdvance, error) {
+	var list []entity.MemberAdvance
+	q := r.db.Where("user_id = ?", userID)
+	if memberID != "" {
+		q = q.Where("member_id = ?", memberID)
+	}
+	err := q.Order("tanggal ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *advanceRepository) FindByID(id uint) (*entity.MemberAdvance, error) {
+	var advance entity.MemberAdvance
+	err := r.db.First(&advance, id).Error
+	return &advance, err
+}
+
+func (r *advanceRepository) Create(advance *entity.MemberAdvance) error {
+	return r.db.Create(advance).Error
+}
+
+func (r *advanceRepository) Update(advance *entity.MemberAdvance) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(advance).Error; err != nil {
+			return err
+		}
+		return refreshAdvanceStatus(tx, []uint{advance.ID})
+	})
+}
+
+func (r *advanceRepository) Delete(advance *entity.MemberAdvance) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteAdvance(tx, advance)
+	})
+}
+
+func deleteAdvance(tx *gorm.DB, advance *entity.MemberAdvance) error {
+	if err := tx.Where("advance_id = ?", advance.ID).Delete(&entity.AdvanceRepayment{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(advance).Error
+}
+
+func (r *advanceRepository) FindRepayments(userID uint, memberID string) ([]entity.AdvanceRepayment, error) {
+	var list []entity.AdvanceRepayment
+	q := r.db.Where("user_id = ?", userID)
+	if memberID != "" {
+		q = q.Where("member_id = ?", memberID)
+	}
+	err := q.Order("month ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *advanceRepository) FindRepaymentsBySalary(salaryID uint) ([]entity.AdvanceRepayment, error) {
+	var list []entity.AdvanceRepayment
+	err := r.db.Where("salary_id = ?", salaryID).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *advanceRepository) CountRepayments(advanceID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&entity.AdvanceRepayment{}).Where("advance_id = ?", advanceID).Count(&n).Error
+	return n, err
+}
+
+func (r *advanceRepository) CreateRepayment(repayment *entity.AdvanceRepayment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(repayment).Error; err != nil {
+			return err
+		}
+		return refreshAdvanceStatus(tx, []uint{repayment.AdvanceID})
+	})
+}
+
+func (r *advanceRepository) CreateLegacy(advance *entity.MemberAdvance, repayment *entity.AdvanceRepayment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createLegacy(tx, advance, repayment)
+	})
+}
+
+func createLegacy(tx *gorm.DB, advance *entity.MemberAdvance, repayment *entity.AdvanceRepayment) error {
+	if err := tx.Create(advance).Error; err != nil {
+		return err
+	}
+	repayment.AdvanceID = advance.ID
+	return tx.Create(repayment).Error
+}
+
+// legacyKasbons query baris kasbons lama beserta gaji dan anggotanya.
+func legacyKasbons(db *gorm.DB) *gorm.DB {
+	return db.Table("kasbons k").
+		Select(`k.id, m.user_id, k.salary_id, s.member_id, s.month, DATE_FORMAT(k.tanggal, '%Y-%m-%d') AS tanggal, k.jumlah, k.keterangan,
+			CASE WHEN s.period_year > 0 THEN CONCAT(s.period_year, '-', LPAD(s.period_month, 2, '0'))
+			ELSE DATE_FORMAT(k.tanggal, '%Y-%m') END AS period`).
+		Joins("JOIN salaries s ON s.id = k.salary_id").
+		Joins("JOIN members m ON m.id = s.member_id")
+}
+
+// FindLegacyKasbons userID 0 = semua user.
+func (r *advanceRepository) FindLegacyKasbons(userID uint) ([]LegacyKasbon, error) {
+	var rows []LegacyKasbon
+	q := legacyKasbons(r.db)
+	if userID != 0 {
+		q = q.Where("m.user_id = ?", userID)
+	}
+	err := q.Order("k.id ASC").Scan(&rows).Error
+	return rows, err
+}
+
+// syncLegacyKasbon menyamakan kasbon ledger hasil migrasi untuk satu baris kasbons lama di dalam transaksi tulis
+// kasbon tersebut: salinan lama dihapus, lalu dibuat ulang jika kasbonnya masih ada.
+func syncLegacyKasbon(tx *gorm.DB, kasbonID uint) error {
+	var old []entity.MemberAdvance
+	if err := tx.Where("legacy_kasbon_id = ?", kasbonID).Find(&old).Error; err != nil {
+		return err
+	}
+	for i := range old {
+		if err := deleteAdvance(tx, &old[i]); err != nil {
+			return err
+		}
+	}
+	var rows []LegacyKasbon
+	if err := legacyKasbons(tx).Where("k.id = ?", kasbonID).Scan(&rows).Error; err != nil {
+		return err
+	}
+	if len(rows) == 0 || rows[0].Period == "" {
+		return nil
+	}
+	advance, repayment := LegacyAdvance(rows[0], rows[0].Period)
+	return createLegacy(tx, advance, repayment)
+}
+
+// FindMigrated kasbon hasil migrasi (legacy_kasbon_id terisi); userID 0 = semua user.
+func (r *advanceRepository) FindMigrated(userID uint) ([]entity.MemberAdvance, error) {
+	var list []entity.MemberAdvance
+	q := r.db.Where("legacy_kasbon_id IS NOT NULL")
+	if userID != 0 {
+		q = q.Where("user_id = ?", userID)
+	}
+	err := q.Find(&list).Error
+	return list, err
+}
+
+// refreshAdvanceStatus menandai kasbon paid_off bila total cicilan sudah menutup jumlahnya.
+func refreshAdvanceStatus(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Exec(`UPDATE member_advances SET status = CASE
+		WHEN (SELECT COALESCE(SUM(r.amount), 0) FROM advance_repayments r WHERE r.advance_id = member_advances.id) >= amount - 0.005
+		THEN ? ELSE ? END WHERE id IN ?`, entity.AdvancePaidOff, entity.AdvanceOpen, ids).Error
+}
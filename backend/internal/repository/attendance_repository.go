@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceFilter nilai nol = tidak difilter; From/To YYYY-MM-DD inklusif.
+type AttendanceFilter struct {
+	MemberID     string
+	ProjectID    uint
+	From         string
+	To           string
+	ApprovedOnly bool
+	IDs          []uint // kosong = semua
+}
+
+type AttendanceRepository interface {
+	FindAll(userID uint, filter AttendanceFilter) ([]entity.Attendance, error)
+	FindByID(id uint) (*entity.Attendance, error)
+	// FindByKey absensi anggota pada tanggal dan shift (kunci unik), untuk upsert input massal.
+	FindByKey(memberID, date, shift string) (*entity.Attendance, error)
+	Save(attendance *entity.Attendance) error
+	// SaveAll menyimpan beberapa absensi dalam satu transaksi (input massal per lokasi per hari).
+	SaveAll(list []entity.Attendance) error
+	Delete(id uint) error
+	// SetApproval menyetujui/membatalkan persetujuan absensi milik user; mengembalikan jumlah baris.
+	SetApproval(userID uint, ids []uint, approved bool) (int64, error)
+}
+
+type attendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceRepository{db}
+}
+
+func (r *attendanceRepository) FindAll(userID uint, filter AttendanceFilter) ([]entity.Attendance, error) {
+	var list []entity.Attendance
+	q := r.db.Where("user_id = ?", userID)
+	if filter.MemberID != "" {
+		q = q.Where("member_id = ?", filter.MemberID)
+	}
+	if filter.ProjectID > 0 {
+		q = q.Where("project_id = ?", filter.ProjectID)
+	}
+	if filter.From != "" {
+		q = q.Where("date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		q = q.Where("date <= ?", filter.To)
+	}
+	if filter.ApprovedOnly {
+		q = q.Where("approved = ?", true)
+	}
+	if len(filter.IDs) > 0 {
+		q = q.Where("id IN ?", filter.IDs)
+	}
+	err := q.Order("date ASC, member_id ASC, shift ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *attendanceRepository) FindByID(id uint) (*entity.Attendance, error) {
+	var attendance entity.Attendance
+	err := r.db.First(&attendance, id).Error
+	return &attendance, err
+}
+
+func (r *attendanceRepository) FindByKey(memberID, date, shift string) (*entity.Attendance, error) {
+	var attendance entity.Attendance
+	err := r.db.Where("member_id = ? AND date = ? AND shift = ?", memberID, date, shift).First(&attendance).Error
+	return &attendance, err
+}
+
+func (r *attendanceRepository) Save(attendance *entity.Attendance) error {
+	return r.db.Save(attendance).Error
+}
+
+func (r *attendanceRepository) SaveAll(list []entity.Attendance) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range list {
+			if err := tx.Save(&list[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *attendanceRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.Attendance{}, id).Error
+}
+
+func (r *attendanceRepository) SetApproval(userID uint, ids []uint, approved bool) (int64, error) {
+	var approvedAt *time.Time
+	if approved {
+		now := time.Now()
+		approvedAt = &now
+	}
+	res := r.db.Model(&entity.Attendance{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Updates(map[string]interface{}{"approved": approved, "approved_at": approvedAt})
+	return res.RowsAffected, res.Error
+}
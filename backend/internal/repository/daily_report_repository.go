@@ -2,17 +2,28 @@ package repository
 
 import (
 	"dashboardadminimb/internal/entity"
+	"encoding/json"
+	"errors"
+	"reflect"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrSkipMerge dikembalikan apply di MergeByDate untuk membiarkan baris apa adanya (tanpa simpan, tanpa error).
+var ErrSkipMerge = errors.New("skip merge")
+
 type DailyReportRepository interface {
 	Create(report *entity.ReportDaily) error
 	FindByProjectID(projectID uint) ([]entity.ReportDaily, error)
+	FindByProjectIDs(projectIDs []uint) ([]entity.ReportDaily, error)
 	FindByID(id uint) (*entity.ReportDaily, error)
-	Update(report *entity.ReportDaily) error
+	FindByProjectAndDate(projectID uint, date string) (*entity.ReportDaily, error)
+	Update(report *entity.ReportDaily, expectedVersion uint) (bool, error)
 	Delete(report *entity.ReportDaily) error
 	FindByDateRange(projectID uint, startDate, endDate string) ([]entity.ReportDaily, error)
+	MergeByDate(projectID uint, date string, apply func(report *entity.ReportDaily, exists bool) error) (created bool, err error)
+	ReplaceProjectReports(projectID uint, reports []entity.ReportDaily) (created, updated, deleted int, err error)
 }
 
 type dailyReportRepository struct {
@@ -24,12 +35,28 @@ func NewDailyReportRepository(db *gorm.DB) DailyReportRepository {
 }
 
 func (r *dailyReportRepository) Create(report *entity.ReportDaily) error {
-	return r.db.Create(report).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		report.Version = 1
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+		return touchProject(tx, report.ProjectID)
+	})
 }
 
 func (r *dailyReportRepository) FindByProjectID(projectID uint) ([]entity.ReportDaily, error) {
 	var reports []entity.ReportDaily
-	err := r.db.Where("project_id = ?", projectID).Preload("Images").Find(&reports).Error
+	err := r.db.Where("project_id = ?", projectID).Preload("Images").Order("date ASC").Find(&reports).Error
+	return reports, err
+}
+
+// FindByProjectIDs semua laporan harian beberapa proyek sekaligus (untuk list proyek).
+func (r *dailyReportRepository) FindByProjectIDs(projectIDs []uint) ([]entity.ReportDaily, error) {
+	var reports []entity.ReportDaily
+	if len(projectIDs) == 0 {
+		return reports, nil
+	}
+	err := r.db.Where("project_id IN ?", projectIDs).Preload("Images").Order("project_id ASC, date ASC").Find(&reports).Error
 	return reports, err
 }
 
@@ -39,17 +66,242 @@ func (r *dailyReportRepository) FindByID(id uint) (*entity.ReportDaily, error) {
 	return &report, err
 }
 
-func (r *dailyReportRepository) Update(report *entity.ReportDaily) error {
-	return r.db.Save(report).Error
+func (r *dailyReportRepository) FindByProjectAndDate(projectID uint, date string) (*entity.ReportDaily, error) {
+	var report entity.ReportDaily
+	err := r.db.Preload("Images").Where("project_id = ? AND date = ?", projectID, date).First(&report).Error
+	return &report, err
+}
+
+// Update menyimpan laporan dan mengganti daftar gambarnya (gambar yang tidak ada di report.Images dihapus).
+// expectedVersion > 0: hanya jika versi baris masih sama; return false jika versi sudah berubah.
+func (r *dailyReportRepository) Update(report *entity.ReportDaily, expectedVersion uint) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current entity.ReportDaily
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "version").First(&current, report.ID).Error; err != nil {
+			return err
+		}
+		if expectedVersion > 0 && current.Version != expectedVersion {
+			return nil
+		}
+		report.Version = current.Version + 1
+		updated = true
+		if err := saveWithImages(tx, report); err != nil {
+			return err
+		}
+		return touchProject(tx, report.ProjectID)
+	})
+	return updated, err
 }
 
 func (r *dailyReportRepository) Delete(report *entity.ReportDaily) error {
-	return r.db.Delete(report).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("report_daily_id = ?", report.ID).Delete(&entity.DailyReportImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(report).Error; err != nil {
+			return err
+		}
+		return touchProject(tx, report.ProjectID)
+	})
 }
 
 func (r *dailyReportRepository) FindByDateRange(projectID uint, startDate, endDate string) ([]entity.ReportDaily, error) {
 	var reports []entity.ReportDaily
 	err := r.db.Where("project_id = ? AND date BETWEEN ? AND ?", projectID, startDate, endDate).
-		Preload("Images").Find(&reports).Error
+		Preload("Images").Order("date ASC").Find(&reports).Error
 	return reports, err
 }
+
+// MergeByDate mengunci baris (project_id, date) lalu menjalankan apply: exists=false berarti baris baru (dibuat setelah apply).
+// Dipakai untuk update sebagian field (push cut-fill, import) tanpa menimpa field lain. Error dari apply membatalkan
+// perubahan dan dikembalikan apa adanya, kecuali ErrSkipMerge.
+func (r *dailyReportRepository) MergeByDate(projectID uint, date string, apply func(report *entity.ReportDaily, exists bool) error) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var report entity.ReportDaily
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Images").
+			Where("project_id = ? AND date = ?", projectID, date).First(&report).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			report = entity.ReportDaily{ProjectID: projectID, Date: date}
+			if err := apply(&report, false); err != nil {
+				return err
+			}
+			report.ProjectID, report.Date, report.Version = projectID, date, 1
+			created = true
+			if err := tx.Create(&report).Error; err != nil {
+				return err
+			}
+			return touchProject(tx, projectID)
+		}
+		if err != nil {
+			return err
+		}
+		version := report.Version
+		if err := apply(&report, true); err != nil {
+			if errors.Is(err, ErrSkipMerge) {
+				return nil
+			}
+			return err
+		}
+		report.Version = version + 1
+		if err := saveWithImages(tx, &report); err != nil {
+			return err
+		}
+		return touchProject(tx, projectID)
+	})
+	return created, err
+}
+
+// ReplaceProjectReports menyamakan isi tabel dengan daftar laporan (kunci: tanggal): tanggal baru dibuat,
+// tanggal yang ada diperbarui (hanya jika isinya berubah, agar versinya tidak naik sia-sia), tanggal yang tidak ada
+// di daftar dihapus. Semua dalam satu transaksi.
+func (r *dailyReportRepository) ReplaceProjectReports(projectID uint, reports []entity.ReportDaily) (created, updated, deleted int, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		created, updated, deleted, err = replaceDailyReports(tx, projectID, reports)
+		return err
+	})
+	return
+}
+
+// replaceDailyReports isi ReplaceProjectReports di dalam transaksi tx (juga dipakai ProjectRepository.*WithDaily).
+func replaceDailyReports(tx *gorm.DB, projectID uint, reports []entity.ReportDaily) (created, updated, deleted int, err error) {
+	err = func() error {
+		var existing []entity.ReportDaily
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Images").Where("project_id = ?", projectID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byDate := make(map[string]entity.ReportDaily, len(existing))
+		for _, e := range existing {
+			byDate[e.Date] = e
+		}
+		keep := map[string]bool{}
+		for i := range reports {
+			rep := reports[i]
+			rep.ProjectID = projectID
+			keep[rep.Date] = true
+			if old, ok := byDate[rep.Date]; ok {
+				if sameDailyReport(old, rep) {
+					continue
+				}
+				rep.ID = old.ID
+				rep.CreatedAt = old.CreatedAt
+				rep.Version = old.Version + 1
+				if err := saveWithImages(tx, &rep); err != nil {
+					return err
+				}
+				updated++
+				continue
+			}
+			rep.ID, rep.Version = 0, 1
+			for j := range rep.Images {
+				rep.Images[j].ID = 0
+				rep.Images[j].ReportDailyID = 0
+			}
+			if err := tx.Create(&rep).Error; err != nil {
+				return err
+			}
+			created++
+		}
+		var removeIDs []uint
+		for _, e := range existing {
+			if !keep[e.Date] {
+				removeIDs = append(removeIDs, e.ID)
+			}
+		}
+		if len(removeIDs) > 0 {
+			if err := tx.Where("report_daily_id IN ?", removeIDs).Delete(&entity.DailyReportImage{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", removeIDs).Delete(&entity.ReportDaily{}).Error; err != nil {
+				return err
+			}
+			deleted = len(removeIDs)
+		}
+		if created+updated+deleted == 0 {
+			return nil
+		}
+		return touchProject(tx, projectID)
+	}()
+	return
+}
+
+// touchProject menaikkan versi proyek: laporan harian bagian dari representasi proyek (reports.daily),
+// jadi ETag proyek harus berubah setiap barisnya berubah.
+func touchProject(tx *gorm.DB, projectID uint) error {
+	return tx.Model(&entity.Project{}).Where("id = ?", projectID).UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
+
+// sameDailyReport true jika isi laporan (tanpa ID/versi/timestamp) tidak berubah.
+func sameDailyReport(a, b entity.ReportDaily) bool {
+	if a.Revenue != b.Revenue || a.Paid != b.Paid || a.Volume != b.Volume || a.TargetVolume != b.TargetVolume ||
+		a.Plan != b.Plan || a.Aktual != b.Aktual || a.TotalWorkers != b.TotalWorkers || a.TotalEquipment != b.TotalEquipment ||
+		a.Ritase != b.Ritase || a.Cuaca != b.Cuaca || a.Catatan != b.Catatan {
+		return false
+	}
+	if !sameJSON(a.Workers, b.Workers) || !sameJSON(a.Equipment, b.Equipment) || len(a.Images) != len(b.Images) {
+		return false
+	}
+	for i := range a.Images {
+		if a.Images[i].ImagePath != b.Images[i].ImagePath || a.Images[i].Description != b.Images[i].Description {
+			return false
+		}
+	}
+	return true
+}
+
+// sameJSON membandingkan isi JSON; kosong/null dianggap sama dengan {}.
+func sameJSON(a, b []byte) bool {
+	var va, vb interface{}
+	if len(a) > 0 {
+		_ = json.Unmarshal(a, &va)
+	}
+	if len(b) > 0 {
+		_ = json.Unmarshal(b, &vb)
+	}
+	if va == nil {
+		va = map[string]interface{}{}
+	}
+	if vb == nil {
+		vb = map[string]interface{}{}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// saveWithImages simpan baris laporan lalu sinkronkan gambar: ID yang sudah milik laporan ini di-update,
+// sisanya dibuat baru, gambar lama yang tidak disebut dihapus.
+func saveWithImages(tx *gorm.DB, report *entity.ReportDaily) error {
+	if err := tx.Omit(clause.Associations).Save(report).Error; err != nil {
+		return err
+	}
+	var current []entity.DailyReportImage
+	if err := tx.Where("report_daily_id = ?", report.ID).Find(&current).Error; err != nil {
+		return err
+	}
+	owned := make(map[uint]bool, len(current))
+	for _, img := range current {
+		owned[img.ID] = true
+	}
+	keep := map[uint]bool{}
+	for i := range report.Images {
+		img := &report.Images[i]
+		img.ReportDailyID = report.ID
+		if !owned[img.ID] {
+			img.ID = 0
+		}
+		if err := tx.Save(img).Error; err != nil {
+			return err
+		}
+		keep[img.ID] = true
+	}
+	var remove []uint
+	for _, img := range current {
+		if !keep[img.ID] {
+			remove = append(remove, img.ID)
+		}
+	}
+	if len(remove) > 0 {
+		return tx.Where("id IN ?", remove).Delete(&entity.DailyReportImage{}).Error
+	}
+	return nil
+}
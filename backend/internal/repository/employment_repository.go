@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type EmploymentRepository interface {
+	FindContracts(memberID string) ([]entity.MemberContract, error)
+	FindContractByID(id uint) (*entity.MemberContract, error)
+	// SaveContract menyimpan kontrak beserta peristiwa "contract"-nya (dibuat/diperbarui) dalam satu transaksi.
+	SaveContract(contract *entity.MemberContract, event *entity.MemberEmploymentEvent) error
+	// DeleteContract menghapus kontrak dan peristiwa "contract"-nya.
+	DeleteContract(id uint) error
+	// FindEvents riwayat anggota urut EffectiveDate lalu ID (urutan penerapan).
+	FindEvents(memberID string) ([]entity.MemberEmploymentEvent, error)
+	FindEventByID(id uint) (*entity.MemberEmploymentEvent, error)
+	FindEventByContract(contractID uint) (*entity.MemberEmploymentEvent, error)
+	SaveEvent(event *entity.MemberEmploymentEvent) error
+	DeleteEvent(id uint) error
+	// FindMemberIDs anggota (lintas user) yang punya riwayat, untuk job penerapan perubahan bertanggal.
+	FindMemberIDs() ([]string, error)
+}
+
+type employmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEmploymentRepository(db *gorm.DB) EmploymentRepository {
+	return &employmentRepository{db}
+}
+
+func (r *employmentRepository) FindContracts(memberID string) ([]entity.MemberContract, error) {
+	var list []entity.MemberContract
+	err := r.db.Where("member_id = ?", memberID).Order("start_date ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *employmentRepository) FindContractByID(id uint) (*entity.MemberContract, error) {
+	var contract entity.MemberContract
+	err := r.db.First(&contract, id).Error
+	return &contract, err
+}
+
+func (r *employmentRepository) SaveContract(contract *entity.MemberContract, event *entity.MemberEmploymentEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(contract).Error; err != nil {
+			return err
+		}
+		event.ContractID = &contract.ID
+		return tx.Save(event).Error
+	})
+}
+
+func (r *employmentRepository) DeleteContract(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("contract_id = ?", id).Delete(&entity.MemberEmploymentEvent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.MemberContract{}, id).Error
+	})
+}
+
+func (r *employmentRepository) FindEvents(memberID string) ([]entity.MemberEmploymentEvent, error) {
+	var list []entity.MemberEmploymentEvent
+	err := r.db.Where("member_id = ?", memberID).Order("effective_date ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *employmentRepository) FindEventByID(id uint) (*entity.MemberEmploymentEvent, error) {
+	var event entity.MemberEmploymentEvent
+	err := r.db.First(&event, id).Error
+	return &event, err
+}
+
+func (r *employmentRepository) FindEventByContract(contractID uint) (*entity.MemberEmploymentEvent, error) {
+	var event entity.MemberEmploymentEvent
+	err := r.db.Where("contract_id = ?", contractID).First(&event).Error
+	return &event, err
+}
+
+func (r *employmentRepository) SaveEvent(event *entity.MemberEmploymentEvent) error {
+	return r.db.Save(event).Error
+}
+
+func (r *employmentRepository) DeleteEvent(id uint) error {
+	return r.db.Delete(&entity.MemberEmploymentEvent{}, id).Error
+}
+
+func (r *employmentRepository) FindMemberIDs() ([]string, error) {
+	var ids []string
+	err := r.db.Model(&entity.MemberEmploymentEvent{}).Distinct().Pluck("member_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EquipmentAssignmentFilter filter daftar penempatan; nilai nol = tidak difilter. Date = aktif pada tanggal itu.
+type EquipmentAssignmentFilter struct {
+	EquipmentID uint
+	ProjectID   uint
+	Date        string
+}
+
+type EquipmentAssignmentRepository interface {
+	FindAll(userID uint, filter EquipmentAssignmentFilter) ([]entity.EquipmentDeployment, error)
+	FindByID(id uint) (*entity.EquipmentAssignment, error)
+	// Save membuat/memperbarui penempatan jika tidak tumpang tindih; return penempatan yang bentrok (tidak disimpan).
+	Save(a *entity.EquipmentAssignment) ([]entity.EquipmentDeployment, error)
+	Delete(id uint) error
+}
+
+type equipmentAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEquipmentAssignmentRepository(db *gorm.DB) EquipmentAssignmentRepository {
+	return &equipmentAssignmentRepository{db}
+}
+
+func deploymentQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("equipment_assignments AS a").
+		Select("a.*, e.name AS equipment_name, e.type AS equipment_type, e.license_plate, p.name AS project_name, m.full_name AS operator_name").
+		Joins("LEFT JOIN equipment e ON e.id = a.equipment_id").
+		Joins("LEFT JOIN projects p ON p.id = a.project_id").
+		Joins("LEFT JOIN members m ON m.id = a.operator_id")
+}
+
+// activeOn rentang [from, to] (to NULL = terbuka) mencakup tanggal date.
+func activeOn(q *gorm.DB, date string) *gorm.DB {
+	return q.Where("a.from_date <= ? AND (a.to_date IS NULL OR a.to_date >= ?)", date, date)
+}
+
+func (r *equipmentAssignmentRepository) FindAll(userID uint, filter EquipmentAssignmentFilter) ([]entity.EquipmentDeployment, error) {
+	var list []entity.EquipmentDeployment
+	q := deploymentQuery(r.db).Where("a.user_id = ?", userID)
+	if filter.EquipmentID > 0 {
+		q = q.Where("a.equipment_id = ?", filter.EquipmentID)
+	}
+	if filter.ProjectID > 0 {
+		q = q.Where("a.project_id = ?", filter.ProjectID)
+	}
+	if filter.Date != "" {
+		q = activeOn(q, filter.Date)
+	}
+	err := q.Order("a.from_date ASC, a.id ASC").Scan(&list).Error
+	return list, err
+}
+
+func (r *equipmentAssignmentRepository) FindByID(id uint) (*entity.EquipmentAssignment, error) {
+	var a entity.EquipmentAssignment
+	err := r.db.First(&a, id).Error
+	return &a, err
+}
+
+// Save mengunci baris equipment agar dua penempatan untuk unit yang sama tidak lolos cek bersamaan.
+func (r *equipmentAssignmentRepository) Save(a *entity.EquipmentAssignment) ([]entity.EquipmentDeployment, error) {
+	var conflicts []entity.EquipmentDeployment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var equipment entity.Equipment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&equipment, a.EquipmentID).Error; err != nil {
+			return err
+		}
+		// Dua rentang bertumpuk jika awal satu <= akhir yang lain dan sebaliknya (akhir NULL = tak terbatas).
+		q := deploymentQuery(tx).Where("a.equipment_id = ? AND a.id <> ?", a.EquipmentID, a.ID).
+			Where("a.to_date IS NULL OR a.to_date >= ?", a.FromDate)
+		if a.ToDate != nil {
+			q = q.Where("a.from_date <= ?", *a.ToDate)
+		}
+		if err := q.Order("a.from_date ASC").Scan(&conflicts).Error; err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return nil
+		}
+		return tx.Save(a).Error
+	})
+	return conflicts, err
+}
+
+func (r *equipmentAssignmentRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.EquipmentAssignment{}, id).Error
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type EquipmentDocumentRepository interface {
+	// FindAll dokumen user; equipmentID 0 = semua alat.
+	FindAll(userID, equipmentID uint) ([]entity.EquipmentDocument, error)
+	// FindWithExpiry semua dokumen (lintas user) yang punya tanggal kedaluwarsa, untuk job pengecekan.
+	FindWithExpiry() ([]entity.EquipmentDocument, error)
+	FindByID(id uint) (*entity.EquipmentDocument, error)
+	Save(doc *entity.EquipmentDocument) error
+	SetNotified(id uint, stage string) error
+	Delete(id uint) error
+}
+
+type equipmentDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewEquipmentDocumentRepository(db *gorm.DB) EquipmentDocumentRepository {
+	return &equipmentDocumentRepository{db}
+}
+
+func (r *equipmentDocumentRepository) FindAll(userID, equipmentID uint) ([]entity.EquipmentDocument, error) {
+	var list []entity.EquipmentDocument
+	q := r.db.Where("user_id = ?", userID)
+	if equipmentID > 0 {
+		q = q.Where("equipment_id = ?", equipmentID)
+	}
+	err := q.Order("equipment_id ASC, doc_type ASC, expiry_date DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *equipmentDocumentRepository) FindWithExpiry() ([]entity.EquipmentDocument, error) {
+	var list []entity.EquipmentDocument
+	err := r.db.Where("expiry_date IS NOT NULL AND expiry_date <> ''").Order("expiry_date ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *equipmentDocumentRepository) FindByID(id uint) (*entity.EquipmentDocument, error) {
+	var doc entity.EquipmentDocument
+	err := r.db.First(&doc, id).Error
+	return &doc, err
+}
+
+func (r *equipmentDocumentRepository) Save(doc *entity.EquipmentDocument) error {
+	return r.db.Save(doc).Error
+}
+
+func (r *equipmentDocumentRepository) SetNotified(id uint, stage string) error {
+	return r.db.Model(&entity.EquipmentDocument{}).Where("id = ?", id).Update("notified_stage", stage).Error
+}
+
+func (r *equipmentDocumentRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.EquipmentDocument{}, id).Error
+}
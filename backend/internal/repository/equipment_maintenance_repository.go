@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type EquipmentMaintenanceRepository interface {
+	CreateReading(r *entity.EquipmentMeterReading) error
+	FindReadings(equipmentID uint) ([]entity.EquipmentMeterReading, error)
+	// MaxReading pembacaan tertinggi sampai tanggal date (inklusif); date kosong = semua.
+	MaxReading(equipmentID uint, date string) (float64, error)
+	// LatestMeters pembacaan tertinggi per alat.
+	LatestMeters(equipmentIDs []uint) (map[uint]float64, error)
+
+	FindPlans(userID, equipmentID uint) ([]entity.MaintenancePlan, error)
+	FindActivePlans() ([]entity.MaintenancePlan, error)
+	FindPlanByID(id uint) (*entity.MaintenancePlan, error)
+	SavePlan(p *entity.MaintenancePlan) error
+	SetPlanNotified(id uint, due string) error
+	DeletePlan(id uint) error
+
+	FindRecords(userID, equipmentID uint) ([]entity.ServiceRecord, error)
+	FindRecordByID(id uint) (*entity.ServiceRecord, error)
+	// CreateRecord menyimpan servis + expense Finance (jika ada) + pembacaan meter, dan memajukan
+	// servis terakhir rencana terkait, dalam satu transaksi.
+	CreateRecord(rec *entity.ServiceRecord, expense *entity.Finance) error
+	// DeleteRecord menghapus servis; expense Finance yang dibuat otomatis ikut dihapus jika deleteExpense.
+	DeleteRecord(rec *entity.ServiceRecord, deleteExpense bool) error
+}
+
+type equipmentMaintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewEquipmentMaintenanceRepository(db *gorm.DB) EquipmentMaintenanceRepository {
+	return &equipmentMaintenanceRepository{db}
+}
+
+func (r *equipmentMaintenanceRepository) CreateReading(reading *entity.EquipmentMeterReading) error {
+	return r.db.Create(reading).Error
+}
+
+func (r *equipmentMaintenanceRepository) FindReadings(equipmentID uint) ([]entity.EquipmentMeterReading, error) {
+	var list []entity.EquipmentMeterReading
+	err := r.db.Where("equipment_id = ?", equipmentID).Order("date DESC, id DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *equipmentMaintenanceRepository) MaxReading(equipmentID uint, date string) (float64, error) {
+	var max float64
+	q := r.db.Model(&entity.EquipmentMeterReading{}).Select("COALESCE(MAX(reading), 0)").Where("equipment_id = ?", equipmentID)
+	if date != "" {
+		q = q.Where("date <= ?", date)
+	}
+	err := q.Scan(&max).Error
+	return max, err
+}
+
+func (r *equipmentMaintenanceRepository) LatestMeters(equipmentIDs []uint) (map[uint]float64, error) {
+	out := map[uint]float64{}
+	if len(equipmentIDs) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		EquipmentID uint
+		Reading     float64
+	}
+	err := r.db.Model(&entity.EquipmentMeterReading{}).Select("equipment_id, MAX(reading) AS reading").
+		Where("equipment_id IN ?", equipmentIDs).Group("equipment_id").Scan(&rows).Error
+	for _, row := range rows {
+		out[row.EquipmentID] = row.Reading
+	}
+	return out, err
+}
+
+func (r *equipmentMaintenanceRepository) FindPlans(userID, equipmentID uint) ([]entity.MaintenancePlan, error) {
+	var list []entity.MaintenancePlan
+	q := r.db.Where("user_id = ?", userID)
+	if equipmentID > 0 {
+		q = q.Where("equipment_id = ?", equipmentID)
+	}
+	err := q.Order("equipment_id ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *equipmentMaintenanceRepository) FindActivePlans() ([]entity.MaintenancePlan, error) {
+	var list []entity.MaintenancePlan
+	err := r.db.Where("active = ?", true).Order("user_id ASC, equipment_id ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *equipmentMaintenanceRepository) FindPlanByID(id uint) (*entity.MaintenancePlan, error) {
+	var p entity.MaintenancePlan
+	err := r.db.First(&p, id).Error
+	return &p, err
+}
+
+func (r *equipmentMaintenanceRepository) SavePlan(p *entity.MaintenancePlan) error {
+	return r.db.Save(p).Error
+}
+
+func (r *equipmentMaintenanceRepository) SetPlanNotified(id uint, due string) error {
+	return r.db.Model(&entity.MaintenancePlan{}).Where("id = ?", id).Update("notified_due", due).Error
+}
+
+func (r *equipmentMaintenanceRepository) DeletePlan(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ServiceRecord{}).Where("plan_id = ?", id).Update("plan_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.MaintenancePlan{}, id).Error
+	})
+}
+
+func (r *equipmentMaintenanceRepository) FindRecords(userID, equipmentID uint) ([]entity.ServiceRecord, error) {
+	var list []entity.ServiceRecord
+	err := r.db.Where("user_id = ? AND equipment_id = ?", userID, equipmentID).Order("date DESC, id DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *equipmentMaintenanceRepository) FindRecordByID(id uint) (*entity.ServiceRecord, error) {
+	var rec entity.ServiceRecord
+	err := r.db.First(&rec, id).Error
+	return &rec, err
+}
+
+func (r *equipmentMaintenanceRepository) CreateRecord(rec *entity.ServiceRecord, expense *entity.Finance) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if expense != nil {
+			if err := tx.Create(expense).Error; err != nil {
+				return err
+			}
+			rec.FinanceID = &expense.ID
+		}
+		if err := tx.Create(rec).Error; err != nil {
+			return err
+		}
+		if rec.Meter > 0 {
+			reading := &entity.EquipmentMeterReading{UserID: rec.UserID, EquipmentID: rec.EquipmentID, Date: rec.Date, Reading: rec.Meter, Notes: "Servis: " + rec.Description}
+			if err := tx.Create(reading).Error; err != nil {
+				return err
+			}
+		}
+		if rec.PlanID == nil {
+			return nil
+		}
+		// Servis lebih lama dari servis terakhir yang tercatat tidak memundurkan rencana.
+		return tx.Model(&entity.MaintenancePlan{}).
+			Where("id = ? AND (last_service_date IS NULL OR last_service_date <= ?)", *rec.PlanID, rec.Date).
+			Updates(map[string]interface{}{"last_service_meter": rec.Meter, "last_service_date": rec.Date, "notified_due": ""}).Error
+	})
+}
+
+func (r *equipmentMaintenanceRepository) DeleteRecord(rec *entity.ServiceRecord, deleteExpense bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if deleteExpense && rec.FinanceID != nil {
+			if err := tx.Delete(&entity.Finance{}, *rec.FinanceID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&entity.ServiceRecord{}, rec.ID).Error
+	})
+}
@@ -25,6 +25,7 @@ type FinanceRepository interface {
 	GetFinanceByStatus(userID uint, status string) ([]entity.Finance, error)
 	GetMonthlySummaryByEquipment(userID uint, monthYYYYMM string) ([]entity.EquipmentMonthlyFinanceRow, error)
 	SumLifetimeFinanceByEquipment(userID uint) ([]entity.EquipmentFinanceSumRow, error)
+	FindByEquipment(userID uint) ([]entity.Finance, error)
 }
 
 type financeRepository struct {
@@ -152,6 +153,13 @@ func (r *financeRepository) SumLifetimeFinanceByEquipment(userID uint) ([]entity
 	return rows, err
 }
 
+// FindByEquipment semua transaksi yang ditautkan ke alat (equipment_id terisi).
+func (r *financeRepository) FindByEquipment(userID uint) ([]entity.Finance, error) {
+	var finances []entity.Finance
+	err := r.db.Where("user_id = ? AND equipment_id IS NOT NULL", userID).Find(&finances).Error
+	return finances, err
+}
+
 func (r *financeRepository) FindAllWithPagination(params response.QueryParams, userID uint) ([]entity.Finance, int, error) {
 	var finances []entity.Finance
 
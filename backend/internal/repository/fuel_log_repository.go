@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// FuelLogFilter nilai nol = tidak difilter; From/To YYYY-MM-DD inklusif.
+type FuelLogFilter struct {
+	EquipmentID uint
+	ProjectID   uint
+	From        string
+	To          string
+}
+
+type FuelLogRepository interface {
+	FindAll(userID uint, filter FuelLogFilter) ([]entity.FuelLog, error)
+	FindByID(id uint) (*entity.FuelLog, error)
+	Save(log *entity.FuelLog) error
+	Delete(id uint) error
+}
+
+type fuelLogRepository struct {
+	db *gorm.DB
+}
+
+func NewFuelLogRepository(db *gorm.DB) FuelLogRepository {
+	return &fuelLogRepository{db}
+}
+
+// FindAll urut per alat lalu kronologis (tanggal, meter), urutan yang dipakai untuk menghitung konsumsi.
+func (r *fuelLogRepository) FindAll(userID uint, filter FuelLogFilter) ([]entity.FuelLog, error) {
+	var list []entity.FuelLog
+	q := r.db.Where("user_id = ?", userID)
+	if filter.EquipmentID > 0 {
+		q = q.Where("equipment_id = ?", filter.EquipmentID)
+	}
+	if filter.ProjectID > 0 {
+		q = q.Where("project_id = ?", filter.ProjectID)
+	}
+	if filter.From != "" {
+		q = q.Where("date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		q = q.Where("date <= ?", filter.To)
+	}
+	err := q.Order("equipment_id ASC, date ASC, meter_reading ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *fuelLogRepository) FindByID(id uint) (*entity.FuelLog, error) {
+	var log entity.FuelLog
+	err := r.db.First(&log, id).Error
+	return &log, err
+}
+
+func (r *fuelLogRepository) Save(log *entity.FuelLog) error {
+	return r.db.Save(log).Error
+}
+
+func (r *fuelLogRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.FuelLog{}, id).Error
+}
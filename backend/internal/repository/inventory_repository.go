@@ -11,6 +11,8 @@ import (
 type InventoryRepository interface {
 	CreateCategory(category *entity.InventoryCategory) error
 	UpdateCategory(category *entity.InventoryCategory) error
+	// UpdateCategoryWithData menyimpan kategori beserta values item yang ditulis ulang (perubahan skema kolom) dalam satu transaksi.
+	UpdateCategoryWithData(category *entity.InventoryCategory, data []entity.InventoryData) error
 	DeleteCategory(id string) error
 	GetAllCategories(userID uint) ([]entity.InventoryCategory, error)
 	GetCategoryByID(id string) (*entity.InventoryCategory, error)
@@ -40,8 +42,28 @@ func (r *inventoryRepository) UpdateCategory(category *entity.InventoryCategory)
 	return r.db.Save(category).Error
 }
 
+func (r *inventoryRepository) UpdateCategoryWithData(category *entity.InventoryCategory, data []entity.InventoryData) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Data").Save(category).Error; err != nil {
+			return err
+		}
+		for i := range data {
+			if err := tx.Model(&entity.InventoryData{}).Where("id = ?", data[i].ID).Update("values", data[i].Values).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteCategory ikut menghapus buku stok item di kategori tersebut.
 func (r *inventoryRepository) DeleteCategory(id string) error {
-	return r.db.Where("id = ?", id).Delete(&entity.InventoryCategory{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&entity.StockMovement{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&entity.InventoryCategory{}).Error
+	})
 }
 
 func (r *inventoryRepository) GetAllCategories(userID uint) ([]entity.InventoryCategory, error) {
@@ -64,8 +86,14 @@ func (r *inventoryRepository) UpdateData(data *entity.InventoryData) error {
 	return r.db.Save(data).Error
 }
 
+// DeleteData ikut menghapus buku stok item.
 func (r *inventoryRepository) DeleteData(id string) error {
-	return r.db.Where("id = ?", id).Delete(&entity.InventoryData{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("item_id = ?", id).Delete(&entity.StockMovement{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&entity.InventoryData{}).Error
+	})
 }
 
 func (r *inventoryRepository) GetDataByCategory(categoryID string) ([]entity.InventoryData, error) {
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockFilter filter mutasi/saldo stok; nilai nol = tidak difilter. From/To (YYYY-MM-DD) hanya untuk mutasi.
+type StockFilter struct {
+	ItemID     string
+	CategoryID string
+	LocationID uint
+	ProjectID  uint
+	From       string
+	To         string
+}
+
+type InventoryStockRepository interface {
+	FindLocations(userID uint) ([]entity.StockLocation, error)
+	FindLocationByID(id uint) (*entity.StockLocation, error)
+	SaveLocation(l *entity.StockLocation) error
+	DeleteLocation(id uint) error
+	CountMovementsAt(locationID uint) (int64, error)
+
+	// FindMovements mutasi urut Date lalu ID (urutan buku stok).
+	FindMovements(userID uint, filter StockFilter) ([]entity.StockMovementEntry, error)
+	FindMovementByID(id uint) (*entity.StockMovement, error)
+	// SaveMovement mengunci baris item lalu menyimpan mutasi; jika saldo berjalan di lokasi asal/tujuan menjadi
+	// negatif pada tanggal mutasi atau sesudahnya, mutasi dibatalkan dan saldo pertama yang negatif dikembalikan.
+	SaveMovement(m *entity.StockMovement) (*entity.StockBalance, error)
+	// DeleteMovement sama seperti SaveMovement untuk penghapusan (mis. menghapus penerimaan yang sudah dipakai).
+	DeleteMovement(m *entity.StockMovement) (*entity.StockBalance, error)
+	// SaveCategorySettings menyimpan kolom jumlah/satuan kategori beserta mutasi saldo awalnya dalam satu transaksi.
+	SaveCategorySettings(category *entity.InventoryCategory, openings []entity.StockMovement) error
+	// Balances saldo on-hand per item per lokasi (lokasi bersaldo nol tetap ikut jika pernah ada mutasi).
+	Balances(userID uint, filter StockFilter) ([]entity.StockBalance, error)
+}
+
+type inventoryStockRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryStockRepository(db *gorm.DB) InventoryStockRepository {
+	return &inventoryStockRepository{db}
+}
+
+// errStockShortfall hanya untuk membatalkan transaksi; pemanggil menerima saldo yang kurang.
+var errStockShortfall = errors.New("stock shortfall")
+
+func (r *inventoryStockRepository) FindLocations(userID uint) ([]entity.StockLocation, error) {
+	var list []entity.StockLocation
+	err := r.db.Where("user_id = ?", userID).Order("kind ASC, name ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *inventoryStockRepository) FindLocationByID(id uint) (*entity.StockLocation, error) {
+	var l entity.StockLocation
+	err := r.db.First(&l, id).Error
+	return &l, err
+}
+
+func (r *inventoryStockRepository) SaveLocation(l *entity.StockLocation) error {
+	return r.db.Save(l).Error
+}
+
+func (r *inventoryStockRepository) DeleteLocation(id uint) error {
+	return r.db.Delete(&entity.StockLocation{}, id).Error
+}
+
+func (r *inventoryStockRepository) CountMovementsAt(locationID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.StockMovement{}).
+		Where("from_location_id = ? OR to_location_id = ?", locationID, locationID).Count(&count).Error
+	return count, err
+}
+
+func (r *inventoryStockRepository) FindMovements(userID uint, filter StockFilter) ([]entity.StockMovementEntry, error) {
+	q := r.db.Table("stock_movements AS sm").
+		Select("sm.*, fl.name AS from_location_name, tl.name AS to_location_name, p.name AS project_name").
+		Joins("LEFT JOIN stock_locations fl ON fl.id = sm.from_location_id").
+		Joins("LEFT JOIN stock_locations tl ON tl.id = sm.to_location_id").
+		Joins("LEFT JOIN projects p ON p.id = sm.project_id").
+		Where("sm.user_id = ?", userID)
+	if filter.ItemID != "" {
+		q = q.Where("sm.item_id = ?", filter.ItemID)
+	}
+	if filter.CategoryID != "" {
+		q = q.Where("sm.category_id = ?", filter.CategoryID)
+	}
+	if filter.LocationID != 0 {
+		q = q.Where("sm.from_location_id = ? OR sm.to_location_id = ?", filter.LocationID, filter.LocationID)
+	}
+	if filter.ProjectID != 0 {
+		q = q.Where("sm.project_id = ?", filter.ProjectID)
+	}
+	if filter.From != "" {
+		q = q.Where("sm.date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		q = q.Where("sm.date <= ?", filter.To)
+	}
+	var list []entity.StockMovementEntry
+	err := q.Order("sm.date ASC, sm.id ASC").Scan(&list).Error
+	return list, err
+}
+
+func (r *inventoryStockRepository) FindMovementByID(id uint) (*entity.StockMovement, error) {
+	var m entity.StockMovement
+	err := r.db.First(&m, id).Error
+	return &m, err
+}
+
+func (r *inventoryStockRepository) SaveMovement(m *entity.StockMovement) (*entity.StockBalance, error) {
+	return r.writeMovement(m, func(tx *gorm.DB) error {
+		return tx.Save(m).Error
+	})
+}
+
+func (r *inventoryStockRepository) DeleteMovement(m *entity.StockMovement) (*entity.StockBalance, error) {
+	return r.writeMovement(m, func(tx *gorm.DB) error {
+		return tx.Delete(&entity.StockMovement{}, m.ID).Error
+	})
+}
+
+// writeMovement menjalankan write dengan baris item terkunci, lalu memastikan saldo berjalan lokasi yang tersentuh
+// tidak negatif sejak m.Date (mutasi bertanggal mundur tidak boleh memakai stok yang baru masuk belakangan).
+func (r *inventoryStockRepository) writeMovement(m *entity.StockMovement, write func(tx *gorm.DB) error) (*entity.StockBalance, error) {
+	var short *entity.StockBalance
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var item entity.InventoryData
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", m.ItemID).First(&item).Error; err != nil {
+			return err
+		}
+		if err := write(tx); err != nil {
+			return err
+		}
+		for _, locationID := range []*uint{m.FromLocationID, m.ToLocationID} {
+			if locationID == nil {
+				continue
+			}
+			balance, err := runningShortfall(tx, m, *locationID)
+			if err != nil {
+				return err
+			}
+			if balance != nil {
+				short = balance
+				return errStockShortfall
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errStockShortfall) {
+		return short, nil
+	}
+	return nil, err
+}
+
+// runningShortfall saldo berjalan item di lokasi urut Date lalu ID; saldo pertama yang negatif sejak m.Date, nil jika tidak ada.
+func runningShortfall(tx *gorm.DB, m *entity.StockMovement, locationID uint) (*entity.StockBalance, error) {
+	var list []entity.StockMovement
+	err := tx.Where("user_id = ? AND item_id = ? AND (from_location_id = ? OR to_location_id = ?)", m.UserID, m.ItemID, locationID, locationID).
+		Order("date ASC, id ASC").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	balance := 0.0
+	for _, mv := range list {
+		if mv.ToLocationID != nil && *mv.ToLocationID == locationID {
+			balance += mv.Quantity
+		}
+		if mv.FromLocationID != nil && *mv.FromLocationID == locationID {
+			balance -= mv.Quantity
+		}
+		// Toleransi pembulatan decimal(15,3); saldo sebelum m.Date tidak berubah oleh mutasi ini.
+		if mv.Date < m.Date || balance >= -0.0005 {
+			continue
+		}
+		short := &entity.StockBalance{ItemID: m.ItemID, LocationID: locationID, Quantity: balance, Date: mv.Date}
+		var l entity.StockLocation
+		if err := tx.Select("name").First(&l, locationID).Error; err == nil {
+			short.LocationName = l.Name
+		}
+		return short, nil
+	}
+	return nil, nil
+}
+
+func (r *inventoryStockRepository) SaveCategorySettings(category *entity.InventoryCategory, openings []entity.StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.InventoryCategory{}).Where("id = ?", category.ID).Updates(map[string]interface{}{
+			"quantity_header": category.QuantityHeader,
+			"unit_header":     category.UnitHeader,
+		}).Error; err != nil {
+			return err
+		}
+		if len(openings) == 0 {
+			return nil
+		}
+		return tx.Create(&openings).Error
+	})
+}
+
+func (r *inventoryStockRepository) Balances(userID uint, filter StockFilter) ([]entity.StockBalance, error) {
+	return stockBalances(r.db, userID, filter)
+}
+
+// stockBalances menjumlah mutasi masuk (to_location_id, +quantity) dan keluar (from_location_id, -quantity).
+// Penyesuaian negatif tercatat di to_location_id dengan quantity negatif sehingga ikut terjumlah.
+func stockBalances(db *gorm.DB, userID uint, filter StockFilter) ([]entity.StockBalance, error) {
+	where := []string{"t.user_id = ?"}
+	args := []interface{}{userID}
+	if filter.ItemID != "" {
+		where = append(where, "t.item_id = ?")
+		args = append(args, filter.ItemID)
+	}
+	if filter.CategoryID != "" {
+		where = append(where, "t.category_id = ?")
+		args = append(args, filter.CategoryID)
+	}
+	if filter.LocationID != 0 {
+		where = append(where, "t.location_id = ?")
+		args = append(args, filter.LocationID)
+	}
+	query := `
+		SELECT t.item_id, t.location_id, COALESCE(l.name, '') AS location_name, SUM(t.qty) AS quantity
+		FROM (
+			SELECT user_id, item_id, category_id, to_location_id AS location_id, quantity AS qty
+			FROM stock_movements WHERE to_location_id IS NOT NULL
+			UNION ALL
+			SELECT user_id, item_id, category_id, from_location_id AS location_id, -quantity AS qty
+			FROM stock_movements WHERE from_location_id IS NOT NULL
+		) t
+		LEFT JOIN stock_locations l ON l.id = t.location_id
+		WHERE ` + strings.Join(where, " AND ") + `
+		GROUP BY t.item_id, t.location_id, l.name
+		ORDER BY t.item_id ASC, l.name ASC`
+	var rows []entity.StockBalance
+	err := db.Raw(query, args...).Scan(&rows).Error
+	return rows, err
+}
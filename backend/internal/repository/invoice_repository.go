@@ -23,6 +23,7 @@ type InvoiceRepository interface {
 	FindByID(id uint) (*entity.Invoice, error)
 	FindAllWithPagination(params response.QueryParams, userID uint) ([]entity.Invoice, int, error)
 	FindCustomerSuggestions(userID uint, search string, limit int) ([]CustomerSuggestion, error)
+	FindEquipmentRows(userID uint) ([]entity.EquipmentInvoiceRow, error)
 }
 
 type invoiceRepository struct {
@@ -35,18 +36,23 @@ func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
 
 func (r *invoiceRepository) Create(inv *entity.Invoice) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Omit("Items").Create(inv).Error; err != nil {
+		return createInvoice(tx, inv)
+	})
+}
+
+// createInvoice menyimpan invoice beserta item-nya di dalam transaksi tx.
+func createInvoice(tx *gorm.DB, inv *entity.Invoice) error {
+	if err := tx.Omit("Items").Create(inv).Error; err != nil {
+		return err
+	}
+	for i := range inv.Items {
+		inv.Items[i].ID = 0
+		inv.Items[i].InvoiceID = inv.ID
+		if err := tx.Create(&inv.Items[i]).Error; err != nil {
 			return err
 		}
-		for i := range inv.Items {
-			inv.Items[i].ID = 0
-			inv.Items[i].InvoiceID = inv.ID
-			if err := tx.Create(&inv.Items[i]).Error; err != nil {
-				return err
-			}
-		}
-		return nil
-	})
+	}
+	return nil
 }
 
 func (r *invoiceRepository) Update(inv *entity.Invoice) error {
@@ -162,3 +168,14 @@ func (r *invoiceRepository) FindCustomerSuggestions(userID uint, search string,
 	err := r.db.Raw(query, args...).Scan(&out).Error
 	return out, err
 }
+
+// FindEquipmentRows semua baris item dari invoice user yang tidak dibatalkan.
+func (r *invoiceRepository) FindEquipmentRows(userID uint) ([]entity.EquipmentInvoiceRow, error) {
+	var rows []entity.EquipmentInvoiceRow
+	err := r.db.Table("invoice_items AS it").
+		Select("it.invoice_id, i.invoice_date, i.quantity_unit, it.item_name, it.quantity, it.days, it.total, it.row_date, it.bbm_quantity, it.bbm_unit_price").
+		Joins("INNER JOIN invoices i ON i.id = it.invoice_id").
+		Where("i.user_id = ? AND i.status <> ?", userID, entity.InvoiceStatusCancelled).
+		Scan(&rows).Error
+	return rows, err
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type KasbonRepository interface {
+	// Create, Update dan Delete ikut menyamakan salinan kasbon di ledger (member_advances) dalam transaksi yang sama.
 	Create(kasbon *entity.Kasbon) error
 	Update(kasbon *entity.Kasbon) error
 	Delete(id uint) error
@@ -26,15 +27,30 @@ func NewKasbonRepository(db *gorm.DB) KasbonRepository {
 }
 
 func (r *kasbonRepository) Create(kasbon *entity.Kasbon) error {
-	return r.db.Create(kasbon).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(kasbon).Error; err != nil {
+			return err
+		}
+		return syncLegacyKasbon(tx, kasbon.ID)
+	})
 }
 
 func (r *kasbonRepository) Update(kasbon *entity.Kasbon) error {
-	return r.db.Save(kasbon).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(kasbon).Error; err != nil {
+			return err
+		}
+		return syncLegacyKasbon(tx, kasbon.ID)
+	})
 }
 
 func (r *kasbonRepository) Delete(id uint) error {
-	return r.db.Delete(&entity.Kasbon{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.Kasbon{}, id).Error; err != nil {
+			return err
+		}
+		return syncLegacyKasbon(tx, id)
+	})
 }
 
 func (r *kasbonRepository) FindBySalaryID(salaryID uint) ([]entity.Kasbon, error) {
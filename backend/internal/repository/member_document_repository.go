@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type MemberDocumentRepository interface {
+	// FindAll dokumen user; memberID kosong = semua anggota.
+	FindAll(userID uint, memberID string) ([]entity.MemberDocument, error)
+	// FindWithExpiry semua dokumen (lintas user) yang punya tanggal kedaluwarsa, untuk job pengecekan.
+	FindWithExpiry() ([]entity.MemberDocument, error)
+	FindByID(id uint) (*entity.MemberDocument, error)
+	// FindByFile dokumen anggota dengan nama file unggahan lama (DELETE /api/members/:id/documents/:fileName).
+	FindByFile(memberID, fileName string) (*entity.MemberDocument, error)
+	Save(doc *entity.MemberDocument) error
+	SetNotified(id uint, stage string) error
+	Delete(id uint) error
+}
+
+type memberDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewMemberDocumentRepository(db *gorm.DB) MemberDocumentRepository {
+	return &memberDocumentRepository{db}
+}
+
+func (r *memberDocumentRepository) FindAll(userID uint, memberID string) ([]entity.MemberDocument, error) {
+	var list []entity.MemberDocument
+	q := r.db.Where("user_id = ?", userID)
+	if memberID != "" {
+		q = q.Where("member_id = ?", memberID)
+	}
+	err := q.Order("member_id ASC, doc_type ASC, expiry_date DESC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *memberDocumentRepository) FindWithExpiry() ([]entity.MemberDocument, error) {
+	var list []entity.MemberDocument
+	err := r.db.Where("expiry_date IS NOT NULL AND expiry_date <> ''").Order("expiry_date ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *memberDocumentRepository) FindByID(id uint) (*entity.MemberDocument, error) {
+	var doc entity.MemberDocument
+	err := r.db.First(&doc, id).Error
+	return &doc, err
+}
+
+func (r *memberDocumentRepository) FindByFile(memberID, fileName string) (*entity.MemberDocument, error) {
+	var doc entity.MemberDocument
+	err := r.db.Where("member_id = ? AND file_url = ?", memberID, fileName).First(&doc).Error
+	return &doc, err
+}
+
+func (r *memberDocumentRepository) Save(doc *entity.MemberDocument) error {
+	return r.db.Save(doc).Error
+}
+
+func (r *memberDocumentRepository) SetNotified(id uint, stage string) error {
+	return r.db.Model(&entity.MemberDocument{}).Where("id = ?", id).Update("notified_stage", stage).Error
+}
+
+func (r *memberDocumentRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.MemberDocument{}, id).Error
+}
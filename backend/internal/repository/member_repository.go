@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"encoding/json"
+	"strings"
+
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/pkg/database"
 	"dashboardadminimb/pkg/response"
@@ -14,16 +17,18 @@ type MemberRepository interface {
 	FindAllWithPagination(params response.QueryParams, userID uint) ([]entity.Member, int, error)
 	FindByID(id string) (*entity.Member, error)
 	Update(member *entity.Member) error
+	// UpdateRole hanya menulis kolom role (sinkron dengan riwayat kepegawaian).
+	UpdateRole(id, role string) error
 	Delete(member *entity.Member) error
 	Count(userID uint) (int64, error)
 	DeactivateMember(id string, reason string, deactivatedAt string) error
 	ActivateMember(id string) error
 	GetMemberTotalSalary(memberID string) (float64, error)
 	GetAllMembersTotalSalary(userID uint) (float64, error)
-	GetMemberTotalSalaryWithFilter(memberID string, year string, month string) (float64, error)
-	GetAllMembersTotalSalaryWithFilter(userID uint, year string, month string) (float64, error)
-	GetAllMembersWithSalaryInfo(userID uint, year string, month string, orderBy string) ([]MemberSalaryInfo, error)
-	GetMemberMonthlySalaryDetails(memberID string, year string) ([]MonthlySalaryDetail, error)
+	GetMemberTotalSalaryWithFilter(memberID string, period SalaryPeriod) (float64, error)
+	GetAllMembersTotalSalaryWithFilter(userID uint, period SalaryPeriod) (float64, error)
+	GetAllMembersWithSalaryInfo(userID uint, period SalaryPeriod, orderBy string) ([]MemberSalaryInfo, error)
+	GetMemberMonthlySalaryDetails(memberID string, period SalaryPeriod) ([]MonthlySalaryDetail, error)
 }
 
 type memberRepository struct {
@@ -40,22 +45,72 @@ func (r *memberRepository) Create(member *entity.Member) error {
 
 func (r *memberRepository) FindAll(userID uint) ([]entity.Member, error) {
 	var members []entity.Member
-	err := r.db.Model(&entity.Member{}).Where("user_id = ?", userID).Find(&members).Error
-	return members, err
+	if err := r.db.Model(&entity.Member{}).Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, r.attachDocuments(members)
 }
 
 func (r *memberRepository) FindByID(id string) (*entity.Member, error) {
 	var member entity.Member
-	err := r.db.Preload("Salaries").Where("id = ?", id).First(&member).Error
-	return &member, err
+	if err := r.db.Preload("Salaries").Where("id = ?", id).First(&member).Error; err != nil {
+		return &member, err
+	}
+	members := []entity.Member{member}
+	err := r.attachDocuments(members)
+	return &members[0], err
+}
+
+// attachDocuments mengisi Member.Documents dengan nama file dokumen yang diunggah lewat
+// POST /api/members/:id/documents (tanpa folder), agar tampilan daftar dokumen lama tetap jalan.
+func (r *memberRepository) attachDocuments(members []entity.Member) error {
+	if len(members) == 0 {
+		return nil
+	}
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.ID
+	}
+	var docs []entity.MemberDocument
+	err := r.db.Select("member_id, file_url").
+		Where("member_id IN ? AND file_url <> ''", ids).
+		Order("id ASC").Find(&docs).Error
+	if err != nil {
+		return err
+	}
+	names := map[string][]string{}
+	for _, d := range docs {
+		if !strings.Contains(d.FileURL, "/") {
+			names[d.MemberID] = append(names[d.MemberID], d.FileURL)
+		}
+	}
+	for i := range members {
+		list := names[members[i].ID]
+		if list == nil {
+			list = []string{}
+		}
+		members[i].Documents, _ = json.Marshal(list)
+	}
+	return nil
 }
 
 func (r *memberRepository) Update(member *entity.Member) error {
 	return r.db.Save(member).Error
 }
 
+func (r *memberRepository) UpdateRole(id, role string) error {
+	return r.db.Model(&entity.Member{}).Where("id = ?", id).Update("role", role).Error
+}
+
 func (r *memberRepository) Delete(member *entity.Member) error {
-	return r.db.Delete(member).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&entity.MemberDocument{}, &entity.MemberContract{}, &entity.MemberEmploymentEvent{}} {
+			if err := tx.Where("member_id = ?", member.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(member).Error
+	})
 }
 
 func (r *memberRepository) Count(userID uint) (int64, error) {
@@ -75,7 +130,7 @@ func (r *memberRepository) FindAllWithPagination(params response.QueryParams, us
 		return nil, 0, err
 	}
 
-	return members, total, nil
+	return members, total, r.attachDocuments(members)
 }
 
 func (r *memberRepository) DeactivateMember(id string, reason string, deactivatedAt string) error {
@@ -117,46 +172,40 @@ func (r *memberRepository) GetAllMembersTotalSalary(userID uint) (float64, error
 	return total, err
 }
 
-func (r *memberRepository) GetMemberTotalSalaryWithFilter(memberID string, year string, month string) (float64, error) {
-	var total float64
-	query := r.db.Model(&entity.Salary{}).Where("member_id = ? AND status = ?", memberID, "Paid")
+// SalaryPeriod rentang periode gaji (bulan inklusif) dalam satu tahun; Year 0 berarti semua periode.
+type SalaryPeriod struct {
+	Year      int
+	FromMonth int
+	ToMonth   int
+}
 
-	monthNames := map[string]string{
-		"01": "Januari", "02": "Februari", "03": "Maret", "04": "April",
-		"05": "Mei", "06": "Juni", "07": "Juli", "08": "Agustus",
-		"09": "September", "10": "Oktober", "11": "November", "12": "Desember",
+// condition filter rentang di atas kolom period_year/period_month (memakai idx_salaries_member_period).
+func (p SalaryPeriod) condition() (string, []interface{}) {
+	if p.Year == 0 {
+		return "", nil
 	}
+	return "salaries.period_year = ? AND salaries.period_month BETWEEN ? AND ?",
+		[]interface{}{p.Year, p.FromMonth, p.ToMonth}
+}
 
-	if year != "" && month != "" {
-		monthName := monthNames[month]
-		query = query.Where("month = ?", monthName+" "+year)
-	} else if year != "" {
-		query = query.Where("month LIKE ?", "% "+year)
+func (r *memberRepository) GetMemberTotalSalaryWithFilter(memberID string, period SalaryPeriod) (float64, error) {
+	var total float64
+	query := r.db.Model(&entity.Salary{}).Where("salaries.member_id = ? AND salaries.status = ?", memberID, "Paid")
+	if cond, args := period.condition(); cond != "" {
+		query = query.Where(cond, args...)
 	}
-
-	err := query.Select("COALESCE(SUM(salary), 0)").Scan(&total).Error
+	err := query.Select("COALESCE(SUM(salaries.salary), 0)").Scan(&total).Error
 	return total, err
 }
 
-func (r *memberRepository) GetAllMembersTotalSalaryWithFilter(userID uint, year string, month string) (float64, error) {
+func (r *memberRepository) GetAllMembersTotalSalaryWithFilter(userID uint, period SalaryPeriod) (float64, error) {
 	var total float64
 	query := r.db.Model(&entity.Salary{}).
 		Joins("JOIN members ON members.id = salaries.member_id").
 		Where("members.user_id = ? AND salaries.status = ?", userID, "Paid")
-
-	monthNames := map[string]string{
-		"01": "Januari", "02": "Februari", "03": "Maret", "04": "April",
-		"05": "Mei", "06": "Juni", "07": "Juli", "08": "Agustus",
-		"09": "September", "10": "Oktober", "11": "November", "12": "Desember",
+	if cond, args := period.condition(); cond != "" {
+		query = query.Where(cond, args...)
 	}
-
-	if year != "" && month != "" {
-		monthName := monthNames[month]
-		query = query.Where("salaries.month = ?", monthName+" "+year)
-	} else if year != "" {
-		query = query.Where("salaries.month LIKE ?", "% "+year)
-	}
-
 	err := query.Select("COALESCE(SUM(salaries.salary), 0)").Scan(&total).Error
 	return total, err
 }
@@ -171,6 +220,8 @@ type MemberSalaryInfo struct {
 
 type MonthlySalaryDetail struct {
 	Month       string  `json:"month"`
+	PeriodYear  int     `json:"period_year"`
+	PeriodMonth int     `json:"period_month"`
 	Salary      float64 `json:"salary"`
 	Loan        float64 `json:"loan"`
 	NetSalary   float64 `json:"net_salary"`
@@ -179,42 +230,23 @@ type MonthlySalaryDetail struct {
 	CreatedAt   string  `json:"created_at"`
 }
 
-func (r *memberRepository) GetAllMembersWithSalaryInfo(userID uint, year string, month string, orderBy string) ([]MemberSalaryInfo, error) {
+func (r *memberRepository) GetAllMembersWithSalaryInfo(userID uint, period SalaryPeriod, orderBy string) ([]MemberSalaryInfo, error) {
 	var results []MemberSalaryInfo
 
-	monthNames := map[string]string{
-		"01": "Januari", "02": "Februari", "03": "Maret", "04": "April",
-		"05": "Mei", "06": "Juni", "07": "Juli", "08": "Agustus",
-		"09": "September", "10": "Oktober", "11": "November", "12": "Desember",
-	}
-
-	var selectQuery string
-	var filterPattern string
-
-	if year != "" && month != "" {
-		monthName := monthNames[month]
-		filterPattern = monthName + " " + year
-		selectQuery = "members.id as member_id, members.full_name, members.role, members.is_active, COALESCE(SUM(CASE WHEN salaries.status = 'Paid' AND salaries.month = ? THEN salaries.salary ELSE 0 END), 0) as total_salary"
-	} else if year != "" {
-		filterPattern = "% " + year
-		selectQuery = "members.id as member_id, members.full_name, members.role, members.is_active, COALESCE(SUM(CASE WHEN salaries.status = 'Paid' AND salaries.month LIKE ? THEN salaries.salary ELSE 0 END), 0) as total_salary"
-	} else {
-		selectQuery = "members.id as member_id, members.full_name, members.role, members.is_active, COALESCE(SUM(CASE WHEN salaries.status = 'Paid' THEN salaries.salary ELSE 0 END), 0) as total_salary"
+	// Filter periode di kondisi JOIN agar anggota tanpa gaji di periode itu tetap muncul dengan total 0.
+	join := "LEFT JOIN salaries ON salaries.member_id = members.id AND salaries.status = ?"
+	args := []interface{}{"Paid"}
+	if cond, condArgs := period.condition(); cond != "" {
+		join += " AND " + cond
+		args = append(args, condArgs...)
 	}
 
 	query := r.db.Table("members").
-		Joins("LEFT JOIN salaries ON salaries.member_id = members.id").
+		Select("members.id as member_id, members.full_name, members.role, members.is_active, COALESCE(SUM(salaries.salary), 0) as total_salary").
+		Joins(join, args...).
 		Where("members.user_id = ?", userID).
 		Group("members.id, members.full_name, members.role, members.is_active")
 
-	if year != "" && month != "" {
-		query = query.Select(selectQuery, filterPattern)
-	} else if year != "" {
-		query = query.Select(selectQuery, filterPattern)
-	} else {
-		query = query.Select(selectQuery)
-	}
-
 	if orderBy == "asc" {
 		query = query.Order("total_salary ASC")
 	} else {
@@ -225,18 +257,17 @@ func (r *memberRepository) GetAllMembersWithSalaryInfo(userID uint, year string,
 	return results, err
 }
 
-func (r *memberRepository) GetMemberMonthlySalaryDetails(memberID string, year string) ([]MonthlySalaryDetail, error) {
+func (r *memberRepository) GetMemberMonthlySalaryDetails(memberID string, period SalaryPeriod) ([]MonthlySalaryDetail, error) {
 	var results []MonthlySalaryDetail
 
 	query := r.db.Table("salaries").
-		Select("month, salary, loan, net_salary, gross_salary, status, created_at").
-		Where("member_id = ?", memberID)
-
-	if year != "" {
-		query = query.Where("month LIKE ?", "% "+year)
+		Select("month, period_year, period_month, salary, loan, net_salary, gross_salary, status, created_at").
+		Where("salaries.member_id = ?", memberID)
+	if cond, args := period.condition(); cond != "" {
+		query = query.Where(cond, args...)
 	}
 
-	query = query.Order("created_at DESC")
+	query = query.Order("period_year DESC, period_month DESC, created_at DESC")
 
 	err := query.Scan(&results).Error
 	return results, err
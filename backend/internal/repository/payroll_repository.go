@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PayrollRepository interface {
+	Create(run *entity.PayrollRun) error
+	Update(run *entity.PayrollRun) error
+	FindAll(userID uint) ([]entity.PayrollRun, error)
+	FindByID(id uint) (*entity.PayrollRun, error)
+	// FindByIDForUpdate sama seperti FindByID dengan baris terkunci (FOR UPDATE); hanya berguna di dalam Transaction.
+	FindByIDForUpdate(id uint) (*entity.PayrollRun, error)
+	FindByMonth(userID uint, month string) (*entity.PayrollRun, error)
+	FindSalaries(runID uint) ([]entity.Salary, error)
+	FindRepayments(runID uint) ([]entity.AdvanceRepayment, error)
+	// Finalize mengunci run (FOR UPDATE), membuat expense dan cicilan kasbon, menyimpan gaji yang sudah
+	// Paid/Locked dan menandai run finalized dalam satu transaksi. false jika run sudah tidak draft.
+	Finalize(run *entity.PayrollRun, salaries []entity.Salary, expenses []entity.Finance, repayments []entity.AdvanceRepayment) (bool, error)
+	// Delete menghapus run draft: gaji kosong yang dibuat run ikut dihapus, sisanya dilepas dari run.
+	Delete(run *entity.PayrollRun) error
+	// Transaction menjalankan fn dalam satu transaksi; repository yang dibuat dari tx ikut transaksi tersebut.
+	Transaction(fn func(tx *gorm.DB) error) error
+}
+
+type payrollRepository struct {
+	db *gorm.DB
+}
+
+func NewPayrollRepository(db *gorm.DB) PayrollRepository {
+	return &payrollRepository{db}
+}
+
+func (r *payrollRepository) Create(run *entity.PayrollRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *payrollRepository) Update(run *entity.PayrollRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *payrollRepository) FindAll(userID uint) ([]entity.PayrollRun, error) {
+	var runs []entity.PayrollRun
+	err := r.db.Where("user_id = ?", userID).Order("month DESC").Find(&runs).Error
+	return runs, err
+}
+
+func (r *payrollRepository) FindByID(id uint) (*entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	err := r.db.First(&run, id).Error
+	return &run, err
+}
+
+func (r *payrollRepository) FindByIDForUpdate(id uint) (*entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&run, id).Error
+	return &run, err
+}
+
+func (r *payrollRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+func (r *payrollRepository) FindByMonth(userID uint, month string) (*entity.PayrollRun, error) {
+	var run entity.PayrollRun
+	err := r.db.Where("user_id = ? AND month = ?", userID, month).First(&run).Error
+	return &run, err
+}
+
+func (r *payrollRepository) FindSalaries(runID uint) ([]entity.Salary, error) {
+	var salaries []entity.Salary
+	err := r.db.Preload("Member").Where("payroll_run_id = ?", runID).Order("id ASC").Find(&salaries).Error
+	return salaries, err
+}
+
+func (r *payrollRepository) FindRepayments(runID uint) ([]entity.AdvanceRepayment, error) {
+	var list []entity.AdvanceRepayment
+	err := r.db.Where("payroll_run_id = ?", runID).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *payrollRepository) Finalize(run *entity.PayrollRun, salaries []entity.Salary, expenses []entity.Finance, repayments []entity.AdvanceRepayment) (bool, error) {
+	finalized := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current entity.PayrollRun
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, run.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != entity.PayrollDraft {
+			return nil
+		}
+		ids := []uint{}
+		for i := range expenses {
+			if err := tx.Create(&expenses[i]).Error; err != nil {
+				return err
+			}
+			ids = append(ids, expenses[i].ID)
+		}
+		advanceIDs := []uint{}
+		for i := range repayments {
+			if err := tx.Create(&repayments[i]).Error; err != nil {
+				return err
+			}
+			advanceIDs = append(advanceIDs, repayments[i].AdvanceID)
+		}
+		if err := refreshAdvanceStatus(tx, advanceIDs); err != nil {
+			return err
+		}
+		for i := range salaries {
+			if err := tx.Model(&salaries[i]).Select("salary", "gross_salary", "loan", "statutory_deduction", "net_salary", "status", "locked").
+				Updates(&salaries[i]).Error; err != nil {
+				return err
+			}
+		}
+		raw, _ := json.Marshal(ids)
+		now := time.Now()
+		run.FinanceIDs, run.Status, run.FinalizedAt = raw, entity.PayrollFinalized, &now
+		if err := tx.Save(run).Error; err != nil {
+			return err
+		}
+		finalized = true
+		return nil
+	})
+	return finalized, err
+}
+
+func (r *payrollRepository) Delete(run *entity.PayrollRun) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		empty := tx.Model(&entity.Salary{}).Where("payroll_run_id = ? AND locked = ?", run.ID, false).
+			Where("COALESCE(gross_salary, 0) = 0 AND COALESCE(loan, 0) = 0 AND COALESCE(salary, 0) = 0").
+			Where("NOT EXISTS (SELECT 1 FROM salary_details d WHERE d.salary_id = salaries.id)").
+			Where("NOT EXISTS (SELECT 1 FROM kasbons k WHERE k.salary_id = salaries.id)")
+		var ids []uint
+		if err := empty.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			if err := tx.Delete(&entity.Salary{}, ids).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&entity.Salary{}).Where("payroll_run_id = ?", run.ID).Update("payroll_run_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(run).Error
+	})
+}
@@ -13,6 +13,7 @@ type ProjectExpenseRepository interface {
 	Delete(id int) error
 	FindByID(id int) (*entity.ProjectExpense, error)
 	FindByProjectID(projectID int) ([]entity.ProjectExpense, error)
+	FindByProjectIDs(projectIDs []int) ([]entity.ProjectExpense, error)
 	FindAll() ([]entity.ProjectExpense, error)
 	GetFinancialSummary(projectID int, incomeRepo ProjectIncomeRepository) (*entity.ProjectFinancialSummary, error)
 }
@@ -55,6 +56,16 @@ func (r *projectExpenseRepository) FindByProjectID(projectID int) ([]entity.Proj
 	return expenses, nil
 }
 
+// FindByProjectIDs pengeluaran beberapa proyek sekaligus (urut tanggal naik).
+func (r *projectExpenseRepository) FindByProjectIDs(projectIDs []int) ([]entity.ProjectExpense, error) {
+	var expenses []entity.ProjectExpense
+	if len(projectIDs) == 0 {
+		return expenses, nil
+	}
+	err := r.db.Where("project_id IN ?", projectIDs).Order("tanggal ASC").Find(&expenses).Error
+	return expenses, err
+}
+
 func (r *projectExpenseRepository) FindAll() ([]entity.ProjectExpense, error) {
 	var expenses []entity.ProjectExpense
 	if err := r.db.Order("tanggal DESC").Find(&expenses).Error; err != nil {
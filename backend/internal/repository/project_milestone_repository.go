@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProjectMilestoneRepository interface {
+	FindByProjectID(projectID uint) ([]entity.ProjectMilestone, error)
+	FindByID(id uint) (*entity.ProjectMilestone, error)
+	CreateWithIncome(milestone *entity.ProjectMilestone, income *entity.ProjectIncome) error
+	UpdateWithIncome(milestone *entity.ProjectMilestone, income *entity.ProjectIncome) error
+	// CreateInvoice menyimpan invoice, income (boleh nil) dan termin yang ditandai invoiced dalam satu transaksi.
+	// Return false tanpa menyimpan apa pun jika termin sudah punya invoice (ditagih bersamaan).
+	CreateInvoice(milestone *entity.ProjectMilestone, income *entity.ProjectIncome, inv *entity.Invoice) (bool, error)
+	Delete(milestone *entity.ProjectMilestone, deleteIncome bool) error
+	FindIncome(id int) (*entity.ProjectIncome, error)
+}
+
+type projectMilestoneRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectMilestoneRepository(db *gorm.DB) ProjectMilestoneRepository {
+	return &projectMilestoneRepository{db}
+}
+
+func (r *projectMilestoneRepository) FindByProjectID(projectID uint) ([]entity.ProjectMilestone, error) {
+	var milestones []entity.ProjectMilestone
+	err := r.db.Where("project_id = ?", projectID).Order("sequence ASC, id ASC").Find(&milestones).Error
+	return milestones, err
+}
+
+func (r *projectMilestoneRepository) FindByID(id uint) (*entity.ProjectMilestone, error) {
+	var milestone entity.ProjectMilestone
+	err := r.db.First(&milestone, id).Error
+	return &milestone, err
+}
+
+// CreateWithIncome membuat income (Planned) lalu termin yang menunjuk ke income tersebut dalam satu transaksi.
+func (r *projectMilestoneRepository) CreateWithIncome(milestone *entity.ProjectMilestone, income *entity.ProjectIncome) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(income).Error; err != nil {
+			return err
+		}
+		milestone.IncomeID = &income.ID
+		return tx.Create(milestone).Error
+	})
+}
+
+// UpdateWithIncome menyimpan termin dan income-nya bersamaan (income nil = hanya termin).
+func (r *projectMilestoneRepository) UpdateWithIncome(milestone *entity.ProjectMilestone, income *entity.ProjectIncome) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if income != nil {
+			if err := tx.Save(income).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(milestone).Error
+	})
+}
+
+func (r *projectMilestoneRepository) CreateInvoice(milestone *entity.ProjectMilestone, income *entity.ProjectIncome, inv *entity.Invoice) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current entity.ProjectMilestone
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "invoice_id").First(&current, milestone.ID).Error; err != nil {
+			return err
+		}
+		if current.InvoiceID != nil {
+			return nil
+		}
+		if err := createInvoice(tx, inv); err != nil {
+			return err
+		}
+		if income != nil {
+			if err := tx.Save(income).Error; err != nil {
+				return err
+			}
+		}
+		milestone.InvoiceID = &inv.ID
+		created = true
+		return tx.Save(milestone).Error
+	})
+	return created, err
+}
+
+// Delete menghapus termin; deleteIncome=true ikut menghapus income terkait (dipakai jika income masih Planned).
+func (r *projectMilestoneRepository) Delete(milestone *entity.ProjectMilestone, deleteIncome bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if deleteIncome && milestone.IncomeID != nil {
+			if err := tx.Delete(&entity.ProjectIncome{}, *milestone.IncomeID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(milestone).Error
+	})
+}
+
+func (r *projectMilestoneRepository) FindIncome(id int) (*entity.ProjectIncome, error) {
+	var income entity.ProjectIncome
+	err := r.db.First(&income, id).Error
+	return &income, err
+}
@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"errors"
+
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/pkg/database"
 	"dashboardadminimb/pkg/response"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProjectRepository interface {
@@ -14,9 +18,14 @@ type ProjectRepository interface {
 	FindAllWithPagination(params response.QueryParams, userID uint) ([]entity.Project, int, error)
 	FindByID(id uint, userID uint) (*entity.Project, error)
 	FindByIDAdmin(id uint) (*entity.Project, error)
-	Update(project *entity.Project) error
+	Update(project *entity.Project, expectedVersion uint) (bool, error)
+	// CreateWithDaily/UpdateWithDaily sama seperti Create/Update, ditambah ReplaceProjectReports(daily) dalam transaksi yang sama.
+	CreateWithDaily(project *entity.Project, daily []entity.ReportDaily) error
+	UpdateWithDaily(project *entity.Project, expectedVersion uint, daily []entity.ReportDaily) (bool, error)
+	// Delete menghapus proyek beserta laporan harian dan gambarnya dalam satu transaksi.
 	Delete(project *entity.Project) error
 	Count(userID uint) (int64, error)
+	UpdateReports(id uint, reports datatypes.JSON) error
 }
 
 type projectRepository struct {
@@ -37,6 +46,16 @@ func (r *projectRepository) Create(project *entity.Project) error {
 	return r.db.Create(project).Error
 }
 
+func (r *projectRepository) CreateWithDaily(project *entity.Project, daily []entity.ReportDaily) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		_, _, _, err := replaceDailyReports(tx, project.ID, daily)
+		return err
+	})
+}
+
 func (r *projectRepository) FindAll(userID uint) ([]entity.Project, error) {
 	var projects []entity.Project
 	err := r.db.Where("user_id = ?", userID).Find(&projects).Error
@@ -55,12 +74,62 @@ func (r *projectRepository) FindByIDAdmin(id uint) (*entity.Project, error) {
 	return &project, err
 }
 
-func (r *projectRepository) Update(project *entity.Project) error {
-	return r.db.Save(project).Error
+// Update menyimpan semua kolom proyek milik project.UserID dan menaikkan versinya. expectedVersion > 0: hanya jika
+// versi di database masih sama. Return false jika tidak ada baris yang cocok (tidak ditemukan atau versi berubah).
+func (r *projectRepository) Update(project *entity.Project, expectedVersion uint) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = updateProject(tx, project, expectedVersion)
+		return err
+	})
+	return updated, err
+}
+
+func (r *projectRepository) UpdateWithDaily(project *entity.Project, expectedVersion uint, daily []entity.ReportDaily) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if updated, err = updateProject(tx, project, expectedVersion); err != nil || !updated {
+			return err
+		}
+		_, _, _, err = replaceDailyReports(tx, project.ID, daily)
+		return err
+	})
+	return updated, err
+}
+
+// updateProject isi Update di dalam transaksi tx.
+func updateProject(tx *gorm.DB, project *entity.Project, expectedVersion uint) (bool, error) {
+	var current entity.Project
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "version").
+		Where("id = ? AND user_id = ?", project.ID, project.UserID).First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if expectedVersion > 0 && current.Version != expectedVersion {
+		return false, nil
+	}
+	project.Version = current.Version + 1
+	return true, tx.Save(project).Error
+}
+
+// UpdateReports hanya menulis kolom reports (tanpa menimpa kolom proyek lain) dan menaikkan versi proyek.
+func (r *projectRepository) UpdateReports(id uint, reports datatypes.JSON) error {
+	return r.db.Model(&entity.Project{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"reports": reports, "version": gorm.Expr("version + 1")}).Error
 }
 
 func (r *projectRepository) Delete(project *entity.Project) error {
-	return r.db.Delete(project).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, _, _, err := replaceDailyReports(tx, project.ID, nil); err != nil {
+			return err
+		}
+		return tx.Delete(project).Error
+	})
 }
 
 func (r *projectRepository) FindAllWithPagination(params response.QueryParams, userID uint) ([]entity.Project, int, error) {
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type PromptTemplateRepository interface {
+	Create(e *entity.PromptTemplate) error
+	Delete(id uint) error
+	FindByID(id uint) (*entity.PromptTemplate, error)
+	FindAll(userID uint, useCase, provider string) ([]entity.PromptTemplate, error)
+	FindActive(userID uint, useCase, provider string) (*entity.PromptTemplate, error)
+	MaxVersion(userID uint, useCase, provider string) (int, error)
+	Activate(e *entity.PromptTemplate) error
+	CreateExtractionLog(l *entity.ExtractionLog) error
+	FindExtractionLogs(userID uint, useCase string, limit int) ([]entity.ExtractionLog, error)
+}
+
+type promptTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewPromptTemplateRepository(db *gorm.DB) PromptTemplateRepository {
+	return &promptTemplateRepository{db: db}
+}
+
+func (r *promptTemplateRepository) Create(e *entity.PromptTemplate) error {
+	return r.db.Create(e).Error
+}
+
+func (r *promptTemplateRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.PromptTemplate{}, id).Error
+}
+
+func (r *promptTemplateRepository) FindByID(id uint) (*entity.PromptTemplate, error) {
+	var e entity.PromptTemplate
+	if err := r.db.First(&e, id).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *promptTemplateRepository) FindAll(userID uint, useCase, provider string) ([]entity.PromptTemplate, error) {
+	var list []entity.PromptTemplate
+	q := r.db.Where("user_id = ?", userID)
+	if useCase != "" {
+		q = q.Where("use_case = ?", useCase)
+	}
+	if provider != "" {
+		q = q.Where("provider = ?", provider)
+	}
+	err := q.Order("use_case ASC, provider ASC, version DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *promptTemplateRepository) FindActive(userID uint, useCase, provider string) (*entity.PromptTemplate, error) {
+	var e entity.PromptTemplate
+	err := r.db.Where("user_id = ? AND use_case = ? AND provider = ? AND is_active = ?", userID, useCase, provider, true).
+		Order("version DESC").First(&e).Error
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *promptTemplateRepository) MaxVersion(userID uint, useCase, provider string) (int, error) {
+	var max int
+	err := r.db.Model(&entity.PromptTemplate{}).
+		Where("user_id = ? AND use_case = ? AND provider = ?", userID, useCase, provider).
+		Select("COALESCE(MAX(version), 0)").Scan(&max).Error
+	return max, err
+}
+
+// Activate menonaktifkan versi lain pada (user, use case, provider) yang sama lalu mengaktifkan e, dalam satu transaksi.
+func (r *promptTemplateRepository) Activate(e *entity.PromptTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.PromptTemplate{}).
+			Where("user_id = ? AND use_case = ? AND provider = ? AND id <> ?", e.UserID, e.UseCase, e.Provider, e.ID).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		e.IsActive = true
+		return tx.Model(e).Update("is_active", true).Error
+	})
+}
+
+func (r *promptTemplateRepository) CreateExtractionLog(l *entity.ExtractionLog) error {
+	return r.db.Create(l).Error
+}
+
+func (r *promptTemplateRepository) FindExtractionLogs(userID uint, useCase string, limit int) ([]entity.ExtractionLog, error) {
+	var list []entity.ExtractionLog
+	q := r.db.Where("user_id = ?", userID)
+	if useCase != "" {
+		q = q.Where("use_case = ?", useCase)
+	}
+	err := q.Order("id DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
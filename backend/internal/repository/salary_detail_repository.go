@@ -14,6 +14,9 @@ type SalaryDetailRepository interface {
 	Delete(id uint) error
 	FindByID(id uint) (*entity.SalaryDetail, error)
 	FindBySalaryID(salaryID uint) ([]entity.SalaryDetail, error)
+	// ReplaceFromAttendance mengganti baris hasil absensi (absensi_id terisi) milik gaji dalam satu transaksi;
+	// baris yang diinput manual tidak disentuh.
+	ReplaceFromAttendance(salaryID uint, details []entity.SalaryDetail) error
 }
 
 type salaryDetailRepository struct {
@@ -37,6 +40,18 @@ func (r *salaryDetailRepository) Delete(id uint) error {
 	return r.db.Delete(&entity.SalaryDetail{}, id).Error
 }
 
+func (r *salaryDetailRepository) ReplaceFromAttendance(salaryID uint, details []entity.SalaryDetail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("salary_id = ? AND absensi_id IS NOT NULL", salaryID).Delete(&entity.SalaryDetail{}).Error; err != nil {
+			return err
+		}
+		if len(details) == 0 {
+			return nil
+		}
+		return tx.Create(&details).Error
+	})
+}
+
 func (r *salaryDetailRepository) FindBySalaryID(salaryID uint) ([]entity.SalaryDetail, error) {
 	var details []entity.SalaryDetail
 	err := r.db.Where("salary_id = ?", salaryID).Find(&details).Error
@@ -6,6 +6,7 @@ import (
 	"dashboardadminimb/pkg/database"
 	"dashboardadminimb/pkg/response"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +17,12 @@ type SalaryRepository interface {
 	FindByMemberID(memberID string) ([]entity.Salary, error)
 	FindByID(id uint) (*entity.Salary, error)
 	FindAllWithPagination(params response.QueryParams) ([]entity.Salary, int, error)
+	// UpdateDocuments hanya menulis kolom documents (tetap boleh untuk gaji yang terkunci).
+	UpdateDocuments(id uint, documents datatypes.JSON) error
+	// FindWithoutPeriod gaji yang kolom periodenya belum terisi (data lama dengan bulan teks bebas).
+	FindWithoutPeriod() ([]entity.Salary, error)
+	// UpdatePeriod hanya menulis month & kolom periode (tetap boleh untuk gaji yang terkunci).
+	UpdatePeriod(salary *entity.Salary) error
 }
 
 type salaryRepository struct {
@@ -34,6 +41,24 @@ func (r *salaryRepository) Update(salary *entity.Salary) error {
 	return r.db.Save(salary).Error
 }
 
+func (r *salaryRepository) UpdateDocuments(id uint, documents datatypes.JSON) error {
+	return r.db.Model(&entity.Salary{}).Where("id = ?", id).Update("documents", documents).Error
+}
+
+func (r *salaryRepository) FindWithoutPeriod() ([]entity.Salary, error) {
+	var salaries []entity.Salary
+	err := r.db.Where("period_year = 0 OR period_year IS NULL").Order("id").Find(&salaries).Error
+	return salaries, err
+}
+
+func (r *salaryRepository) UpdatePeriod(salary *entity.Salary) error {
+	return r.db.Model(&entity.Salary{}).Where("id = ?", salary.ID).Updates(map[string]interface{}{
+		"month":        salary.Month,
+		"period_year":  salary.PeriodYear,
+		"period_month": salary.PeriodMonth,
+	}).Error
+}
+
 func (r *salaryRepository) Delete(salary *entity.Salary) error {
 	return r.db.Delete(salary).Error
 }
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type StatutoryRepository interface {
+	FindSetting(userID uint) (*entity.PayrollTaxSetting, error)
+	SaveSetting(setting *entity.PayrollTaxSetting) error
+	// ReplaceDeductions mengganti seluruh baris potongan satu gaji dalam satu transaksi.
+	ReplaceDeductions(salaryID uint, lines []entity.SalaryDeduction) error
+	FindBySalary(salaryID uint) ([]entity.SalaryDeduction, error)
+	FindByMonth(userID uint, month string) ([]entity.SalaryDeduction, error)
+	// FindYearBefore baris potongan anggota pada tahun yang sama sebelum bulan month (YYYY-MM), kecuali gaji excludeSalaryID.
+	FindYearBefore(memberID, month string, excludeSalaryID uint) ([]entity.SalaryDeduction, error)
+}
+
+type statutoryRepository struct {
+	db *gorm.DB
+}
+
+func NewStatutoryRepository(db *gorm.DB) StatutoryRepository {
+	return &statutoryRepository{db}
+}
+
+func (r *statutoryRepository) FindSetting(userID uint) (*entity.PayrollTaxSetting, error) {
+	var setting entity.PayrollTaxSetting
+	err := r.db.Where("user_id = ?", userID).First(&setting).Error
+	return &setting, err
+}
+
+func (r *statutoryRepository) SaveSetting(setting *entity.PayrollTaxSetting) error {
+	return r.db.Save(setting).Error
+}
+
+func (r *statutoryRepository) ReplaceDeductions(salaryID uint, lines []entity.SalaryDeduction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("salary_id = ?", salaryID).Delete(&entity.SalaryDeduction{}).Error; err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return tx.Create(&lines).Error
+	})
+}
+
+func (r *statutoryRepository) FindBySalary(salaryID uint) ([]entity.SalaryDeduction, error) {
+	var lines []entity.SalaryDeduction
+	err := r.db.Where("salary_id = ?", salaryID).Order("id ASC").Find(&lines).Error
+	return lines, err
+}
+
+func (r *statutoryRepository) FindByMonth(userID uint, month string) ([]entity.SalaryDeduction, error) {
+	var lines []entity.SalaryDeduction
+	err := r.db.Where("user_id = ? AND month = ?", userID, month).Order("member_id ASC, id ASC").Find(&lines).Error
+	return lines, err
+}
+
+func (r *statutoryRepository) FindYearBefore(memberID, month string, excludeSalaryID uint) ([]entity.SalaryDeduction, error) {
+	var lines []entity.SalaryDeduction
+	err := r.db.Where("member_id = ? AND month >= ? AND month < ? AND salary_id <> ?", memberID, month[:4]+"-01", month, excludeSalaryID).
+		Order("month ASC").Find(&lines).Error
+	return lines, err
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAdvanceInvalid   = errors.New("kasbon tidak valid: anggota wajib, tanggal YYYY-MM-DD, jumlah > 0, cicilan >= 1, start_month YYYY-MM")
+	ErrAdvanceLegacy    = errors.New("kasbon hasil migrasi mengikuti data kasbon gaji, ubah dari gaji terkait")
+	ErrAdvanceRepaid    = errors.New("kasbon sudah punya pembayaran: jumlah tidak boleh di bawah total terbayar dan kasbon tidak bisa dihapus")
+	ErrRepaymentInvalid = errors.New("pembayaran tidak valid: jumlah > 0 dan tidak melebihi sisa kasbon, tanggal YYYY-MM-DD")
+)
+
+type AdvanceService interface {
+	List(userID uint, memberID string) ([]entity.MemberAdvance, error)
+	Get(id, userID uint) (*entity.MemberAdvance, error)
+	Save(userID uint, advance *entity.MemberAdvance) error
+	Delete(id, userID uint) error
+	// Repay mencatat pembayaran manual di luar payroll.
+	Repay(userID, advanceID uint, repayment *entity.AdvanceRepayment) error
+	// Balances saldo kasbon per anggota.
+	Balances(userID uint) ([]entity.AdvanceBalance, error)
+	// History pencairan dan pembayaran satu anggota dengan saldo berjalan.
+	History(userID uint, memberID string) ([]entity.AdvanceHistoryEntry, error)
+	// PlanDeductions cicilan terjadwal per anggota untuk bulan payroll (belum disimpan).
+	PlanDeductions(userID uint, month string) (map[string][]entity.AdvanceRepayment, error)
+	// ScheduledInstallment total cicilan terjadwal satu anggota untuk bulan month (sama dengan PlanDeductions).
+	ScheduledInstallment(userID uint, memberID, month string) (float64, error)
+	// MigrateLegacyKasbons menyalin baris kasbons (per gaji) ke ledger. Aman dijalankan ulang; userID 0 = semua user.
+	MigrateLegacyKasbons(userID uint, dryRun bool) (*KasbonMigration, error)
+}
+
+// KasbonMigration hasil sinkronisasi kasbons lama ke member_advances.
+type KasbonMigration struct {
+	Kasbons int `json:"kasbons"`
+	Created int `json:"created"`
+	Updated int `json:"updated"` // jumlah/anggota berubah: baris ledger dibuat ulang
+	Removed int `json:"removed"` // kasbon lama sudah dihapus
+	Skipped int `json:"skipped"`
+}
+
+type advanceService struct {
+	repo       repository.AdvanceRepository
+	memberRepo repository.MemberRepository
+}
+
+func NewAdvanceService(repo repository.AdvanceRepository, memberRepo repository.MemberRepository) AdvanceService {
+	return &advanceService{repo, memberRepo}
+}
+
+// scheduledInstallment potongan bulan month: nol sebelum StartMonth, saat sudah lunas, atau jika bulan itu
+// sudah ada pembayaran; selain itu cicilan, maksimal sisa kasbon.
+func scheduledInstallment(a entity.MemberAdvance, month string, paidThisMonth bool) float64 {
+	if a.LegacyKasbonID != nil || month < a.StartMonth || paidThisMonth || a.Outstanding <= 0 {
+		return 0
+	}
+	return round2(math.Min(a.InstallmentAmount, a.Outstanding))
+}
+
+// load kasbon user beserta Repaid/Outstanding dan daftar bulan yang sudah dibayar per kasbon. Hanya membaca;
+// kasbon lama disalin ke ledger oleh cmd/migrate-kasbon dan oleh KasbonRepository setiap kali kasbon lama ditulis.
+func (s *advanceService) load(userID uint, memberID string) ([]entity.MemberAdvance, []entity.AdvanceRepayment, map[uint]map[string]bool, error) {
+	advances, err := s.repo.FindAll(userID, memberID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	repayments, err := s.repo.FindRepayments(userID, memberID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	repaid := map[uint]float64{}
+	months := map[uint]map[string]bool{}
+	for _, r := range repayments {
+		repaid[r.AdvanceID] += r.Amount
+		if months[r.AdvanceID] == nil {
+			months[r.AdvanceID] = map[string]bool{}
+		}
+		months[r.AdvanceID][r.Month] = true
+	}
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	names := map[string]string{}
+	for _, m := range members {
+		names[m.ID] = m.FullName
+	}
+	for i := range advances {
+		advances[i].MemberName = names[advances[i].MemberID]
+		advances[i].Repaid = round2(repaid[advances[i].ID])
+		advances[i].Outstanding = round2(math.Max(advances[i].Amount-advances[i].Repaid, 0))
+	}
+	return advances, repayments, months, nil
+}
+
+func (s *advanceService) List(userID uint, memberID string) ([]entity.MemberAdvance, error) {
+	advances, _, _, err := s.load(userID, memberID)
+	return advances, err
+}
+
+func (s *advanceService) Get(id, userID uint) (*entity.MemberAdvance, error) {
+	advance, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if advance.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	advances, _, _, err := s.load(userID, advance.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range advances {
+		if advances[i].ID == id {
+			return &advances[i], nil
+		}
+	}
+	return advance, nil
+}
+
+func (s *advanceService) Save(userID uint, advance *entity.MemberAdvance) error {
+	if advance.Installments == 0 {
+		advance.Installments = 1
+	}
+	if advance.StartMonth == "" && len(advance.Tanggal) >= 7 {
+		advance.StartMonth = advance.Tanggal[:7]
+	}
+	if advance.MemberID == "" || !validDay(advance.Tanggal) || advance.Amount <= 0 || advance.Installments < 1 ||
+		!validMonth(advance.StartMonth) {
+		return ErrAdvanceInvalid
+	}
+	member, err := s.memberRepo.FindByID(advance.MemberID)
+	if err != nil || member.UserID != userID {
+		return ErrAdvanceInvalid
+	}
+	advance.Amount = round2(advance.Amount)
+	if advance.InstallmentAmount <= 0 {
+		advance.InstallmentAmount = round2(advance.Amount / float64(advance.Installments))
+	}
+	advance.UserID = userID
+	if advance.ID == 0 {
+		advance.LegacyKasbonID, advance.Status = nil, entity.AdvanceOpen
+		return s.repo.Create(advance)
+	}
+	existing, err := s.Get(advance.ID, userID)
+	if err != nil {
+		return err
+	}
+	if existing.LegacyKasbonID != nil {
+		return ErrAdvanceLegacy
+	}
+	if advance.Amount < existing.Repaid {
+		return ErrAdvanceRepaid
+	}
+	advance.LegacyKasbonID, advance.CreatedAt = nil, existing.CreatedAt
+	return s.repo.Update(advance)
+}
+
+func (s *advanceService) Delete(id, userID uint) error {
+	advance, err := s.Get(id, userID)
+	if err != nil {
+		return err
+	}
+	if advance.LegacyKasbonID != nil {
+		return ErrAdvanceLegacy
+	}
+	n, err := s.repo.CountRepayments(id)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrAdvanceRepaid
+	}
+	return s.repo.Delete(advance)
+}
+
+func (s *advanceService) Repay(userID, advanceID uint, repayment *entity.AdvanceRepayment) error {
+	advance, err := s.Get(advanceID, userID)
+	if err != nil {
+		return err
+	}
+	if repayment.Tanggal == "" {
+		repayment.Tanggal = today().Format(dayLayout)
+	}
+	if repayment.Month == "" && len(repayment.Tanggal) >= 7 {
+		repayment.Month = repayment.Tanggal[:7]
+	}
+	repayment.Amount = round2(repayment.Amount)
+	if !validDay(repayment.Tanggal) || !validMonth(repayment.Month) || repayment.Amount <= 0 ||
+		repayment.Amount > advance.Outstanding {
+		return ErrRepaymentInvalid
+	}
+	repayment.ID, repayment.UserID, repayment.AdvanceID, repayment.MemberID = 0, userID, advance.ID, advance.MemberID
+	repayment.Source, repayment.SalaryID, repayment.PayrollRunID = entity.RepaymentManual, nil, nil
+	return s.repo.CreateRepayment(repayment)
+}
+
+func (s *advanceService) Balances(userID uint) ([]entity.AdvanceBalance, error) {
+	advances, _, months, err := s.load(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	month := today().Format("2006-01")
+	byMember := map[string]*entity.AdvanceBalance{}
+	var order []string
+	for _, a := range advances {
+		b := byMember[a.MemberID]
+		if b == nil {
+			b = &entity.AdvanceBalance{MemberID: a.MemberID, MemberName: a.MemberName}
+			byMember[a.MemberID] = b
+			order = append(order, a.MemberID)
+		}
+		b.Disbursed += a.Amount
+		b.Repaid += a.Repaid
+		b.Outstanding += a.Outstanding
+		if a.Outstanding > 0 {
+			b.OpenAdvances++
+		}
+		b.NextDeduction += scheduledInstallment(a, month, months[a.ID][month])
+	}
+	out := make([]entity.AdvanceBalance, 0, len(order))
+	for _, id := range order {
+		b := byMember[id]
+		b.Disbursed, b.Repaid, b.Outstanding, b.NextDeduction = round2(b.Disbursed), round2(b.Repaid), round2(b.Outstanding), round2(b.NextDeduction)
+		out = append(out, *b)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Outstanding > out[j].Outstanding })
+	return out, nil
+}
+
+func (s *advanceService) History(userID uint, memberID string) ([]entity.AdvanceHistoryEntry, error) {
+	member, err := s.memberRepo.FindByID(memberID)
+	if err != nil || member.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	advances, repayments, _, err := s.load(userID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entity.AdvanceHistoryEntry
+	for _, a := range advances {
+		entries = append(entries, entity.AdvanceHistoryEntry{
+			Date: a.Tanggal, Kind: "disbursement", AdvanceID: a.ID, Month: a.StartMonth, Amount: a.Amount, Note: a.Keterangan,
+		})
+	}
+	for _, r := range repayments {
+		date := r.Tanggal
+		if date == "" {
+			date = r.Month + "-01"
+		}
+		entries = append(entries, entity.AdvanceHistoryEntry{
+			Date: date, Kind: "repayment", AdvanceID: r.AdvanceID, Month: r.Month, Source: r.Source, Amount: r.Amount, Note: r.Keterangan,
+		})
+	}
+	// Pada tanggal yang sama pencairan dicatat sebelum pembayaran agar saldo tidak negatif.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date < entries[j].Date
+		}
+		return entries[i].Kind == "disbursement" && entries[j].Kind != "disbursement"
+	})
+	balance := 0.0
+	for i := range entries {
+		if entries[i].Kind == "disbursement" {
+			balance += entries[i].Amount
+		} else {
+			balance -= entries[i].Amount
+		}
+		entries[i].Balance = round2(balance)
+	}
+	return entries, nil
+}
+
+func (s *advanceService) PlanDeductions(userID uint, month string) (map[string][]entity.AdvanceRepayment, error) {
+	advances, _, months, err := s.load(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	plan := map[string][]entity.AdvanceRepayment{}
+	for _, a := range advances {
+		amount := scheduledInstallment(a, month, months[a.ID][month])
+		if amount <= 0 {
+			continue
+		}
+		plan[a.MemberID] = append(plan[a.MemberID], entity.AdvanceRepayment{
+			UserID:     userID,
+			AdvanceID:  a.ID,
+			MemberID:   a.MemberID,
+			Month:      month,
+			Amount:     amount,
+			Source:     entity.RepaymentPayroll,
+			Keterangan: fmt.Sprintf("Cicilan kasbon %s", a.Tanggal),
+		})
+	}
+	return plan, nil
+}
+
+func (s *advanceService) ScheduledInstallment(userID uint, memberID, month string) (float64, error) {
+	advances, _, months, err := s.load(userID, memberID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, a := range advances {
+		total += scheduledInstallment(a, month, months[a.ID][month])
+	}
+	return round2(total), nil
+}
+
+func (s *advanceService) MigrateLegacyKasbons(userID uint, dryRun bool) (*KasbonMigration, error) {
+	res := &KasbonMigration{}
+	rows, err := s.repo.FindLegacyKasbons(userID)
+	if err != nil {
+		return res, err
+	}
+	migrated, err := s.repo.FindMigrated(userID)
+	if err != nil {
+		return res, err
+	}
+	byLegacy := map[uint]entity.MemberAdvance{}
+	for _, a := range migrated {
+		byLegacy[*a.LegacyKasbonID] = a
+	}
+	res.Kasbons = len(rows)
+	seen := map[uint]bool{}
+	for _, k := range rows {
+		seen[k.ID] = true
+		month := salaryMonthKey(k.Month)
+		if month == "" && len(k.Tanggal) >= 7 {
+			month = k.Tanggal[:7]
+		}
+		date := k.Tanggal
+		if !validDay(date) {
+			date = month + "-01"
+		}
+		if existing, ok := byLegacy[k.ID]; ok {
+			if existing.Amount == round2(k.Jumlah) && existing.MemberID == k.MemberID && existing.StartMonth == month {
+				res.Skipped++
+				continue
+			}
+			res.Updated++
+			if dryRun {
+				continue
+			}
+			if err := s.repo.Delete(&existing); err != nil {
+				return res, err
+			}
+		} else {
+			res.Created++
+			if dryRun {
+				continue
+			}
+		}
+		k.Tanggal = date
+		advance, repayment := repository.LegacyAdvance(k, month)
+		if err := s.repo.CreateLegacy(advance, repayment); err != nil {
+			return res, err
+		}
+	}
+	for legacyID, a := range byLegacy {
+		if seen[legacyID] {
+			continue
+		}
+		res.Removed++
+		if dryRun {
+			continue
+		}
+		if err := s.repo.Delete(&a); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrAttendanceInvalid = errors.New("absensi tidak valid: tanggal YYYY-MM-DD, jam HH:MM, status hadir/izin/sakit/alpa/cuti, dasar bayar hour/trip, angka tidak boleh negatif, lembur per trip butuh overtime_rate")
+	ErrAttendanceLink    = errors.New("anggota, proyek atau alat tidak ditemukan untuk user ini")
+	ErrAttendanceLocked  = errors.New("gaji anggota untuk bulan ini sudah difinalisasi; absensinya tidak bisa diubah")
+)
+
+var attendanceStatuses = map[string]bool{
+	entity.AttendancePresent: true,
+	entity.AttendanceLeave:   true,
+	entity.AttendanceSick:    true,
+	entity.AttendanceAbsent:  true,
+	entity.AttendanceOff:     true,
+}
+
+// BulkAttendance input absensi satu lokasi untuk satu tanggal; nilai di header dipakai bila entri kosong.
+type BulkAttendance struct {
+	Date         string                `json:"date"`
+	Shift        string                `json:"shift"`
+	ProjectID    *uint                 `json:"project_id"`
+	Site         string                `json:"site"`
+	CheckIn      string                `json:"check_in"`
+	CheckOut     string                `json:"check_out"`
+	PayBasis     string                `json:"pay_basis"`
+	Rate         float64               `json:"rate"`
+	OvertimeRate float64               `json:"overtime_rate"`
+	Approve      bool                  `json:"approve"` // langsung setujui semua entri
+	Entries      []BulkAttendanceEntry `json:"entries"`
+}
+
+type BulkAttendanceEntry struct {
+	MemberID      string  `json:"member_id"`
+	Status        string  `json:"status"`
+	EquipmentID   *uint   `json:"equipment_id"`
+	CheckIn       string  `json:"check_in"`
+	CheckOut      string  `json:"check_out"`
+	Hours         float64 `json:"hours"`
+	OvertimeHours float64 `json:"overtime_hours"`
+	Trips         float64 `json:"trips"`
+	Rate          float64 `json:"rate"`
+	Notes         string  `json:"notes"`
+}
+
+type AttendanceService interface {
+	List(userID uint, filter repository.AttendanceFilter) ([]entity.Attendance, error)
+	Get(id, userID uint) (*entity.Attendance, error)
+	Save(userID uint, attendance *entity.Attendance) error
+	// SaveBulk membuat/memperbarui absensi per (anggota, tanggal, shift); semua entri divalidasi dulu.
+	SaveBulk(userID uint, bulk *BulkAttendance) ([]entity.Attendance, error)
+	Delete(id, userID uint) error
+	SetApproval(userID uint, ids []uint, approved bool) (int64, error)
+	// Matrix rekap absensi bulan YYYY-MM per anggota per tanggal.
+	Matrix(userID uint, month string, projectID uint) (*entity.AttendanceMatrix, error)
+	// SyncSalaryDetails mengganti baris SalaryDetail hasil absensi dengan absensi hadir yang disetujui
+	// pada bulan gaji. Gaji yang sudah terkunci tidak diubah.
+	SyncSalaryDetails(salary *entity.Salary) error
+}
+
+type attendanceService struct {
+	repo           repository.AttendanceRepository
+	memberRepo     repository.MemberRepository
+	salaryRepo     repository.SalaryRepository
+	detailRepo     repository.SalaryDetailRepository
+	equipmentRepo  repository.EquipmentRepository
+	projectService ProjectService
+	licences       MemberDocumentService
+	employment     EmploymentService
+}
+
+func NewAttendanceService(repo repository.AttendanceRepository, memberRepo repository.MemberRepository, salaryRepo repository.SalaryRepository, detailRepo repository.SalaryDetailRepository, equipmentRepo repository.EquipmentRepository, projectService ProjectService, licences MemberDocumentService, employment EmploymentService) AttendanceService {
+	return &attendanceService{repo, memberRepo, salaryRepo, detailRepo, equipmentRepo, projectService, licences, employment}
+}
+
+// clockMinutes "HH:MM" → menit sejak tengah malam.
+func clockMinutes(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// workedHours durasi CheckIn–CheckOut; CheckOut yang lebih kecil berarti shift lewat tengah malam.
+func workedHours(checkIn, checkOut string) float64 {
+	in, ok1 := clockMinutes(checkIn)
+	out, ok2 := clockMinutes(checkOut)
+	if !ok1 || !ok2 {
+		return 0
+	}
+	if out <= in {
+		out += 24 * 60
+	}
+	return round2(float64(out-in) / 60)
+}
+
+func (s *attendanceService) annotate(userID uint, list []entity.Attendance) error {
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, m := range members {
+		names[m.ID] = m.FullName
+	}
+	equipment, err := s.equipmentRepo.FindAll(userID, "", "")
+	if err != nil {
+		return err
+	}
+	units := map[uint]string{}
+	for _, e := range equipment {
+		units[e.ID] = e.Name
+	}
+	for i := range list {
+		list[i].MemberName = names[list[i].MemberID]
+		if list[i].EquipmentID != nil {
+			list[i].EquipmentName = units[*list[i].EquipmentID]
+		}
+	}
+	return nil
+}
+
+func (s *attendanceService) List(userID uint, filter repository.AttendanceFilter) ([]entity.Attendance, error) {
+	list, err := s.repo.FindAll(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return list, s.annotate(userID, list)
+}
+
+func (s *attendanceService) Get(id, userID uint) (*entity.Attendance, error) {
+	a, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, errors.New("absensi tidak ditemukan")
+	}
+	return a, nil
+}
+
+// ensureUnlocked ErrAttendanceLocked jika gaji anggota pada bulan tanggal itu sudah dikunci payroll run.
+func (s *attendanceService) ensureUnlocked(memberID, date string) error {
+	salaries, err := s.salaryRepo.FindByMemberID(memberID)
+	if err != nil {
+		return err
+	}
+	for _, sal := range salaries {
+		if sal.Locked && salaryMonthKey(sal.Month) == date[:7] {
+			return ErrAttendanceLocked
+		}
+	}
+	return nil
+}
+
+// prepare menormalkan dan memvalidasi absensi sebelum disimpan.
+func (s *attendanceService) prepare(userID uint, a *entity.Attendance) error {
+	a.UserID = userID
+	a.Shift, a.Site = strings.TrimSpace(a.Shift), strings.TrimSpace(a.Site)
+	if a.Status == "" {
+		a.Status = entity.AttendancePresent
+	}
+	if a.PayBasis == "" {
+		a.PayBasis = entity.PayPerHour
+	}
+	_, inOK := clockMinutes(a.CheckIn)
+	_, outOK := clockMinutes(a.CheckOut)
+	if !validDay(a.Date) || !attendanceStatuses[a.Status] ||
+		(a.PayBasis != entity.PayPerHour && a.PayBasis != entity.PayPerTrip) ||
+		(a.CheckIn != "" && !inOK) || (a.CheckOut != "" && !outOK) ||
+		a.Hours < 0 || a.OvertimeHours < 0 || a.Trips < 0 || a.Rate < 0 || a.OvertimeRate < 0 ||
+		(a.PayBasis == entity.PayPerTrip && a.OvertimeHours > 0 && a.OvertimeRate == 0) {
+		return ErrAttendanceInvalid
+	}
+	if a.Status != entity.AttendancePresent {
+		a.Hours, a.OvertimeHours, a.Trips = 0, 0, 0
+	} else if a.Hours == 0 && a.CheckIn != "" && a.CheckOut != "" {
+		a.Hours = round2(workedHours(a.CheckIn, a.CheckOut) - a.OvertimeHours)
+		if a.Hours < 0 {
+			a.Hours = 0
+		}
+	}
+	if m, err := s.memberRepo.FindByID(a.MemberID); err != nil || m.UserID != userID {
+		return ErrAttendanceLink
+	}
+	if a.ProjectID != nil && *a.ProjectID == 0 {
+		a.ProjectID = nil
+	}
+	if a.ProjectID != nil {
+		if _, err := s.projectService.GetProjectByID(*a.ProjectID, userID); err != nil {
+			return ErrAttendanceLink
+		}
+	}
+	if a.EquipmentID != nil && *a.EquipmentID == 0 {
+		a.EquipmentID = nil
+	}
+	a.Warnings = nil
+	if a.EquipmentID != nil {
+		equipment, err := s.equipmentRepo.FindByIDForUser(*a.EquipmentID, userID)
+		if err != nil {
+			return ErrAttendanceLink
+		}
+		// Operator tanpa lisensi berlaku tetap dicatat, hanya diberi peringatan.
+		if a.Status == entity.AttendancePresent {
+			a.Warnings, err = s.licences.LicenceWarnings(userID, a.MemberID, equipment.Type, a.Date, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if !a.Approved {
+		a.ApprovedAt = nil
+	} else if a.ApprovedAt == nil {
+		now := time.Now()
+		a.ApprovedAt = &now
+	}
+	return s.ensureUnlocked(a.MemberID, a.Date)
+}
+
+// Save membuat/memperbarui absensi. Memindahkan absensi keluar dari bulan yang sudah dikunci juga ditolak.
+func (s *attendanceService) Save(userID uint, a *entity.Attendance) error {
+	if a.ID != 0 {
+		existing, err := s.Get(a.ID, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureUnlocked(existing.MemberID, existing.Date); err != nil {
+			return err
+		}
+	}
+	if err := s.prepare(userID, a); err != nil {
+		return err
+	}
+	return s.repo.Save(a)
+}
+
+func (s *attendanceService) SaveBulk(userID uint, bulk *BulkAttendance) ([]entity.Attendance, error) {
+	if len(bulk.Entries) == 0 {
+		return nil, ErrAttendanceInvalid
+	}
+	shift := strings.TrimSpace(bulk.Shift)
+	list := make([]entity.Attendance, 0, len(bulk.Entries))
+	seen := map[string]bool{}
+	for _, e := range bulk.Entries {
+		if seen[e.MemberID] {
+			return nil, ErrAttendanceInvalid
+		}
+		seen[e.MemberID] = true
+		a := entity.Attendance{}
+		if existing, err := s.repo.FindByKey(e.MemberID, bulk.Date, shift); err == nil {
+			if existing.UserID != userID {
+				return nil, ErrAttendanceLink
+			}
+			a = *existing
+		}
+		a.MemberID, a.Date, a.Shift, a.Status = e.MemberID, bulk.Date, shift, e.Status
+		a.ProjectID, a.Site, a.EquipmentID = bulk.ProjectID, bulk.Site, e.EquipmentID
+		a.CheckIn, a.CheckOut = e.CheckIn, e.CheckOut
+		if a.CheckIn == "" {
+			a.CheckIn = bulk.CheckIn
+		}
+		if a.CheckOut == "" {
+			a.CheckOut = bulk.CheckOut
+		}
+		a.Hours, a.OvertimeHours, a.Trips = e.Hours, e.OvertimeHours, e.Trips
+		a.PayBasis, a.Rate, a.OvertimeRate = bulk.PayBasis, e.Rate, bulk.OvertimeRate
+		if a.Rate == 0 {
+			a.Rate = bulk.Rate
+		}
+		a.Notes = e.Notes
+		a.Approved = bulk.Approve || a.Approved
+		if err := s.prepare(userID, &a); err != nil {
+			return nil, fmt.Errorf("anggota %s: %w", e.MemberID, err)
+		}
+		list = append(list, a)
+	}
+	if err := s.repo.SaveAll(list); err != nil {
+		return nil, err
+	}
+	return list, s.annotate(userID, list)
+}
+
+func (s *attendanceService) Delete(id, userID uint) error {
+	a, err := s.Get(id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureUnlocked(a.MemberID, a.Date); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *attendanceService) SetApproval(userID uint, ids []uint, approved bool) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	list, err := s.repo.FindAll(userID, repository.AttendanceFilter{IDs: ids})
+	if err != nil {
+		return 0, err
+	}
+	// Persetujuan mengubah detail gaji, jadi absensi pada gaji yang sudah dikunci ditolak seperti Save/Delete.
+	checked := map[string]bool{}
+	for _, a := range list {
+		key := a.MemberID + "|" + a.Date[:7]
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+		if err := s.ensureUnlocked(a.MemberID, a.Date); err != nil {
+			return 0, err
+		}
+	}
+	return s.repo.SetApproval(userID, ids, approved)
+}
+
+func (s *attendanceService) Matrix(userID uint, month string, projectID uint) (*entity.AttendanceMatrix, error) {
+	if !validMonth(month) {
+		return nil, ErrPayrollMonth
+	}
+	start, _ := time.Parse("2006-01", month)
+	days := start.AddDate(0, 1, -1).Day()
+	list, err := s.repo.FindAll(userID, repository.AttendanceFilter{
+		ProjectID: projectID,
+		From:      month + "-01",
+		To:        fmt.Sprintf("%s-%02d", month, days),
+	})
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	byMember := map[string][]entity.Attendance{}
+	for _, a := range list {
+		byMember[a.MemberID] = append(byMember[a.MemberID], a)
+	}
+	matrix := &entity.AttendanceMatrix{Month: month, Days: days, Rows: []entity.AttendanceMatrixRow{}}
+	for _, m := range members {
+		entries := byMember[m.ID]
+		// Tanpa filter proyek tampilkan semua anggota aktif; dengan filter hanya yang punya absensi di proyek itu.
+		if len(entries) == 0 && (!m.IsActive || projectID > 0) {
+			continue
+		}
+		row := entity.AttendanceMatrixRow{MemberID: m.ID, MemberName: m.FullName,
+			Days: make([]entity.AttendanceMatrixCell, days), StatusCounts: map[string]int{}}
+		for d := range row.Days {
+			row.Days[d].Date = fmt.Sprintf("%s-%02d", month, d+1)
+		}
+		for _, a := range entries {
+			cell := &row.Days[dayOfMonth(a.Date)-1]
+			// Satu tanggal bisa punya beberapa shift: hadir di salah satu shift dihitung hadir.
+			if cell.Status == "" {
+				cell.Approved = true
+			}
+			if cell.Status == "" || a.Status == entity.AttendancePresent {
+				cell.Status = a.Status
+			}
+			cell.Hours = round2(cell.Hours + a.Hours)
+			cell.Overtime = round2(cell.Overtime + a.OvertimeHours)
+			cell.Trips += a.Trips
+			cell.Approved = cell.Approved && a.Approved
+			if a.Status == entity.AttendancePresent && !a.Approved {
+				row.Pending++
+			}
+		}
+		for _, cell := range row.Days {
+			if cell.Status == "" {
+				continue
+			}
+			row.StatusCounts[cell.Status]++
+			row.TotalHours = round2(row.TotalHours + cell.Hours)
+			row.TotalOvertime = round2(row.TotalOvertime + cell.Overtime)
+			row.TotalTrips += cell.Trips
+		}
+		row.PresentDays = row.StatusCounts[entity.AttendancePresent]
+		matrix.Rows = append(matrix.Rows, row)
+	}
+	sort.SliceStable(matrix.Rows, func(i, j int) bool {
+		return strings.ToLower(matrix.Rows[i].MemberName) < strings.ToLower(matrix.Rows[j].MemberName)
+	})
+	return matrix, nil
+}
+
+func dayOfMonth(date string) int {
+	t, _ := parseDay(date)
+	return t.Day()
+}
+
+func (s *attendanceService) SyncSalaryDetails(salary *entity.Salary) error {
+	if salary.ID == 0 || salary.Locked {
+		return nil
+	}
+	month := salaryMonthKey(salary.Month)
+	if salary.PeriodYear > 0 {
+		month = fmt.Sprintf("%04d-%02d", salary.PeriodYear, salary.PeriodMonth)
+	}
+	if month == "" {
+		return nil
+	}
+	member, err := s.memberRepo.FindByID(salary.MemberID)
+	if err != nil {
+		return err
+	}
+	list, err := s.repo.FindAll(member.UserID, repository.AttendanceFilter{
+		MemberID:     member.ID,
+		From:         month + "-01",
+		To:           month + "-31",
+		ApprovedOnly: true,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.annotate(member.UserID, list); err != nil {
+		return err
+	}
+	termsOn, err := s.employment.TermsLookup(member.ID)
+	if err != nil {
+		return err
+	}
+	projects := map[uint]string{}
+	details := []entity.SalaryDetail{}
+	for i := range list {
+		a := &list[i]
+		if a.Status != entity.AttendancePresent {
+			continue
+		}
+		place := a.Site
+		if place == "" && a.ProjectID != nil {
+			if _, ok := projects[*a.ProjectID]; !ok {
+				if p, err := s.projectService.GetProjectByID(*a.ProjectID, member.UserID); err == nil {
+					projects[*a.ProjectID] = p.Name
+				}
+			}
+			place = projects[*a.ProjectID]
+		}
+		label := "Absensi"
+		for _, part := range []string{a.Shift, place, a.EquipmentName} {
+			if part != "" {
+				label += " - " + part
+			}
+		}
+		tanggal, _ := parseDay(a.Date)
+		qty, unit := a.Hours, "jam"
+		if a.PayBasis == entity.PayPerTrip {
+			qty, unit = a.Trips, "trip"
+		}
+		// Tarif kosong = tarif kontrak/riwayat yang berlaku pada tanggal absensi; jika itu pun kosong, hitung ulang
+		// ditolak agar baris tidak tercatat Rp0.
+		baseRate, source := a.Rate, entity.RateSourceManual
+		if baseRate == 0 {
+			terms := termsOn(a.Date)
+			baseRate, source = terms.HourlyRate, entity.RateSourceContract
+			if a.PayBasis == entity.PayPerTrip {
+				baseRate = terms.TripRate
+			}
+		}
+		if baseRate == 0 && (qty > 0 || (a.OvertimeHours > 0 && a.OvertimeRate == 0)) {
+			return fmt.Errorf("%w (absensi %s, per %s)", ErrSalaryRateMissing, a.Date, unit)
+		}
+		if qty > 0 {
+			details = append(details, entity.SalaryDetail{SalaryID: salary.ID, Tanggal: tanggal, JamTrip: float32(qty),
+				HargaPerJam: baseRate, Keterangan: fmt.Sprintf("%s (%s)", label, unit), AbsensiID: &a.ID, RateSource: source})
+		}
+		if a.OvertimeHours > 0 {
+			rate, overtimeSource := a.OvertimeRate, entity.RateSourceManual
+			if rate == 0 {
+				rate, overtimeSource = round2(baseRate*entity.DefaultOvertimeMultiplier), source
+			}
+			details = append(details, entity.SalaryDetail{SalaryID: salary.ID, Tanggal: tanggal, JamTrip: float32(a.OvertimeHours),
+				HargaPerJam: rate, Keterangan: label + " (lembur)", AbsensiID: &a.ID, RateSource: overtimeSource})
+		}
+	}
+	return s.detailRepo.ReplaceFromAttendance(salary.ID, details)
+}
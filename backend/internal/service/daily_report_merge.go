@@ -0,0 +1,143 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+)
+
+// DailyReportPatch satu entri merge laporan harian: wajib "date" (YYYY-MM-DD), opsional "version" (versi baris yang
+// terakhir dilihat client), dan hanya field yang diubah. Field yang tidak dikirim tidak disentuh.
+type DailyReportPatch map[string]json.RawMessage
+
+// Status hasil merge per tanggal.
+const (
+	MergeCreated  = "created"
+	MergeUpdated  = "updated"
+	MergeConflict = "conflict"
+)
+
+// DailyMergeResult hasil merge satu tanggal. Current berisi baris terbaru jika conflict (nil jika baris sudah dihapus).
+type DailyMergeResult struct {
+	Date    string              `json:"date"`
+	Status  string              `json:"status"`
+	Version uint                `json:"version"`
+	Current *entity.ReportDaily `json:"current,omitempty"`
+}
+
+var errDailyConflict = errors.New("daily report version conflict")
+
+// field yang dikelola server; diabaikan jika ada di patch.
+var dailyPatchReadOnly = map[string]bool{"id": true, "projectId": true, "date": true, "version": true, "createdAt": true, "updatedAt": true}
+
+func (p DailyReportPatch) date() string {
+	var d string
+	_ = json.Unmarshal(p["date"], &d)
+	return d
+}
+
+func (p DailyReportPatch) version() uint {
+	var v uint
+	_ = json.Unmarshal(p["version"], &v)
+	return v
+}
+
+// applyTo menimpa field report dengan field yang ada di patch (lewat JSON agar nama field sama dengan API).
+func (p DailyReportPatch) applyTo(report *entity.ReportDaily) error {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	for k, v := range p {
+		if !dailyPatchReadOnly[k] {
+			doc[k] = v
+		}
+	}
+	merged, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	id, projectID, date, version, createdAt := report.ID, report.ProjectID, report.Date, report.Version, report.CreatedAt
+	*report = entity.ReportDaily{}
+	if err := json.Unmarshal(merged, report); err != nil {
+		return err
+	}
+	report.ID, report.ProjectID, report.Date, report.Version, report.CreatedAt = id, projectID, date, version, createdAt
+	return nil
+}
+
+// validate memastikan tanggal valid dan semua field bisa dibaca sebelum ada yang disimpan.
+func (p DailyReportPatch) validate() error {
+	date := p.date()
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("date harus YYYY-MM-DD: %q", date)
+	}
+	var scratch entity.ReportDaily
+	if err := p.applyTo(&scratch); err != nil {
+		return fmt.Errorf("%s: %w", date, err)
+	}
+	return nil
+}
+
+// MergeDailyReports menggabungkan perubahan per tanggal ke tabel daily_reports. Entri dengan version yang sudah usang
+// tidak disimpan dan dilaporkan sebagai conflict beserta baris terbaru; entri lain tetap disimpan.
+// Tanpa version: field yang dikirim menimpa nilai saat ini (field lain tetap).
+func (s *projectService) MergeDailyReports(projectID uint, patches []DailyReportPatch) ([]DailyMergeResult, error) {
+	seen := map[string]bool{}
+	for _, p := range patches {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if seen[p.date()] {
+			return nil, fmt.Errorf("tanggal %s dikirim lebih dari sekali", p.date())
+		}
+		seen[p.date()] = true
+	}
+	if _, err := s.MigrateDailyReports(projectID, false); err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	results := make([]DailyMergeResult, 0, len(patches))
+	for _, p := range patches {
+		res := DailyMergeResult{Date: p.date()}
+		var saved entity.ReportDaily
+		created, err := s.dailyRepo.MergeByDate(projectID, res.Date, func(r *entity.ReportDaily, exists bool) error {
+			if want := p.version(); want > 0 && (!exists || r.Version != want) {
+				return errDailyConflict
+			}
+			if err := p.applyTo(r); err != nil {
+				return err
+			}
+			if !exists {
+				r.CreatedAt = now
+			}
+			r.UpdatedAt = now
+			defaultReportJSON(r)
+			saved = *r
+			return nil
+		})
+		switch {
+		case errors.Is(err, errDailyConflict):
+			res.Status = MergeConflict
+			if current, err := s.dailyRepo.FindByProjectAndDate(projectID, res.Date); err == nil {
+				res.Current = current
+				res.Version = current.Version
+			}
+		case err != nil:
+			return results, err
+		case created:
+			res.Status, res.Version = MergeCreated, 1
+		default:
+			res.Status, res.Version = MergeUpdated, saved.Version+1
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -3,16 +3,33 @@ package service
 import (
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/repository"
+	"errors"
+	"strings"
 	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
+var ErrDailyReportExists = errors.New("laporan harian untuk tanggal ini sudah ada; edit laporan yang ada")
+
 type DailyReportService interface {
 	CreateDailyReport(report *entity.ReportDaily) error
 	GetDailyReportsByProject(projectID uint) ([]entity.ReportDaily, error)
 	GetDailyReportByID(id uint) (*entity.ReportDaily, error)
-	UpdateDailyReport(report *entity.ReportDaily) error
+	UpdateDailyReport(report *entity.ReportDaily, expectedVersion uint) error
 	DeleteDailyReport(id uint) error
 	GetDailyReportsByDateRange(projectID uint, startDate, endDate string) ([]entity.ReportDaily, error)
+	MergeCutFill(projectID uint, entries []CutFillEntry) (updated, created int, err error)
+}
+
+// CutFillEntry data harian cut-fill dari Smart Nota. Hanya field ini yang diubah pada baris yang sudah ada.
+type CutFillEntry struct {
+	Date    string
+	Ritase  float64
+	Aktual  float64
+	Cuaca   string
+	Catatan string
 }
 
 type dailyReportService struct {
@@ -26,12 +43,20 @@ func NewDailyReportService(dailyReportRepo repository.DailyReportRepository) Dai
 }
 
 func (s *dailyReportService) CreateDailyReport(report *entity.ReportDaily) error {
+	if _, err := s.dailyReportRepo.FindByProjectAndDate(report.ProjectID, report.Date); err == nil {
+		return ErrDailyReportExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
 	now := time.Now().Unix()
+	report.ID = 0
 	report.CreatedAt = now
 	report.UpdatedAt = now
+	defaultReportJSON(report)
 
 	// Set timestamps for images
 	for i := range report.Images {
+		report.Images[i].ID = 0
 		report.Images[i].CreatedAt = now
 		report.Images[i].UpdatedAt = now
 	}
@@ -47,15 +72,33 @@ func (s *dailyReportService) GetDailyReportByID(id uint) (*entity.ReportDaily, e
 	return s.dailyReportRepo.FindByID(id)
 }
 
-func (s *dailyReportService) UpdateDailyReport(report *entity.ReportDaily) error {
+// UpdateDailyReport expectedVersion > 0: gagal dengan ErrVersionConflict jika baris sudah diubah orang lain.
+func (s *dailyReportService) UpdateDailyReport(report *entity.ReportDaily, expectedVersion uint) error {
+	existing, err := s.dailyReportRepo.FindByID(report.ID)
+	if err != nil {
+		return err
+	}
+	if other, err := s.dailyReportRepo.FindByProjectAndDate(existing.ProjectID, report.Date); err == nil && other.ID != report.ID {
+		return ErrDailyReportExists
+	}
+	report.ProjectID = existing.ProjectID
+	report.CreatedAt = existing.CreatedAt
 	report.UpdatedAt = time.Now().Unix()
+	defaultReportJSON(report)
 
 	// Update timestamps for images
 	for i := range report.Images {
 		report.Images[i].UpdatedAt = time.Now().Unix()
 	}
 
-	return s.dailyReportRepo.Update(report)
+	ok, err := s.dailyReportRepo.Update(report, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 func (s *dailyReportService) DeleteDailyReport(id uint) error {
@@ -69,3 +112,45 @@ func (s *dailyReportService) DeleteDailyReport(id uint) error {
 func (s *dailyReportService) GetDailyReportsByDateRange(projectID uint, startDate, endDate string) ([]entity.ReportDaily, error) {
 	return s.dailyReportRepo.FindByDateRange(projectID, startDate, endDate)
 }
+
+// MergeCutFill menulis ritase/aktual/cuaca/catatan per tanggal; field lain (volume, pekerja, gambar) tidak disentuh
+// sehingga edit bersamaan dari dashboard tidak tertimpa.
+func (s *dailyReportService) MergeCutFill(projectID uint, entries []CutFillEntry) (updated, created int, err error) {
+	now := time.Now().Unix()
+	for _, e := range entries {
+		isNew, err := s.dailyReportRepo.MergeByDate(projectID, strings.TrimSpace(e.Date), func(r *entity.ReportDaily, exists bool) error {
+			r.Ritase = e.Ritase
+			r.Aktual = e.Aktual
+			if e.Cuaca != "" || !exists {
+				r.Cuaca = e.Cuaca
+			}
+			if e.Catatan != "" || !exists {
+				r.Catatan = e.Catatan
+			}
+			if !exists {
+				r.CreatedAt = now
+				defaultReportJSON(r)
+			}
+			r.UpdatedAt = now
+			return nil
+		})
+		if err != nil {
+			return updated, created, err
+		}
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+	return updated, created, nil
+}
+
+func defaultReportJSON(r *entity.ReportDaily) {
+	if len(r.Workers) == 0 {
+		r.Workers = datatypes.JSON("{}")
+	}
+	if len(r.Equipment) == 0 {
+		r.Equipment = datatypes.JSON("{}")
+	}
+}
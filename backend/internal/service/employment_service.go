@@ -0,0 +1,389 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrEmploymentInvalid = errors.New("data kepegawaian tidak valid: contract_type pkwt/pkwtt, tanggal YYYY-MM-DD, PKWT wajib end_date >= start_date, tarif tidak boleh negatif")
+	ErrContractOverlap   = errors.New("kontrak tumpang tindih dengan kontrak lain anggota ini")
+	ErrEmploymentEvent   = errors.New("hanya perubahan jabatan/tarif yang bisa dihapus; peristiwa kontrak ikut kontraknya")
+)
+
+// EmploymentChange perubahan jabatan dan/atau tarif mulai EffectiveDate; field kosong/nil = tetap seperti sebelumnya.
+type EmploymentChange struct {
+	EffectiveDate string   `json:"effective_date"`
+	Role          string   `json:"role"`
+	HourlyRate    *float64 `json:"hourly_rate"`
+	TripRate      *float64 `json:"trip_rate"`
+	Notes         string   `json:"notes"`
+}
+
+type EmploymentService interface {
+	History(memberID string, userID uint) (*entity.EmploymentHistory, error)
+	// TermsOn jabatan dan tarif anggota yang berlaku pada date (YYYY-MM-DD), dipakai payroll.
+	TermsOn(memberID, date string) (*entity.EmploymentTerms, error)
+	// TermsLookup seperti TermsOn untuk banyak tanggal: riwayat dan kontrak anggota dimuat sekali.
+	TermsLookup(memberID string) (func(date string) entity.EmploymentTerms, error)
+	// TermsFor seperti TermsOn untuk anggota milik user; date divalidasi.
+	TermsFor(memberID string, userID uint, date string) (*entity.EmploymentTerms, error)
+	GetContract(id uint, memberID string, userID uint) (*entity.MemberContract, error)
+	// SaveContract membuat/memperbarui kontrak; jabatan dan tarifnya berlaku mulai StartDate.
+	SaveContract(memberID string, userID uint, contract *entity.MemberContract) error
+	DeleteContract(id uint, memberID string, userID uint) error
+	RecordChange(memberID string, userID uint, change *EmploymentChange) (*entity.MemberEmploymentEvent, error)
+	DeleteEvent(id uint, memberID string, userID uint) error
+	// RecordJoined mencatat jabatan awal anggota baru mulai JoinDate.
+	RecordJoined(member *entity.Member) error
+	// RecordStatus mencatat penonaktifan/pengaktifan kembali (EmploymentDeactivated/EmploymentReactivated).
+	RecordStatus(member *entity.Member, eventType, date, notes string) error
+	// ApplyDue dipanggil job harian: Member.Role diselaraskan dengan jabatan yang berlaku hari ini
+	// (perubahan bertanggal ke depan baru terlihat saat tanggalnya tiba).
+	ApplyDue(now time.Time) (int, error)
+}
+
+type employmentService struct {
+	repo       repository.EmploymentRepository
+	memberRepo repository.MemberRepository
+}
+
+func NewEmploymentService(repo repository.EmploymentRepository, memberRepo repository.MemberRepository) EmploymentService {
+	return &employmentService{repo, memberRepo}
+}
+
+// carriesTerms peristiwa yang menetapkan jabatan dan tarif.
+func carriesTerms(eventType string) bool {
+	return eventType == entity.EmploymentJoined || eventType == entity.EmploymentContract || eventType == entity.EmploymentChange
+}
+
+// termsOn menerapkan riwayat (urut tanggal) sampai date. Tanpa riwayat, jabatan diambil dari Member; tanpa
+// peristiwa status, status aktif juga dari Member.
+func termsOn(member *entity.Member, events []entity.MemberEmploymentEvent, contracts []entity.MemberContract, date string) entity.EmploymentTerms {
+	t := entity.EmploymentTerms{MemberID: member.ID, Date: date, Role: member.Role, Active: member.IsActive}
+	for _, e := range events {
+		if e.EventType == entity.EmploymentDeactivated || e.EventType == entity.EmploymentReactivated {
+			t.Active = true
+			break
+		}
+	}
+	for _, e := range events {
+		if e.EffectiveDate > date {
+			break
+		}
+		switch {
+		case carriesTerms(e.EventType):
+			t.Role, t.HourlyRate, t.TripRate = e.Role, e.HourlyRate, e.TripRate
+		case e.EventType == entity.EmploymentDeactivated:
+			t.Active = false
+		case e.EventType == entity.EmploymentReactivated:
+			t.Active = true
+		}
+	}
+	for _, c := range contracts {
+		if c.StartDate <= date && (c.EndDate == nil || *c.EndDate >= date) {
+			id := c.ID
+			t.ContractID, t.ContractType = &id, c.ContractType
+		}
+	}
+	return t
+}
+
+func contractStatus(c *entity.MemberContract, day string) string {
+	switch {
+	case c.StartDate > day:
+		return entity.ContractUpcoming
+	case c.EndDate != nil && *c.EndDate < day:
+		return entity.ContractEnded
+	}
+	return entity.ContractActive
+}
+
+func (s *employmentService) member(memberID string, userID uint) (*entity.Member, error) {
+	m, err := s.memberRepo.FindByID(memberID)
+	if err != nil || m.UserID != userID {
+		return nil, errors.New("anggota tidak ditemukan")
+	}
+	return m, nil
+}
+
+func (s *employmentService) load(member *entity.Member) ([]entity.MemberEmploymentEvent, []entity.MemberContract, error) {
+	events, err := s.repo.FindEvents(member.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	contracts, err := s.repo.FindContracts(member.ID)
+	return events, contracts, err
+}
+
+// syncRole menyamakan Member.Role dengan jabatan yang berlaku pada day.
+func (s *employmentService) syncRole(member *entity.Member, day string) (bool, error) {
+	events, contracts, err := s.load(member)
+	if err != nil {
+		return false, err
+	}
+	t := termsOn(member, events, contracts, day)
+	if t.Role == "" || t.Role == member.Role {
+		return false, nil
+	}
+	member.Role = t.Role
+	return true, s.memberRepo.UpdateRole(member.ID, t.Role)
+}
+
+func (s *employmentService) History(memberID string, userID uint) (*entity.EmploymentHistory, error) {
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, contracts, err := s.load(m)
+	if err != nil {
+		return nil, err
+	}
+	day := today().Format(dayLayout)
+	for i := range contracts {
+		contracts[i].Status = contractStatus(&contracts[i], day)
+	}
+	return &entity.EmploymentHistory{Contracts: contracts, Events: events, Current: termsOn(m, events, contracts, day)}, nil
+}
+
+func (s *employmentService) TermsOn(memberID, date string) (*entity.EmploymentTerms, error) {
+	m, err := s.memberRepo.FindByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	events, contracts, err := s.load(m)
+	if err != nil {
+		return nil, err
+	}
+	t := termsOn(m, events, contracts, date)
+	return &t, nil
+}
+
+func (s *employmentService) TermsLookup(memberID string) (func(date string) entity.EmploymentTerms, error) {
+	m, err := s.memberRepo.FindByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	events, contracts, err := s.load(m)
+	if err != nil {
+		return nil, err
+	}
+	return func(date string) entity.EmploymentTerms {
+		return termsOn(m, events, contracts, date)
+	}, nil
+}
+
+func (s *employmentService) TermsFor(memberID string, userID uint, date string) (*entity.EmploymentTerms, error) {
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !validDay(date) {
+		return nil, ErrEmploymentInvalid
+	}
+	events, contracts, err := s.load(m)
+	if err != nil {
+		return nil, err
+	}
+	t := termsOn(m, events, contracts, date)
+	return &t, nil
+}
+
+func (s *employmentService) GetContract(id uint, memberID string, userID uint) (*entity.MemberContract, error) {
+	c, err := s.repo.FindContractByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserID != userID || c.MemberID != memberID {
+		return nil, errors.New("kontrak tidak ditemukan")
+	}
+	c.Status = contractStatus(c, today().Format(dayLayout))
+	return c, nil
+}
+
+func (s *employmentService) SaveContract(memberID string, userID uint, c *entity.MemberContract) error {
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return err
+	}
+	c.UserID, c.MemberID = userID, memberID
+	c.ContractType = strings.ToLower(strings.TrimSpace(c.ContractType))
+	c.Role = strings.TrimSpace(c.Role)
+	if c.EndDate != nil && strings.TrimSpace(*c.EndDate) == "" {
+		c.EndDate = nil
+	}
+	if !validDay(c.StartDate) || (c.EndDate != nil && (!validDay(*c.EndDate) || *c.EndDate < c.StartDate)) ||
+		c.HourlyRate < 0 || c.TripRate < 0 {
+		return ErrEmploymentInvalid
+	}
+	switch c.ContractType {
+	case entity.ContractPKWT:
+		if c.EndDate == nil {
+			return ErrEmploymentInvalid
+		}
+	case entity.ContractPKWTT:
+	default:
+		return ErrEmploymentInvalid
+	}
+	events, contracts, err := s.load(m)
+	if err != nil {
+		return err
+	}
+	for _, o := range contracts {
+		if o.ID == c.ID {
+			continue
+		}
+		startsBeforeEnd := c.EndDate == nil || o.StartDate <= *c.EndDate
+		endsAfterStart := o.EndDate == nil || *o.EndDate >= c.StartDate
+		if startsBeforeEnd && endsAfterStart {
+			return ErrContractOverlap
+		}
+	}
+	if c.Role == "" {
+		c.Role = termsOn(m, events, contracts, c.StartDate).Role
+	}
+	event := &entity.MemberEmploymentEvent{}
+	if c.ID > 0 {
+		if existing, err := s.repo.FindEventByContract(c.ID); err == nil {
+			event = existing
+		}
+	}
+	event.UserID, event.MemberID, event.EventType = userID, memberID, entity.EmploymentContract
+	event.EffectiveDate, event.Role, event.HourlyRate, event.TripRate = c.StartDate, c.Role, c.HourlyRate, c.TripRate
+	event.Notes = strings.TrimSpace(fmt.Sprintf("Kontrak %s %s", strings.ToUpper(c.ContractType), c.Number))
+	if err := s.repo.SaveContract(c, event); err != nil {
+		return err
+	}
+	c.Status = contractStatus(c, today().Format(dayLayout))
+	_, err = s.syncRole(m, today().Format(dayLayout))
+	return err
+}
+
+func (s *employmentService) DeleteContract(id uint, memberID string, userID uint) error {
+	if _, err := s.GetContract(id, memberID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteContract(id); err != nil {
+		return err
+	}
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.syncRole(m, today().Format(dayLayout))
+	return err
+}
+
+func (s *employmentService) RecordChange(memberID string, userID uint, change *EmploymentChange) (*entity.MemberEmploymentEvent, error) {
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !validDay(change.EffectiveDate) ||
+		(change.HourlyRate != nil && *change.HourlyRate < 0) || (change.TripRate != nil && *change.TripRate < 0) {
+		return nil, ErrEmploymentInvalid
+	}
+	events, contracts, err := s.load(m)
+	if err != nil {
+		return nil, err
+	}
+	before := termsOn(m, events, contracts, change.EffectiveDate)
+	event := &entity.MemberEmploymentEvent{
+		UserID:        userID,
+		MemberID:      memberID,
+		EventType:     entity.EmploymentChange,
+		EffectiveDate: change.EffectiveDate,
+		Role:          before.Role,
+		HourlyRate:    before.HourlyRate,
+		TripRate:      before.TripRate,
+		Notes:         strings.TrimSpace(change.Notes),
+	}
+	if r := strings.TrimSpace(change.Role); r != "" {
+		event.Role = r
+	}
+	if change.HourlyRate != nil {
+		event.HourlyRate = *change.HourlyRate
+	}
+	if change.TripRate != nil {
+		event.TripRate = *change.TripRate
+	}
+	if err := s.repo.SaveEvent(event); err != nil {
+		return nil, err
+	}
+	_, err = s.syncRole(m, today().Format(dayLayout))
+	return event, err
+}
+
+func (s *employmentService) DeleteEvent(id uint, memberID string, userID uint) error {
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return err
+	}
+	e, err := s.repo.FindEventByID(id)
+	if err != nil || e.MemberID != memberID {
+		return errors.New("riwayat tidak ditemukan")
+	}
+	if e.EventType != entity.EmploymentChange {
+		return ErrEmploymentEvent
+	}
+	if err := s.repo.DeleteEvent(id); err != nil {
+		return err
+	}
+	_, err = s.syncRole(m, today().Format(dayLayout))
+	return err
+}
+
+func (s *employmentService) RecordJoined(member *entity.Member) error {
+	date := member.JoinDate
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	if !validDay(date) {
+		date = today().Format(dayLayout)
+	}
+	return s.repo.SaveEvent(&entity.MemberEmploymentEvent{
+		UserID:        member.UserID,
+		MemberID:      member.ID,
+		EventType:     entity.EmploymentJoined,
+		EffectiveDate: date,
+		Role:          member.Role,
+	})
+}
+
+func (s *employmentService) RecordStatus(member *entity.Member, eventType, date, notes string) error {
+	return s.repo.SaveEvent(&entity.MemberEmploymentEvent{
+		UserID:        member.UserID,
+		MemberID:      member.ID,
+		EventType:     eventType,
+		EffectiveDate: date,
+		Notes:         notes,
+	})
+}
+
+func (s *employmentService) ApplyDue(now time.Time) (int, error) {
+	ids, err := s.repo.FindMemberIDs()
+	if err != nil {
+		return 0, err
+	}
+	day := now.Format(dayLayout)
+	updated := 0
+	for _, id := range ids {
+		m, err := s.memberRepo.FindByID(id)
+		if err != nil {
+			continue
+		}
+		changed, err := s.syncRole(m, day)
+		if err != nil {
+			return updated, err
+		}
+		if changed {
+			updated++
+		}
+	}
+	return updated, nil
+}
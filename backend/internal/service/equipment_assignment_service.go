@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrAssignmentInvalid = errors.New("penempatan tidak valid: equipment, proyek dan from_date (YYYY-MM-DD) wajib; to_date >= from_date; rate_unit hari/jam/bulan; operator_id anggota sendiri")
+	ErrAssignmentOverlap = errors.New("alat sudah ditempatkan di proyek lain pada rentang tanggal tersebut")
+)
+
+// ProjectDeployment alat yang berada di satu proyek pada suatu tanggal.
+type ProjectDeployment struct {
+	ProjectID   uint                         `json:"project_id"`
+	ProjectName string                       `json:"project_name"`
+	Equipment   []entity.EquipmentDeployment `json:"equipment"`
+}
+
+// DeploymentSnapshot posisi seluruh armada pada satu tanggal: per proyek + unit yang tidak ditempatkan.
+type DeploymentSnapshot struct {
+	Date     string              `json:"date"`
+	Projects []ProjectDeployment `json:"projects"`
+	Idle     []entity.Equipment  `json:"idle"`
+}
+
+type EquipmentAssignmentService interface {
+	List(userID uint, filter repository.EquipmentAssignmentFilter) ([]entity.EquipmentDeployment, error)
+	GetByID(id, userID uint) (*entity.EquipmentAssignment, error)
+	// Save membuat (ID 0) atau memperbarui penempatan. ErrAssignmentOverlap disertai penempatan yang bentrok.
+	// Operator tanpa lisensi berlaku untuk jenis alat tetap disimpan, dengan peringatan di a.Warnings.
+	Save(userID uint, a *entity.EquipmentAssignment) ([]entity.EquipmentDeployment, error)
+	Delete(id, userID uint) error
+	DeployedOn(userID uint, date string) (*DeploymentSnapshot, error)
+}
+
+type equipmentAssignmentService struct {
+	repo           repository.EquipmentAssignmentRepository
+	equipmentRepo  repository.EquipmentRepository
+	projectService ProjectService
+	licences       MemberDocumentService
+}
+
+func NewEquipmentAssignmentService(repo repository.EquipmentAssignmentRepository, equipmentRepo repository.EquipmentRepository, projectService ProjectService, licences MemberDocumentService) EquipmentAssignmentService {
+	return &equipmentAssignmentService{repo, equipmentRepo, projectService, licences}
+}
+
+func (s *equipmentAssignmentService) List(userID uint, filter repository.EquipmentAssignmentFilter) ([]entity.EquipmentDeployment, error) {
+	return s.repo.FindAll(userID, filter)
+}
+
+func (s *equipmentAssignmentService) GetByID(id, userID uint) (*entity.EquipmentAssignment, error) {
+	a, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, errors.New("penempatan tidak ditemukan")
+	}
+	return a, nil
+}
+
+func (s *equipmentAssignmentService) Save(userID uint, a *entity.EquipmentAssignment) ([]entity.EquipmentDeployment, error) {
+	a.UserID = userID
+	a.FromDate = strings.TrimSpace(a.FromDate)
+	if a.ToDate != nil && strings.TrimSpace(*a.ToDate) == "" {
+		a.ToDate = nil
+	}
+	from, ok := parseDay(a.FromDate)
+	if !ok || len(a.FromDate) != 10 || a.EquipmentID == 0 || a.ProjectID == 0 || a.Rate < 0 {
+		return nil, ErrAssignmentInvalid
+	}
+	if a.ToDate != nil {
+		to, ok := parseDay(*a.ToDate)
+		if !ok || len(*a.ToDate) != 10 || to.Before(from) {
+			return nil, ErrAssignmentInvalid
+		}
+	}
+	switch a.RateUnit {
+	case "":
+		a.RateUnit = "hari"
+	case "hari", "jam", "bulan":
+	default:
+		return nil, ErrAssignmentInvalid
+	}
+	equipment, err := s.equipmentRepo.FindByIDForUser(a.EquipmentID, userID)
+	if err != nil {
+		return nil, ErrAssignmentInvalid
+	}
+	if _, err := s.projectService.GetProjectByID(a.ProjectID, userID); err != nil {
+		return nil, ErrAssignmentInvalid
+	}
+	if a.OperatorID != nil && strings.TrimSpace(*a.OperatorID) == "" {
+		a.OperatorID = nil
+	}
+	a.Warnings = nil
+	if a.OperatorID != nil {
+		a.Warnings, err = s.licences.LicenceWarnings(userID, *a.OperatorID, equipment.Type, a.FromDate, a.ToDate)
+		if errors.Is(err, ErrMemberDocumentMember) {
+			return nil, ErrAssignmentInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	conflicts, err := s.repo.Save(a)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return conflicts, ErrAssignmentOverlap
+	}
+	return nil, nil
+}
+
+func (s *equipmentAssignmentService) Delete(id, userID uint) error {
+	if _, err := s.GetByID(id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// DeployedOn alat yang sedang ditempatkan pada date, dikelompokkan per proyek, plus unit yang menganggur.
+func (s *equipmentAssignmentService) DeployedOn(userID uint, date string) (*DeploymentSnapshot, error) {
+	if _, ok := parseDay(date); !ok || len(date) != 10 {
+		return nil, ErrAssignmentInvalid
+	}
+	active, err := s.repo.FindAll(userID, repository.EquipmentAssignmentFilter{Date: date})
+	if err != nil {
+		return nil, err
+	}
+	fleet, err := s.equipmentRepo.FindAll(userID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	snap := &DeploymentSnapshot{Date: date, Projects: []ProjectDeployment{}, Idle: []entity.Equipment{}}
+	index := map[uint]int{}
+	deployed := map[uint]bool{}
+	for _, d := range active {
+		i, ok := index[d.ProjectID]
+		if !ok {
+			i = len(snap.Projects)
+			index[d.ProjectID] = i
+			snap.Projects = append(snap.Projects, ProjectDeployment{ProjectID: d.ProjectID, ProjectName: d.ProjectName})
+		}
+		snap.Projects[i].Equipment = append(snap.Projects[i].Equipment, d)
+		deployed[d.EquipmentID] = true
+	}
+	for _, e := range fleet {
+		if !deployed[e.ID] {
+			snap.Idle = append(snap.Idle, e)
+		}
+	}
+	return snap, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var ErrDocumentInvalid = errors.New("dokumen tidak valid: doc_type stnk/kir/asuransi/sia/silo/lainnya, tanggal YYYY-MM-DD, expiry_date >= issue_date")
+
+var documentTypes = map[string]string{
+	entity.DocSTNK:      "STNK",
+	entity.DocKIR:       "KIR",
+	entity.DocInsurance: "Asuransi",
+	entity.DocSIA:       "SIA",
+	entity.DocSILO:      "SILO",
+	entity.DocOther:     "Dokumen",
+}
+
+// stageRank urutan keparahan status; aktivitas dicatat saat status naik ke tingkat yang belum pernah dicatat.
+var stageRank = map[string]int{
+	entity.DocStatus30:      1,
+	entity.DocStatus14:      2,
+	entity.DocStatus7:       3,
+	entity.DocStatusExpired: 4,
+}
+
+type EquipmentDocumentService interface {
+	List(equipmentID, userID uint) ([]entity.EquipmentDocument, error)
+	Get(id, equipmentID, userID uint) (*entity.EquipmentDocument, error)
+	Save(equipmentID, userID uint, doc *entity.EquipmentDocument) error
+	Delete(id, equipmentID, userID uint) error
+	// Compliance kelengkapan dokumen seluruh armada; onlyIssues = hanya alat yang tidak compliant atau punya dokumen hampir habis.
+	Compliance(userID uint, onlyIssues bool) ([]entity.EquipmentCompliance, error)
+	// CheckExpiring dipanggil job harian: dokumen yang masuk batas 30/14/7 hari atau kedaluwarsa dicatat sebagai aktivitas.
+	CheckExpiring(now time.Time) (int, error)
+}
+
+type equipmentDocumentService struct {
+	repo            repository.EquipmentDocumentRepository
+	equipmentRepo   repository.EquipmentRepository
+	activityService ActivityService
+}
+
+func NewEquipmentDocumentService(repo repository.EquipmentDocumentRepository, equipmentRepo repository.EquipmentRepository, activityService ActivityService) EquipmentDocumentService {
+	return &equipmentDocumentService{repo, equipmentRepo, activityService}
+}
+
+// evaluateDocument mengisi DaysLeft dan Status terhadap tanggal now.
+func evaluateDocument(doc *entity.EquipmentDocument, now time.Time) {
+	doc.DaysLeft, doc.Status = evaluateExpiry(doc.ExpiryDate, now)
+}
+
+// evaluateExpiry sisa hari dan status DocStatus* untuk tanggal kedaluwarsa (nil = tidak kedaluwarsa).
+func evaluateExpiry(expiryDate *string, now time.Time) (*int, string) {
+	if expiryDate == nil {
+		return nil, entity.DocStatusNoExpiry
+	}
+	expiry, ok := parseDay(*expiryDate)
+	if !ok {
+		return nil, entity.DocStatusNoExpiry
+	}
+	days := daysBetween(now, expiry)
+	switch {
+	case days < 0:
+		return &days, entity.DocStatusExpired
+	case days <= 7:
+		return &days, entity.DocStatus7
+	case days <= 14:
+		return &days, entity.DocStatus14
+	case days <= 30:
+		return &days, entity.DocStatus30
+	default:
+		return &days, entity.DocStatusValid
+	}
+}
+
+// currentDocuments dokumen yang berlaku per alat+jenis: masa berlaku paling akhir (tanpa kedaluwarsa menang).
+// Dokumen lama yang sudah diperpanjang tidak lagi dihitung. Jenis "lainnya" berdiri sendiri-sendiri.
+func currentDocuments(docs []entity.EquipmentDocument) []entity.EquipmentDocument {
+	index := map[string]int{}
+	out := []entity.EquipmentDocument{}
+	for _, d := range docs {
+		key := fmt.Sprintf("%d/%s", d.EquipmentID, d.DocType)
+		if d.DocType == entity.DocOther {
+			key = fmt.Sprintf("id/%d", d.ID)
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(out)
+			out = append(out, d)
+			continue
+		}
+		if laterExpiry(d.ExpiryDate, out[i].ExpiryDate) {
+			out[i] = d
+		}
+	}
+	return out
+}
+
+// laterExpiry a berlaku lebih lama dari b; nil (tanpa kedaluwarsa) paling lama.
+func laterExpiry(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	return *a > *b
+}
+
+func (s *equipmentDocumentService) List(equipmentID, userID uint) ([]entity.EquipmentDocument, error) {
+	if _, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID); err != nil {
+		return nil, err
+	}
+	docs, err := s.repo.FindAll(userID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	for i := range docs {
+		evaluateDocument(&docs[i], now)
+	}
+	return docs, nil
+}
+
+func (s *equipmentDocumentService) Get(id, equipmentID, userID uint) (*entity.EquipmentDocument, error) {
+	doc, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if doc.UserID != userID || doc.EquipmentID != equipmentID {
+		return nil, errors.New("dokumen tidak ditemukan")
+	}
+	evaluateDocument(doc, today())
+	return doc, nil
+}
+
+func (s *equipmentDocumentService) Save(equipmentID, userID uint, doc *entity.EquipmentDocument) error {
+	if _, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID); err != nil {
+		return err
+	}
+	doc.UserID, doc.EquipmentID = userID, equipmentID
+	doc.DocType = strings.ToLower(strings.TrimSpace(doc.DocType))
+	if _, ok := documentTypes[doc.DocType]; !ok {
+		return ErrDocumentInvalid
+	}
+	for _, d := range []**string{&doc.IssueDate, &doc.ExpiryDate} {
+		if *d != nil && strings.TrimSpace(**d) == "" {
+			*d = nil
+		}
+		if *d != nil && !validDay(**d) {
+			return ErrDocumentInvalid
+		}
+	}
+	if doc.IssueDate != nil && doc.ExpiryDate != nil && *doc.ExpiryDate < *doc.IssueDate {
+		return ErrDocumentInvalid
+	}
+	if doc.ID > 0 {
+		// Masa berlaku berubah (perpanjangan): peringatan dimulai dari awal lagi.
+		if old, err := s.repo.FindByID(doc.ID); err == nil && !sameDate(old.ExpiryDate, doc.ExpiryDate) {
+			doc.NotifiedStage = ""
+		}
+	}
+	if err := s.repo.Save(doc); err != nil {
+		return err
+	}
+	evaluateDocument(doc, today())
+	return nil
+}
+
+func sameDate(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (s *equipmentDocumentService) Delete(id, equipmentID, userID uint) error {
+	if _, err := s.Get(id, equipmentID, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *equipmentDocumentService) Compliance(userID uint, onlyIssues bool) ([]entity.EquipmentCompliance, error) {
+	fleet, err := s.equipmentRepo.FindAll(userID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.repo.FindAll(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	byEquipment := map[uint][]entity.EquipmentDocument{}
+	for _, d := range currentDocuments(docs) {
+		evaluateDocument(&d, now)
+		byEquipment[d.EquipmentID] = append(byEquipment[d.EquipmentID], d)
+	}
+	out := []entity.EquipmentCompliance{}
+	for _, e := range fleet {
+		c := entity.EquipmentCompliance{
+			EquipmentID:   e.ID,
+			EquipmentName: e.Name,
+			EquipmentType: e.Type,
+			LicensePlate:  e.LicensePlate,
+			Missing:       []string{},
+			Expired:       []entity.EquipmentDocument{},
+			Expiring:      []entity.EquipmentDocument{},
+			Documents:     byEquipment[e.ID],
+		}
+		if c.Documents == nil {
+			c.Documents = []entity.EquipmentDocument{}
+		}
+		have := map[string]bool{}
+		for _, d := range c.Documents {
+			have[d.DocType] = true
+			switch d.Status {
+			case entity.DocStatusExpired:
+				c.Expired = append(c.Expired, d)
+			case entity.DocStatus30, entity.DocStatus14, entity.DocStatus7:
+				c.Expiring = append(c.Expiring, d)
+			}
+		}
+		for _, t := range entity.RequiredDocuments[e.Type] {
+			if !have[t] {
+				c.Missing = append(c.Missing, t)
+			}
+		}
+		requiredExpired := false
+		for _, d := range c.Expired {
+			for _, t := range entity.RequiredDocuments[e.Type] {
+				requiredExpired = requiredExpired || d.DocType == t
+			}
+		}
+		c.Compliant = len(c.Missing) == 0 && !requiredExpired
+		if onlyIssues && c.Compliant && len(c.Expiring) == 0 && len(c.Expired) == 0 {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (s *equipmentDocumentService) CheckExpiring(now time.Time) (int, error) {
+	docs, err := s.repo.FindWithExpiry()
+	if err != nil {
+		return 0, err
+	}
+	day, _ := time.Parse(dayLayout, now.Format(dayLayout))
+	equipment := map[uint]*entity.Equipment{}
+	logged := 0
+	for _, d := range currentDocuments(docs) {
+		evaluateDocument(&d, day)
+		if stageRank[d.Status] == 0 || stageRank[d.Status] <= stageRank[d.NotifiedStage] {
+			continue
+		}
+		e, ok := equipment[d.EquipmentID]
+		if !ok {
+			e, _ = s.equipmentRepo.FindByID(d.EquipmentID)
+			equipment[d.EquipmentID] = e
+		}
+		if e == nil {
+			continue
+		}
+		name := e.Name
+		if e.LicensePlate != "" {
+			name += " (" + e.LicensePlate + ")"
+		}
+		label := documentTypes[d.DocType]
+		title := fmt.Sprintf("%s %s berakhir dalam %d hari", label, name, *d.DaysLeft)
+		if d.Status == entity.DocStatusExpired {
+			title = fmt.Sprintf("%s %s sudah kedaluwarsa", label, name)
+		}
+		desc := fmt.Sprintf("No. %s, berlaku sampai %s", d.Number, *d.ExpiryDate)
+		if err := s.activityService.LogActivity(d.UserID, entity.ActivityCompliance, title, desc); err != nil {
+			return logged, err
+		}
+		if err := s.repo.SetNotified(d.ID, d.Status); err != nil {
+			return logged, err
+		}
+		logged++
+	}
+	return logged, nil
+}
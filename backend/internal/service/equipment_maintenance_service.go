@@ -0,0 +1,388 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+// SourceMaintenance Finance.Source untuk expense yang dibuat dari riwayat servis.
+const SourceMaintenance = "maintenance"
+
+var (
+	ErrMeterInvalid       = errors.New("pembacaan meter tidak valid: tanggal YYYY-MM-DD dan nilai tidak boleh lebih kecil dari pembacaan sebelumnya")
+	ErrPlanInvalid        = errors.New("rencana perawatan tidak valid: nama wajib, interval_meter atau interval_days harus diisi")
+	ErrServiceInvalid     = errors.New("servis tidak valid: tanggal YYYY-MM-DD wajib, biaya tidak boleh negatif")
+	ErrServiceFinanceLink = errors.New("finance_id harus transaksi expense milik user")
+)
+
+type EquipmentMaintenanceService interface {
+	ListReadings(equipmentID, userID uint) ([]entity.EquipmentMeterReading, error)
+	AddReading(equipmentID, userID uint, r *entity.EquipmentMeterReading) error
+	ListPlans(equipmentID, userID uint) ([]entity.MaintenancePlan, error)
+	SavePlan(equipmentID, userID uint, p *entity.MaintenancePlan) error
+	GetPlan(id, equipmentID, userID uint) (*entity.MaintenancePlan, error)
+	DeletePlan(id, equipmentID, userID uint) error
+	ListRecords(equipmentID, userID uint) ([]entity.ServiceRecord, error)
+	CreateRecord(equipmentID, userID uint, rec *entity.ServiceRecord) error
+	DeleteRecord(id, equipmentID, userID uint) error
+	// DueList rencana armada yang overdue (dan due_soon jika includeDueSoon), terlambat paling parah dulu.
+	DueList(userID uint, includeDueSoon bool) ([]entity.MaintenanceDue, error)
+	// CheckOverdue dipanggil job berkala: setiap rencana yang baru overdue dicatat sekali sebagai aktivitas.
+	CheckOverdue(now time.Time) (int, error)
+}
+
+type equipmentMaintenanceService struct {
+	repo            repository.EquipmentMaintenanceRepository
+	equipmentRepo   repository.EquipmentRepository
+	financeRepo     repository.FinanceRepository
+	activityService ActivityService
+}
+
+func NewEquipmentMaintenanceService(repo repository.EquipmentMaintenanceRepository, equipmentRepo repository.EquipmentRepository, financeRepo repository.FinanceRepository, activityService ActivityService) EquipmentMaintenanceService {
+	return &equipmentMaintenanceService{repo, equipmentRepo, financeRepo, activityService}
+}
+
+func today() time.Time {
+	t, _ := time.Parse(dayLayout, time.Now().Format(dayLayout))
+	return t
+}
+
+func validDay(s string) bool {
+	_, ok := parseDay(s)
+	return ok && len(s) == len(dayLayout)
+}
+
+func (s *equipmentMaintenanceService) ListReadings(equipmentID, userID uint) ([]entity.EquipmentMeterReading, error) {
+	if _, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindReadings(equipmentID)
+}
+
+func (s *equipmentMaintenanceService) AddReading(equipmentID, userID uint, r *entity.EquipmentMeterReading) error {
+	if _, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID); err != nil {
+		return err
+	}
+	r.ID, r.UserID, r.EquipmentID = 0, userID, equipmentID
+	if r.Date == "" {
+		r.Date = today().Format(dayLayout)
+	}
+	if !validDay(r.Date) || r.Reading < 0 {
+		return ErrMeterInvalid
+	}
+	max, err := s.repo.MaxReading(equipmentID, r.Date)
+	if err != nil {
+		return err
+	}
+	if r.Reading < max {
+		return ErrMeterInvalid
+	}
+	return s.repo.CreateReading(r)
+}
+
+// evaluatePlan mengisi field jatuh tempo. Jatuh tempo hari dihitung dari servis terakhir, atau tanggal rencana dibuat.
+func evaluatePlan(p *entity.MaintenancePlan, unit string, current float64, now time.Time) {
+	p.MeterUnit, p.CurrentMeter, p.Status = unit, current, entity.MaintenanceOK
+	overdue, dueSoon := false, false
+	if p.IntervalMeter > 0 {
+		next := p.LastServiceMeter + p.IntervalMeter
+		remaining := round2(next - current)
+		p.NextDueMeter, p.RemainingMeter = &next, &remaining
+		threshold := p.DueSoonMeter
+		if threshold <= 0 {
+			threshold = p.IntervalMeter / 10
+		}
+		overdue = remaining <= 0
+		dueSoon = remaining <= threshold
+	}
+	if p.IntervalDays > 0 {
+		base, ok := time.Time{}, false
+		if p.LastServiceDate != nil {
+			base, ok = parseDay(*p.LastServiceDate)
+		}
+		if !ok {
+			base, _ = time.Parse(dayLayout, p.CreatedAt.Format(dayLayout))
+		}
+		nextDate := base.AddDate(0, 0, p.IntervalDays).Format(dayLayout)
+		due, _ := parseDay(nextDate)
+		days := daysBetween(now, due)
+		p.NextDueDate, p.RemainingDays = &nextDate, &days
+		overdue = overdue || days < 0
+		dueSoon = dueSoon || days <= 7
+	}
+	switch {
+	case overdue:
+		p.Status = entity.MaintenanceOverdue
+	case dueSoon:
+		p.Status = entity.MaintenanceDueSoon
+	}
+}
+
+// dueKey identitas siklus jatuh tempo; berubah setelah servis sehingga keterlambatan berikutnya dicatat lagi.
+func dueKey(p *entity.MaintenancePlan) string {
+	key := ""
+	if p.NextDueMeter != nil {
+		key = fmt.Sprintf("m%.1f", *p.NextDueMeter)
+	}
+	if p.NextDueDate != nil {
+		key += "d" + *p.NextDueDate
+	}
+	return key
+}
+
+func (s *equipmentMaintenanceService) ListPlans(equipmentID, userID uint) ([]entity.MaintenancePlan, error) {
+	e, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := s.repo.FindPlans(userID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.MaxReading(equipmentID, "")
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	for i := range plans {
+		evaluatePlan(&plans[i], entity.MeterUnitOf(*e), current, now)
+	}
+	return plans, nil
+}
+
+func (s *equipmentMaintenanceService) GetPlan(id, equipmentID, userID uint) (*entity.MaintenancePlan, error) {
+	p, err := s.repo.FindPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.UserID != userID || p.EquipmentID != equipmentID {
+		return nil, errors.New("rencana perawatan tidak ditemukan")
+	}
+	return p, nil
+}
+
+func (s *equipmentMaintenanceService) SavePlan(equipmentID, userID uint, p *entity.MaintenancePlan) error {
+	e, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID)
+	if err != nil {
+		return err
+	}
+	p.UserID, p.EquipmentID = userID, equipmentID
+	p.Name = strings.TrimSpace(p.Name)
+	if p.LastServiceDate != nil && *p.LastServiceDate == "" {
+		p.LastServiceDate = nil
+	}
+	if p.Name == "" || p.IntervalMeter < 0 || p.IntervalDays < 0 || p.DueSoonMeter < 0 || p.LastServiceMeter < 0 ||
+		(p.IntervalMeter == 0 && p.IntervalDays == 0) || (p.LastServiceDate != nil && !validDay(*p.LastServiceDate)) {
+		return ErrPlanInvalid
+	}
+	if err := s.repo.SavePlan(p); err != nil {
+		return err
+	}
+	current, err := s.repo.MaxReading(equipmentID, "")
+	if err != nil {
+		return err
+	}
+	evaluatePlan(p, entity.MeterUnitOf(*e), current, today())
+	return nil
+}
+
+func (s *equipmentMaintenanceService) DeletePlan(id, equipmentID, userID uint) error {
+	if _, err := s.GetPlan(id, equipmentID, userID); err != nil {
+		return err
+	}
+	return s.repo.DeletePlan(id)
+}
+
+func (s *equipmentMaintenanceService) ListRecords(equipmentID, userID uint) ([]entity.ServiceRecord, error) {
+	if _, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindRecords(userID, equipmentID)
+}
+
+// CreateRecord mencatat servis. Jika finance_id kosong dan cost > 0, dibuat expense Finance baru untuk alat ini;
+// meter kosong diisi pembacaan tertinggi sampai tanggal servis, meter yang lebih kecil dari itu ditolak (ErrMeterInvalid).
+func (s *equipmentMaintenanceService) CreateRecord(equipmentID, userID uint, rec *entity.ServiceRecord) error {
+	e, err := s.equipmentRepo.FindByIDForUser(equipmentID, userID)
+	if err != nil {
+		return err
+	}
+	rec.ID, rec.UserID, rec.EquipmentID = 0, userID, equipmentID
+	if rec.Date == "" {
+		rec.Date = today().Format(dayLayout)
+	}
+	if !validDay(rec.Date) || rec.Cost < 0 || rec.Meter < 0 {
+		return ErrServiceInvalid
+	}
+	var plan *entity.MaintenancePlan
+	if rec.PlanID != nil && *rec.PlanID > 0 {
+		if plan, err = s.GetPlan(*rec.PlanID, equipmentID, userID); err != nil {
+			return ErrServiceInvalid
+		}
+	} else {
+		rec.PlanID = nil
+	}
+	max, err := s.repo.MaxReading(equipmentID, rec.Date)
+	if err != nil {
+		return err
+	}
+	if rec.Meter == 0 {
+		rec.Meter = max
+	}
+	// Meter servis ikut dicatat sebagai pembacaan, jadi aturannya sama dengan AddReading.
+	if rec.Meter < max {
+		return ErrMeterInvalid
+	}
+	if strings.TrimSpace(rec.Description) == "" && plan != nil {
+		rec.Description = plan.Name
+	}
+
+	var expense *entity.Finance
+	if rec.FinanceID != nil && *rec.FinanceID > 0 {
+		f, err := s.financeRepo.FindByID(*rec.FinanceID)
+		if err != nil || f.UserID != userID || f.Type != entity.Expense {
+			return ErrServiceFinanceLink
+		}
+	} else if rec.Cost > 0 {
+		rec.FinanceID = nil
+		expense = &entity.Finance{
+			UserID:       userID,
+			Tanggal:      rec.Date,
+			Unit:         1,
+			Jumlah:       rec.Cost,
+			HargaPerUnit: rec.Cost,
+			Keterangan:   fmt.Sprintf("Servis %s: %s", e.Name, rec.Description),
+			Type:         entity.Expense,
+			Category:     entity.CategoryJasa,
+			Status:       "Paid",
+			Source:       SourceMaintenance,
+			EquipmentID:  &e.ID,
+			VendorName:   rec.Mechanic,
+		}
+	} else {
+		rec.FinanceID = nil
+	}
+	return s.repo.CreateRecord(rec, expense)
+}
+
+// DeleteRecord menghapus servis beserta expense yang dibuat otomatis (Source maintenance); expense yang ditautkan manual dibiarkan.
+func (s *equipmentMaintenanceService) DeleteRecord(id, equipmentID, userID uint) error {
+	rec, err := s.repo.FindRecordByID(id)
+	if err != nil {
+		return err
+	}
+	if rec.UserID != userID || rec.EquipmentID != equipmentID {
+		return errors.New("riwayat servis tidak ditemukan")
+	}
+	deleteExpense := false
+	if rec.FinanceID != nil {
+		if f, err := s.financeRepo.FindByID(*rec.FinanceID); err == nil && f.Source == SourceMaintenance {
+			deleteExpense = true
+		}
+	}
+	return s.repo.DeleteRecord(rec, deleteExpense)
+}
+
+// evaluateAll mengevaluasi rencana aktif terhadap meter terbaru dan data alat.
+func (s *equipmentMaintenanceService) evaluateAll(plans []entity.MaintenancePlan, now time.Time) ([]entity.MaintenanceDue, error) {
+	ids := []uint{}
+	equipment := map[uint]*entity.Equipment{}
+	for _, p := range plans {
+		if _, ok := equipment[p.EquipmentID]; !ok {
+			e, err := s.equipmentRepo.FindByID(p.EquipmentID)
+			if err != nil {
+				e = nil // alat sudah dihapus: rencana diabaikan
+			}
+			equipment[p.EquipmentID] = e
+			ids = append(ids, p.EquipmentID)
+		}
+	}
+	meters, err := s.repo.LatestMeters(ids)
+	if err != nil {
+		return nil, err
+	}
+	out := []entity.MaintenanceDue{}
+	for _, p := range plans {
+		e := equipment[p.EquipmentID]
+		if e == nil || !p.Active {
+			continue
+		}
+		evaluatePlan(&p, entity.MeterUnitOf(*e), meters[p.EquipmentID], now)
+		out = append(out, entity.MaintenanceDue{MaintenancePlan: p, EquipmentName: e.Name, LicensePlate: e.LicensePlate})
+	}
+	return out, nil
+}
+
+func (s *equipmentMaintenanceService) DueList(userID uint, includeDueSoon bool) ([]entity.MaintenanceDue, error) {
+	plans, err := s.repo.FindPlans(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.evaluateAll(plans, today())
+	if err != nil {
+		return nil, err
+	}
+	out := []entity.MaintenanceDue{}
+	for _, d := range all {
+		if d.Status == entity.MaintenanceOverdue || (includeDueSoon && d.Status == entity.MaintenanceDueSoon) {
+			out = append(out, d)
+		}
+	}
+	// Overdue dulu, lalu sisa meter paling kecil.
+	rank := func(d entity.MaintenanceDue) float64 {
+		r := 0.0
+		if d.RemainingMeter != nil {
+			r = *d.RemainingMeter
+		} else if d.RemainingDays != nil {
+			r = float64(*d.RemainingDays)
+		}
+		if d.Status == entity.MaintenanceOverdue {
+			r -= 1e12
+		}
+		return r
+	}
+	sort.SliceStable(out, func(i, j int) bool { return rank(out[i]) < rank(out[j]) })
+	return out, nil
+}
+
+func (s *equipmentMaintenanceService) CheckOverdue(now time.Time) (int, error) {
+	plans, err := s.repo.FindActivePlans()
+	if err != nil {
+		return 0, err
+	}
+	day, _ := time.Parse(dayLayout, now.Format(dayLayout))
+	all, err := s.evaluateAll(plans, day)
+	if err != nil {
+		return 0, err
+	}
+	logged := 0
+	for _, d := range all {
+		key := dueKey(&d.MaintenancePlan)
+		if d.Status != entity.MaintenanceOverdue || d.NotifiedDue == key {
+			continue
+		}
+		unit := strings.ToUpper(d.MeterUnit)
+		var detail []string
+		if d.NextDueMeter != nil {
+			detail = append(detail, fmt.Sprintf("jatuh tempo %.0f %s, meter sekarang %.0f %s", *d.NextDueMeter, unit, d.CurrentMeter, unit))
+		}
+		if d.NextDueDate != nil {
+			detail = append(detail, "jatuh tempo tanggal "+*d.NextDueDate)
+		}
+		title := fmt.Sprintf("Servis terlambat: %s — %s", d.EquipmentName, d.Name)
+		if err := s.activityService.LogActivity(d.UserID, entity.ActivityMaintenance, title, strings.Join(detail, "; ")); err != nil {
+			return logged, err
+		}
+		if err := s.repo.SetPlanNotified(d.ID, key); err != nil {
+			return logged, err
+		}
+		logged++
+	}
+	return logged, nil
+}
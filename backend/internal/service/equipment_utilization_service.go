@@ -0,0 +1,358 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/gemini"
+	"dashboardadminimb/internal/repository"
+)
+
+// Urutan ranking armada.
+const (
+	UtilizationSortProfit         = "profit"
+	UtilizationSortUtilization    = "utilization"
+	UtilizationSortRevenuePerHour = "revenue_per_hour"
+	UtilizationSortPayback        = "payback"
+)
+
+var ErrUtilizationQuery = errors.New("periode tidak valid (from <= to, maks 366 hari, format YYYY-MM-DD), hours_per_day 1-24, sort profit/utilization/revenue_per_hour/payback")
+
+// MaxUtilizationDays panjang periode analisis maksimum (inklusif); perhitungan per hari, jadi periode dibatasi.
+const MaxUtilizationDays = 366
+
+// ValidPeriod from <= to dan panjang periode tidak melebihi MaxUtilizationDays.
+func (q UtilizationQuery) ValidPeriod() bool {
+	return !q.To.Before(q.From) && q.To.Sub(q.From) < MaxUtilizationDays*24*time.Hour
+}
+
+// UtilizationQuery periode analisis. HoursPerDay dipakai mengonversi baris invoice berbasis hari ke jam.
+type UtilizationQuery struct {
+	From        time.Time
+	To          time.Time
+	HoursPerDay float64
+	SortBy      string
+}
+
+type EquipmentUtilizationService interface {
+	Ranking(userID uint, q UtilizationQuery) ([]entity.EquipmentUtilization, error)
+	GetByEquipment(id, userID uint, q UtilizationQuery) (*entity.EquipmentUtilization, error)
+}
+
+type equipmentUtilizationService struct {
+	equipmentRepo  repository.EquipmentRepository
+	assignmentRepo repository.EquipmentAssignmentRepository
+	invoiceRepo    repository.InvoiceRepository
+	financeRepo    repository.FinanceRepository
+	dailyRepo      repository.DailyReportRepository
+}
+
+func NewEquipmentUtilizationService(equipmentRepo repository.EquipmentRepository, assignmentRepo repository.EquipmentAssignmentRepository, invoiceRepo repository.InvoiceRepository, financeRepo repository.FinanceRepository, dailyRepo repository.DailyReportRepository) EquipmentUtilizationService {
+	return &equipmentUtilizationService{equipmentRepo, assignmentRepo, invoiceRepo, financeRepo, dailyRepo}
+}
+
+func (q *UtilizationQuery) normalize() error {
+	if q.HoursPerDay == 0 {
+		q.HoursPerDay = 8
+	}
+	if q.SortBy == "" {
+		q.SortBy = UtilizationSortProfit
+	}
+	switch q.SortBy {
+	case UtilizationSortProfit, UtilizationSortUtilization, UtilizationSortRevenuePerHour, UtilizationSortPayback:
+	default:
+		return ErrUtilizationQuery
+	}
+	if !q.ValidPeriod() || q.HoursPerDay < 1 || q.HoursPerDay > 24 {
+		return ErrUtilizationQuery
+	}
+	return nil
+}
+
+func (s *equipmentUtilizationService) GetByEquipment(id, userID uint, q UtilizationQuery) (*entity.EquipmentUtilization, error) {
+	if err := q.normalize(); err != nil {
+		return nil, err
+	}
+	e, err := s.equipmentRepo.FindByIDForUser(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.compute(userID, []entity.Equipment{*e}, q)
+	if err != nil {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// Ranking seluruh armada, diurutkan menurun menurut SortBy (payback: persentase balik modal).
+func (s *equipmentUtilizationService) Ranking(userID uint, q UtilizationQuery) ([]entity.EquipmentUtilization, error) {
+	if err := q.normalize(); err != nil {
+		return nil, err
+	}
+	fleet, err := s.equipmentRepo.FindAll(userID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.compute(userID, fleet, q)
+	if err != nil {
+		return nil, err
+	}
+	key := func(u entity.EquipmentUtilization) float64 {
+		switch q.SortBy {
+		case UtilizationSortUtilization:
+			return u.UtilizationPercent
+		case UtilizationSortRevenuePerHour:
+			return u.RevenuePerHour
+		case UtilizationSortPayback:
+			return u.PaybackPercent
+		}
+		return u.Profit
+	}
+	sort.SliceStable(list, func(i, j int) bool { return key(list[i]) > key(list[j]) })
+	for i := range list {
+		list[i].Rank = i + 1
+	}
+	return list, nil
+}
+
+// unitUsage akumulasi per unit sebelum dibentuk menjadi EquipmentUtilization.
+type unitUsage struct {
+	assigned, invoiced, reported map[string]bool
+	hours, billed, income, cost  float64
+	lifeBilled, lifeIncome       float64
+	lifeCost                     float64
+}
+
+func (s *equipmentUtilizationService) compute(userID uint, fleet []entity.Equipment, q UtilizationQuery) ([]entity.EquipmentUtilization, error) {
+	assignments, err := s.assignmentRepo.FindAll(userID, repository.EquipmentAssignmentFilter{})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.invoiceRepo.FindEquipmentRows(userID)
+	if err != nil {
+		return nil, err
+	}
+	finances, err := s.financeRepo.FindByEquipment(userID)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := []uint{}
+	seen := map[uint]bool{}
+	for _, a := range assignments {
+		if !seen[a.ProjectID] {
+			seen[a.ProjectID] = true
+			projectIDs = append(projectIDs, a.ProjectID)
+		}
+	}
+	reports, err := s.dailyRepo.FindByProjectIDs(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	// Laporan harian per proyek+tanggal: jumlah alat per nama/jenis.
+	reportEquipment := map[uint]map[string]map[string]float64{}
+	for _, r := range reports {
+		d, ok := parseDay(r.Date)
+		if !ok || d.Before(q.From) || d.After(q.To) {
+			continue
+		}
+		counts := map[string]float64{}
+		addCounts(counts, r.Equipment)
+		if reportEquipment[r.ProjectID] == nil {
+			reportEquipment[r.ProjectID] = map[string]map[string]float64{}
+		}
+		reportEquipment[r.ProjectID][d.Format(dayLayout)] = counts
+	}
+
+	inPeriod := func(d time.Time) bool { return !d.Before(q.From) && !d.After(q.To) }
+	out := make([]entity.EquipmentUtilization, 0, len(fleet))
+	for _, e := range fleet {
+		u := unitUsage{assigned: map[string]bool{}, invoiced: map[string]bool{}, reported: map[string]bool{}}
+
+		for _, a := range assignments {
+			if a.EquipmentID != e.ID {
+				continue
+			}
+			from, ok := parseDay(a.FromDate)
+			if !ok {
+				continue
+			}
+			to := q.To
+			if a.ToDate != nil {
+				if t, ok := parseDay(*a.ToDate); ok && t.Before(to) {
+					to = t
+				}
+			}
+			if from.Before(q.From) {
+				from = q.From
+			}
+			for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+				day := d.Format(dayLayout)
+				u.assigned[day] = true
+				for k, n := range reportEquipment[a.ProjectID][day] {
+					if n > 0 && reportsUnit(k, e) {
+						u.reported[day] = true
+						break
+					}
+				}
+			}
+		}
+
+		for _, r := range rows {
+			if !invoiceRowIsUnit(r.ItemName, e) {
+				continue
+			}
+			u.lifeBilled += r.Total
+			d, hasRowDate := parseLooseDay(r.RowDate)
+			if !hasRowDate {
+				var ok bool
+				if d, ok = parseLooseDay(r.InvoiceDate); !ok {
+					continue
+				}
+			}
+			if !inPeriod(d) {
+				continue
+			}
+			u.billed += r.Total
+			days := r.Days
+			if days == 0 {
+				days = r.Quantity
+			}
+			if strings.EqualFold(strings.TrimSpace(r.QuantityUnit), "jam") {
+				u.hours += r.Quantity
+			} else {
+				u.hours += days * q.HoursPerDay
+			}
+			if hasRowDate && days > 0 {
+				u.invoiced[d.Format(dayLayout)] = true
+			}
+		}
+
+		for _, f := range finances {
+			if f.EquipmentID == nil || *f.EquipmentID != e.ID {
+				continue
+			}
+			d, ok := parseDay(f.Tanggal)
+			if f.Type == entity.Income {
+				u.lifeIncome += f.Jumlah
+				if ok && inPeriod(d) {
+					u.income += f.Jumlah
+				}
+			} else {
+				u.lifeCost += f.Jumlah
+				if ok && inPeriod(d) {
+					u.cost += f.Jumlah
+				}
+			}
+		}
+		out = append(out, u.result(e, q))
+	}
+	return out, nil
+}
+
+func (u unitUsage) result(e entity.Equipment, q UtilizationQuery) entity.EquipmentUtilization {
+	res := entity.EquipmentUtilization{
+		EquipmentID:   e.ID,
+		EquipmentName: e.Name,
+		EquipmentType: e.Type,
+		LicensePlate:  e.LicensePlate,
+		From:          q.From.Format(dayLayout),
+		To:            q.To.Format(dayLayout),
+		PeriodDays:    daysBetween(q.From, q.To) + 1,
+		AssignedDays:  len(u.assigned),
+		InvoicedDays:  len(u.invoiced),
+		ReportedDays:  len(u.reported),
+		Hours:         round2(u.hours),
+		BilledRevenue: round2(u.billed),
+		FinanceIncome: round2(u.income),
+		Cost:          round2(u.cost),
+		PurchasePrice: e.PurchasePrice,
+		LifetimeCost:  round2(u.lifeCost),
+	}
+	working := map[string]bool{}
+	for d := range u.invoiced {
+		working[d] = true
+	}
+	for d := range u.reported {
+		working[d] = true
+	}
+	res.WorkingDays = len(working)
+	res.IdleDays = res.PeriodDays - res.WorkingDays
+	res.UtilizationPercent = round2(float64(res.WorkingDays) / float64(res.PeriodDays) * 100)
+
+	// Pemasukan Finance alat biasanya pelunasan invoice yang sama; dipakai hanya jika belum ada invoice.
+	res.Revenue = res.BilledRevenue
+	if res.Revenue == 0 {
+		res.Revenue = res.FinanceIncome
+	}
+	res.LifetimeRevenue = round2(u.lifeBilled)
+	if res.LifetimeRevenue == 0 {
+		res.LifetimeRevenue = round2(u.lifeIncome)
+	}
+	res.Profit = round2(res.Revenue - res.Cost)
+	res.LifetimeProfit = round2(res.LifetimeRevenue - res.LifetimeCost)
+	if res.Hours > 0 {
+		res.RevenuePerHour = round2(res.Revenue / res.Hours)
+		res.CostPerHour = round2(res.Cost / res.Hours)
+	}
+	if e.PurchasePrice > 0 {
+		res.PaybackPercent = round2(res.LifetimeProfit / e.PurchasePrice * 100)
+		remaining := e.PurchasePrice - res.LifetimeProfit
+		monthlyProfit := res.Profit / (float64(res.PeriodDays) / 30.4375)
+		switch {
+		case remaining <= 0:
+			months := 0.0
+			res.PaybackMonths = &months
+		case monthlyProfit > 0:
+			months := round2(remaining / monthlyProfit)
+			res.PaybackMonths = &months
+		}
+	}
+	return res
+}
+
+// invoiceRowIsUnit baris invoice memakai nama alat (atau plat nomor) sebagai ItemName.
+func invoiceRowIsUnit(itemName string, e entity.Equipment) bool {
+	n := strings.ToLower(strings.TrimSpace(itemName))
+	return n != "" && (n == strings.ToLower(strings.TrimSpace(e.Name)) ||
+		(e.LicensePlate != "" && n == strings.ToLower(strings.TrimSpace(e.LicensePlate))))
+}
+
+// reportsUnit key jumlah alat di laporan harian (nama bebas, mis. "Excavator") cocok dengan unit:
+// sama dengan nama/plat, atau merupakan bagian dari nama alat.
+func reportsUnit(key string, e entity.Equipment) bool {
+	k := strings.ToLower(strings.TrimSpace(key))
+	name := strings.ToLower(e.Name)
+	if k == "" {
+		return false
+	}
+	return k == strings.TrimSpace(name) || (e.LicensePlate != "" && k == strings.ToLower(strings.TrimSpace(e.LicensePlate))) ||
+		(len(k) >= 3 && strings.Contains(name, k))
+}
+
+var indonesianMonths = map[string]time.Month{
+	"januari": time.January, "februari": time.February, "maret": time.March, "april": time.April,
+	"mei": time.May, "juni": time.June, "juli": time.July, "agustus": time.August,
+	"september": time.September, "oktober": time.October, "november": time.November, "desember": time.December,
+}
+
+// parseLooseDay tanggal baris invoice: format timesheet (lihat gemini.NormalizeRowDate) atau "31 Januari 2026".
+func parseLooseDay(s string) (time.Time, bool) {
+	if d, ok := parseDay(gemini.NormalizeRowDate(s)); ok {
+		return d, true
+	}
+	parts := strings.Fields(s)
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	day, err1 := strconv.Atoi(parts[0])
+	year, err2 := strconv.Atoi(parts[2])
+	month, ok := indonesianMonths[strings.ToLower(parts[1])]
+	if err1 != nil || err2 != nil || !ok {
+		return time.Time{}, false
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), true
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrFuelInvalid = errors.New("log BBM tidak valid: tanggal YYYY-MM-DD, liter > 0, harga dan meter tidak boleh negatif")
+	ErrFuelLink    = errors.New("proyek, operator atau finance_id (expense) tidak ditemukan untuk user ini")
+)
+
+// Anomali konsumsi dibandingkan terhadap rata-rata historis unit (tanpa interval itu sendiri)
+// hanya jika ada cukup interval pembanding.
+const (
+	DefaultFuelAnomalyPercent = 30
+	minFuelIntervals          = 3
+)
+
+type FuelService interface {
+	// List log BBM beranotasi (selisih meter, liter/jam, anomali); anotasi memakai seluruh riwayat unit.
+	List(userID uint, filter repository.FuelLogFilter, anomalyPercent float64) ([]entity.FuelLog, error)
+	Get(id, userID uint) (*entity.FuelLog, error)
+	Save(userID uint, log *entity.FuelLog) error
+	Delete(id, userID uint) error
+	// Consumption ringkasan per unit dalam periode + rekonsiliasi BBM ditagih (invoice) vs dikeluarkan (log).
+	Consumption(userID uint, filter repository.FuelLogFilter, anomalyPercent float64) ([]entity.FuelConsumption, error)
+}
+
+type fuelService struct {
+	repo            repository.FuelLogRepository
+	equipmentRepo   repository.EquipmentRepository
+	memberRepo      repository.MemberRepository
+	financeRepo     repository.FinanceRepository
+	invoiceRepo     repository.InvoiceRepository
+	maintenanceRepo repository.EquipmentMaintenanceRepository
+	projectService  ProjectService
+}
+
+func NewFuelService(repo repository.FuelLogRepository, equipmentRepo repository.EquipmentRepository, memberRepo repository.MemberRepository, financeRepo repository.FinanceRepository, invoiceRepo repository.InvoiceRepository, maintenanceRepo repository.EquipmentMaintenanceRepository, projectService ProjectService) FuelService {
+	return &fuelService{repo, equipmentRepo, memberRepo, financeRepo, invoiceRepo, maintenanceRepo, projectService}
+}
+
+// annotateFuelLogs mengisi MeterDelta, LitersPerHour dan Anomaly untuk log satu unit yang sudah urut kronologis.
+// Konsumsi pengisian ke-i = liter pengisian ke-i / (meter i - meter sebelumnya).
+func annotateFuelLogs(logs []entity.FuelLog, anomalyPercent float64) (totalLiters, totalHours float64) {
+	prev := -1
+	valid := map[int]bool{}
+	for i := range logs {
+		if logs[i].MeterReading <= 0 {
+			continue
+		}
+		if prev >= 0 {
+			delta := round2(logs[i].MeterReading - logs[prev].MeterReading)
+			logs[i].MeterDelta = &delta
+			if delta > 0 {
+				lph := round2(logs[i].Liters / delta)
+				logs[i].LitersPerHour = &lph
+				valid[i] = true
+				totalLiters += logs[i].Liters
+				totalHours += delta
+			} else {
+				logs[i].Anomaly, logs[i].AnomalyReason = true, "meter tidak bertambah sejak pengisian sebelumnya"
+			}
+		}
+		prev = i
+	}
+	if len(valid)-1 < minFuelIntervals {
+		return totalLiters, totalHours
+	}
+	for i := range valid {
+		otherLiters, otherHours := totalLiters-logs[i].Liters, totalHours-*logs[i].MeterDelta
+		if otherHours <= 0 {
+			continue
+		}
+		avg := otherLiters / otherHours
+		diff := (*logs[i].LitersPerHour - avg) / avg * 100
+		if math.Abs(diff) > anomalyPercent {
+			direction := "di atas"
+			if diff < 0 {
+				direction = "di bawah"
+			}
+			logs[i].Anomaly = true
+			logs[i].AnomalyReason = fmt.Sprintf("%.0f%% %s rata-rata unit (%.2f L per jam/km)", math.Abs(diff), direction, avg)
+		}
+	}
+	return totalLiters, totalHours
+}
+
+// history log seluruh riwayat per unit (sudah dianotasi), untuk unit yang relevan dengan filter.
+func (s *fuelService) history(userID uint, filter repository.FuelLogFilter, anomalyPercent float64) (map[uint][]entity.FuelLog, map[uint][2]float64, error) {
+	if anomalyPercent <= 0 {
+		anomalyPercent = DefaultFuelAnomalyPercent
+	}
+	all, err := s.repo.FindAll(userID, repository.FuelLogFilter{EquipmentID: filter.EquipmentID})
+	if err != nil {
+		return nil, nil, err
+	}
+	byUnit := map[uint][]entity.FuelLog{}
+	for _, l := range all {
+		byUnit[l.EquipmentID] = append(byUnit[l.EquipmentID], l)
+	}
+	totals := map[uint][2]float64{}
+	for id, logs := range byUnit {
+		liters, hours := annotateFuelLogs(logs, anomalyPercent)
+		totals[id] = [2]float64{liters, hours}
+	}
+	return byUnit, totals, nil
+}
+
+func inFuelFilter(l entity.FuelLog, f repository.FuelLogFilter) bool {
+	if f.ProjectID > 0 && (l.ProjectID == nil || *l.ProjectID != f.ProjectID) {
+		return false
+	}
+	return (f.From == "" || l.Date >= f.From) && (f.To == "" || l.Date <= f.To)
+}
+
+func (s *fuelService) List(userID uint, filter repository.FuelLogFilter, anomalyPercent float64) ([]entity.FuelLog, error) {
+	byUnit, _, err := s.history(userID, filter, anomalyPercent)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, m := range members {
+		names[m.ID] = m.FullName
+	}
+	out := []entity.FuelLog{}
+	for _, logs := range byUnit {
+		for _, l := range logs {
+			if !inFuelFilter(l, filter) {
+				continue
+			}
+			if l.OperatorID != nil {
+				l.OperatorName = names[*l.OperatorID]
+			}
+			out = append(out, l)
+		}
+	}
+	sortFuelLogs(out)
+	return out, nil
+}
+
+// sortFuelLogs terbaru dulu.
+func sortFuelLogs(logs []entity.FuelLog) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].Date != logs[j].Date {
+			return logs[i].Date > logs[j].Date
+		}
+		return logs[i].ID > logs[j].ID
+	})
+}
+
+func (s *fuelService) Get(id, userID uint) (*entity.FuelLog, error) {
+	l, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if l.UserID != userID {
+		return nil, errors.New("log BBM tidak ditemukan")
+	}
+	return l, nil
+}
+
+// Save membuat/memperbarui log. Total kosong = liter × harga. Pembacaan meter log baru juga dicatat
+// sebagai hour meter alat bila tidak lebih kecil dari pembacaan terakhir.
+func (s *fuelService) Save(userID uint, l *entity.FuelLog) error {
+	l.UserID = userID
+	if !validDay(l.Date) || l.Liters <= 0 || l.PricePerLiter < 0 || l.MeterReading < 0 || l.Total < 0 {
+		return ErrFuelInvalid
+	}
+	if _, err := s.equipmentRepo.FindByIDForUser(l.EquipmentID, userID); err != nil {
+		return ErrFuelInvalid
+	}
+	if l.ProjectID != nil && *l.ProjectID == 0 {
+		l.ProjectID = nil
+	}
+	if l.ProjectID != nil {
+		if _, err := s.projectService.GetProjectByID(*l.ProjectID, userID); err != nil {
+			return ErrFuelLink
+		}
+	}
+	if l.OperatorID != nil && strings.TrimSpace(*l.OperatorID) == "" {
+		l.OperatorID = nil
+	}
+	if l.OperatorID != nil {
+		if m, err := s.memberRepo.FindByID(*l.OperatorID); err != nil || m.UserID != userID {
+			return ErrFuelLink
+		}
+	}
+	if l.FinanceID != nil && *l.FinanceID == 0 {
+		l.FinanceID = nil
+	}
+	if l.FinanceID != nil {
+		if f, err := s.financeRepo.FindByID(*l.FinanceID); err != nil || f.UserID != userID || f.Type != entity.Expense {
+			return ErrFuelLink
+		}
+	}
+	if l.Total == 0 {
+		l.Total = round2(l.Liters * l.PricePerLiter)
+	}
+	isNew := l.ID == 0
+	if err := s.repo.Save(l); err != nil {
+		return err
+	}
+	if isNew && l.MeterReading > 0 {
+		if max, err := s.maintenanceRepo.MaxReading(l.EquipmentID, l.Date); err == nil && l.MeterReading >= max {
+			_ = s.maintenanceRepo.CreateReading(&entity.EquipmentMeterReading{
+				UserID: userID, EquipmentID: l.EquipmentID, Date: l.Date, Reading: l.MeterReading, Notes: "Isi BBM",
+			})
+		}
+	}
+	return nil
+}
+
+func (s *fuelService) Delete(id, userID uint) error {
+	if _, err := s.Get(id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *fuelService) Consumption(userID uint, filter repository.FuelLogFilter, anomalyPercent float64) ([]entity.FuelConsumption, error) {
+	byUnit, totals, err := s.history(userID, filter, anomalyPercent)
+	if err != nil {
+		return nil, err
+	}
+	fleet, err := s.equipmentRepo.FindAll(userID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.invoiceRepo.FindEquipmentRows(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := []entity.FuelConsumption{}
+	for _, e := range fleet {
+		if filter.EquipmentID > 0 && e.ID != filter.EquipmentID {
+			continue
+		}
+		c := entity.FuelConsumption{EquipmentID: e.ID, EquipmentName: e.Name, LicensePlate: e.LicensePlate,
+			MeterUnit: entity.MeterUnitOf(e), From: filter.From, To: filter.To, Logs: []entity.FuelLog{}}
+		if t := totals[e.ID]; t[1] > 0 {
+			c.HistLitersPerHour = round2(t[0] / t[1])
+		}
+		for _, l := range byUnit[e.ID] {
+			if !inFuelFilter(l, filter) {
+				continue
+			}
+			c.IssuedLiters += l.Liters
+			c.IssuedCost += l.Total
+			if l.LitersPerHour != nil {
+				c.MeterHours += *l.MeterDelta
+			}
+			if l.Anomaly {
+				c.Anomalies++
+			}
+			c.Logs = append(c.Logs, l)
+		}
+		var intervalLiters float64
+		for _, l := range c.Logs {
+			if l.LitersPerHour != nil {
+				intervalLiters += l.Liters
+			}
+		}
+		if c.MeterHours > 0 {
+			c.AvgLitersPerHour = round2(intervalLiters / c.MeterHours)
+		}
+		// BBM ditagih tidak bisa difilter per proyek (invoice tidak menyimpan proyek).
+		if filter.ProjectID == 0 {
+			for _, r := range rows {
+				if r.BbmQuantity == 0 || !invoiceRowIsUnit(r.ItemName, e) {
+					continue
+				}
+				d, ok := parseLooseDay(r.RowDate)
+				if !ok {
+					if d, ok = parseLooseDay(r.InvoiceDate); !ok {
+						continue
+					}
+				}
+				day := d.Format(dayLayout)
+				if (filter.From != "" && day < filter.From) || (filter.To != "" && day > filter.To) {
+					continue
+				}
+				c.BilledLiters += r.BbmQuantity
+				c.BilledAmount += r.BbmQuantity * r.BbmUnitPrice
+			}
+		}
+		if c.IssuedLiters == 0 && c.BilledLiters == 0 {
+			continue
+		}
+		c.IssuedLiters, c.IssuedCost = round2(c.IssuedLiters), round2(c.IssuedCost)
+		c.BilledLiters, c.BilledAmount = round2(c.BilledLiters), round2(c.BilledAmount)
+		c.MeterHours = round2(c.MeterHours)
+		c.LitersVariance = round2(c.BilledLiters - c.IssuedLiters)
+		c.AmountVariance = round2(c.BilledAmount - c.IssuedCost)
+		out = append(out, c)
+	}
+	return out, nil
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrInventorySchema    = errors.New("definisi kolom inventori tidak valid")
+	ErrInventoryValue     = errors.New("nilai inventori tidak valid")
+	ErrInventoryDuplicate = errors.New("nilai kolom unik sudah dipakai item lain")
+)
+
+// legacyHeaderTypes tipe kolom dari versi awal (frontend lama masih mengirimnya).
+var legacyHeaderTypes = map[string]string{"": entity.HeaderText, "string": entity.HeaderText, "integer": entity.HeaderNumber, "float": entity.HeaderNumber}
+
+// headerInput membedakan "required" yang tidak dikirim (ikut optional lama) dari required=false.
+type headerInput struct {
+	entity.InventoryHeader
+	Required *bool `json:"required"`
+}
+
+// normalizeHeaders memeriksa dan menormalkan definisi kolom: ID unik, tipe dikenal, opsi enum dan default valid.
+func normalizeHeaders(raw datatypes.JSON) ([]entity.InventoryHeader, error) {
+	var input []headerInput
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &input); err != nil {
+			return nil, fmt.Errorf("%w: headers harus array", ErrInventorySchema)
+		}
+	}
+	headers := make([]entity.InventoryHeader, 0, len(input))
+	seen := map[string]bool{}
+	for _, in := range input {
+		h := in.InventoryHeader
+		h.ID, h.Name = strings.TrimSpace(h.ID), strings.TrimSpace(h.Name)
+		if h.ID == "" || seen[h.ID] {
+			return nil, fmt.Errorf("%w: id kolom kosong atau ganda (%q)", ErrInventorySchema, h.ID)
+		}
+		seen[h.ID] = true
+		if h.Name == "" {
+			h.Name = h.ID
+		}
+		if t, ok := legacyHeaderTypes[h.Type]; ok {
+			h.Integer = h.Integer || h.Type == "integer"
+			h.Type = t
+		}
+		switch h.Type {
+		case entity.HeaderText, entity.HeaderNumber, entity.HeaderDate, entity.HeaderEnum, entity.HeaderCurrency, entity.HeaderImage:
+		default:
+			return nil, fmt.Errorf("%w: tipe kolom %s tidak dikenal (%s)", ErrInventorySchema, h.Name, h.Type)
+		}
+		h.Required = !h.Optional
+		if in.Required != nil {
+			h.Required = *in.Required
+		}
+		h.Optional = !h.Required
+		if h.Type != entity.HeaderNumber {
+			h.Integer = false
+		}
+		if h.Type == entity.HeaderImage {
+			// Gambar disimpan di InventoryData.Images, bukan di Values.
+			h.Required, h.Optional, h.Unique, h.Default = false, true, false, nil
+		}
+		options := h.Options
+		h.Options = nil
+		if h.Type == entity.HeaderEnum {
+			picked := map[string]bool{}
+			for _, o := range options {
+				o = strings.TrimSpace(o)
+				if o != "" && !picked[strings.ToLower(o)] {
+					picked[strings.ToLower(o)] = true
+					h.Options = append(h.Options, o)
+				}
+			}
+			if len(h.Options) == 0 {
+				return nil, fmt.Errorf("%w: kolom enum %s butuh options", ErrInventorySchema, h.Name)
+			}
+		}
+		if h.Default != nil {
+			v, ok := coerceValue(h, h.Default)
+			if !ok {
+				return nil, fmt.Errorf("%w: default kolom %s tidak sesuai tipe %s", ErrInventorySchema, h.Name, h.Type)
+			}
+			h.Default = v
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+// coerceValue mengubah nilai ke bentuk baku tipe kolom; nil untuk kosong, false jika tidak bisa diubah.
+func coerceValue(h entity.InventoryHeader, v interface{}) (interface{}, bool) {
+	if s, ok := v.(string); ok {
+		v = strings.TrimSpace(s)
+		if v == "" {
+			return nil, true
+		}
+	}
+	if v == nil {
+		return nil, true
+	}
+	switch h.Type {
+	case entity.HeaderText:
+		switch x := v.(type) {
+		case string:
+			return x, true
+		case float64:
+			return strconv.FormatFloat(x, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(x), true
+		}
+	case entity.HeaderNumber, entity.HeaderCurrency:
+		var n float64
+		switch x := v.(type) {
+		case float64:
+			n = x
+		case string:
+			parsed, err := strconv.ParseFloat(x, 64)
+			if err != nil {
+				return nil, false
+			}
+			n = parsed
+		default:
+			return nil, false
+		}
+		if h.Type == entity.HeaderCurrency {
+			return round2(n), true
+		}
+		if h.Integer && n != float64(int64(n)) {
+			return nil, false
+		}
+		return n, true
+	case entity.HeaderDate:
+		if s, ok := v.(string); ok && validDay(s) {
+			return s, true
+		}
+		if s, ok := v.(string); ok && len(s) > 10 && validDay(s[:10]) {
+			return s[:10], true
+		}
+	case entity.HeaderEnum:
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		for _, o := range h.Options {
+			if strings.EqualFold(o, s) {
+				return o, true
+			}
+		}
+	case entity.HeaderImage:
+		return v, true
+	}
+	return nil, false
+}
+
+// validateValues menerapkan skema ke values: coerce per tipe, isi default, cek wajib. Kunci di luar skema dibuang.
+func validateValues(headers []entity.InventoryHeader, values map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(headers))
+	for _, h := range headers {
+		v, ok := coerceValue(h, values[h.ID])
+		if !ok {
+			return nil, fmt.Errorf("%w: kolom %s harus bertipe %s", ErrInventoryValue, h.Name, headerTypeLabel(h))
+		}
+		if v == nil {
+			v = h.Default
+		}
+		if v == nil {
+			if h.Required {
+				return nil, fmt.Errorf("%w: kolom %s wajib diisi", ErrInventoryValue, h.Name)
+			}
+			continue
+		}
+		out[h.ID] = v
+	}
+	return out, nil
+}
+
+func headerTypeLabel(h entity.InventoryHeader) string {
+	switch {
+	case h.Type == entity.HeaderEnum:
+		return "enum (" + strings.Join(h.Options, "/") + ")"
+	case h.Type == entity.HeaderDate:
+		return "date (YYYY-MM-DD)"
+	case h.Integer:
+		return "number bulat"
+	}
+	return h.Type
+}
+
+// uniqueKey bentuk pembanding kolom unik (teks tanpa beda huruf besar/kecil).
+func uniqueKey(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strings.ToLower(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// uniqueIndex nilai kolom unik per header: uniqueKey -> ID item pemiliknya. Dibangun sekali per pemeriksaan
+// sehingga values item lain tidak di-decode ulang untuk setiap kolom/baris.
+type uniqueIndex map[string]map[string]string
+
+func newUniqueIndex(headers []entity.InventoryHeader) uniqueIndex {
+	idx := uniqueIndex{}
+	for _, h := range headers {
+		if h.Unique {
+			idx[h.ID] = map[string]string{}
+		}
+	}
+	return idx
+}
+
+// check ErrInventoryDuplicate jika nilai kolom unik values sudah dipakai item lain (selain selfID).
+func (idx uniqueIndex) check(headers []entity.InventoryHeader, values map[string]interface{}, selfID string) error {
+	for _, h := range headers {
+		seen, unique := idx[h.ID]
+		v, ok := values[h.ID]
+		if !unique || !ok {
+			continue
+		}
+		if owner, dup := seen[uniqueKey(v)]; dup && owner != selfID {
+			return fmt.Errorf("%w: %s = %v (item %s)", ErrInventoryDuplicate, h.Name, v, owner)
+		}
+	}
+	return nil
+}
+
+// add mencatat nilai kolom unik item; nilai yang sudah tercatat tetap milik item pertama.
+func (idx uniqueIndex) add(values map[string]interface{}, id string) {
+	for headerID, seen := range idx {
+		v, ok := values[headerID]
+		if !ok {
+			continue
+		}
+		if _, dup := seen[uniqueKey(v)]; !dup {
+			seen[uniqueKey(v)] = id
+		}
+	}
+}
+
+// checkUnique memastikan nilai kolom unik item tidak dipakai item lain (selain selfID) di kategori yang sama.
+func checkUnique(headers []entity.InventoryHeader, values map[string]interface{}, selfID string, others []entity.InventoryData) error {
+	idx := newUniqueIndex(headers)
+	if len(idx) == 0 {
+		return nil
+	}
+	for i := range others {
+		if others[i].ID == selfID {
+			continue
+		}
+		other := map[string]interface{}{}
+		_ = json.Unmarshal(others[i].Values, &other)
+		idx.add(other, others[i].ID)
+	}
+	return idx.check(headers, values, selfID)
+}
+
+// migrateValues menulis ulang values semua item ke skema baru: kolom yang dihapus dibuang, kolom dengan
+// RenamedFrom mengambil nilai kolom lama, dan tipe yang berubah di-coerce. Gagal jika ada nilai yang tidak bisa diubah.
+func migrateValues(headers []entity.InventoryHeader, data []entity.InventoryData) error {
+	migrated := make([]map[string]interface{}, len(data))
+	for i := range data {
+		old := map[string]interface{}{}
+		_ = json.Unmarshal(data[i].Values, &old)
+		source := make(map[string]interface{}, len(headers))
+		for _, h := range headers {
+			key := h.ID
+			if h.RenamedFrom != "" {
+				key = h.RenamedFrom
+			}
+			if v, ok := old[key]; ok {
+				source[h.ID] = v
+			}
+		}
+		values, err := validateValues(headers, source)
+		if err != nil {
+			return fmt.Errorf("item %s: %w", data[i].ID, err)
+		}
+		data[i].Values, _ = json.Marshal(values)
+		migrated[i] = values
+	}
+	idx := newUniqueIndex(headers)
+	if len(idx) == 0 {
+		return nil
+	}
+	for i := range data {
+		if err := idx.check(headers, migrated[i], data[i].ID); err != nil {
+			return err
+		}
+		idx.add(migrated[i], data[i].ID)
+	}
+	return nil
+}
+
+// sortInventoryData mengurutkan item menurut satu kolom sesuai tipenya; nilai kosong selalu di akhir.
+func sortInventoryData(headers []entity.InventoryHeader, data []entity.InventoryData, headerID string, desc bool) error {
+	var header *entity.InventoryHeader
+	for i := range headers {
+		if headers[i].ID == headerID {
+			header = &headers[i]
+		}
+	}
+	if header == nil || header.Type == entity.HeaderImage {
+		return fmt.Errorf("%w: kolom %s tidak bisa diurutkan", ErrInventoryValue, headerID)
+	}
+	rank := map[string]int{}
+	for i, o := range header.Options {
+		rank[o] = i
+	}
+	keys := make([]interface{}, len(data))
+	for i := range data {
+		values := map[string]interface{}{}
+		_ = json.Unmarshal(data[i].Values, &values)
+		v, ok := coerceValue(*header, values[headerID])
+		if !ok {
+			v = nil
+		}
+		keys[i] = v
+	}
+	less := func(a, b interface{}) bool {
+		switch header.Type {
+		case entity.HeaderNumber, entity.HeaderCurrency:
+			return a.(float64) < b.(float64)
+		case entity.HeaderEnum:
+			return rank[a.(string)] < rank[b.(string)]
+		case entity.HeaderText:
+			return strings.ToLower(a.(string)) < strings.ToLower(b.(string))
+		}
+		return a.(string) < b.(string)
+	}
+	idx := make([]int, len(data))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := keys[idx[i]], keys[idx[j]]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if desc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+	sorted := make([]entity.InventoryData, len(data))
+	for i, k := range idx {
+		sorted[i] = data[k]
+	}
+	copy(data, sorted)
+	return nil
+}
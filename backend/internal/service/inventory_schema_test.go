@@ -0,0 +1,195 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dashboardadminimb/internal/entity"
+)
+
+var testHeaders = []entity.InventoryHeader{
+	{ID: "nama", Name: "Nama", Type: entity.HeaderText, Required: true, Unique: true},
+	{ID: "qty", Name: "Qty", Type: entity.HeaderNumber, Integer: true, Default: float64(0)},
+	{ID: "harga", Name: "Harga", Type: entity.HeaderCurrency},
+	{ID: "tgl", Name: "Tanggal", Type: entity.HeaderDate},
+	{ID: "kondisi", Name: "Kondisi", Type: entity.HeaderEnum, Options: []string{"Baik", "Rusak"}},
+}
+
+// item InventoryData dengan values hasil JSON seperti yang tersimpan di database.
+func item(id string, values map[string]interface{}) entity.InventoryData {
+	raw, _ := json.Marshal(values)
+	return entity.InventoryData{ID: id, Values: raw}
+}
+
+func itemValues(t *testing.T, d entity.InventoryData) map[string]interface{} {
+	t.Helper()
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(d.Values, &values); err != nil {
+		t.Fatalf("values item %s: %v", d.ID, err)
+	}
+	return values
+}
+
+func TestValidateValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr string // "" = sukses
+	}{
+		{
+			name:   "coerce per tipe dan isi default",
+			values: map[string]interface{}{"nama": " Bor ", "harga": "1500.456", "tgl": "2025-01-02T08:00:00Z", "kondisi": "baik"},
+			want:   map[string]interface{}{"nama": "Bor", "qty": float64(0), "harga": 1500.46, "tgl": "2025-01-02", "kondisi": "Baik"},
+		},
+		{
+			name:   "angka jadi teks, kunci di luar skema dibuang",
+			values: map[string]interface{}{"nama": float64(12), "qty": "3", "lain": "x"},
+			want:   map[string]interface{}{"nama": "12", "qty": float64(3)},
+		},
+		{name: "wajib kosong", values: map[string]interface{}{"nama": "  "}, wantErr: "wajib diisi"},
+		{name: "integer berdesimal", values: map[string]interface{}{"nama": "Bor", "qty": 1.5}, wantErr: "number bulat"},
+		{name: "tanggal tidak valid", values: map[string]interface{}{"nama": "Bor", "tgl": "02/01/2025"}, wantErr: "YYYY-MM-DD"},
+		{name: "enum di luar opsi", values: map[string]interface{}{"nama": "Bor", "kondisi": "Hilang"}, wantErr: "Baik/Rusak"},
+		{name: "currency bukan angka", values: map[string]interface{}{"nama": "Bor", "harga": "mahal"}, wantErr: "currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateValues(testHeaders, tt.values)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInventoryValue) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, ingin ErrInventoryValue berisi %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("values = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateValues(t *testing.T) {
+	renamed := []entity.InventoryHeader{
+		{ID: "judul", Name: "Judul", Type: entity.HeaderText, Required: true, Unique: true, RenamedFrom: "nama"},
+		{ID: "qty", Name: "Qty", Type: entity.HeaderText},
+	}
+	numeric := []entity.InventoryHeader{
+		{ID: "nama", Name: "Nama", Type: entity.HeaderText, Required: true},
+		{ID: "qty", Name: "Qty", Type: entity.HeaderNumber, Integer: true},
+	}
+	unique := []entity.InventoryHeader{{ID: "nama", Name: "Nama", Type: entity.HeaderText, Unique: true}}
+
+	tests := []struct {
+		name    string
+		headers []entity.InventoryHeader
+		data    []entity.InventoryData
+		want    []map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:    "rename, ubah tipe, kolom terhapus dibuang",
+			headers: renamed,
+			data:    []entity.InventoryData{item("1", map[string]interface{}{"nama": "Bor", "qty": 3, "harga": 1000})},
+			want:    []map[string]interface{}{{"judul": "Bor", "qty": "3"}},
+		},
+		{
+			name:    "teks angka jadi number",
+			headers: numeric,
+			data:    []entity.InventoryData{item("1", map[string]interface{}{"nama": "Bor", "qty": "4"})},
+			want:    []map[string]interface{}{{"nama": "Bor", "qty": float64(4)}},
+		},
+		{
+			name:    "nilai tidak bisa diubah",
+			headers: numeric,
+			data:    []entity.InventoryData{item("1", map[string]interface{}{"nama": "Bor", "qty": "empat"})},
+			wantErr: ErrInventoryValue,
+		},
+		{
+			name:    "kolom wajib baru tanpa default",
+			headers: []entity.InventoryHeader{{ID: "kode", Name: "Kode", Type: entity.HeaderText, Required: true}},
+			data:    []entity.InventoryData{item("1", map[string]interface{}{"nama": "Bor"})},
+			wantErr: ErrInventoryValue,
+		},
+		{
+			name:    "kolom jadi unik dengan nilai ganda",
+			headers: unique,
+			data:    []entity.InventoryData{item("1", map[string]interface{}{"nama": "Bor"}), item("2", map[string]interface{}{"nama": "bor"})},
+			wantErr: ErrInventoryDuplicate,
+		},
+		{
+			name:    "kolom unik tanpa nilai ganda",
+			headers: unique,
+			data:    []entity.InventoryData{item("1", map[string]interface{}{"nama": "Bor"}), item("2", map[string]interface{}{})},
+			want:    []map[string]interface{}{{"nama": "Bor"}, {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrateValues(tt.headers, tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			for i, want := range tt.want {
+				if got := itemValues(t, tt.data[i]); !reflect.DeepEqual(got, want) {
+					t.Fatalf("item %s = %v, ingin %v", tt.data[i].ID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSortInventoryData(t *testing.T) {
+	data := []entity.InventoryData{
+		item("a", map[string]interface{}{"nama": "bor", "qty": 10, "kondisi": "Rusak", "tgl": "2025-03-01"}),
+		item("b", map[string]interface{}{"nama": "Amplas", "qty": 2, "tgl": "2025-01-15"}),
+		item("c", map[string]interface{}{"nama": "Cat", "qty": "x", "kondisi": "Baik"}),
+		item("d", map[string]interface{}{"qty": 9, "kondisi": "baik", "tgl": "2025-02-01"}),
+	}
+	tests := []struct {
+		name     string
+		headerID string
+		desc     bool
+		want     string // urutan ID item
+		wantErr  bool
+	}{
+		{name: "teks tanpa beda huruf", headerID: "nama", want: "bacd"},
+		{name: "teks menurun, kosong tetap di akhir", headerID: "nama", desc: true, want: "cabd"},
+		{name: "number, nilai tidak valid di akhir", headerID: "qty", want: "bdac"},
+		{name: "enum mengikuti urutan opsi", headerID: "kondisi", want: "cdab"},
+		{name: "enum menurun", headerID: "kondisi", desc: true, want: "acdb"},
+		{name: "tanggal", headerID: "tgl", want: "bdac"},
+		{name: "kolom tidak ada", headerID: "warna", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := append([]entity.InventoryData(nil), data...)
+			err := sortInventoryData(testHeaders, list, tt.headerID, tt.desc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := ""
+			for _, d := range list {
+				got += d.ID
+			}
+			if got != tt.want {
+				t.Fatalf("urutan = %s, ingin %s", got, tt.want)
+			}
+		})
+	}
+}
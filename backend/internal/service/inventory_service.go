@@ -3,6 +3,7 @@ package service
 import (
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/repository"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,36 +12,101 @@ import (
 
 type InventoryService interface {
 	CreateCategory(userID uint, category *entity.InventoryCategory) error
+	// UpdateCategory: jika Headers berubah, values semua item ditulis ulang ke skema baru (lihat migrateValues).
 	UpdateCategory(category *entity.InventoryCategory) error
 	DeleteCategory(id string) error
 	GetAllCategories(userID uint) ([]entity.InventoryCategory, error)
 	GetCategoryByID(id string) (*entity.InventoryCategory, error)
 
+	// CreateData/UpdateData memvalidasi values terhadap skema kolom kategori (ErrInventoryValue/ErrInventoryDuplicate).
 	CreateData(data *entity.InventoryData) error
 	UpdateData(data *entity.InventoryData) error
+	// UpdateImages menyimpan perubahan Images tanpa validasi values.
+	UpdateImages(data *entity.InventoryData) error
 	DeleteData(id string) error
 	GetDataByCategory(categoryID string) ([]entity.InventoryData, error)
+	// SortedData item kategori diurutkan menurut kolom sortBy sesuai tipenya.
+	SortedData(categoryID, sortBy string, desc bool) ([]entity.InventoryData, error)
 	GenerateDataID(categoryTitle, dataName string, index int) string
 
 	GetDataByID(id string) (*entity.InventoryData, error)
 }
 
 type inventoryService struct {
-	repo repository.InventoryRepository
+	repo      repository.InventoryRepository
+	stockRepo repository.InventoryStockRepository
 }
 
-func NewInventoryService(repo repository.InventoryRepository) InventoryService {
-	return &inventoryService{repo}
+func NewInventoryService(repo repository.InventoryRepository, stockRepo repository.InventoryStockRepository) InventoryService {
+	return &inventoryService{repo, stockRepo}
 }
 
 func (s *inventoryService) CreateCategory(userID uint, category *entity.InventoryCategory) error {
+	headers, err := normalizeHeaders(category.Headers)
+	if err != nil {
+		return err
+	}
+	category.Headers, _ = json.Marshal(headers)
 	category.ID = uuid.New().String()
 	category.UserID = userID
+	category.Data = nil
+	category.QuantityHeader, category.UnitHeader = "", ""
 	return s.repo.CreateCategory(category)
 }
 
+// UpdateCategory mempertahankan pemilik dan pengaturan stok (diubah lewat ConfigureCategory), kecuali kolomnya diganti nama/dihapus.
 func (s *inventoryService) UpdateCategory(category *entity.InventoryCategory) error {
-	return s.repo.UpdateCategory(category)
+	existing, err := s.repo.GetCategoryByID(category.ID)
+	if err != nil {
+		return err
+	}
+	category.UserID = existing.UserID
+	category.QuantityHeader, category.UnitHeader = existing.QuantityHeader, existing.UnitHeader
+	category.Data = nil
+	if len(category.Headers) == 0 {
+		category.Headers = existing.Headers
+		return s.repo.UpdateCategory(category)
+	}
+	headers, err := normalizeHeaders(category.Headers)
+	if err != nil {
+		return err
+	}
+	data := existing.Data
+	if err := migrateValues(headers, data); err != nil {
+		return err
+	}
+	category.QuantityHeader = renamedHeader(headers, category.QuantityHeader)
+	category.UnitHeader = renamedHeader(headers, category.UnitHeader)
+	for i := range headers {
+		if headers[i].ID == category.QuantityHeader && headers[i].Type != entity.HeaderNumber && headers[i].Type != entity.HeaderCurrency {
+			return fmt.Errorf("%w: kolom jumlah stok %s harus number", ErrInventorySchema, headers[i].Name)
+		}
+		headers[i].RenamedFrom = ""
+	}
+	category.Headers, _ = json.Marshal(headers)
+	// Kolom jumlah tetap mengikuti buku stok setelah values ditulis ulang.
+	for i := range data {
+		applyStockQuantity(s.stockRepo, category, &data[i])
+	}
+	return s.repo.UpdateCategoryWithData(category, data)
+}
+
+// renamedHeader ID baru kolom id setelah perubahan skema; "" jika kolom dihapus.
+func renamedHeader(headers []entity.InventoryHeader, id string) string {
+	if id == "" {
+		return ""
+	}
+	for _, h := range headers {
+		if h.RenamedFrom == id {
+			return h.ID
+		}
+	}
+	for _, h := range headers {
+		if h.ID == id {
+			return id
+		}
+	}
+	return ""
 }
 
 func (s *inventoryService) DeleteCategory(id string) error {
@@ -56,10 +122,63 @@ func (s *inventoryService) GetCategoryByID(id string) (*entity.InventoryCategory
 }
 
 func (s *inventoryService) CreateData(data *entity.InventoryData) error {
+	category, err := s.repo.GetCategoryByID(data.CategoryID)
+	if err != nil {
+		return err
+	}
+	data.ID = ""
+	if err := s.applySchema(category, data); err != nil {
+		return err
+	}
+	data.ID = s.GenerateDataID(category.Title, inventoryItemName(category, data), len(category.Data))
 	return s.repo.CreateData(data)
 }
 
+// UpdateData: kolom jumlah item yang punya buku stok selalu diisi ulang dari mutasi, bukan dari input.
 func (s *inventoryService) UpdateData(data *entity.InventoryData) error {
+	existing, err := s.repo.GetDataByID(data.ID)
+	if err != nil {
+		return err
+	}
+	data.CategoryID = existing.CategoryID
+	if len(data.Images) == 0 {
+		data.Images = existing.Images
+	}
+	category, err := s.repo.GetCategoryByID(existing.CategoryID)
+	if err != nil {
+		return err
+	}
+	applyStockQuantity(s.stockRepo, category, data)
+	if err := s.applySchema(category, data); err != nil {
+		return err
+	}
+	return s.repo.UpdateData(data)
+}
+
+// applySchema memvalidasi dan menormalkan data.Values terhadap skema kolom kategori.
+func (s *inventoryService) applySchema(category *entity.InventoryCategory, data *entity.InventoryData) error {
+	headers, err := normalizeHeaders(category.Headers)
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{}
+	if len(data.Values) > 0 {
+		if err := json.Unmarshal(data.Values, &values); err != nil {
+			return fmt.Errorf("%w: values harus objek JSON", ErrInventoryValue)
+		}
+	}
+	values, err = validateValues(headers, values)
+	if err != nil {
+		return err
+	}
+	if err := checkUnique(headers, values, data.ID, category.Data); err != nil {
+		return err
+	}
+	data.Values, _ = json.Marshal(values)
+	return nil
+}
+
+func (s *inventoryService) UpdateImages(data *entity.InventoryData) error {
 	return s.repo.UpdateData(data)
 }
 
@@ -71,6 +190,22 @@ func (s *inventoryService) GetDataByCategory(categoryID string) ([]entity.Invent
 	return s.repo.GetDataByCategory(categoryID)
 }
 
+func (s *inventoryService) SortedData(categoryID, sortBy string, desc bool) ([]entity.InventoryData, error) {
+	category, err := s.repo.GetCategoryByID(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := normalizeHeaders(category.Headers)
+	if err != nil {
+		return nil, err
+	}
+	data := category.Data
+	if err := sortInventoryData(headers, data, sortBy, desc); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 func (s *inventoryService) GenerateDataID(categoryTitle, dataName string, index int) string {
 	cleanCategory := strings.ToUpper(strings.ReplaceAll(categoryTitle, " ", "-"))
 	cleanName := strings.ToUpper(strings.ReplaceAll(dataName, " ", "-"))
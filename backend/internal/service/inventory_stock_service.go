@@ -0,0 +1,452 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrStockInvalid         = errors.New("mutasi stok tidak valid: item, date (YYYY-MM-DD) dan quantity > 0 wajib (adjustment boleh negatif, bukan nol); receipt/adjustment butuh to_location_id, issue butuh from_location_id, transfer butuh keduanya dan berbeda")
+	ErrStockInsufficient    = errors.New("stok di lokasi tidak mencukupi")
+	ErrStockLocationInvalid = errors.New("lokasi stok tidak valid: name wajib, kind warehouse/site, project_id proyek sendiri")
+	ErrStockLocationInUse   = errors.New("lokasi masih dipakai di mutasi stok")
+	ErrStockSettingsInvalid = errors.New("pengaturan stok tidak valid: quantity_header harus header number/currency kategori, unit_header header kategori; opening_location_id wajib jika item sudah punya jumlah")
+)
+
+// StockSettings penetapan kolom jumlah/satuan kategori. Jumlah yang sudah terisi pada item tanpa mutasi
+// dicatat sebagai penyesuaian saldo awal di OpeningLocationID pada OpeningDate (default hari ini).
+type StockSettings struct {
+	QuantityHeader    string `json:"quantity_header"`
+	UnitHeader        string `json:"unit_header"`
+	OpeningLocationID *uint  `json:"opening_location_id"`
+	OpeningDate       string `json:"opening_date"`
+}
+
+type InventoryStockService interface {
+	ListLocations(userID uint) ([]entity.StockLocation, error)
+	GetLocation(id, userID uint) (*entity.StockLocation, error)
+	SaveLocation(userID uint, l *entity.StockLocation) error
+	DeleteLocation(id, userID uint) error
+
+	ListMovements(userID uint, filter repository.StockFilter) ([]entity.StockMovementEntry, error)
+	// ItemHistory buku stok satu item dengan saldo total berjalan.
+	ItemHistory(userID uint, itemID string) ([]entity.StockMovementEntry, error)
+	// SaveMovement mencatat mutasi baru. ErrStockInsufficient disertai saldo lokasi yang akan menjadi negatif.
+	SaveMovement(userID uint, m *entity.StockMovement) (*entity.StockBalance, error)
+	DeleteMovement(id, userID uint) (*entity.StockBalance, error)
+
+	// OnHand stok per item per lokasi (filter CategoryID/LocationID/ItemID).
+	OnHand(userID uint, filter repository.StockFilter) ([]entity.ItemStock, error)
+	ConfigureCategory(userID uint, categoryID string, settings StockSettings) (*entity.InventoryCategory, error)
+}
+
+type inventoryStockService struct {
+	repo           repository.InventoryStockRepository
+	inventoryRepo  repository.InventoryRepository
+	projectService ProjectService
+}
+
+func NewInventoryStockService(repo repository.InventoryStockRepository, inventoryRepo repository.InventoryRepository, projectService ProjectService) InventoryStockService {
+	return &inventoryStockService{repo, inventoryRepo, projectService}
+}
+
+func (s *inventoryStockService) ListLocations(userID uint) ([]entity.StockLocation, error) {
+	return s.repo.FindLocations(userID)
+}
+
+func (s *inventoryStockService) GetLocation(id, userID uint) (*entity.StockLocation, error) {
+	l, err := s.repo.FindLocationByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if l.UserID != userID {
+		return nil, errors.New("lokasi stok tidak ditemukan")
+	}
+	return l, nil
+}
+
+func (s *inventoryStockService) SaveLocation(userID uint, l *entity.StockLocation) error {
+	l.UserID = userID
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Kind == "" {
+		l.Kind = entity.StockLocationWarehouse
+	}
+	if l.Name == "" || (l.Kind != entity.StockLocationWarehouse && l.Kind != entity.StockLocationSite) {
+		return ErrStockLocationInvalid
+	}
+	if l.ProjectID != nil && *l.ProjectID == 0 {
+		l.ProjectID = nil
+	}
+	if l.ProjectID != nil {
+		if _, err := s.projectService.GetProjectByID(*l.ProjectID, userID); err != nil {
+			return ErrStockLocationInvalid
+		}
+	}
+	return s.repo.SaveLocation(l)
+}
+
+func (s *inventoryStockService) DeleteLocation(id, userID uint) error {
+	if _, err := s.GetLocation(id, userID); err != nil {
+		return err
+	}
+	count, err := s.repo.CountMovementsAt(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrStockLocationInUse
+	}
+	return s.repo.DeleteLocation(id)
+}
+
+func (s *inventoryStockService) ListMovements(userID uint, filter repository.StockFilter) ([]entity.StockMovementEntry, error) {
+	return s.repo.FindMovements(userID, filter)
+}
+
+func (s *inventoryStockService) ItemHistory(userID uint, itemID string) ([]entity.StockMovementEntry, error) {
+	if _, _, err := s.item(userID, itemID); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.FindMovements(userID, repository.StockFilter{ItemID: itemID})
+	if err != nil {
+		return nil, err
+	}
+	balance := 0.0
+	for i := range list {
+		if list[i].ToLocationID != nil {
+			balance += list[i].Quantity
+		}
+		if list[i].FromLocationID != nil {
+			balance -= list[i].Quantity
+		}
+		list[i].Balance = round3(balance)
+	}
+	return list, nil
+}
+
+func (s *inventoryStockService) SaveMovement(userID uint, m *entity.StockMovement) (*entity.StockBalance, error) {
+	m.ID = 0
+	m.UserID = userID
+	m.Date = strings.TrimSpace(m.Date)
+	if m.FromLocationID != nil && *m.FromLocationID == 0 {
+		m.FromLocationID = nil
+	}
+	if m.ToLocationID != nil && *m.ToLocationID == 0 {
+		m.ToLocationID = nil
+	}
+	if m.ProjectID != nil && *m.ProjectID == 0 {
+		m.ProjectID = nil
+	}
+	if !validDay(m.Date) || m.Quantity == 0 || (m.Quantity < 0 && m.MovementType != entity.StockAdjustment) {
+		return nil, ErrStockInvalid
+	}
+	from, to := m.FromLocationID != nil, m.ToLocationID != nil
+	switch m.MovementType {
+	case entity.StockReceipt, entity.StockAdjustment:
+		if from || !to {
+			return nil, ErrStockInvalid
+		}
+	case entity.StockIssue:
+		if !from || to {
+			return nil, ErrStockInvalid
+		}
+	case entity.StockTransfer:
+		if !from || !to || *m.FromLocationID == *m.ToLocationID {
+			return nil, ErrStockInvalid
+		}
+	default:
+		return nil, ErrStockInvalid
+	}
+	category, data, err := s.item(userID, m.ItemID)
+	if err != nil {
+		return nil, ErrStockInvalid
+	}
+	m.CategoryID = category.ID
+	// Proyek default mengikuti lokasi site tujuan (atau asal untuk pengeluaran).
+	for _, id := range []*uint{m.ToLocationID, m.FromLocationID} {
+		if id == nil {
+			continue
+		}
+		l, err := s.GetLocation(*id, userID)
+		if err != nil {
+			return nil, ErrStockInvalid
+		}
+		if m.ProjectID == nil && l.ProjectID != nil {
+			m.ProjectID = l.ProjectID
+		}
+	}
+	if m.ProjectID != nil {
+		if _, err := s.projectService.GetProjectByID(*m.ProjectID, userID); err != nil {
+			return nil, ErrStockInvalid
+		}
+	}
+	if strings.TrimSpace(m.Unit) == "" {
+		m.Unit = itemUnit(category, data)
+	}
+	short, err := s.repo.SaveMovement(m)
+	if err != nil {
+		return nil, err
+	}
+	if short != nil {
+		return short, ErrStockInsufficient
+	}
+	return nil, s.syncQuantity(category, m.ItemID)
+}
+
+func (s *inventoryStockService) DeleteMovement(id, userID uint) (*entity.StockBalance, error) {
+	m, err := s.repo.FindMovementByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if m.UserID != userID {
+		return nil, errors.New("mutasi stok tidak ditemukan")
+	}
+	category, _, err := s.item(userID, m.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	short, err := s.repo.DeleteMovement(m)
+	if err != nil {
+		return nil, err
+	}
+	if short != nil {
+		return short, ErrStockInsufficient
+	}
+	return nil, s.syncQuantity(category, m.ItemID)
+}
+
+func (s *inventoryStockService) OnHand(userID uint, filter repository.StockFilter) ([]entity.ItemStock, error) {
+	balances, err := s.repo.Balances(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.inventoryRepo.GetAllCategories(userID)
+	if err != nil {
+		return nil, err
+	}
+	byItem := map[string][]entity.StockBalance{}
+	for _, b := range balances {
+		if round3(b.Quantity) == 0 {
+			// Lokasi yang sudah kosong tidak ditampilkan, tapi item tetap tercatat punya mutasi.
+			if _, ok := byItem[b.ItemID]; !ok {
+				byItem[b.ItemID] = nil
+			}
+			continue
+		}
+		byItem[b.ItemID] = append(byItem[b.ItemID], b)
+	}
+	result := make([]entity.ItemStock, 0)
+	for i := range categories {
+		category := &categories[i]
+		if filter.CategoryID != "" && category.ID != filter.CategoryID {
+			continue
+		}
+		for j := range category.Data {
+			data := &category.Data[j]
+			if filter.ItemID != "" && data.ID != filter.ItemID {
+				continue
+			}
+			locations, moved := byItem[data.ID]
+			// Item tanpa mutasi hanya tampil untuk kategori yang punya kolom jumlah dan tanpa filter lokasi.
+			if !moved && (category.QuantityHeader == "" || filter.LocationID != 0) {
+				continue
+			}
+			stock := entity.ItemStock{
+				ItemID:     data.ID,
+				CategoryID: category.ID,
+				Name:       inventoryItemName(category, data),
+				Unit:       itemUnit(category, data),
+				Locations:  make([]entity.StockBalance, 0, len(locations)),
+			}
+			for _, b := range locations {
+				stock.Total += b.Quantity
+				stock.Locations = append(stock.Locations, b)
+			}
+			stock.Total = round3(stock.Total)
+			result = append(result, stock)
+		}
+	}
+	return result, nil
+}
+
+func (s *inventoryStockService) ConfigureCategory(userID uint, categoryID string, settings StockSettings) (*entity.InventoryCategory, error) {
+	category, err := s.inventoryRepo.GetCategoryByID(categoryID)
+	if err != nil || category.UserID != userID {
+		return nil, errors.New("kategori tidak ditemukan")
+	}
+	headers, err := normalizeHeaders(category.Headers)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]string{}
+	for _, h := range headers {
+		types[h.ID] = h.Type
+	}
+	settings.QuantityHeader = strings.TrimSpace(settings.QuantityHeader)
+	settings.UnitHeader = strings.TrimSpace(settings.UnitHeader)
+	if t, ok := types[settings.QuantityHeader]; settings.QuantityHeader != "" && (!ok || (t != entity.HeaderNumber && t != entity.HeaderCurrency)) {
+		return nil, ErrStockSettingsInvalid
+	}
+	if t, ok := types[settings.UnitHeader]; settings.UnitHeader != "" && (!ok || t == entity.HeaderImage) {
+		return nil, ErrStockSettingsInvalid
+	}
+	if settings.OpeningDate == "" {
+		settings.OpeningDate = today().Format(dayLayout)
+	}
+	if !validDay(settings.OpeningDate) {
+		return nil, ErrStockSettingsInvalid
+	}
+	var opening *entity.StockLocation
+	if settings.OpeningLocationID != nil && *settings.OpeningLocationID != 0 {
+		if opening, err = s.GetLocation(*settings.OpeningLocationID, userID); err != nil {
+			return nil, ErrStockSettingsInvalid
+		}
+	}
+
+	// Saldo awal: jumlah lama pada item yang belum punya buku stok.
+	var openings []entity.StockMovement
+	if settings.QuantityHeader != "" {
+		balances, err := s.repo.Balances(userID, repository.StockFilter{CategoryID: category.ID})
+		if err != nil {
+			return nil, err
+		}
+		moved := map[string]bool{}
+		for _, b := range balances {
+			moved[b.ItemID] = true
+		}
+		for i := range category.Data {
+			data := &category.Data[i]
+			qty := itemNumber(data, settings.QuantityHeader)
+			if moved[data.ID] || qty <= 0 {
+				continue
+			}
+			if opening == nil {
+				return nil, ErrStockSettingsInvalid
+			}
+			openings = append(openings, entity.StockMovement{
+				UserID:       userID,
+				ItemID:       data.ID,
+				CategoryID:   category.ID,
+				MovementType: entity.StockAdjustment,
+				Date:         settings.OpeningDate,
+				Quantity:     qty,
+				Unit:         itemUnit(category, data),
+				ToLocationID: &opening.ID,
+				ProjectID:    opening.ProjectID,
+				Reference:    "SALDO-AWAL",
+				Notes:        "Saldo awal dari kolom jumlah kategori",
+			})
+		}
+	}
+
+	category.QuantityHeader, category.UnitHeader = settings.QuantityHeader, settings.UnitHeader
+	if err := s.repo.SaveCategorySettings(category, openings); err != nil {
+		return nil, err
+	}
+	for i := range category.Data {
+		if err := s.syncQuantity(category, category.Data[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.inventoryRepo.GetCategoryByID(category.ID)
+}
+
+// item data inventori milik user beserta kategorinya.
+func (s *inventoryStockService) item(userID uint, itemID string) (*entity.InventoryCategory, *entity.InventoryData, error) {
+	data, err := s.inventoryRepo.GetDataByID(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	category, err := s.inventoryRepo.GetCategoryByID(data.CategoryID)
+	if err != nil || category.UserID != userID {
+		return nil, nil, errors.New("item inventori tidak ditemukan")
+	}
+	return category, data, nil
+}
+
+// syncQuantity menulis total stok item ke kolom jumlah kategori (jika ditetapkan dan item punya mutasi).
+func (s *inventoryStockService) syncQuantity(category *entity.InventoryCategory, itemID string) error {
+	if category.QuantityHeader == "" {
+		return nil
+	}
+	data, err := s.inventoryRepo.GetDataByID(itemID)
+	if err != nil {
+		return err
+	}
+	if !applyStockQuantity(s.repo, category, data) {
+		return nil
+	}
+	return s.inventoryRepo.UpdateData(data)
+}
+
+// applyStockQuantity mengganti nilai kolom jumlah dengan total buku stok; false jika item belum punya mutasi.
+func applyStockQuantity(repo repository.InventoryStockRepository, category *entity.InventoryCategory, data *entity.InventoryData) bool {
+	if category.QuantityHeader == "" {
+		return false
+	}
+	balances, err := repo.Balances(category.UserID, repository.StockFilter{ItemID: data.ID})
+	if err != nil || len(balances) == 0 {
+		return false
+	}
+	total := 0.0
+	for _, b := range balances {
+		total += b.Quantity
+	}
+	values := map[string]interface{}{}
+	_ = json.Unmarshal(data.Values, &values)
+	values[category.QuantityHeader] = round3(total)
+	data.Values, _ = json.Marshal(values)
+	return true
+}
+
+// inventoryItemName nilai kolom teks pertama (juga dipakai untuk ID item); data.ID jika tidak ada.
+func inventoryItemName(category *entity.InventoryCategory, data *entity.InventoryData) string {
+	headers, _ := normalizeHeaders(category.Headers)
+	values := map[string]interface{}{}
+	_ = json.Unmarshal(data.Values, &values)
+	for _, h := range headers {
+		if h.Type != entity.HeaderText {
+			continue
+		}
+		if v, ok := values[h.ID].(string); ok {
+			return v
+		}
+	}
+	return data.ID
+}
+
+func itemUnit(category *entity.InventoryCategory, data *entity.InventoryData) string {
+	if category.UnitHeader == "" {
+		return ""
+	}
+	values := map[string]interface{}{}
+	_ = json.Unmarshal(data.Values, &values)
+	if v, ok := values[category.UnitHeader].(string); ok {
+		return strings.TrimSpace(v)
+	}
+	return ""
+}
+
+// itemNumber nilai numerik kolom item (angka JSON atau teks angka); 0 jika kosong/bukan angka.
+func itemNumber(data *entity.InventoryData, header string) float64 {
+	values := map[string]interface{}{}
+	_ = json.Unmarshal(data.Values, &values)
+	switch v := values[header].(type) {
+	case float64:
+		return v
+	case string:
+		n, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n
+	}
+	return 0
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
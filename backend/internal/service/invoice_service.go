@@ -40,17 +40,7 @@ func itemTotal(item *entity.InvoiceItem) float64 {
 
 func (s *invoiceService) Create(userID uint, inv *entity.Invoice) error {
 	inv.UserID = userID
-	if inv.AttachmentPhotosPerPage <= 0 {
-		inv.AttachmentPhotosPerPage = 1
-	}
-	var subtotal float64
-	for i := range inv.Items {
-		inv.Items[i].Total = itemTotal(&inv.Items[i])
-		subtotal += inv.Items[i].Total
-	}
-	inv.Subtotal = subtotal
-	inv.TaxAmount = subtotal * (inv.TaxPercent / 100)
-	inv.Total = subtotal + inv.TaxAmount
+	fillInvoiceTotals(inv)
 	if inv.Status == "" {
 		inv.Status = entity.InvoiceStatusDraft
 	}
@@ -58,6 +48,12 @@ func (s *invoiceService) Create(userID uint, inv *entity.Invoice) error {
 }
 
 func (s *invoiceService) Update(inv *entity.Invoice) error {
+	fillInvoiceTotals(inv)
+	return s.repo.Update(inv)
+}
+
+// fillInvoiceTotals menghitung total per item, subtotal, pajak dan total invoice.
+func fillInvoiceTotals(inv *entity.Invoice) {
 	if inv.AttachmentPhotosPerPage <= 0 {
 		inv.AttachmentPhotosPerPage = 1
 	}
@@ -69,7 +65,6 @@ func (s *invoiceService) Update(inv *entity.Invoice) error {
 	inv.Subtotal = subtotal
 	inv.TaxAmount = subtotal * (inv.TaxPercent / 100)
 	inv.Total = subtotal + inv.TaxAmount
-	return s.repo.Update(inv)
 }
 
 func (s *invoiceService) Delete(id uint) error {
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrMemberDocumentInvalid = errors.New("dokumen anggota tidak valid: doc_type ktp/sim_b2/sio/mcu/lainnya, tanggal YYYY-MM-DD, expiry_date >= issue_date")
+	ErrMemberDocumentMember  = errors.New("anggota tidak ditemukan")
+)
+
+var memberDocumentTypes = map[string]string{
+	entity.MemberDocKTP:     "KTP",
+	entity.MemberDocSIMB2:   "SIM B2",
+	entity.MemberDocSIO:     "SIO",
+	entity.MemberDocMedical: "Medical checkup",
+	entity.MemberDocOther:   "Dokumen",
+}
+
+type MemberDocumentService interface {
+	// List dokumen user; memberID kosong = semua anggota.
+	List(memberID string, userID uint) ([]entity.MemberDocument, error)
+	Get(id, userID uint) (*entity.MemberDocument, error)
+	Save(userID uint, doc *entity.MemberDocument) error
+	Delete(id, userID uint) error
+	// DeleteFile menghapus dokumen unggahan lama berdasarkan nama file.
+	DeleteFile(memberID, fileName string, userID uint) error
+	// Expiring anggota aktif yang dokumennya sudah kedaluwarsa atau berakhir dalam days hari.
+	Expiring(userID uint, days int) ([]entity.MemberDocumentAlert, error)
+	// CheckExpiring dipanggil job harian: dokumen yang masuk batas 30/14/7 hari atau kedaluwarsa dicatat sebagai aktivitas.
+	CheckExpiring(now time.Time) (int, error)
+	// LicenceWarnings lisensi wajib operator untuk jenis alat yang tidak ada, kedaluwarsa pada from,
+	// atau berakhir sebelum to (nil = penempatan terbuka). ErrMemberDocumentMember bila anggota bukan milik user.
+	LicenceWarnings(userID uint, memberID, equipmentType, from string, to *string) ([]entity.LicenceWarning, error)
+}
+
+type memberDocumentService struct {
+	repo            repository.MemberDocumentRepository
+	memberRepo      repository.MemberRepository
+	activityService ActivityService
+}
+
+func NewMemberDocumentService(repo repository.MemberDocumentRepository, memberRepo repository.MemberRepository, activityService ActivityService) MemberDocumentService {
+	return &memberDocumentService{repo, memberRepo, activityService}
+}
+
+// currentMemberDocuments dokumen yang berlaku per anggota+jenis (lihat currentDocuments).
+func currentMemberDocuments(docs []entity.MemberDocument) []entity.MemberDocument {
+	index := map[string]int{}
+	out := []entity.MemberDocument{}
+	for _, d := range docs {
+		key := d.MemberID + "/" + d.DocType
+		if d.DocType == entity.MemberDocOther {
+			key = fmt.Sprintf("id/%d", d.ID)
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(out)
+			out = append(out, d)
+			continue
+		}
+		if laterExpiry(d.ExpiryDate, out[i].ExpiryDate) {
+			out[i] = d
+		}
+	}
+	return out
+}
+
+func (s *memberDocumentService) member(memberID string, userID uint) (*entity.Member, error) {
+	m, err := s.memberRepo.FindByID(memberID)
+	if err != nil || m.UserID != userID {
+		return nil, ErrMemberDocumentMember
+	}
+	return m, nil
+}
+
+func (s *memberDocumentService) List(memberID string, userID uint) ([]entity.MemberDocument, error) {
+	if memberID != "" {
+		if _, err := s.member(memberID, userID); err != nil {
+			return nil, err
+		}
+	}
+	docs, err := s.repo.FindAll(userID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	for i := range docs {
+		docs[i].DaysLeft, docs[i].Status = evaluateExpiry(docs[i].ExpiryDate, now)
+	}
+	return docs, nil
+}
+
+func (s *memberDocumentService) Get(id, userID uint) (*entity.MemberDocument, error) {
+	doc, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if doc.UserID != userID {
+		return nil, errors.New("dokumen tidak ditemukan")
+	}
+	doc.DaysLeft, doc.Status = evaluateExpiry(doc.ExpiryDate, today())
+	return doc, nil
+}
+
+func (s *memberDocumentService) Save(userID uint, doc *entity.MemberDocument) error {
+	if _, err := s.member(doc.MemberID, userID); err != nil {
+		return err
+	}
+	doc.UserID = userID
+	doc.DocType = strings.ToLower(strings.TrimSpace(doc.DocType))
+	if doc.DocType == "" {
+		doc.DocType = entity.MemberDocOther
+	}
+	if _, ok := memberDocumentTypes[doc.DocType]; !ok {
+		return ErrMemberDocumentInvalid
+	}
+	for _, d := range []**string{&doc.IssueDate, &doc.ExpiryDate} {
+		if *d != nil && strings.TrimSpace(**d) == "" {
+			*d = nil
+		}
+		if *d != nil && !validDay(**d) {
+			return ErrMemberDocumentInvalid
+		}
+	}
+	if doc.IssueDate != nil && doc.ExpiryDate != nil && *doc.ExpiryDate < *doc.IssueDate {
+		return ErrMemberDocumentInvalid
+	}
+	if doc.ID > 0 {
+		// Masa berlaku berubah (perpanjangan): peringatan dimulai dari awal lagi.
+		if old, err := s.repo.FindByID(doc.ID); err == nil && !sameDate(old.ExpiryDate, doc.ExpiryDate) {
+			doc.NotifiedStage = ""
+		}
+	}
+	if err := s.repo.Save(doc); err != nil {
+		return err
+	}
+	doc.DaysLeft, doc.Status = evaluateExpiry(doc.ExpiryDate, today())
+	return nil
+}
+
+func (s *memberDocumentService) Delete(id, userID uint) error {
+	if _, err := s.Get(id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *memberDocumentService) DeleteFile(memberID, fileName string, userID uint) error {
+	if _, err := s.member(memberID, userID); err != nil {
+		return err
+	}
+	doc, err := s.repo.FindByFile(memberID, fileName)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(doc.ID)
+}
+
+func (s *memberDocumentService) Expiring(userID uint, days int) ([]entity.MemberDocumentAlert, error) {
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.repo.FindAll(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	alerts := map[string]*entity.MemberDocumentAlert{}
+	soonest := map[string]int{}
+	for _, d := range currentMemberDocuments(docs) {
+		d.DaysLeft, d.Status = evaluateExpiry(d.ExpiryDate, now)
+		if d.DaysLeft == nil || *d.DaysLeft > days {
+			continue
+		}
+		a, ok := alerts[d.MemberID]
+		if !ok {
+			a = &entity.MemberDocumentAlert{MemberID: d.MemberID, Expired: []entity.MemberDocument{}, Expiring: []entity.MemberDocument{}}
+			alerts[d.MemberID] = a
+			soonest[d.MemberID] = *d.DaysLeft
+		}
+		if d.Status == entity.DocStatusExpired {
+			a.Expired = append(a.Expired, d)
+		} else {
+			a.Expiring = append(a.Expiring, d)
+		}
+		if *d.DaysLeft < soonest[d.MemberID] {
+			soonest[d.MemberID] = *d.DaysLeft
+		}
+	}
+	out := []entity.MemberDocumentAlert{}
+	for _, m := range members {
+		a, ok := alerts[m.ID]
+		if !ok || !m.IsActive {
+			continue
+		}
+		a.MemberName, a.Role = m.FullName, m.Role
+		out = append(out, *a)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return soonest[out[i].MemberID] < soonest[out[j].MemberID] })
+	return out, nil
+}
+
+func (s *memberDocumentService) CheckExpiring(now time.Time) (int, error) {
+	docs, err := s.repo.FindWithExpiry()
+	if err != nil {
+		return 0, err
+	}
+	day, _ := time.Parse(dayLayout, now.Format(dayLayout))
+	members := map[string]*entity.Member{}
+	logged := 0
+	for _, d := range currentMemberDocuments(docs) {
+		d.DaysLeft, d.Status = evaluateExpiry(d.ExpiryDate, day)
+		if stageRank[d.Status] == 0 || stageRank[d.Status] <= stageRank[d.NotifiedStage] {
+			continue
+		}
+		m, ok := members[d.MemberID]
+		if !ok {
+			m, _ = s.memberRepo.FindByID(d.MemberID)
+			members[d.MemberID] = m
+		}
+		if m == nil || !m.IsActive {
+			continue
+		}
+		label := memberDocumentTypes[d.DocType]
+		title := fmt.Sprintf("%s %s berakhir dalam %d hari", label, m.FullName, *d.DaysLeft)
+		if d.Status == entity.DocStatusExpired {
+			title = fmt.Sprintf("%s %s sudah kedaluwarsa", label, m.FullName)
+		}
+		desc := fmt.Sprintf("No. %s, berlaku sampai %s", d.Number, *d.ExpiryDate)
+		if err := s.activityService.LogActivity(d.UserID, entity.ActivityCompliance, title, desc); err != nil {
+			return logged, err
+		}
+		if err := s.repo.SetNotified(d.ID, d.Status); err != nil {
+			return logged, err
+		}
+		logged++
+	}
+	return logged, nil
+}
+
+func (s *memberDocumentService) LicenceWarnings(userID uint, memberID, equipmentType, from string, to *string) ([]entity.LicenceWarning, error) {
+	m, err := s.member(memberID, userID)
+	if err != nil {
+		return nil, err
+	}
+	required := entity.OperatorLicences[equipmentType]
+	if len(required) == 0 {
+		return nil, nil
+	}
+	docs, err := s.repo.FindAll(userID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]entity.MemberDocument{}
+	for _, d := range currentMemberDocuments(docs) {
+		current[d.DocType] = d
+	}
+	warnings := []entity.LicenceWarning{}
+	for _, t := range required {
+		label := memberDocumentTypes[t]
+		w := entity.LicenceWarning{MemberID: m.ID, MemberName: m.FullName, DocType: t}
+		d, ok := current[t]
+		switch {
+		case !ok:
+			w.Problem = entity.LicenceMissing
+			w.Message = fmt.Sprintf("%s belum punya %s", m.FullName, label)
+		case d.ExpiryDate == nil:
+			continue
+		case *d.ExpiryDate < from:
+			w.Problem, w.ExpiryDate = entity.LicenceExpired, d.ExpiryDate
+			w.Message = fmt.Sprintf("%s %s sudah kedaluwarsa sejak %s", label, m.FullName, *d.ExpiryDate)
+		case to != nil && *d.ExpiryDate < *to:
+			w.Problem, w.ExpiryDate = entity.LicenceExpiring, d.ExpiryDate
+			w.Message = fmt.Sprintf("%s %s berakhir %s, sebelum penempatan selesai", label, m.FullName, *d.ExpiryDate)
+		default:
+			continue
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings, nil
+}
@@ -19,18 +19,20 @@ type MemberService interface {
 	ActivateMember(id string) error
 	GetMemberTotalSalary(memberID string) (float64, error)
 	GetAllMembersTotalSalary(userID uint) (float64, error)
-	GetMemberTotalSalaryWithFilter(memberID string, year string, month string) (float64, error)
-	GetAllMembersTotalSalaryWithFilter(userID uint, year string, month string) (float64, error)
-	GetAllMembersWithSalaryInfo(userID uint, year string, month string, orderBy string) ([]repository.MemberSalaryInfo, error)
-	GetMemberMonthlySalaryDetails(memberID string, year string) ([]repository.MonthlySalaryDetail, error)
+	// Filter periode: year (YYYY), lalu month (01-12) atau quarter (1-4); tanpa year = semua periode.
+	GetMemberTotalSalaryWithFilter(memberID string, year, month, quarter string) (float64, error)
+	GetAllMembersTotalSalaryWithFilter(userID uint, year, month, quarter string) (float64, error)
+	GetAllMembersWithSalaryInfo(userID uint, year, month, quarter, orderBy string) ([]repository.MemberSalaryInfo, error)
+	GetMemberMonthlySalaryDetails(memberID string, year, month, quarter string) ([]repository.MonthlySalaryDetail, error)
 }
 
 type memberService struct {
-	repo repository.MemberRepository
+	repo       repository.MemberRepository
+	employment EmploymentService
 }
 
-func NewMemberService(repo repository.MemberRepository) MemberService {
-	return &memberService{repo}
+func NewMemberService(repo repository.MemberRepository, employment EmploymentService) MemberService {
+	return &memberService{repo, employment}
 }
 
 func (s *memberService) GetMemberCount(userID uint) (int64, error) {
@@ -39,7 +41,10 @@ func (s *memberService) GetMemberCount(userID uint) (int64, error) {
 
 func (s *memberService) CreateMember(userID uint, member *entity.Member) error {
 	member.UserID = userID
-	return s.repo.Create(member)
+	if err := s.repo.Create(member); err != nil {
+		return err
+	}
+	return s.employment.RecordJoined(member)
 }
 
 func (s *memberService) GetAllMembers(userID uint) ([]entity.Member, error) {
@@ -50,8 +55,23 @@ func (s *memberService) GetMemberByID(id string) (*entity.Member, error) {
 	return s.repo.FindByID(id)
 }
 
+// UpdateMember jabatan yang diubah langsung dicatat sebagai perubahan mulai hari ini.
 func (s *memberService) UpdateMember(member *entity.Member) error {
-	return s.repo.Update(member)
+	stored, err := s.repo.FindByID(member.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Update(member); err != nil {
+		return err
+	}
+	if member.Role == stored.Role {
+		return nil
+	}
+	_, err = s.employment.RecordChange(member.ID, member.UserID, &EmploymentChange{
+		EffectiveDate: today().Format(dayLayout),
+		Role:          member.Role,
+	})
+	return err
 }
 
 func (s *memberService) DeleteMember(id string) error {
@@ -67,12 +87,26 @@ func (s *memberService) GetAllMembersWithPagination(params response.QueryParams,
 }
 
 func (s *memberService) DeactivateMember(id string, reason string) error {
-	deactivatedAt := time.Now().Format(time.RFC3339)
-	return s.repo.DeactivateMember(id, reason, deactivatedAt)
+	member, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := s.repo.DeactivateMember(id, reason, now.Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return s.employment.RecordStatus(member, entity.EmploymentDeactivated, now.Format(dayLayout), reason)
 }
 
 func (s *memberService) ActivateMember(id string) error {
-	return s.repo.ActivateMember(id)
+	member, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ActivateMember(id); err != nil {
+		return err
+	}
+	return s.employment.RecordStatus(member, entity.EmploymentReactivated, today().Format(dayLayout), "")
 }
 
 func (s *memberService) GetMemberTotalSalary(memberID string) (float64, error) {
@@ -83,18 +117,34 @@ func (s *memberService) GetAllMembersTotalSalary(userID uint) (float64, error) {
 	return s.repo.GetAllMembersTotalSalary(userID)
 }
 
-func (s *memberService) GetMemberTotalSalaryWithFilter(memberID string, year string, month string) (float64, error) {
-	return s.repo.GetMemberTotalSalaryWithFilter(memberID, year, month)
+func (s *memberService) GetMemberTotalSalaryWithFilter(memberID string, year, month, quarter string) (float64, error) {
+	period, err := parseSalaryPeriod(year, month, quarter)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.GetMemberTotalSalaryWithFilter(memberID, period)
 }
 
-func (s *memberService) GetAllMembersTotalSalaryWithFilter(userID uint, year string, month string) (float64, error) {
-	return s.repo.GetAllMembersTotalSalaryWithFilter(userID, year, month)
+func (s *memberService) GetAllMembersTotalSalaryWithFilter(userID uint, year, month, quarter string) (float64, error) {
+	period, err := parseSalaryPeriod(year, month, quarter)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.GetAllMembersTotalSalaryWithFilter(userID, period)
 }
 
-func (s *memberService) GetAllMembersWithSalaryInfo(userID uint, year string, month string, orderBy string) ([]repository.MemberSalaryInfo, error) {
-	return s.repo.GetAllMembersWithSalaryInfo(userID, year, month, orderBy)
+func (s *memberService) GetAllMembersWithSalaryInfo(userID uint, year, month, quarter, orderBy string) ([]repository.MemberSalaryInfo, error) {
+	period, err := parseSalaryPeriod(year, month, quarter)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAllMembersWithSalaryInfo(userID, period, orderBy)
 }
 
-func (s *memberService) GetMemberMonthlySalaryDetails(memberID string, year string) ([]repository.MonthlySalaryDetail, error) {
-	return s.repo.GetMemberMonthlySalaryDetails(memberID, year)
+func (s *memberService) GetMemberMonthlySalaryDetails(memberID string, year, month, quarter string) ([]repository.MonthlySalaryDetail, error) {
+	period, err := parseSalaryPeriod(year, month, quarter)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetMemberMonthlySalaryDetails(memberID, period)
 }
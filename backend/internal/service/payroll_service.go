@@ -0,0 +1,395 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPayrollMonth       = errors.New("bulan payroll harus berformat YYYY-MM")
+	ErrPayrollExists      = errors.New("payroll run untuk bulan ini sudah ada")
+	ErrPayrollNotDraft    = errors.New("payroll run sudah difinalisasi")
+	ErrPayrollExpenseMode = errors.New("expense_mode harus single atau per_member, payment_date YYYY-MM-DD")
+)
+
+// SourcePayroll penanda Finance expense yang dibuat saat finalisasi payroll run.
+const SourcePayroll = "payroll"
+
+type PayrollService interface {
+	List(userID uint) ([]entity.PayrollRun, error)
+	// Get run beserta item review per anggota.
+	Get(id, userID uint) (*entity.PayrollRun, error)
+	// CreateDraft membuat run draft: gaji dibuat (atau diambil jika sudah ada) untuk tiap anggota aktif lalu dihitung,
+	// semuanya dalam satu transaksi.
+	CreateDraft(userID uint, month, notes string) (*entity.PayrollRun, error)
+	// Refresh menyinkronkan ulang anggota, jam kerja dan kasbon pada run draft.
+	Refresh(id, userID uint) (*entity.PayrollRun, error)
+	// Finalize menyinkronkan ulang gaji, menguncinya, menandai Paid dan mencatat pengeluaran Finance dalam satu transaksi.
+	Finalize(id, userID uint, expenseMode, paymentDate string) (*entity.PayrollRun, error)
+	Delete(id, userID uint) error
+}
+
+type payrollService struct {
+	repo           repository.PayrollRepository
+	salaryRepo     repository.SalaryRepository
+	memberRepo     repository.MemberRepository
+	detailService  SalaryDetailService
+	kasbonService  KasbonService
+	advanceService AdvanceService
+	statutory      StatutoryService
+	attendance     AttendanceService
+	// inTx membangun payroll service yang semua repository-nya memakai tx; nil untuk service yang sudah di dalam transaksi.
+	inTx func(tx *gorm.DB) PayrollService
+}
+
+func NewPayrollService(repo repository.PayrollRepository, salaryRepo repository.SalaryRepository, memberRepo repository.MemberRepository, detailService SalaryDetailService, kasbonService KasbonService, advanceService AdvanceService, statutory StatutoryService, attendance AttendanceService, inTx func(tx *gorm.DB) PayrollService) PayrollService {
+	return &payrollService{repo, salaryRepo, memberRepo, detailService, kasbonService, advanceService, statutory, attendance, inTx}
+}
+
+func sumRepayments(list []entity.AdvanceRepayment) float64 {
+	total := 0.0
+	for _, r := range list {
+		total += r.Amount
+	}
+	return round2(total)
+}
+
+func validMonth(month string) bool {
+	_, err := time.Parse("2006-01", month)
+	return err == nil && len(month) == 7
+}
+
+func (s *payrollService) List(userID uint) ([]entity.PayrollRun, error) {
+	return s.repo.FindAll(userID)
+}
+
+func (s *payrollService) find(id, userID uint) (*entity.PayrollRun, error) {
+	run, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if run.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return run, nil
+}
+
+func (s *payrollService) Get(id, userID uint) (*entity.PayrollRun, error) {
+	run, err := s.find(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	var plan map[string][]entity.AdvanceRepayment
+	if run.Status == entity.PayrollDraft {
+		plan, err = s.advanceService.PlanDeductions(userID, run.Month)
+	} else {
+		plan, err = s.recordedRepayments(run.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return run, s.loadItems(run, plan)
+}
+
+func (s *payrollService) CreateDraft(userID uint, month, notes string) (*entity.PayrollRun, error) {
+	if !validMonth(month) {
+		return nil, ErrPayrollMonth
+	}
+	// Run dan sinkronisasi gajinya satu transaksi: jika sync gagal, tidak tertinggal draft kosong yang memblokir bulan itu.
+	if s.inTx != nil {
+		var run *entity.PayrollRun
+		err := s.repo.Transaction(func(tx *gorm.DB) error {
+			var err error
+			run, err = s.inTx(tx).CreateDraft(userID, month, notes)
+			return err
+		})
+		return run, err
+	}
+	if _, err := s.repo.FindByMonth(userID, month); err == nil {
+		return nil, ErrPayrollExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	run := &entity.PayrollRun{UserID: userID, Month: month, Status: entity.PayrollDraft, Notes: notes}
+	if err := s.repo.Create(run); err != nil {
+		return nil, err
+	}
+	plan, err := s.sync(run)
+	if err != nil {
+		return nil, err
+	}
+	return run, s.loadItems(run, plan)
+}
+
+func (s *payrollService) Refresh(id, userID uint) (*entity.PayrollRun, error) {
+	run, err := s.find(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != entity.PayrollDraft {
+		return nil, ErrPayrollNotDraft
+	}
+	plan, err := s.sync(run)
+	if err != nil {
+		return nil, err
+	}
+	return run, s.loadItems(run, plan)
+}
+
+// sync memastikan setiap anggota aktif (dan anggota nonaktif yang sudah punya gaji bulan itu) punya gaji
+// di run, lalu menghitung ulang gross dari SalaryDetail dan potongan dari Kasbon serta cicilan kasbon ledger.
+// Mengembalikan rencana cicilan per anggota yang dipakai untuk potongan; tarif kontrak yang belum ada dicatat di run.Warnings.
+func (s *payrollService) sync(run *entity.PayrollRun) (map[string][]entity.AdvanceRepayment, error) {
+	members, err := s.memberRepo.FindAll(run.UserID)
+	if err != nil {
+		return nil, err
+	}
+	run.Warnings = nil
+	plan, err := s.advanceService.PlanDeductions(run.UserID, run.Month)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		salaries, err := s.salaryRepo.FindByMemberID(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		var salary *entity.Salary
+		for i := range salaries {
+			if salaryMonthKey(salaries[i].Month) == run.Month {
+				salary = &salaries[i]
+				break
+			}
+		}
+		if salary == nil {
+			if !m.IsActive {
+				continue
+			}
+			salary = &entity.Salary{MemberID: m.ID, Month: run.Month, Status: "Pending"}
+		}
+		if salary.Locked {
+			continue
+		}
+		salary.PayrollRunID = &run.ID
+		if err := setSalaryPeriod(salary); err != nil {
+			return nil, err
+		}
+		// Gaji baru disimpan dulu agar absensi yang disetujui bisa dijadikan detailnya.
+		if salary.ID == 0 {
+			if err := s.salaryRepo.Create(salary); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.recalculate(salary, run.UserID); err != nil {
+			return nil, err
+		}
+		for _, w := range salary.RateWarnings {
+			run.Warnings = append(run.Warnings, m.FullName+" - "+w)
+		}
+		if err := s.salaryRepo.Update(salary); err != nil {
+			return nil, err
+		}
+	}
+	return plan, s.updateTotals(run)
+}
+
+// recalculate mengikuti RecalculateSalary, tetapi gross yang diisi manual (tanpa detail) tidak dinolkan.
+// Loan selalu dihitung ulang lewat salaryLoan (kasbon gaji + cicilan kasbon ledger bulan ini + LoanAdjustment manual).
+func (s *payrollService) recalculate(salary *entity.Salary, userID uint) error {
+	if err := s.attendance.SyncSalaryDetails(salary); err != nil {
+		return err
+	}
+	if err := s.detailService.FillRates(salary); err != nil {
+		return err
+	}
+	details, err := s.detailService.GetDetailsBySalary(salary.ID)
+	if err != nil {
+		return err
+	}
+	if len(details) > 0 {
+		gross := 0.0
+		for _, d := range details {
+			gross += float64(d.JamTrip) * d.HargaPerJam
+		}
+		salary.GrossSalary, salary.Salary = gross, gross
+	} else if salary.GrossSalary == 0 {
+		salary.GrossSalary = salary.Salary
+	}
+	loan, err := salaryLoan(s.kasbonService, s.advanceService, userID, salary)
+	if err != nil {
+		return err
+	}
+	salary.Loan = loan
+	salary.NetSalary = salary.GrossSalary - salary.Loan
+	return s.statutory.Apply(salary)
+}
+
+func (s *payrollService) updateTotals(run *entity.PayrollRun) error {
+	salaries, err := s.repo.FindSalaries(run.ID)
+	if err != nil {
+		return err
+	}
+	run.MemberCount, run.TotalGross, run.TotalLoan, run.TotalStatutory, run.TotalNet = len(salaries), 0, 0, 0, 0
+	for _, sal := range salaries {
+		run.TotalGross += sal.GrossSalary
+		run.TotalLoan += sal.Loan
+		run.TotalStatutory += sal.Statutory
+		run.TotalNet += sal.NetSalary
+	}
+	run.TotalGross, run.TotalLoan, run.TotalNet = round2(run.TotalGross), round2(run.TotalLoan), round2(run.TotalNet)
+	run.TotalStatutory = round2(run.TotalStatutory)
+	return s.repo.Update(run)
+}
+
+func (s *payrollService) recordedRepayments(runID uint) (map[string][]entity.AdvanceRepayment, error) {
+	list, err := s.repo.FindRepayments(runID)
+	if err != nil {
+		return nil, err
+	}
+	byMember := map[string][]entity.AdvanceRepayment{}
+	for _, r := range list {
+		byMember[r.MemberID] = append(byMember[r.MemberID], r)
+	}
+	return byMember, nil
+}
+
+// loadItems plan: cicilan kasbon per anggota (rencana untuk draft, yang tercatat untuk run final).
+func (s *payrollService) loadItems(run *entity.PayrollRun, plan map[string][]entity.AdvanceRepayment) error {
+	salaries, err := s.repo.FindSalaries(run.ID)
+	if err != nil {
+		return err
+	}
+	run.Items = make([]entity.PayrollItem, 0, len(salaries))
+	for _, sal := range salaries {
+		details, err := s.detailService.GetDetailsBySalary(sal.ID)
+		if err != nil {
+			return err
+		}
+		kasbons, err := s.kasbonService.GetKasbonsBySalary(sal.ID)
+		if err != nil {
+			return err
+		}
+		hours := 0.0
+		for _, d := range details {
+			hours += float64(d.JamTrip)
+		}
+		run.Items = append(run.Items, entity.PayrollItem{
+			SalaryID:    sal.ID,
+			MemberID:    sal.MemberID,
+			MemberName:  sal.Member.FullName,
+			Role:        sal.Member.Role,
+			Hours:       round2(hours),
+			DetailCount: len(details),
+			KasbonCount: len(kasbons),
+			GrossSalary: sal.GrossSalary,
+			Loan:        sal.Loan,
+			Installment: sumRepayments(plan[sal.MemberID]),
+			Statutory:   sal.Statutory,
+			NetSalary:   sal.NetSalary,
+			Status:      sal.Status,
+			Locked:      sal.Locked,
+		})
+	}
+	return nil
+}
+
+func (s *payrollService) Finalize(id, userID uint, expenseMode, paymentDate string) (*entity.PayrollRun, error) {
+	if expenseMode == "" {
+		expenseMode = entity.PayrollExpenseSingle
+	}
+	if paymentDate == "" {
+		paymentDate = today().Format(dayLayout)
+	}
+	if (expenseMode != entity.PayrollExpenseSingle && expenseMode != entity.PayrollExpensePerMember) || !validDay(paymentDate) {
+		return nil, ErrPayrollExpenseMode
+	}
+	if s.inTx != nil {
+		var run *entity.PayrollRun
+		err := s.repo.Transaction(func(tx *gorm.DB) error {
+			var err error
+			run, err = s.inTx(tx).Finalize(id, userID, expenseMode, paymentDate)
+			return err
+		})
+		return run, err
+	}
+	// Di dalam transaksi: run dikunci dulu agar Refresh/Finalize lain menunggu sampai gaji terkunci.
+	run, err := s.repo.FindByIDForUpdate(id)
+	if err != nil {
+		return nil, err
+	}
+	if run.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if run.Status != entity.PayrollDraft {
+		return nil, ErrPayrollNotDraft
+	}
+	// Hitung ulang di transaksi yang sama agar total yang dibayar sama dengan yang tersimpan.
+	plan, err := s.sync(run)
+	if err != nil {
+		return nil, err
+	}
+	salaries, err := s.repo.FindSalaries(run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	expense := func(amount float64, keterangan string) entity.Finance {
+		return entity.Finance{
+			UserID:       userID,
+			Tanggal:      paymentDate,
+			Unit:         1,
+			Jumlah:       amount,
+			HargaPerUnit: amount,
+			Keterangan:   keterangan,
+			Type:         entity.Expense,
+			Category:     entity.CategoryGaji,
+			Status:       "Paid",
+			Source:       SourcePayroll,
+			TanggalBayar: paymentDate,
+		}
+	}
+	var expenses []entity.Finance
+	var repayments []entity.AdvanceRepayment
+	for i := range salaries {
+		salaries[i].Status, salaries[i].Locked = "Paid", true
+		for _, r := range plan[salaries[i].MemberID] {
+			r.SalaryID, r.PayrollRunID, r.Tanggal = &salaries[i].ID, &run.ID, paymentDate
+			repayments = append(repayments, r)
+		}
+		if expenseMode == entity.PayrollExpensePerMember && salaries[i].NetSalary > 0 {
+			expenses = append(expenses, expense(salaries[i].NetSalary,
+				fmt.Sprintf("Gaji %s %s", salaries[i].Member.FullName, run.Month)))
+		}
+	}
+	if expenseMode == entity.PayrollExpenseSingle && run.TotalNet > 0 {
+		expenses = append(expenses, expense(run.TotalNet,
+			fmt.Sprintf("Payroll %s (%d anggota)", run.Month, len(salaries))))
+	}
+
+	run.ExpenseMode, run.PaymentDate = expenseMode, &paymentDate
+	ok, err := s.repo.Finalize(run, salaries, expenses, repayments)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPayrollNotDraft
+	}
+	return run, s.loadItems(run, plan)
+}
+
+func (s *payrollService) Delete(id, userID uint) error {
+	run, err := s.find(id, userID)
+	if err != nil {
+		return err
+	}
+	if run.Status != entity.PayrollDraft {
+		return ErrPayrollNotDraft
+	}
+	return s.repo.Delete(run)
+}
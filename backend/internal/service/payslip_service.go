@@ -0,0 +1,408 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/pdfdoc"
+	"dashboardadminimb/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrPayslipEmpty = errors.New("tidak ada gaji untuk bulan ini")
+
+// Payslip file PDF slip gaji siap diunduh.
+type Payslip struct {
+	FileName string
+	Data     []byte
+}
+
+type PayslipService interface {
+	// Render membuat PDF slip gaji satu Salary.
+	Render(salaryID, userID uint) (*Payslip, error)
+	// Store merender lalu menyimpan slip di upload dir dan mencantumkannya di Salary.Documents (ditimpa jika sudah ada).
+	Store(salaryID, userID uint) (*entity.Salary, *Payslip, error)
+	// ExportMonth ZIP berisi slip gaji semua anggota untuk bulan YYYY-MM.
+	ExportMonth(userID uint, month string) (*Payslip, error)
+}
+
+type payslipService struct {
+	salaryRepo    repository.SalaryRepository
+	memberRepo    repository.MemberRepository
+	userRepo      repository.UserRepository
+	advanceRepo   repository.AdvanceRepository
+	salaryService SalaryService
+	detailService SalaryDetailService
+	kasbonService KasbonService
+	statutory     StatutoryService
+	uploadDir     string
+}
+
+func NewPayslipService(salaryRepo repository.SalaryRepository, memberRepo repository.MemberRepository, userRepo repository.UserRepository, advanceRepo repository.AdvanceRepository, salaryService SalaryService, detailService SalaryDetailService, kasbonService KasbonService, statutory StatutoryService, uploadDir string) PayslipService {
+	return &payslipService{salaryRepo, memberRepo, userRepo, advanceRepo, salaryService, detailService, kasbonService, statutory, uploadDir}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func payslipFileName(salary *entity.Salary, member *entity.Member) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(member.FullName, "-"), "-")
+	month := salaryMonthKey(salary.Month)
+	if month == "" {
+		month = strings.Trim(unsafeFileChars.ReplaceAllString(salary.Month, "-"), "-")
+	}
+	return fmt.Sprintf("slip-gaji-%d-%s-%s.pdf", salary.ID, month, name)
+}
+
+// formatRupiah 1234567.5 → "Rp 1.234.568".
+func formatRupiah(v float64) string {
+	neg := v < 0
+	if neg {
+		v = -v
+	}
+	digits := fmt.Sprintf("%.0f", v)
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	if neg {
+		return "-Rp " + b.String()
+	}
+	return "Rp " + b.String()
+}
+
+// formatHours 8 → "8", 7.5 → "7,5".
+func formatHours(v float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+	return strings.Replace(s, ".", ",", 1)
+}
+
+func (s *payslipService) owned(salaryID, userID uint) (*entity.Salary, *entity.Member, error) {
+	salary, err := s.salaryRepo.FindByID(salaryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	member, err := s.memberRepo.FindByID(salary.MemberID)
+	if err != nil || member.UserID != userID {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	return salary, member, nil
+}
+
+func (s *payslipService) Render(salaryID, userID uint) (*Payslip, error) {
+	salary, member, err := s.owned(salaryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(salary, member, user)
+}
+
+type payslipLine struct {
+	date, note string
+	hours      float64
+	rate       float64
+	amount     float64
+}
+
+func (s *payslipService) render(salary *entity.Salary, member *entity.Member, user *entity.User) (*Payslip, error) {
+	details, err := s.detailService.GetDetailsBySalary(salary.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(details, func(i, j int) bool { return details[i].Tanggal.Before(details[j].Tanggal) })
+	kasbons, err := s.kasbonService.GetKasbonsBySalary(salary.ID)
+	if err != nil {
+		return nil, err
+	}
+	installments, err := s.advanceRepo.FindRepaymentsBySalary(salary.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var earnings, deductions []payslipLine
+	for _, d := range details {
+		earnings = append(earnings, payslipLine{
+			date: d.Tanggal.Format("02/01/2006"), note: d.Keterangan,
+			hours: float64(d.JamTrip), rate: d.HargaPerJam, amount: float64(d.JamTrip) * d.HargaPerJam,
+		})
+	}
+	gross := salary.GrossSalary
+	if gross == 0 {
+		gross = salary.Salary
+	}
+	if len(earnings) == 0 && gross != 0 {
+		earnings = append(earnings, payslipLine{note: "Gaji pokok", amount: gross})
+	}
+	for _, k := range kasbons {
+		deductions = append(deductions, payslipLine{date: k.Tanggal.Format("02/01/2006"), note: "Kasbon " + k.Keterangan, amount: k.Jumlah})
+	}
+	// Kasbon lama sudah muncul dari tabel kasbons; dari ledger cukup cicilan.
+	for _, r := range installments {
+		if r.Source == entity.RepaymentSalary {
+			continue
+		}
+		date := r.Tanggal
+		if t, ok := parseDay(date); ok {
+			date = t.Format("02/01/2006")
+		}
+		deductions = append(deductions, payslipLine{date: date, note: "Cicilan kasbon " + r.Keterangan, amount: r.Amount})
+	}
+	loan := salary.Loan
+	if len(deductions) == 0 && loan != 0 {
+		deductions = append(deductions, payslipLine{note: "Potongan", amount: loan})
+	}
+	statutory, err := s.statutory.Lines(salary.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	var contributions []payslipLine
+	for _, l := range statutory {
+		line := payslipLine{note: fmt.Sprintf("%s (%s%%)", l.Name, formatHours(l.Rate)), amount: l.Amount}
+		if l.Party == entity.PartyEmployee {
+			deductions = append(deductions, line)
+		} else {
+			contributions = append(contributions, line)
+		}
+	}
+
+	doc := pdfdoc.New()
+	const left, right = 40.0, pdfdoc.A4Width - 40
+	company := strings.TrimSpace(user.CompanyName)
+	if company == "" {
+		company = user.Name
+	}
+
+	// Kop: logo + nama perusahaan di kiri, judul & periode di kanan.
+	textX := left
+	if logo := s.logo(user.CompanyLogo); logo != nil {
+		if doc.Image(logo, left, 36, 70, 48) == nil {
+			textX = left + 82
+		}
+	}
+	doc.Text(textX, 58, 15, true, company)
+	doc.Text(textX, 74, 9, false, user.Email)
+	doc.TextRight(right, 58, 18, true, "SLIP GAJI")
+	doc.TextRight(right, 74, 10, false, "Periode: "+monthLabel(salary.Month))
+	doc.Line(left, 92, right, 92, 1.2)
+
+	y := 114.0
+	info := [][2]string{
+		{"Nama", member.FullName},
+		{"Jabatan", member.Role},
+		{"ID Anggota", member.ID},
+		{"Status", salary.Status},
+	}
+	for _, row := range info {
+		doc.Text(left, y, 10, false, row[0])
+		doc.Text(left+90, y, 10, false, ": "+row[1])
+		y += 15
+	}
+	doc.TextRight(right, 114, 9, false, "Dicetak: "+today().Format("02/01/2006"))
+
+	gray := color.RGBA{230, 230, 230, 255}
+	ensureSpace := func(need float64) {
+		if y+need > pdfdoc.A4Height-50 {
+			doc.AddPage()
+			y = 50
+		}
+	}
+	section := func(title string, header []string) {
+		ensureSpace(50)
+		y += 14
+		doc.Text(left, y, 11, true, title)
+		y += 8
+		doc.FillRect(left, y, right-left, 16, gray)
+		doc.Text(left+4, y+11, 9, true, header[0])
+		doc.Text(left+74, y+11, 9, true, header[1])
+		if header[2] != "" {
+			doc.TextRight(right-170, y+11, 9, true, header[2])
+			doc.TextRight(right-90, y+11, 9, true, header[3])
+		}
+		doc.TextRight(right-4, y+11, 9, true, header[4])
+		y += 16
+	}
+	total := func(label string, amount float64) {
+		ensureSpace(20)
+		doc.Line(left, y+2, right, y+2, 0.5)
+		y += 15
+		doc.Text(left+4, y, 10, true, label)
+		doc.TextRight(right-4, y, 10, true, formatRupiah(amount))
+		y += 6
+	}
+	truncate := func(s string, width float64) string {
+		if pdfdoc.TextWidth(s, 9, false) <= width {
+			return s
+		}
+		r := []rune(s)
+		for len(r) > 0 && pdfdoc.TextWidth(string(r)+"...", 9, false) > width {
+			r = r[:len(r)-1]
+		}
+		return strings.TrimSpace(string(r)) + "..."
+	}
+
+	section("Pendapatan", []string{"Tanggal", "Keterangan", "Jam/Trip", "Harga/Jam", "Subtotal"})
+	for _, l := range earnings {
+		ensureSpace(15)
+		y += 14
+		doc.Text(left+4, y, 9, false, l.date)
+		doc.Text(left+74, y, 9, false, truncate(l.note, right-left-74-180))
+		if l.rate != 0 || l.hours != 0 {
+			doc.TextRight(right-170, y, 9, false, formatHours(l.hours))
+			doc.TextRight(right-90, y, 9, false, formatRupiah(l.rate))
+		}
+		doc.TextRight(right-4, y, 9, false, formatRupiah(l.amount))
+	}
+	total("Total Pendapatan (Kotor)", gross)
+
+	section("Potongan", []string{"Tanggal", "Keterangan", "", "", "Jumlah"})
+	for _, l := range deductions {
+		ensureSpace(15)
+		y += 14
+		doc.Text(left+4, y, 9, false, l.date)
+		doc.Text(left+74, y, 9, false, truncate(l.note, right-left-74-100))
+		doc.TextRight(right-4, y, 9, false, formatRupiah(l.amount))
+	}
+	if len(deductions) == 0 {
+		y += 14
+		doc.Text(left+74, y, 9, false, "Tidak ada potongan")
+	}
+	total("Total Potongan", loan+salary.Statutory)
+
+	if len(contributions) > 0 {
+		section("Ditanggung Perusahaan (tidak memotong gaji)", []string{"", "Keterangan", "", "", "Jumlah"})
+		for _, l := range contributions {
+			ensureSpace(15)
+			y += 14
+			doc.Text(left+74, y, 9, false, l.note)
+			doc.TextRight(right-4, y, 9, false, formatRupiah(l.amount))
+		}
+		y += 6
+	}
+
+	ensureSpace(120)
+	y += 18
+	doc.FillRect(left, y, right-left, 26, color.RGBA{214, 234, 248, 255})
+	doc.Text(left+8, y+17, 12, true, "GAJI BERSIH")
+	doc.TextRight(right-8, y+17, 12, true, formatRupiah(salary.NetSalary))
+	y += 70
+
+	doc.Text(left, y, 9, false, "Penerima,")
+	doc.TextRight(right, y, 9, false, company+",")
+	y += 50
+	doc.Text(left, y, 9, true, member.FullName)
+	doc.Line(right-150, y+2, right, y+2, 0.5)
+	y += 30
+	doc.Text(left, y, 8, false, fmt.Sprintf("Slip ini dibuat otomatis oleh sistem %s dan sah tanpa tanda tangan basah.", company))
+
+	return &Payslip{FileName: payslipFileName(salary, member), Data: doc.Bytes()}, nil
+}
+
+// logo membaca CompanyLogo ("/uploads/logos/x.png") dari upload dir; nil jika tidak ada atau bukan file lokal.
+func (s *payslipService) logo(path string) []byte {
+	rel := strings.TrimPrefix(strings.TrimSpace(path), "/uploads/")
+	if rel == "" || rel == path || strings.Contains(rel, "..") || strings.EqualFold(filepath.Ext(rel), ".svg") {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(s.uploadDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (s *payslipService) Store(salaryID, userID uint) (*entity.Salary, *Payslip, error) {
+	salary, member, err := s.owned(salaryID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	slip, err := s.render(salary, member, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(s.uploadDir, os.ModePerm); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(s.uploadDir, slip.FileName), slip.Data, 0o644); err != nil {
+		return nil, nil, err
+	}
+	var documents []string
+	_ = json.Unmarshal(salary.Documents, &documents)
+	for _, d := range documents {
+		if d == slip.FileName {
+			return salary, slip, nil
+		}
+	}
+	documents = append(documents, slip.FileName)
+	if err := s.salaryService.UpdateDocuments(salary, documents); err != nil {
+		return nil, nil, err
+	}
+	return salary, slip, nil
+}
+
+func (s *payslipService) ExportMonth(userID uint, month string) (*Payslip, error) {
+	if !validMonth(month) {
+		return nil, ErrPayrollMonth
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	count := 0
+	for i := range members {
+		salaries, err := s.salaryRepo.FindByMemberID(members[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for j := range salaries {
+			if salaryMonthKey(salaries[j].Month) != month {
+				continue
+			}
+			slip, err := s.render(&salaries[j], &members[i], user)
+			if err != nil {
+				return nil, err
+			}
+			w, err := zw.Create(slip.FileName)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := w.Write(slip.Data); err != nil {
+				return nil, err
+			}
+			count++
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrPayslipEmpty
+	}
+	return &Payslip{FileName: fmt.Sprintf("slip-gaji-%s.zip", month), Data: buf.Bytes()}, nil
+}
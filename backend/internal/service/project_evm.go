@@ -0,0 +1,252 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+)
+
+// Sumber kurva planned value.
+const (
+	PlanSourceDaily  = "daily"  // kumulatif plan laporan harian
+	PlanSourceLinear = "linear" // BAC dibagi rata dari StartDate sampai EndDate
+)
+
+const dayLayout = "2006-01-02"
+
+// parseDay membaca tanggal YYYY-MM-DD (juga format dengan jam, mis. 2024-01-05T00:00:00Z).
+func parseDay(s string) (time.Time, bool) {
+	if len(s) > 10 {
+		s = s[:10]
+	}
+	t, err := time.Parse(dayLayout, s)
+	return t, err == nil
+}
+
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// GetEarnedValue analisis EVM satu proyek sampai asOf, lengkap dengan kurva PV/EV/AC.
+func (s *projectReportService) GetEarnedValue(projectID, userID uint, asOf time.Time) (*entity.EarnedValue, error) {
+	project, rows, err := s.dailyRows(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.expenseRepo.FindByProjectID(int(project.ID))
+	if err != nil {
+		return nil, err
+	}
+	ev := computeEarnedValue(project, rows, expenses, asOf)
+	return &ev, nil
+}
+
+// GetEarnedValueSummaries ringkasan EVM semua proyek user (tanpa kurva) untuk dashboard.
+func (s *projectReportService) GetEarnedValueSummaries(userID uint, asOf time.Time) ([]entity.EarnedValue, error) {
+	projects, err := s.projectRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(projects))
+	projectIDs := make([]uint, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, int(p.ID))
+		projectIDs = append(projectIDs, p.ID)
+	}
+	table, err := s.dailyRepo.FindByProjectIDs(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	daily := map[uint][]entity.ReportDaily{}
+	for _, r := range table {
+		daily[r.ProjectID] = append(daily[r.ProjectID], r)
+	}
+	expenses, err := s.expenseRepo.FindByProjectIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byProject := map[int][]entity.ProjectExpense{}
+	for _, e := range expenses {
+		byProject[e.ProjectID] = append(byProject[e.ProjectID], e)
+	}
+	out := make([]entity.EarnedValue, 0, len(projects))
+	for i := range projects {
+		rows, _, err := withLegacyDaily(&projects[i], daily[projects[i].ID])
+		if err != nil {
+			rows = daily[projects[i].ID] // reports lama tidak terbaca: hitung dari tabel saja
+		}
+		rows = validDailyRows(rows)
+		ev := computeEarnedValue(&projects[i], rows, byProject[int(projects[i].ID)], asOf)
+		ev.Curve = nil
+		out = append(out, ev)
+	}
+	return out, nil
+}
+
+// computeEarnedValue menghitung PV/EV/AC kumulatif per tanggal sampai asOf lalu indikator di asOf.
+//   - Nilai per satuan volume = UnitPrice, atau BAC / volume baseline jika UnitPrice kosong.
+//   - PV dari kumulatif plan harian; jika tidak ada plan sama sekali, linear StartDate–EndDate.
+//   - EV = kumulatif aktual × nilai per satuan; AC = kumulatif ProjectExpense (semua status).
+func computeEarnedValue(project *entity.Project, rows []entity.ReportDaily, expenses []entity.ProjectExpense, asOf time.Time) entity.EarnedValue {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	res := entity.EarnedValue{ProjectID: project.ID, ProjectName: project.Name, AsOf: asOf.Format(dayLayout), Curve: []entity.EarnedValuePoint{}}
+
+	var totalPlan float64
+	for _, r := range rows {
+		totalPlan += r.Plan
+	}
+	baseline := project.TotalVolume
+	if baseline <= 0 {
+		baseline = totalPlan
+	}
+	res.BAC = project.TotalVolume * project.UnitPrice
+	if res.BAC <= 0 {
+		res.BAC = project.TotalRevenue
+	}
+	valuePerUnit := project.UnitPrice
+	if valuePerUnit <= 0 && baseline > 0 {
+		valuePerUnit = res.BAC / baseline
+	}
+
+	start, hasStart := parseDay(project.StartDate)
+	end, hasEnd := parseDay(project.EndDate)
+	linear := totalPlan <= 0 && hasStart && hasEnd && !end.Before(start)
+	res.PlanSource = PlanSourceDaily
+	if linear {
+		res.PlanSource = PlanSourceLinear
+	}
+	plannedPV := func(d time.Time, cumPlan float64) float64 {
+		if !linear {
+			return cumPlan * valuePerUnit
+		}
+		total := daysBetween(start, end) + 1
+		elapsed := daysBetween(start, d) + 1
+		if elapsed <= 0 {
+			return 0
+		}
+		if elapsed >= total {
+			return res.BAC
+		}
+		return res.BAC * float64(elapsed) / float64(total)
+	}
+
+	// Titik kurva: semua tanggal laporan & pengeluaran sampai asOf, ditambah asOf sendiri.
+	type dayDelta struct{ plan, aktual, cost float64 }
+	deltas := map[string]*dayDelta{}
+	at := func(date string) *dayDelta {
+		if deltas[date] == nil {
+			deltas[date] = &dayDelta{}
+		}
+		return deltas[date]
+	}
+	for _, r := range rows {
+		if d, ok := parseDay(r.Date); ok && !d.After(asOf) {
+			at(r.Date).plan += r.Plan
+			at(r.Date).aktual += r.Aktual
+		}
+	}
+	for _, e := range expenses {
+		if d, ok := parseDay(e.Tanggal); ok && !d.After(asOf) {
+			at(d.Format(dayLayout)).cost += e.Jumlah
+		}
+	}
+	at(res.AsOf)
+	dates := make([]string, 0, len(deltas))
+	for d := range deltas {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	var cumPlan, cumAktual, cumCost float64
+	completedOn := ""
+	for _, date := range dates {
+		dd := deltas[date]
+		cumPlan += dd.plan
+		cumAktual += dd.aktual
+		cumCost += dd.cost
+		if completedOn == "" && project.TotalVolume > 0 && cumAktual >= project.TotalVolume {
+			completedOn = date
+		}
+		d, _ := parseDay(date)
+		res.Curve = append(res.Curve, entity.EarnedValuePoint{
+			Date: date,
+			PV:   round2(plannedPV(d, cumPlan)),
+			EV:   round2(cumAktual * valuePerUnit),
+			AC:   round2(cumCost),
+		})
+	}
+	last := res.Curve[len(res.Curve)-1]
+	res.PV, res.EV, res.AC = last.PV, last.EV, last.AC
+	res.ActualVolume = cumAktual
+
+	res.SV = round2(res.EV - res.PV)
+	res.CV = round2(res.EV - res.AC)
+	if res.PV > 0 {
+		res.SPI = round2(res.EV / res.PV)
+	}
+	if res.AC > 0 {
+		res.CPI = round2(res.EV / res.AC)
+	}
+	remaining := math.Max(res.BAC-res.EV, 0)
+	if res.CPI > 0 {
+		res.EAC = round2(res.AC + remaining/(res.EV/res.AC))
+	} else {
+		res.EAC = round2(res.AC + remaining) // belum ada data biaya/progres: anggap sisa sesuai anggaran
+	}
+	res.ETC = round2(res.EAC - res.AC)
+	res.VAC = round2(res.BAC - res.EAC)
+	if res.BAC > 0 {
+		res.PlannedPercent = round2(res.PV / res.BAC * 100)
+	}
+	if baseline > 0 {
+		res.ProgressPercent = round2(cumAktual / baseline * 100)
+	}
+
+	// Rencana selesai: EndDate proyek, atau tanggal terakhir yang punya plan.
+	plannedEnd, hasPlannedEnd := end, hasEnd
+	if !hasPlannedEnd {
+		for i := len(rows) - 1; i >= 0; i-- {
+			if rows[i].Plan > 0 {
+				plannedEnd, hasPlannedEnd = parseDay(rows[i].Date)
+				break
+			}
+		}
+	}
+	if hasPlannedEnd {
+		res.PlannedCompletionDate = plannedEnd.Format(dayLayout)
+	}
+
+	// Proyeksi selesai: tanggal volume tercapai; jika belum, durasi rencana / SPI; jika tidak bisa,
+	// sisa volume dibagi rata-rata produksi harian sejak laporan aktual pertama.
+	var projected time.Time
+	switch {
+	case completedOn != "":
+		projected, _ = parseDay(completedOn)
+	case res.SPI > 0 && hasStart && hasPlannedEnd && !plannedEnd.Before(start):
+		planned := float64(daysBetween(start, plannedEnd) + 1)
+		projected = start.AddDate(0, 0, int(math.Ceil(planned/(res.EV/res.PV)))-1)
+	default:
+		first := ""
+		for _, r := range rows {
+			if r.Aktual > 0 {
+				first = r.Date
+				break
+			}
+		}
+		if d, ok := parseDay(first); ok && project.TotalVolume > cumAktual && !d.After(asOf) {
+			rate := cumAktual / float64(daysBetween(d, asOf)+1)
+			if rate > 0 {
+				projected = asOf.AddDate(0, 0, int(math.Ceil((project.TotalVolume-cumAktual)/rate)))
+			}
+		}
+	}
+	if !projected.IsZero() {
+		res.ProjectedCompletionDate = projected.Format(dayLayout)
+		if hasPlannedEnd {
+			res.DelayDays = daysBetween(plannedEnd, projected)
+		}
+	}
+	return res
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+var (
+	ErrMilestoneInvalid          = errors.New("termin tidak valid: nama wajib, persen 0–100 atau nominal > 0, trigger progress 0–100")
+	ErrMilestonePercentExceeded  = errors.New("total persen termin melebihi 100%")
+	ErrMilestoneNotReached       = errors.New("termin belum tercapai; belum bisa ditagih")
+	ErrMilestoneAlreadyInvoiced  = errors.New("termin sudah dibuatkan invoice")
+	ErrMilestoneCustomerRequired = errors.New("customer wajib diisi untuk membuat invoice termin")
+	ErrMilestoneTemplateRequired = errors.New("belum ada template invoice; buat template dulu atau kirim templateId")
+)
+
+// KategoriTermin kategori ProjectIncome yang dibuat dari termin.
+const KategoriTermin = "Termin"
+
+type ProjectMilestoneService interface {
+	List(projectID, userID uint) ([]entity.ProjectMilestone, error)
+	Create(projectID, userID uint, milestone *entity.ProjectMilestone) error
+	Update(projectID, userID, id uint, milestone *entity.ProjectMilestone) error
+	Delete(projectID, userID, id uint) error
+	CreateInvoiceDraft(projectID, userID, id uint, req entity.MilestoneInvoiceRequest) (*entity.Invoice, error)
+}
+
+type projectMilestoneService struct {
+	repo           repository.ProjectMilestoneRepository
+	projectService ProjectService
+	customerRepo   repository.CustomerRepository
+	templateRepo   repository.InvoiceTemplateRepository
+}
+
+func NewProjectMilestoneService(repo repository.ProjectMilestoneRepository, projectService ProjectService,
+	customerRepo repository.CustomerRepository, templateRepo repository.InvoiceTemplateRepository) ProjectMilestoneService {
+	return &projectMilestoneService{repo, projectService, customerRepo, templateRepo}
+}
+
+// contractValue nilai kontrak untuk termin persen: TotalRevenue, atau TotalVolume × UnitPrice.
+func contractValue(p *entity.Project) float64 {
+	if p.TotalRevenue > 0 {
+		return p.TotalRevenue
+	}
+	return p.TotalVolume * p.UnitPrice
+}
+
+// progressTimeline progress volume kumulatif (%) per tanggal laporan. Baseline = TotalVolume, atau total plan.
+type progressTimeline struct {
+	dates   []string
+	percent []float64
+}
+
+func newProgressTimeline(project *entity.Project, rows []entity.ReportDaily) progressTimeline {
+	baseline := project.TotalVolume
+	if baseline <= 0 {
+		for _, r := range rows {
+			baseline += r.Plan
+		}
+	}
+	var t progressTimeline
+	var cum float64
+	for _, r := range rows {
+		cum += r.Aktual
+		pct := 0.0
+		if baseline > 0 {
+			pct = cum / baseline * 100
+		}
+		t.dates = append(t.dates, r.Date)
+		t.percent = append(t.percent, pct)
+	}
+	return t
+}
+
+func (t progressTimeline) current() float64 {
+	if len(t.percent) == 0 {
+		return 0
+	}
+	return round2(t.percent[len(t.percent)-1])
+}
+
+// reachedOn tanggal pertama progress >= target ("" jika belum).
+func (t progressTimeline) reachedOn(target float64) string {
+	for i, p := range t.percent {
+		if p+1e-9 >= target {
+			return t.dates[i]
+		}
+	}
+	return ""
+}
+
+func (s *projectMilestoneService) load(projectID, userID uint) (*entity.Project, progressTimeline, error) {
+	project, err := s.projectService.GetProjectByID(projectID, userID)
+	if err != nil {
+		return nil, progressTimeline{}, err
+	}
+	rows, err := dailyRowsOf(project)
+	if err != nil {
+		return nil, progressTimeline{}, err
+	}
+	return project, newProgressTimeline(project, rows), nil
+}
+
+// normalize validasi input dan hitung nominal dari persen.
+func normalizeMilestone(project *entity.Project, m *entity.ProjectMilestone) error {
+	m.Name = strings.TrimSpace(m.Name)
+	if m.AmountType == "" {
+		m.AmountType = entity.MilestoneAmountPercent
+	}
+	if m.Name == "" || m.TriggerProgress < 0 || m.TriggerProgress > 100 || m.HoldDays < 0 {
+		return ErrMilestoneInvalid
+	}
+	if m.PlannedDate != "" {
+		if _, ok := parseDay(m.PlannedDate); !ok {
+			return ErrMilestoneInvalid
+		}
+	}
+	switch m.AmountType {
+	case entity.MilestoneAmountPercent:
+		if m.Percent <= 0 || m.Percent > 100 {
+			return ErrMilestoneInvalid
+		}
+		m.Amount = round2(contractValue(project) * m.Percent / 100)
+	case entity.MilestoneAmountFixed:
+		if m.Amount <= 0 {
+			return ErrMilestoneInvalid
+		}
+		m.Percent = 0
+		if v := contractValue(project); v > 0 {
+			m.Percent = round2(m.Amount / v * 100)
+		}
+	default:
+		return ErrMilestoneInvalid
+	}
+	return nil
+}
+
+// checkPercentTotal total persen termin (termasuk m, tanpa baris dengan excludeID) tidak boleh > 100.
+func checkPercentTotal(existing []entity.ProjectMilestone, m *entity.ProjectMilestone, excludeID uint) error {
+	total := m.Percent
+	for _, e := range existing {
+		if e.ID != excludeID {
+			total += e.Percent
+		}
+	}
+	if total > 100.0001 {
+		return ErrMilestonePercentExceeded
+	}
+	return nil
+}
+
+func incomeDate(project *entity.Project, m *entity.ProjectMilestone) string {
+	if m.PlannedDate != "" {
+		return m.PlannedDate
+	}
+	if d, ok := parseDay(project.EndDate); ok {
+		return d.Format(dayLayout)
+	}
+	return time.Now().Format(dayLayout)
+}
+
+func incomeDescription(m *entity.ProjectMilestone) string {
+	return fmt.Sprintf("Termin %d: %s", m.Sequence, m.Name)
+}
+
+// List daftar termin proyek. Termin pending yang syaratnya sudah terpenuhi tampil reached dan nominal termin persen
+// yang belum ditagih mengikuti nilai kontrak terbaru. Hanya dihitung, tidak disimpan (lihat CreateInvoiceDraft).
+func (s *projectMilestoneService) List(projectID, userID uint) ([]entity.ProjectMilestone, error) {
+	project, timeline, err := s.load(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	milestones, err := s.repo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().Format(dayLayout)
+	for i := range milestones {
+		evaluateMilestone(project, timeline, &milestones[i], today)
+	}
+	return milestones, nil
+}
+
+// evaluateMilestone mengisi progress, tanggal bisa ditagih, status reached dan nominal terbaru termin di memori.
+func evaluateMilestone(project *entity.Project, timeline progressTimeline, m *entity.ProjectMilestone, today string) {
+	m.CurrentProgress = timeline.current()
+	reached := timeline.reachedOn(m.TriggerProgress)
+	if m.TriggerProgress <= 0 {
+		reached = m.CreatedAt.Format(dayLayout)
+		if d, ok := parseDay(project.StartDate); ok {
+			reached = d.Format(dayLayout)
+		}
+	}
+	if reached != "" {
+		d, _ := parseDay(reached)
+		m.ClaimableDate = d.AddDate(0, 0, m.HoldDays).Format(dayLayout)
+	}
+	if m.Status != entity.MilestoneStatusPending {
+		return
+	}
+	if m.AmountType == entity.MilestoneAmountPercent {
+		if amount := round2(contractValue(project) * m.Percent / 100); math.Abs(amount-m.Amount) >= 0.01 {
+			m.Amount = amount
+		}
+	}
+	if m.ClaimableDate != "" && m.ClaimableDate <= today {
+		m.Status = entity.MilestoneStatusReached
+		m.ReachedDate = &m.ClaimableDate
+	}
+}
+
+func (s *projectMilestoneService) Create(projectID, userID uint, m *entity.ProjectMilestone) error {
+	project, _, err := s.load(projectID, userID)
+	if err != nil {
+		return err
+	}
+	if err := normalizeMilestone(project, m); err != nil {
+		return err
+	}
+	existing, err := s.repo.FindByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	if err := checkPercentTotal(existing, m, 0); err != nil {
+		return err
+	}
+	m.ID, m.ProjectID, m.Status, m.ReachedDate, m.InvoiceID = 0, projectID, entity.MilestoneStatusPending, nil, nil
+	if m.Sequence <= 0 {
+		m.Sequence = len(existing) + 1
+	}
+	income := &entity.ProjectIncome{
+		ProjectID: int(projectID),
+		Tanggal:   incomeDate(project, m),
+		Kategori:  KategoriTermin,
+		Deskripsi: incomeDescription(m),
+		Jumlah:    m.Amount,
+		Status:    "Planned",
+	}
+	return s.repo.CreateWithIncome(m, income)
+}
+
+func (s *projectMilestoneService) find(projectID, id uint) (*entity.ProjectMilestone, error) {
+	m, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if m.ProjectID != projectID {
+		return nil, errors.New("termin tidak ditemukan")
+	}
+	return m, nil
+}
+
+// Update mengubah termin yang belum ditagih; income Planned terkait ikut disesuaikan.
+func (s *projectMilestoneService) Update(projectID, userID, id uint, m *entity.ProjectMilestone) error {
+	project, _, err := s.load(projectID, userID)
+	if err != nil {
+		return err
+	}
+	current, err := s.find(projectID, id)
+	if err != nil {
+		return err
+	}
+	if current.Status == entity.MilestoneStatusInvoiced {
+		return ErrMilestoneAlreadyInvoiced
+	}
+	if err := normalizeMilestone(project, m); err != nil {
+		return err
+	}
+	existing, err := s.repo.FindByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	if err := checkPercentTotal(existing, m, id); err != nil {
+		return err
+	}
+	m.ID, m.ProjectID, m.IncomeID, m.InvoiceID, m.CreatedAt = current.ID, projectID, current.IncomeID, nil, current.CreatedAt
+	m.Status, m.ReachedDate = entity.MilestoneStatusPending, nil // dievaluasi ulang saat List
+	if m.Sequence <= 0 {
+		m.Sequence = current.Sequence
+	}
+	var income *entity.ProjectIncome
+	if m.IncomeID != nil {
+		if inc, err := s.repo.FindIncome(*m.IncomeID); err == nil && inc.Status != "Received" {
+			inc.Tanggal = incomeDate(project, m)
+			inc.Deskripsi = incomeDescription(m)
+			inc.Jumlah = m.Amount
+			inc.Status = "Planned"
+			income = inc
+		}
+	}
+	return s.repo.UpdateWithIncome(m, income)
+}
+
+// Delete menghapus termin yang belum ditagih beserta income-nya jika belum diterima.
+func (s *projectMilestoneService) Delete(projectID, userID, id uint) error {
+	if _, _, err := s.load(projectID, userID); err != nil {
+		return err
+	}
+	m, err := s.find(projectID, id)
+	if err != nil {
+		return err
+	}
+	if m.Status == entity.MilestoneStatusInvoiced {
+		return ErrMilestoneAlreadyInvoiced
+	}
+	deleteIncome := false
+	if m.IncomeID != nil {
+		if inc, err := s.repo.FindIncome(*m.IncomeID); err == nil && inc.Status != "Received" {
+			deleteIncome = true
+		}
+	}
+	return s.repo.Delete(m, deleteIncome)
+}
+
+// CreateInvoiceDraft membuat draft invoice satu baris untuk termin yang sudah tercapai, lalu dalam satu transaksi
+// menandai termin invoiced (dengan nominal dan tanggal tercapai hasil evaluasi) dan mengubah income Planned → Pending.
+func (s *projectMilestoneService) CreateInvoiceDraft(projectID, userID, id uint, req entity.MilestoneInvoiceRequest) (*entity.Invoice, error) {
+	project, timeline, err := s.load(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.find(projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	evaluateMilestone(project, timeline, m, time.Now().Format(dayLayout))
+	switch m.Status {
+	case entity.MilestoneStatusInvoiced:
+		return nil, ErrMilestoneAlreadyInvoiced
+	case entity.MilestoneStatusPending:
+		return nil, ErrMilestoneNotReached
+	}
+
+	if req.CustomerID == nil {
+		req.CustomerID = project.CustomerID
+	}
+	if req.CustomerID == nil {
+		return nil, ErrMilestoneCustomerRequired
+	}
+	customer, err := s.customerRepo.FindByID(*req.CustomerID)
+	if err != nil || customer.UserID != userID {
+		return nil, ErrMilestoneCustomerRequired
+	}
+	templateID := req.TemplateID
+	if templateID == 0 {
+		templates, err := s.templateRepo.FindAll(userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(templates) == 0 {
+			return nil, ErrMilestoneTemplateRequired
+		}
+		templateID = templates[0].ID
+	} else if t, err := s.templateRepo.FindByID(templateID); err != nil || t.UserID != userID {
+		return nil, ErrMilestoneTemplateRequired
+	}
+
+	invoiceDate := req.InvoiceDate
+	if _, ok := parseDay(invoiceDate); !ok {
+		invoiceDate = time.Now().Format(dayLayout)
+	}
+	number := strings.TrimSpace(req.InvoiceNumber)
+	if number == "" {
+		number = fmt.Sprintf("TRM/%d/%d/%s", project.ID, m.Sequence, strings.ReplaceAll(invoiceDate, "-", ""))
+	}
+	desc := fmt.Sprintf("Proyek %s", project.Name)
+	if m.AmountType == entity.MilestoneAmountPercent {
+		desc = fmt.Sprintf("%s — %.2f%% dari nilai kontrak", desc, m.Percent)
+	}
+	customerID := customer.ID
+	inv := &entity.Invoice{
+		UserID:          userID,
+		TemplateID:      templateID,
+		CustomerID:      &customerID,
+		InvoiceNumber:   number,
+		InvoiceDate:     invoiceDate,
+		Status:          entity.InvoiceStatusDraft,
+		CustomerName:    customer.Name,
+		CustomerPhone:   customer.Phone,
+		CustomerEmail:   customer.Email,
+		CustomerAddress: customer.Address,
+		TaxPercent:      req.TaxPercent,
+		Subject:         fmt.Sprintf("Tagihan Termin %d", m.Sequence),
+		QuantityUnit:    "termin",
+		PriceUnitLabel:  "Nilai",
+		AttachmentsJSON: "[]",
+		Items: []entity.InvoiceItem{{
+			ItemName:    incomeDescription(m),
+			Description: desc,
+			Quantity:    1,
+			Price:       m.Amount,
+		}},
+	}
+	fillInvoiceTotals(inv)
+
+	var income *entity.ProjectIncome
+	if m.IncomeID != nil {
+		if inc, err := s.repo.FindIncome(*m.IncomeID); err == nil && inc.Status == "Planned" {
+			inc.Jumlah, inc.Status = m.Amount, "Pending"
+			income = inc
+		}
+	}
+	m.Status = entity.MilestoneStatusInvoiced
+	created, err := s.repo.CreateInvoice(m, income, inv)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrMilestoneAlreadyInvoiced
+	}
+	return inv, nil
+}
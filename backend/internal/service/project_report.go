@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+
+	"gorm.io/datatypes"
+)
+
+// splitReports memisahkan reports.daily dari dokumen Project.Reports. hasDaily=false jika dokumen kosong
+// atau tidak punya key "daily" (klien tidak mengirim laporan harian → tabel tidak disentuh).
+// rest = dokumen tanpa "daily" (weekly, monthly, _smartNota, ...) untuk disimpan di kolom reports.
+func splitReports(raw datatypes.JSON) (daily []entity.ReportDaily, rest datatypes.JSON, hasDaily bool, err error) {
+	if len(raw) == 0 || strings.TrimSpace(string(raw)) == "null" {
+		return nil, raw, false, nil
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, nil, false, fmt.Errorf("reports bukan objek JSON: %w", err)
+	}
+	dailyRaw, ok := doc["daily"]
+	if !ok {
+		return nil, raw, false, nil
+	}
+	delete(doc, "daily")
+	restJSON, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var items []map[string]interface{}
+	if len(dailyRaw) > 0 && string(dailyRaw) != "null" {
+		if err := json.Unmarshal(dailyRaw, &items); err != nil {
+			return nil, nil, false, fmt.Errorf("reports.daily bukan array: %w", err)
+		}
+	}
+	// Tanggal ganda: entri terakhir yang dipakai (sama seperti frontend yang menimpa per tanggal).
+	index := map[string]int{}
+	for _, m := range items {
+		r, err := dailyFromMap(m)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if i, dup := index[r.Date]; dup {
+			daily[i] = r
+			continue
+		}
+		index[r.Date] = len(daily)
+		daily = append(daily, r)
+	}
+	return daily, datatypes.JSON(restJSON), true, nil
+}
+
+// dailyFromMap membaca satu entri reports.daily secara longgar (angka boleh string, field boleh hilang).
+func dailyFromMap(m map[string]interface{}) (entity.ReportDaily, error) {
+	date := strings.TrimSpace(fmt.Sprint(m["date"]))
+	if len(date) >= 10 {
+		date = date[:10]
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return entity.ReportDaily{}, fmt.Errorf("reports.daily: tanggal tidak valid %q", m["date"])
+	}
+	r := entity.ReportDaily{
+		Date:           date,
+		Revenue:        toFloat(m["revenue"]),
+		Paid:           toFloat(m["paid"]),
+		Volume:         toFloat(m["volume"]),
+		TargetVolume:   toFloat(m["targetVolume"]),
+		Plan:           toFloat(m["plan"]),
+		Aktual:         toFloat(m["aktual"]),
+		Workers:        toJSONObject(m["workers"]),
+		Equipment:      toJSONObject(m["equipment"]),
+		TotalWorkers:   int(toFloat(m["totalWorkers"])),
+		TotalEquipment: int(toFloat(m["totalEquipment"])),
+		Ritase:         toFloat(m["ritase"]),
+		Cuaca:          toString(m["cuaca"]),
+		Catatan:        toString(m["catatan"]),
+		CreatedAt:      int64(toFloat(m["createdAt"])),
+		UpdatedAt:      int64(toFloat(m["updatedAt"])),
+	}
+	if imgs, ok := m["images"].([]interface{}); ok {
+		for _, it := range imgs {
+			im, ok := it.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			r.Images = append(r.Images, entity.DailyReportImage{
+				ID:          uint(toFloat(im["id"])),
+				ImagePath:   toString(im["imagePath"]),
+				Description: toString(im["description"]),
+				CreatedAt:   int64(toFloat(im["createdAt"])),
+				UpdatedAt:   int64(toFloat(im["updatedAt"])),
+			})
+		}
+	}
+	return r, nil
+}
+
+// reportsView menyusun kembali bentuk lama Project.Reports ({daily, weekly, monthly, ...}) dari kolom reports + tabel.
+func reportsView(rest datatypes.JSON, daily []entity.ReportDaily) datatypes.JSON {
+	doc := map[string]interface{}{}
+	if len(rest) > 0 {
+		_ = json.Unmarshal(rest, &doc)
+		if doc == nil {
+			doc = map[string]interface{}{}
+		}
+	}
+	if len(daily) == 0 && len(doc) == 0 {
+		return rest
+	}
+	sort.SliceStable(daily, func(i, j int) bool { return daily[i].Date < daily[j].Date })
+	for i := range daily {
+		if len(daily[i].Workers) == 0 {
+			daily[i].Workers = datatypes.JSON("{}")
+		}
+		if len(daily[i].Equipment) == 0 {
+			daily[i].Equipment = datatypes.JSON("{}")
+		}
+		if daily[i].Images == nil {
+			daily[i].Images = []entity.DailyReportImage{}
+		}
+	}
+	if daily == nil {
+		daily = []entity.ReportDaily{}
+	}
+	doc["daily"] = daily
+	for _, k := range []string{"weekly", "monthly"} {
+		if doc[k] == nil {
+			doc[k] = []interface{}{}
+		}
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return rest
+	}
+	return datatypes.JSON(b)
+}
+
+func toFloat(v interface{}) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f
+	case bool:
+		if t {
+			return 1
+		}
+	}
+	return 0
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func toJSONObject(v interface{}) datatypes.JSON {
+	if m, ok := v.(map[string]interface{}); ok {
+		if b, err := json.Marshal(m); err == nil {
+			return datatypes.JSON(b)
+		}
+	}
+	return datatypes.JSON("{}")
+}
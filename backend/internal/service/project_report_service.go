@@ -0,0 +1,279 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+)
+
+// Interval kurva S.
+const (
+	SCurveDaily   = "daily"
+	SCurveWeekly  = "weekly"
+	SCurveMonthly = "monthly"
+)
+
+var ErrInvalidInterval = errors.New("interval harus daily, weekly atau monthly")
+
+// SCurve kurva S proyek. Baseline = TotalVolume proyek, atau total plan harian jika TotalVolume kosong.
+type SCurve struct {
+	ProjectID uint                 `json:"projectId"`
+	Interval  string               `json:"interval"`
+	Baseline  float64              `json:"baseline"`
+	Points    []entity.SCurvePoint `json:"points"`
+}
+
+// ProjectReportService menghitung rekap mingguan/bulanan dan kurva S langsung dari laporan harian,
+// sehingga weekly/monthly tidak perlu lagi diisi manual.
+type ProjectReportService interface {
+	GetWeeklyReports(projectID, userID uint) ([]entity.ReportWeekly, error)
+	GetMonthlyReports(projectID, userID uint) ([]entity.ReportMonthly, error)
+	GetSCurve(projectID, userID uint, interval string) (*SCurve, error)
+	GetEarnedValue(projectID, userID uint, asOf time.Time) (*entity.EarnedValue, error)
+	GetEarnedValueSummaries(userID uint, asOf time.Time) ([]entity.EarnedValue, error)
+}
+
+type projectReportService struct {
+	projectRepo repository.ProjectRepository
+	dailyRepo   repository.DailyReportRepository
+	expenseRepo repository.ProjectExpenseRepository
+}
+
+func NewProjectReportService(projectRepo repository.ProjectRepository, dailyRepo repository.DailyReportRepository, expenseRepo repository.ProjectExpenseRepository) ProjectReportService {
+	return &projectReportService{projectRepo, dailyRepo, expenseRepo}
+}
+
+// dailyRows laporan harian proyek (tabel + entri JSON lama yang belum dimigrasi), urut tanggal.
+func (s *projectReportService) dailyRows(projectID, userID uint) (*entity.Project, []entity.ReportDaily, error) {
+	project, err := s.projectRepo.FindByID(projectID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	table, err := s.dailyRepo.FindByProjectID(project.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, _, err := withLegacyDaily(project, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	return project, validDailyRows(rows), nil
+}
+
+// dailyRowsOf baris harian dari view Project.Reports, urut tanggal. Baris dengan tanggal tidak valid dilewati.
+func dailyRowsOf(project *entity.Project) ([]entity.ReportDaily, error) {
+	daily, _, _, err := splitReports(project.Reports)
+	if err != nil {
+		return nil, err
+	}
+	return validDailyRows(daily), nil
+}
+
+// validDailyRows baris bertanggal valid, urut tanggal.
+func validDailyRows(daily []entity.ReportDaily) []entity.ReportDaily {
+	rows := make([]entity.ReportDaily, 0, len(daily))
+	for _, r := range daily {
+		if _, err := time.Parse("2006-01-02", r.Date); err == nil {
+			rows = append(rows, r)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Date < rows[j].Date })
+	return rows
+}
+
+type reportBucket struct {
+	key  string
+	rows []entity.ReportDaily
+}
+
+// groupRows mengelompokkan baris (sudah urut tanggal) berdasarkan key; urutan bucket mengikuti tanggal.
+func groupRows(rows []entity.ReportDaily, keyOf func(time.Time) string) []reportBucket {
+	var buckets []reportBucket
+	index := map[string]int{}
+	for _, r := range rows {
+		d, _ := time.Parse("2006-01-02", r.Date)
+		k := keyOf(d)
+		i, ok := index[k]
+		if !ok {
+			i = len(buckets)
+			index[k] = i
+			buckets = append(buckets, reportBucket{key: k})
+		}
+		buckets[i].rows = append(buckets[i].rows, r)
+	}
+	return buckets
+}
+
+func isoWeekKey(d time.Time) string {
+	y, w := d.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", y, w)
+}
+
+func monthKey(d time.Time) string {
+	return d.Format("2006-01")
+}
+
+// periodTotals plan/aktual dijumlah; volume dan targetVolume diambil dari hari terakhir periode (nilai kumulatif).
+type periodTotals struct {
+	plan, aktual, volume, targetVolume float64
+	rollup                             entity.ReportRollup
+}
+
+func summarize(rows []entity.ReportDaily) periodTotals {
+	t := periodTotals{rollup: entity.ReportRollup{
+		Days:      len(rows),
+		Workers:   map[string]float64{},
+		Equipment: map[string]float64{},
+	}}
+	for _, r := range rows {
+		t.plan += r.Plan
+		t.aktual += r.Aktual
+		t.rollup.TotalWorkers += r.TotalWorkers
+		t.rollup.TotalEquipment += r.TotalEquipment
+		addCounts(t.rollup.Workers, r.Workers)
+		addCounts(t.rollup.Equipment, r.Equipment)
+	}
+	if n := len(rows); n > 0 {
+		last := rows[n-1]
+		t.volume, t.targetVolume = last.Volume, last.TargetVolume
+		t.rollup.AvgWorkers = round2(float64(t.rollup.TotalWorkers) / float64(n))
+		t.rollup.AvgEquipment = round2(float64(t.rollup.TotalEquipment) / float64(n))
+	}
+	t.rollup.AvgWorkersByType = averageCounts(t.rollup.Workers, len(rows))
+	t.rollup.AvgEquipmentByType = averageCounts(t.rollup.Equipment, len(rows))
+	return t
+}
+
+// addCounts menambahkan objek JSON {"operator": 2, ...} ke total per jenis. Nilai non-angka diabaikan.
+func addCounts(dst map[string]float64, raw []byte) {
+	if len(raw) == 0 {
+		return
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return
+	}
+	for k, v := range m {
+		dst[k] += toFloat(v)
+	}
+}
+
+func averageCounts(totals map[string]float64, days int) map[string]float64 {
+	out := make(map[string]float64, len(totals))
+	for k, v := range totals {
+		if days > 0 {
+			out[k] = round2(v / float64(days))
+		}
+	}
+	return out
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func (s *projectReportService) GetWeeklyReports(projectID, userID uint) ([]entity.ReportWeekly, error) {
+	_, rows, err := s.dailyRows(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := []entity.ReportWeekly{}
+	var cumPlan, cumAktual float64
+	for _, b := range groupRows(rows, isoWeekKey) {
+		t := summarize(b.rows)
+		cumPlan += t.plan
+		cumAktual += t.aktual
+		t.rollup.CumulativePlan, t.rollup.CumulativeAktual = cumPlan, cumAktual
+		first, last := b.rows[0].Date, b.rows[len(b.rows)-1].Date
+		out = append(out, entity.ReportWeekly{
+			Week:         b.key,
+			Label:        first + " s/d " + last,
+			StartDate:    first,
+			EndDate:      last,
+			TargetPlan:   t.plan,
+			TargetAktual: t.aktual,
+			Volume:       t.volume,
+			TargetVolume: t.targetVolume,
+			ReportRollup: t.rollup,
+		})
+	}
+	return out, nil
+}
+
+func (s *projectReportService) GetMonthlyReports(projectID, userID uint) ([]entity.ReportMonthly, error) {
+	_, rows, err := s.dailyRows(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := []entity.ReportMonthly{}
+	var cumPlan, cumAktual float64
+	for _, b := range groupRows(rows, monthKey) {
+		t := summarize(b.rows)
+		cumPlan += t.plan
+		cumAktual += t.aktual
+		t.rollup.CumulativePlan, t.rollup.CumulativeAktual = cumPlan, cumAktual
+		out = append(out, entity.ReportMonthly{
+			Month:        b.key,
+			StartDate:    b.rows[0].Date,
+			EndDate:      b.rows[len(b.rows)-1].Date,
+			TargetPlan:   t.plan,
+			TargetAktual: t.aktual,
+			Volume:       t.volume,
+			TargetVolume: t.targetVolume,
+			ReportRollup: t.rollup,
+		})
+	}
+	return out, nil
+}
+
+// GetSCurve rencana vs aktual kumulatif per hari/minggu/bulan. Persentase terhadap baseline (0 jika baseline 0).
+func (s *projectReportService) GetSCurve(projectID, userID uint, interval string) (*SCurve, error) {
+	if interval == "" {
+		interval = SCurveDaily
+	}
+	var keyOf func(time.Time) string
+	switch interval {
+	case SCurveDaily:
+		keyOf = func(d time.Time) string { return d.Format("2006-01-02") }
+	case SCurveWeekly:
+		keyOf = isoWeekKey
+	case SCurveMonthly:
+		keyOf = monthKey
+	default:
+		return nil, ErrInvalidInterval
+	}
+	project, rows, err := s.dailyRows(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	curve := &SCurve{ProjectID: project.ID, Interval: interval, Baseline: project.TotalVolume, Points: []entity.SCurvePoint{}}
+	if curve.Baseline <= 0 {
+		for _, r := range rows {
+			curve.Baseline += r.Plan
+		}
+	}
+	var cumPlan, cumAktual float64
+	for _, b := range groupRows(rows, keyOf) {
+		p := entity.SCurvePoint{Period: b.key, Date: b.rows[len(b.rows)-1].Date}
+		for _, r := range b.rows {
+			p.Plan += r.Plan
+			p.Aktual += r.Aktual
+		}
+		cumPlan += p.Plan
+		cumAktual += p.Aktual
+		p.CumulativePlan, p.CumulativeAktual = cumPlan, cumAktual
+		if curve.Baseline > 0 {
+			p.PlanPercent = round2(cumPlan / curve.Baseline * 100)
+			p.AktualPercent = round2(cumAktual / curve.Baseline * 100)
+			p.Deviation = round2(p.AktualPercent - p.PlanPercent)
+		}
+		curve.Points = append(curve.Points, p)
+	}
+	return curve, nil
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"errors"
+
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/repository"
 	"dashboardadminimb/pkg/response"
+
+	"gorm.io/datatypes"
 )
 
 type ProjectService interface {
@@ -12,17 +16,39 @@ type ProjectService interface {
 	GetAllProjectsWithPagination(params response.QueryParams, userID uint) ([]entity.Project, int, error)
 	GetProjectByID(id uint, userID uint) (*entity.Project, error)
 	GetProjectByIDAdmin(id uint) (*entity.Project, error)
-	UpdateProject(project *entity.Project) error
+	UpdateProject(project *entity.Project, expectedVersion uint) error
 	DeleteProject(id uint, userID uint) error
 	GetProjectCount(userID uint) (int64, error)
+	MigrateDailyReports(projectID uint, dryRun bool) (*DailyReportMigration, error)
+	MergeDailyReports(projectID uint, patches []DailyReportPatch) ([]DailyMergeResult, error)
 }
 
+// ErrVersionConflict data sudah diubah pengguna lain sejak versi yang dikirim client (If-Match).
+var ErrVersionConflict = errors.New("data sudah diubah oleh pengguna lain; muat ulang lalu coba lagi")
+
+var ErrProjectCustomerNotFound = errors.New("customer tidak ditemukan")
+
 type projectService struct {
-	repo repository.ProjectRepository
+	repo         repository.ProjectRepository
+	dailyRepo    repository.DailyReportRepository
+	customerRepo repository.CustomerRepository
 }
 
-func NewProjectService(repo repository.ProjectRepository) ProjectService {
-	return &projectService{repo}
+func NewProjectService(repo repository.ProjectRepository, dailyRepo repository.DailyReportRepository, customerRepo repository.CustomerRepository) ProjectService {
+	return &projectService{repo, dailyRepo, customerRepo}
+}
+
+// checkCustomer memastikan CustomerID (jika diisi) milik user yang sama. 0 dianggap kosong.
+func (s *projectService) checkCustomer(project *entity.Project) error {
+	if project.CustomerID == nil || *project.CustomerID == 0 {
+		project.CustomerID = nil
+		return nil
+	}
+	customer, err := s.customerRepo.FindByID(*project.CustomerID)
+	if err != nil || customer.UserID != project.UserID {
+		return ErrProjectCustomerNotFound
+	}
+	return nil
 }
 
 func (s *projectService) GetProjectCount(userID uint) (int64, error) {
@@ -31,23 +57,77 @@ func (s *projectService) GetProjectCount(userID uint) (int64, error) {
 
 func (s *projectService) CreateProject(userID uint, project *entity.Project) error {
 	project.UserID = userID
-	return s.repo.Create(project)
+	if err := s.checkCustomer(project); err != nil {
+		return err
+	}
+	daily, rest, hasDaily, err := splitReports(project.Reports)
+	if err != nil {
+		return err
+	}
+	project.Reports = rest
+	if hasDaily {
+		err = s.repo.CreateWithDaily(project, daily)
+	} else {
+		err = s.repo.Create(project)
+	}
+	if err != nil {
+		return err
+	}
+	return s.attachReports([]*entity.Project{project})
 }
 
 func (s *projectService) GetAllProjects(userID uint) ([]entity.Project, error) {
-	return s.repo.FindAll(userID)
+	projects, err := s.repo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	return projects, s.attachReportsSlice(projects)
 }
 
 func (s *projectService) GetProjectByID(id uint, userID uint) (*entity.Project, error) {
-	return s.repo.FindByID(id, userID)
+	project, err := s.repo.FindByID(id, userID)
+	if err != nil {
+		return project, err
+	}
+	return project, s.attachReports([]*entity.Project{project})
 }
 
 func (s *projectService) GetProjectByIDAdmin(id uint) (*entity.Project, error) {
-	return s.repo.FindByIDAdmin(id)
+	project, err := s.repo.FindByIDAdmin(id)
+	if err != nil {
+		return project, err
+	}
+	return project, s.attachReports([]*entity.Project{project})
 }
 
-func (s *projectService) UpdateProject(project *entity.Project) error {
-	return s.repo.Update(project)
+// UpdateProject menyimpan proyek. Jika reports berisi "daily", baris laporan harian disinkronkan ke tabel
+// daily_reports (per tanggal) dan hanya sisa dokumen (weekly/monthly/metadata) yang disimpan di kolom reports.
+// expectedVersion > 0: gagal dengan ErrVersionConflict jika versi proyek di database sudah berbeda.
+func (s *projectService) UpdateProject(project *entity.Project, expectedVersion uint) error {
+	if err := s.checkCustomer(project); err != nil {
+		return err
+	}
+	daily, rest, hasDaily, err := splitReports(project.Reports)
+	if err != nil {
+		return err
+	}
+	project.Reports = rest
+	var ok bool
+	if hasDaily {
+		ok, err = s.repo.UpdateWithDaily(project, expectedVersion, daily)
+	} else {
+		ok, err = s.repo.Update(project, expectedVersion)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if _, err := s.repo.FindByID(project.ID, project.UserID); err != nil {
+			return err
+		}
+		return ErrVersionConflict
+	}
+	return s.attachReports([]*entity.Project{project})
 }
 
 func (s *projectService) DeleteProject(id uint, userID uint) error {
@@ -59,5 +139,128 @@ func (s *projectService) DeleteProject(id uint, userID uint) error {
 }
 
 func (s *projectService) GetAllProjectsWithPagination(params response.QueryParams, userID uint) ([]entity.Project, int, error) {
-	return s.repo.FindAllWithPagination(params, userID)
+	projects, total, err := s.repo.FindAllWithPagination(params, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return projects, total, s.attachReportsSlice(projects)
+}
+
+func (s *projectService) attachReportsSlice(projects []entity.Project) error {
+	ptrs := make([]*entity.Project, len(projects))
+	for i := range projects {
+		ptrs[i] = &projects[i]
+	}
+	return s.attachReports(ptrs)
+}
+
+// attachReports mengisi Project.Reports dengan view kompatibel: kolom reports + laporan harian dari tabel.
+func (s *projectService) attachReports(projects []*entity.Project) error {
+	ids := make([]uint, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	rows, err := s.dailyRepo.FindByProjectIDs(ids)
+	if err != nil {
+		return err
+	}
+	byProject := map[uint][]entity.ReportDaily{}
+	for _, r := range rows {
+		byProject[r.ProjectID] = append(byProject[r.ProjectID], r)
+	}
+	for _, p := range projects {
+		rows, rest, err := withLegacyDaily(p, byProject[p.ID])
+		if err != nil {
+			continue // JSON lama tidak terbaca: tampilkan apa adanya
+		}
+		p.Reports = reportsView(rest, rows)
+	}
+	return nil
+}
+
+// withLegacyDaily menambahkan ke rows (dari tabel) entri "daily" dokumen lama yang belum dimigrasi (cmd/migrate-reports)
+// dan tanggalnya belum ada di tabel, agar tidak ada data yang hilang di masa transisi. rest = kolom reports tanpa "daily".
+func withLegacyDaily(p *entity.Project, rows []entity.ReportDaily) ([]entity.ReportDaily, datatypes.JSON, error) {
+	legacy, rest, hasDaily, err := splitReports(p.Reports)
+	if err != nil || !hasDaily {
+		return rows, p.Reports, err
+	}
+	inTable := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		inTable[r.Date] = true
+	}
+	for _, r := range legacy {
+		if !inTable[r.Date] {
+			r.ProjectID = p.ID
+			rows = append(rows, r)
+		}
+	}
+	return rows, rest, nil
+}
+
+// DailyReportMigration hasil migrasi reports.daily satu proyek ke tabel daily_reports.
+type DailyReportMigration struct {
+	ProjectID uint     `json:"project_id"`
+	InBlob    int      `json:"in_blob"`  // entri daily di JSON
+	Created   int      `json:"created"`  // baris baru di tabel
+	Skipped   int      `json:"skipped"`  // tanggal sudah ada di tabel (tabel menang)
+	Stripped  bool     `json:"stripped"` // "daily" dihapus dari kolom reports
+	Dates     []string `json:"dates,omitempty"`
+}
+
+// MigrateDailyReports memindahkan reports.daily dari kolom JSON ke tabel. Aman dijalankan ulang:
+// tanggal yang sudah ada di tabel tidak ditimpa, dan "daily" baru dihapus dari JSON setelah semua baris tersimpan.
+// Proyek yang sudah dimigrasi (tanpa "daily" di kolom) tidak disentuh.
+func (s *projectService) MigrateDailyReports(projectID uint, dryRun bool) (*DailyReportMigration, error) {
+	res := &DailyReportMigration{ProjectID: projectID}
+	project, err := s.repo.FindByIDAdmin(projectID)
+	if err != nil {
+		return res, err
+	}
+	daily, rest, hasDaily, err := splitReports(project.Reports)
+	if err != nil || !hasDaily {
+		return res, err
+	}
+	res.InBlob = len(daily)
+	for i := range daily {
+		rep := daily[i]
+		if dryRun {
+			if _, err := s.dailyRepo.FindByProjectAndDate(project.ID, rep.Date); err == nil {
+				res.Skipped++
+			} else {
+				res.Created++
+				res.Dates = append(res.Dates, rep.Date)
+			}
+			continue
+		}
+		created, err := s.dailyRepo.MergeByDate(project.ID, rep.Date, func(r *entity.ReportDaily, exists bool) error {
+			if exists {
+				return repository.ErrSkipMerge
+			}
+			rep.ProjectID, rep.ID = project.ID, 0
+			for j := range rep.Images {
+				rep.Images[j].ID = 0
+			}
+			*r = rep
+			return nil
+		})
+		if err != nil {
+			return res, err
+		}
+		if created {
+			res.Created++
+			res.Dates = append(res.Dates, rep.Date)
+		} else {
+			res.Skipped++
+		}
+	}
+	if dryRun {
+		return res, nil
+	}
+	// Simpan hanya kolom reports agar field proyek lain yang diubah bersamaan tidak tertimpa.
+	if err := s.repo.UpdateReports(project.ID, rest); err != nil {
+		return res, err
+	}
+	res.Stripped = true
+	return res, nil
 }
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/prompt"
+	"dashboardadminimb/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrPromptTemplateNotFound = errors.New("versi prompt tidak ditemukan")
+
+type PromptTemplateService interface {
+	Create(userID uint, e *entity.PromptTemplate, activate bool) error
+	Delete(id, userID uint) error
+	GetByID(id, userID uint) (*entity.PromptTemplate, error)
+	GetAll(userID uint, useCase, provider string) ([]entity.PromptTemplate, error)
+	Activate(id, userID uint) (*entity.PromptTemplate, error)
+	Resolve(userID uint, useCase, provider string, data prompt.Data) (string, *entity.PromptTemplate, error)
+	RecordExtraction(l *entity.ExtractionLog) error
+	GetExtractionLogs(userID uint, useCase string, limit int) ([]entity.ExtractionLog, error)
+}
+
+type promptTemplateService struct {
+	repo repository.PromptTemplateRepository
+}
+
+func NewPromptTemplateService(repo repository.PromptTemplateRepository) PromptTemplateService {
+	return &promptTemplateService{repo: repo}
+}
+
+// Create menyimpan versi baru (nomor versi = max + 1 per use case/provider). activate=true langsung jadi versi aktif.
+func (s *promptTemplateService) Create(userID uint, e *entity.PromptTemplate, activate bool) error {
+	if !prompt.ValidUseCase(e.UseCase) {
+		return fmt.Errorf("use_case tidak dikenal: %q", e.UseCase)
+	}
+	if e.Provider == "" {
+		e.Provider = prompt.ProviderAny
+	}
+	if !prompt.ValidProvider(e.Provider) {
+		return fmt.Errorf("provider tidak dikenal: %q", e.Provider)
+	}
+	if err := prompt.Validate(e.Body); err != nil {
+		return err
+	}
+	max, err := s.repo.MaxVersion(userID, e.UseCase, e.Provider)
+	if err != nil {
+		return err
+	}
+	e.ID = 0
+	e.UserID = userID
+	e.Version = max + 1
+	e.IsActive = false
+	if err := s.repo.Create(e); err != nil {
+		return err
+	}
+	if activate {
+		return s.repo.Activate(e)
+	}
+	return nil
+}
+
+func (s *promptTemplateService) Delete(id, userID uint) error {
+	e, err := s.GetByID(id, userID)
+	if err != nil {
+		return err
+	}
+	if e.IsActive {
+		return errors.New("versi prompt yang aktif tidak bisa dihapus; aktifkan versi lain dulu")
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *promptTemplateService) GetByID(id, userID uint) (*entity.PromptTemplate, error) {
+	e, err := s.repo.FindByID(id)
+	if err != nil || e.UserID != userID {
+		return nil, ErrPromptTemplateNotFound
+	}
+	return e, nil
+}
+
+func (s *promptTemplateService) GetAll(userID uint, useCase, provider string) ([]entity.PromptTemplate, error) {
+	return s.repo.FindAll(userID, useCase, provider)
+}
+
+func (s *promptTemplateService) Activate(id, userID uint) (*entity.PromptTemplate, error) {
+	e, err := s.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Activate(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Resolve merender versi aktif untuk provider (fallback ke provider "*"). Jika tidak ada versi aktif,
+// kembalikan ("", nil, nil) agar pemanggil memakai prompt bawaan di kode.
+func (s *promptTemplateService) Resolve(userID uint, useCase, provider string, data prompt.Data) (string, *entity.PromptTemplate, error) {
+	for _, p := range []string{provider, prompt.ProviderAny} {
+		tpl, err := s.repo.FindActive(userID, useCase, p)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		text, err := prompt.Render(tpl.Body, data)
+		if err != nil {
+			return "", nil, fmt.Errorf("prompt %s v%d: %w", tpl.UseCase, tpl.Version, err)
+		}
+		return text, tpl, nil
+	}
+	return "", nil, nil
+}
+
+func (s *promptTemplateService) RecordExtraction(l *entity.ExtractionLog) error {
+	return s.repo.CreateExtractionLog(l)
+}
+
+func (s *promptTemplateService) GetExtractionLogs(userID uint, useCase string, limit int) ([]entity.ExtractionLog, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.repo.FindExtractionLogs(userID, useCase, limit)
+}
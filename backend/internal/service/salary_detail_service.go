@@ -2,36 +2,137 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/repository"
 )
 
+var (
+	ErrSalaryDetailGenerated = errors.New("detail gaji ini dibuat dari absensi; ubah atau batalkan persetujuan absensinya")
+	ErrSalaryRateMissing     = errors.New("tarif anggota belum ada di kontrak/riwayat tarif; isi tarifnya secara manual")
+)
+
 type SalaryDetailService interface {
 	CreateDetail(detail *entity.SalaryDetail) error
 	UpdateDetail(detail *entity.SalaryDetail) error
 	DeleteDetail(id uint) error
 	GetDetailByID(id uint) (*entity.SalaryDetail, error)
 	GetDetailsBySalary(salaryID uint) ([]entity.SalaryDetail, error)
+	// FillRates menyelesaikan ulang harga_per_jam baris bersumber kontrak (dan baris kosong) dengan tarif per jam
+	// yang berlaku pada Tanggal-nya. Baris yang tarifnya belum ada dibiarkan dan dicatat di salary.RateWarnings
+	// agar hitung ulang dan payroll tidak terhenti; penolakan tarif kosong hanya saat detail dibuat/diubah.
+	FillRates(salary *entity.Salary) error
 }
 
 type salaryDetailService struct {
-	repo repository.SalaryDetailRepository
+	repo       repository.SalaryDetailRepository
+	salaryRepo repository.SalaryRepository
+	employment EmploymentService
+}
+
+func NewSalaryDetailService(repo repository.SalaryDetailRepository, salaryRepo repository.SalaryRepository, employment EmploymentService) SalaryDetailService {
+	return &salaryDetailService{repo, salaryRepo, employment}
 }
 
-func NewSalaryDetailService(repo repository.SalaryDetailRepository) SalaryDetailService {
-	return &salaryDetailService{repo}
+// hourlyRate tarif per jam pada terms; baris detail gaji berbasis jam, jadi tarif trip tidak dipakai sebagai cadangan.
+func hourlyRate(terms entity.EmploymentTerms, tanggal time.Time) (float64, error) {
+	if terms.HourlyRate <= 0 {
+		return 0, fmt.Errorf("%w (%s)", ErrSalaryRateMissing, tanggal.Format(dayLayout))
+	}
+	return terms.HourlyRate, nil
 }
 
+// fillRate harga_per_jam kosong diisi tarif kontrak pada Tanggal (RateSourceContract); selain itu RateSourceManual.
+// existing baris sebelum diubah: harga yang tidak diubah pada baris kontrak tetap mengikuti kontrak.
+func (s *salaryDetailService) fillRate(detail, existing *entity.SalaryDetail) error {
+	if existing != nil && existing.RateSource == entity.RateSourceContract && detail.HargaPerJam == existing.HargaPerJam {
+		detail.HargaPerJam = 0
+	}
+	if detail.HargaPerJam != 0 {
+		detail.RateSource = entity.RateSourceManual
+		return nil
+	}
+	salary, err := s.salaryRepo.FindByID(detail.SalaryID)
+	if err != nil {
+		return err
+	}
+	terms, err := s.employment.TermsOn(salary.MemberID, detail.Tanggal.Format(dayLayout))
+	if err != nil {
+		return err
+	}
+	if detail.HargaPerJam, err = hourlyRate(*terms, detail.Tanggal); err != nil {
+		return err
+	}
+	detail.RateSource = entity.RateSourceContract
+	return nil
+}
+
+// CreateDetail harga_per_jam 0 diisi tarif yang berlaku pada Tanggal.
 func (s *salaryDetailService) CreateDetail(detail *entity.SalaryDetail) error {
+	detail.AbsensiID = nil
+	if err := s.fillRate(detail, nil); err != nil {
+		return err
+	}
 	return s.repo.Create(detail)
 }
 
 func (s *salaryDetailService) UpdateDetail(detail *entity.SalaryDetail) error {
+	existing, err := s.repo.FindByID(detail.ID)
+	if err != nil {
+		return err
+	}
+	if existing.AbsensiID != nil {
+		return ErrSalaryDetailGenerated
+	}
+	detail.AbsensiID = nil
+	if err := s.fillRate(detail, existing); err != nil {
+		return err
+	}
 	return s.repo.Update(detail)
 }
 func (s *salaryDetailService) DeleteDetail(id uint) error {
+	if existing, err := s.repo.FindByID(id); err == nil && existing.AbsensiID != nil {
+		return ErrSalaryDetailGenerated
+	}
 	return s.repo.Delete(id)
 }
+func (s *salaryDetailService) FillRates(salary *entity.Salary) error {
+	if salary.Locked {
+		return nil
+	}
+	details, err := s.repo.FindBySalaryID(salary.ID)
+	if err != nil {
+		return err
+	}
+	termsOn, err := s.employment.TermsLookup(salary.MemberID)
+	if err != nil {
+		return err
+	}
+	salary.RateWarnings = nil
+	for i := range details {
+		d := &details[i]
+		if d.AbsensiID != nil || (d.RateSource != entity.RateSourceContract && d.HargaPerJam != 0) {
+			continue
+		}
+		rate, err := hourlyRate(termsOn(d.Tanggal.Format(dayLayout)), d.Tanggal)
+		if err != nil {
+			salary.RateWarnings = append(salary.RateWarnings, fmt.Sprintf("%s: %v", d.Keterangan, err))
+			continue
+		}
+		if rate == d.HargaPerJam && d.RateSource == entity.RateSourceContract {
+			continue
+		}
+		d.HargaPerJam, d.RateSource = rate, entity.RateSourceContract
+		if err := s.repo.Update(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *salaryDetailService) GetDetailsBySalary(salaryID uint) ([]entity.SalaryDetail, error) {
 	return s.repo.FindBySalaryID(salaryID)
 }
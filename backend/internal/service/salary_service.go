@@ -1,11 +1,104 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"dashboardadminimb/internal/entity"
 	"dashboardadminimb/internal/repository"
 	"dashboardadminimb/pkg/response"
 )
 
+var (
+	ErrSalaryLocked = errors.New("gaji sudah difinalisasi di payroll run dan tidak bisa diubah")
+	ErrSalaryMonth  = errors.New("bulan gaji tidak dikenali, gunakan format YYYY-MM atau \"Januari 2026\"")
+	ErrSalaryPeriod = errors.New("filter periode tidak valid: year YYYY, month 01-12, quarter 1-4")
+)
+
+// salaryMonthKey bulan gaji sebagai YYYY-MM. Salary.Month lama berisi teks bebas ("Januari 2026", "01/2026");
+// "" jika tidak dikenali.
+func salaryMonthKey(month string) string {
+	month = strings.TrimSpace(month)
+	if validMonth(month) {
+		return month
+	}
+	parts := strings.FieldsFunc(month, func(r rune) bool { return r == ' ' || r == '/' || r == '-' })
+	if len(parts) != 2 {
+		return ""
+	}
+	year, err := strconv.Atoi(parts[1])
+	if err != nil || year < 1900 {
+		return ""
+	}
+	m, ok := indonesianMonths[strings.ToLower(parts[0])]
+	if !ok {
+		n, err := strconv.Atoi(parts[0])
+		if err != nil || n < 1 || n > 12 {
+			return ""
+		}
+		m = time.Month(n)
+	}
+	return fmt.Sprintf("%04d-%02d", year, int(m))
+}
+
+// setSalaryPeriod menormalkan salary.Month ke YYYY-MM dan mengisi kolom periode yang dipakai agregat gaji.
+func setSalaryPeriod(salary *entity.Salary) error {
+	key := salaryMonthKey(salary.Month)
+	if key == "" {
+		return ErrSalaryMonth
+	}
+	t, _ := time.Parse("2006-01", key)
+	salary.Month, salary.PeriodYear, salary.PeriodMonth = key, t.Year(), int(t.Month())
+	return nil
+}
+
+// parseSalaryPeriod filter periode dari query string. Tanpa year berarti semua periode (month/quarter
+// diabaikan seperti sebelumnya); quarter 1-4 menjadi rentang tiga bulan.
+func parseSalaryPeriod(year, month, quarter string) (repository.SalaryPeriod, error) {
+	year, month, quarter = strings.TrimSpace(year), strings.TrimSpace(month), strings.TrimSpace(quarter)
+	if year == "" {
+		return repository.SalaryPeriod{}, nil
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 1900 || y > 9999 {
+		return repository.SalaryPeriod{}, ErrSalaryPeriod
+	}
+	period := repository.SalaryPeriod{Year: y, FromMonth: 1, ToMonth: 12}
+	switch {
+	case month != "":
+		m, err := strconv.Atoi(month)
+		if err != nil || m < 1 || m > 12 {
+			return repository.SalaryPeriod{}, ErrSalaryPeriod
+		}
+		period.FromMonth, period.ToMonth = m, m
+	case quarter != "":
+		q, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(quarter), "Q"))
+		if err != nil || q < 1 || q > 4 {
+			return repository.SalaryPeriod{}, ErrSalaryPeriod
+		}
+		period.FromMonth, period.ToMonth = q*3-2, q*3
+	}
+	return period, nil
+}
+
+// monthLabel "2026-01" → "Januari 2026"; nilai lain dikembalikan apa adanya.
+func monthLabel(month string) string {
+	t, err := time.Parse("2006-01", salaryMonthKey(month))
+	if err != nil {
+		return month
+	}
+	for name, m := range indonesianMonths {
+		if m == t.Month() {
+			return fmt.Sprintf("%s%s %d", strings.ToUpper(name[:1]), name[1:], t.Year())
+		}
+	}
+	return month
+}
+
 type SalaryService interface {
 	CreateSalary(salary *entity.Salary) error
 	UpdateSalary(salary *entity.Salary) error
@@ -15,6 +108,19 @@ type SalaryService interface {
 	GetAllSalariesWithPagination(params response.QueryParams) ([]entity.Salary, int, error)
 	GetSalaryByID(id uint) (*entity.Salary, error)
 	RecalculateSalary(salaryID uint) error
+	// EnsureEditable ErrSalaryLocked jika gaji (beserta detail & kasbonnya) sudah dikunci payroll run.
+	EnsureEditable(salaryID uint) error
+	// UpdateDocuments menyimpan daftar dokumen gaji; lampiran (slip, bukti transfer) tetap boleh setelah terkunci.
+	UpdateDocuments(salary *entity.Salary, documents []string) error
+	// MigrateSalaryPeriods menormalkan bulan teks bebas ke YYYY-MM dan mengisi period_year/period_month.
+	MigrateSalaryPeriods(dryRun bool) (*SalaryPeriodMigration, error)
+}
+
+// SalaryPeriodMigration hasil pengisian kolom periode gaji lama.
+type SalaryPeriodMigration struct {
+	Salaries     int      `json:"salaries"`
+	Updated      int      `json:"updated"`
+	Unrecognized []string `json:"unrecognized"` // "id: month" yang perlu diperbaiki manual
 }
 
 type salaryService struct {
@@ -22,6 +128,9 @@ type salaryService struct {
 	memberRepo    repository.MemberRepository
 	detailService SalaryDetailService
 	kasbonService KasbonService
+	advances      AdvanceService
+	statutory     StatutoryService
+	attendance    AttendanceService
 }
 
 func NewSalaryService(
@@ -29,12 +138,18 @@ func NewSalaryService(
 	memberRepo repository.MemberRepository,
 	detailService SalaryDetailService,
 	kasbonService KasbonService,
+	advances AdvanceService,
+	statutory StatutoryService,
+	attendance AttendanceService,
 ) SalaryService {
 	return &salaryService{
 		salaryRepo:    salaryRepo,
 		memberRepo:    memberRepo,
 		detailService: detailService,
 		kasbonService: kasbonService,
+		advances:      advances,
+		statutory:     statutory,
+		attendance:    attendance,
 	}
 }
 
@@ -44,10 +159,19 @@ func (s *salaryService) CreateSalary(salary *entity.Salary) error {
 	if err != nil {
 		return err
 	}
+	if err := setSalaryPeriod(salary); err != nil {
+		return err
+	}
 	return s.salaryRepo.Create(salary)
 }
 
 func (s *salaryService) UpdateSalary(salary *entity.Salary) error {
+	if err := s.EnsureEditable(salary.ID); err != nil {
+		return err
+	}
+	if err := setSalaryPeriod(salary); err != nil {
+		return err
+	}
 	return s.salaryRepo.Update(salary)
 }
 
@@ -56,9 +180,54 @@ func (s *salaryService) DeleteSalary(salaryID uint) error {
 	if err != nil {
 		return err
 	}
+	if salary.Locked {
+		return ErrSalaryLocked
+	}
 	return s.salaryRepo.Delete(salary)
 }
 
+func (s *salaryService) UpdateDocuments(salary *entity.Salary, documents []string) error {
+	raw, err := json.Marshal(documents)
+	if err != nil {
+		return err
+	}
+	salary.Documents = raw
+	return s.salaryRepo.UpdateDocuments(salary.ID, raw)
+}
+
+func (s *salaryService) MigrateSalaryPeriods(dryRun bool) (*SalaryPeriodMigration, error) {
+	res := &SalaryPeriodMigration{Unrecognized: []string{}}
+	salaries, err := s.salaryRepo.FindWithoutPeriod()
+	if err != nil {
+		return res, err
+	}
+	res.Salaries = len(salaries)
+	for i := range salaries {
+		if err := setSalaryPeriod(&salaries[i]); err != nil {
+			res.Unrecognized = append(res.Unrecognized, fmt.Sprintf("%d: %q", salaries[i].ID, salaries[i].Month))
+			continue
+		}
+		if !dryRun {
+			if err := s.salaryRepo.UpdatePeriod(&salaries[i]); err != nil {
+				return res, err
+			}
+		}
+		res.Updated++
+	}
+	return res, nil
+}
+
+func (s *salaryService) EnsureEditable(salaryID uint) error {
+	salary, err := s.salaryRepo.FindByID(salaryID)
+	if err != nil {
+		return err
+	}
+	if salary.Locked {
+		return ErrSalaryLocked
+	}
+	return nil
+}
+
 // DeleteAllByMemberID deletes all salaries and related kasbons/details for a member (for cascade delete before member delete).
 func (s *salaryService) DeleteAllByMemberID(memberID string) error {
 	salaries, err := s.salaryRepo.FindByMemberID(memberID)
@@ -94,6 +263,14 @@ func (s *salaryService) RecalculateSalary(salaryID uint) error {
 		return err
 	}
 
+	// Baris detail dari absensi yang disetujui dibuat ulang dulu; tarif kosong diisi dari kontrak/riwayat tarif
+	if err := s.attendance.SyncSalaryDetails(salary); err != nil {
+		return err
+	}
+	if err := s.detailService.FillRates(salary); err != nil {
+		return err
+	}
+
 	// Hitung Gross dari SalaryDetail
 	details, err := s.detailService.GetDetailsBySalary(salaryID)
 	if err != nil {
@@ -104,14 +281,14 @@ func (s *salaryService) RecalculateSalary(salaryID uint) error {
 		gross += float64(d.JamTrip) * d.HargaPerJam
 	}
 
-	// Hitung Loan dari Kasbon
-	kasbons, err := s.kasbonService.GetKasbonsBySalary(salaryID)
+	// Hitung Loan dari kasbon gaji dan cicilan kasbon ledger
+	member, err := s.memberRepo.FindByID(salary.MemberID)
 	if err != nil {
 		return err
 	}
-	loan := 0.0
-	for _, k := range kasbons {
-		loan += k.Jumlah
+	loan, err := salaryLoan(s.kasbonService, s.advances, member.UserID, salary)
+	if err != nil {
+		return err
 	}
 
 	// Update nilai Salary
@@ -119,9 +296,39 @@ func (s *salaryService) RecalculateSalary(salaryID uint) error {
 	salary.Loan = loan
 	salary.NetSalary = gross - loan
 	salary.Salary = gross
+
+	// Potongan wajib (PPh 21, BPJS) sesuai pengaturan user
+	if err := s.statutory.Apply(salary); err != nil {
+		return err
+	}
 	return s.salaryRepo.Update(salary)
 }
 
+// salaryLoan potongan pinjaman satu gaji: kasbon gaji + LoanAdjustment manual, ditambah cicilan kasbon ledger
+// terjadwal bulan itu jika gaji sudah masuk payroll run (gaji terkunci tidak dihitung ulang).
+func salaryLoan(kasbonService KasbonService, advances AdvanceService, userID uint, salary *entity.Salary) (float64, error) {
+	kasbons, err := kasbonService.GetKasbonsBySalary(salary.ID)
+	if err != nil {
+		return 0, err
+	}
+	loan := salary.LoanAdjustment
+	for _, k := range kasbons {
+		loan += k.Jumlah
+	}
+	if salary.PayrollRunID != nil && advances != nil {
+		month := salaryMonthKey(salary.Month)
+		if salary.PeriodYear > 0 {
+			month = fmt.Sprintf("%04d-%02d", salary.PeriodYear, salary.PeriodMonth)
+		}
+		installment, err := advances.ScheduledInstallment(userID, salary.MemberID, month)
+		if err != nil {
+			return 0, err
+		}
+		loan += installment
+	}
+	return round2(loan), nil
+}
+
 func (s *salaryService) GetAllSalariesWithPagination(params response.QueryParams) ([]entity.Salary, int, error) {
 	return s.salaryRepo.FindAllWithPagination(params)
 }
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"dashboardadminimb/internal/entity"
+	"dashboardadminimb/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrTaxSettingInvalid = errors.New("tarif harus 0–100 persen dan batas upah tidak boleh negatif")
+
+// terBracket batas atas penghasilan bruto bulanan (inklusif) dan tarif efektif persen.
+type terBracket struct {
+	upTo float64
+	rate float64
+}
+
+// Tarif Efektif Rata-rata bulanan PP 58/2023. Kategori A: TK/0, TK/1, K/0; B: TK/2, TK/3, K/1, K/2; C: K/3.
+var terTables = map[string][]terBracket{
+	"A": {
+		{5400000, 0}, {5650000, 0.25}, {5950000, 0.5}, {6300000, 0.75}, {6750000, 1}, {7500000, 1.25},
+		{8550000, 1.5}, {9650000, 1.75}, {10050000, 2}, {10350000, 2.25}, {10700000, 2.5}, {11050000, 3},
+		{11600000, 3.5}, {12500000, 4}, {13750000, 5}, {15100000, 6}, {16950000, 7}, {19750000, 8},
+		{24150000, 9}, {26450000, 10}, {28000000, 11}, {30050000, 12}, {32400000, 13}, {35400000, 14},
+		{39100000, 15}, {43850000, 16}, {47800000, 17}, {51400000, 18}, {56300000, 19}, {62200000, 20},
+		{68600000, 21}, {77500000, 22}, {89000000, 23}, {103000000, 24}, {125000000, 25}, {157000000, 26},
+		{206000000, 27}, {337000000, 28}, {454000000, 29}, {550000000, 30}, {695000000, 31}, {910000000, 32},
+		{1400000000, 33}, {math.Inf(1), 34},
+	},
+	"B": {
+		{6200000, 0}, {6500000, 0.25}, {6850000, 0.5}, {7300000, 0.75}, {9200000, 1}, {10750000, 1.5},
+		{11250000, 2}, {11600000, 2.5}, {12600000, 3}, {13600000, 4}, {14950000, 5}, {16400000, 6},
+		{18450000, 7}, {21850000, 8}, {26000000, 9}, {27700000, 10}, {29350000, 11}, {31450000, 12},
+		{33950000, 13}, {37100000, 14}, {41100000, 15}, {45800000, 16}, {49500000, 17}, {53800000, 18},
+		{58500000, 19}, {64000000, 20}, {71000000, 21}, {80000000, 22}, {93000000, 23}, {109000000, 24},
+		{129000000, 25}, {163000000, 26}, {211000000, 27}, {374000000, 28}, {459000000, 29}, {555000000, 30},
+		{704000000, 31}, {957000000, 32}, {1405000000, 33}, {math.Inf(1), 34},
+	},
+	"C": {
+		{6600000, 0}, {6950000, 0.25}, {7350000, 0.5}, {7800000, 0.75}, {8850000, 1}, {9800000, 1.25},
+		{10950000, 1.5}, {11200000, 1.75}, {12050000, 2}, {12950000, 3}, {14150000, 4}, {15550000, 5},
+		{17050000, 6}, {19500000, 7}, {22700000, 8}, {26600000, 9}, {28100000, 10}, {30100000, 11},
+		{32600000, 12}, {35400000, 13}, {38900000, 14}, {43000000, 15}, {47400000, 16}, {51200000, 17},
+		{55800000, 18}, {60400000, 19}, {66700000, 20}, {74500000, 21}, {83200000, 22}, {95600000, 23},
+		{110000000, 24}, {134000000, 25}, {169000000, 26}, {221000000, 27}, {390000000, 28}, {463000000, 29},
+		{561000000, 30}, {709000000, 31}, {965000000, 32}, {1419000000, 33}, {math.Inf(1), 34},
+	},
+}
+
+// normalizePTKP "k/1" → "K/1"; status tidak dikenal dianggap TK/0.
+func normalizePTKP(status string) string {
+	s := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(status), " ", ""))
+	switch s {
+	case "TK/0", "TK/1", "TK/2", "TK/3", "K/0", "K/1", "K/2", "K/3":
+		return s
+	}
+	return "TK/0"
+}
+
+func terCategory(ptkp string) string {
+	switch normalizePTKP(ptkp) {
+	case "TK/2", "TK/3", "K/1", "K/2":
+		return "B"
+	case "K/3":
+		return "C"
+	}
+	return "A"
+}
+
+func terRate(ptkp string, bruto float64) float64 {
+	for _, b := range terTables[terCategory(ptkp)] {
+		if bruto <= b.upTo {
+			return b.rate
+		}
+	}
+	return 34
+}
+
+// ptkpAmount PTKP setahun: 54 jt + 4,5 jt kawin + 4,5 jt per tanggungan (maks 3).
+func ptkpAmount(ptkp string) float64 {
+	s := normalizePTKP(ptkp)
+	amount := 54000000.0
+	if strings.HasPrefix(s, "K/") {
+		amount += 4500000
+	}
+	return amount + float64(s[len(s)-1]-'0')*4500000
+}
+
+// pasal17 PPh terutang setahun atas PKP dengan tarif progresif UU HPP.
+func pasal17(pkp float64) float64 {
+	brackets := []terBracket{{60000000, 5}, {250000000, 15}, {500000000, 25}, {5000000000, 30}, {math.Inf(1), 35}}
+	tax, lower := 0.0, 0.0
+	for _, b := range brackets {
+		if pkp <= lower {
+			break
+		}
+		tax += (math.Min(pkp, b.upTo) - lower) * b.rate / 100
+		lower = b.upTo
+	}
+	return math.Floor(tax)
+}
+
+func capped(wage, limit float64) float64 {
+	if limit > 0 && wage > limit {
+		return limit
+	}
+	return wage
+}
+
+// computeStatutory menghitung baris potongan wajib satu gaji. Januari–November PPh 21 memakai TER dari bruto
+// bulanan (gaji + JKK, JKM, BPJS Kesehatan yang ditanggung perusahaan); Desember dihitung ulang setahun
+// (Pasal 17 dikurangi PPh yang sudah dipotong) dari baris potongan bulan-bulan sebelumnya (prior).
+func computeStatutory(setting entity.PayrollTaxSetting, member *entity.Member, gross float64, month string, prior []entity.SalaryDeduction) []entity.SalaryDeduction {
+	var lines []entity.SalaryDeduction
+	add := func(code, name, party string, base, rate float64) float64 {
+		amount := math.Round(base * rate / 100)
+		lines = append(lines, entity.SalaryDeduction{Code: code, Name: name, Party: party, Base: round2(base), Rate: rate, Amount: amount})
+		return amount
+	}
+	if gross <= 0 {
+		return nil
+	}
+	benefits, iuran := 0.0, 0.0
+	if setting.BPJSTKEnabled && member.BPJSEmployment {
+		iuran += add(entity.DeductionJHT, "BPJS TK - JHT", entity.PartyEmployee, gross, setting.JHTEmployeeRate)
+		add(entity.DeductionJHT, "BPJS TK - JHT", entity.PartyEmployer, gross, setting.JHTEmployerRate)
+		jpBase := capped(gross, setting.JPWageCap)
+		iuran += add(entity.DeductionJP, "BPJS TK - JP", entity.PartyEmployee, jpBase, setting.JPEmployeeRate)
+		add(entity.DeductionJP, "BPJS TK - JP", entity.PartyEmployer, jpBase, setting.JPEmployerRate)
+		benefits += add(entity.DeductionJKK, "BPJS TK - JKK", entity.PartyEmployer, gross, setting.JKKRate)
+		benefits += add(entity.DeductionJKM, "BPJS TK - JKM", entity.PartyEmployer, gross, setting.JKMRate)
+	}
+	if setting.BPJSKesEnabled && member.BPJSHealth {
+		kesBase := capped(gross, setting.KesWageCap)
+		add(entity.DeductionBPJSKes, "BPJS Kesehatan", entity.PartyEmployee, kesBase, setting.KesEmployeeRate)
+		benefits += add(entity.DeductionBPJSKes, "BPJS Kesehatan", entity.PartyEmployer, kesBase, setting.KesEmployerRate)
+	}
+	if !setting.PPh21Enabled {
+		return lines
+	}
+	bruto := gross + benefits
+	rate := terRate(member.PTKPStatus, bruto)
+	amount := math.Floor(bruto * rate / 100)
+	if strings.HasSuffix(month, "-12") {
+		brutoYear, withheld, iuranYear, months := bruto, 0.0, iuran, 1.0
+		for _, l := range prior {
+			switch {
+			case l.Code == entity.DeductionPPh21:
+				brutoYear += l.Base
+				withheld += l.Amount
+				months++
+			case l.Party == entity.PartyEmployee && (l.Code == entity.DeductionJHT || l.Code == entity.DeductionJP):
+				iuranYear += l.Amount
+			}
+		}
+		biayaJabatan := math.Min(brutoYear*0.05, 500000*months)
+		pkp := math.Floor((brutoYear-biayaJabatan-iuranYear-ptkpAmount(member.PTKPStatus))/1000) * 1000
+		amount = math.Max(pasal17(math.Max(pkp, 0))-withheld, 0)
+		rate = 0
+		if bruto > 0 {
+			rate = math.Round(amount/bruto*100000) / 1000
+		}
+	}
+	lines = append(lines, entity.SalaryDeduction{
+		Code: entity.DeductionPPh21, Name: "PPh 21", Party: entity.PartyEmployee, Base: round2(bruto), Rate: rate, Amount: amount,
+	})
+	return lines
+}
+
+type StatutoryService interface {
+	GetSetting(userID uint) (*entity.PayrollTaxSetting, error)
+	SaveSetting(userID uint, setting *entity.PayrollTaxSetting) error
+	// Apply menghitung ulang potongan wajib gaji (baris disimpan), mengisi Statutory dan NetSalary.
+	// Gaji belum disimpan; pemanggil yang menyimpan. Gaji baru (ID 0) atau terkunci dilewati.
+	Apply(salary *entity.Salary) error
+	Lines(salaryID, userID uint) ([]entity.SalaryDeduction, error)
+	// Recap rekap potongan wajib per anggota untuk bulan YYYY-MM (SPT Masa PPh 21 & iuran BPJS).
+	Recap(userID uint, month string) (*entity.StatutoryRecap, error)
+}
+
+type statutoryService struct {
+	repo       repository.StatutoryRepository
+	salaryRepo repository.SalaryRepository
+	memberRepo repository.MemberRepository
+}
+
+func NewStatutoryService(repo repository.StatutoryRepository, salaryRepo repository.SalaryRepository, memberRepo repository.MemberRepository) StatutoryService {
+	return &statutoryService{repo, salaryRepo, memberRepo}
+}
+
+func (s *statutoryService) GetSetting(userID uint) (*entity.PayrollTaxSetting, error) {
+	setting, err := s.repo.FindSetting(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		def := entity.DefaultPayrollTaxSetting(userID)
+		return &def, nil
+	}
+	return setting, err
+}
+
+func (s *statutoryService) SaveSetting(userID uint, setting *entity.PayrollTaxSetting) error {
+	for _, rate := range []float64{setting.JHTEmployeeRate, setting.JHTEmployerRate, setting.JPEmployeeRate, setting.JPEmployerRate,
+		setting.JKKRate, setting.JKMRate, setting.KesEmployeeRate, setting.KesEmployerRate} {
+		if rate < 0 || rate > 100 {
+			return ErrTaxSettingInvalid
+		}
+	}
+	if setting.JPWageCap < 0 || setting.KesWageCap < 0 {
+		return ErrTaxSettingInvalid
+	}
+	existing, err := s.GetSetting(userID)
+	if err != nil {
+		return err
+	}
+	setting.ID, setting.UserID, setting.CreatedAt = existing.ID, userID, existing.CreatedAt
+	return s.repo.SaveSetting(setting)
+}
+
+func (s *statutoryService) Apply(salary *entity.Salary) error {
+	if salary.ID == 0 || salary.Locked {
+		return nil
+	}
+	member, err := s.memberRepo.FindByID(salary.MemberID)
+	if err != nil {
+		return err
+	}
+	setting, err := s.GetSetting(member.UserID)
+	if err != nil {
+		return err
+	}
+	month := salaryMonthKey(salary.Month)
+	var prior []entity.SalaryDeduction
+	if strings.HasSuffix(month, "-12") && setting.PPh21Enabled {
+		if prior, err = s.repo.FindYearBefore(member.ID, month, salary.ID); err != nil {
+			return err
+		}
+	}
+	gross := salary.GrossSalary
+	if gross == 0 {
+		gross = salary.Salary
+	}
+	lines := computeStatutory(*setting, member, gross, month, prior)
+	employee := 0.0
+	for i := range lines {
+		lines[i].UserID, lines[i].SalaryID, lines[i].MemberID, lines[i].Month = member.UserID, salary.ID, member.ID, month
+		if lines[i].Party == entity.PartyEmployee {
+			employee += lines[i].Amount
+		}
+	}
+	if err := s.repo.ReplaceDeductions(salary.ID, lines); err != nil {
+		return err
+	}
+	salary.Statutory = round2(employee)
+	salary.NetSalary = round2(gross - salary.Loan - salary.Statutory)
+	return nil
+}
+
+func (s *statutoryService) Lines(salaryID, userID uint) ([]entity.SalaryDeduction, error) {
+	salary, err := s.salaryRepo.FindByID(salaryID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.memberRepo.FindByID(salary.MemberID)
+	if err != nil || member.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.repo.FindBySalary(salaryID)
+}
+
+func (s *statutoryService) Recap(userID uint, month string) (*entity.StatutoryRecap, error) {
+	if !validMonth(month) {
+		return nil, ErrPayrollMonth
+	}
+	lines, err := s.repo.FindByMonth(userID, month)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]entity.Member{}
+	for _, m := range members {
+		byID[m.ID] = m
+	}
+	recap := &entity.StatutoryRecap{Month: month, Rows: []entity.StatutoryRecapRow{},
+		TotalEmployee: map[string]float64{}, TotalEmployer: map[string]float64{}}
+	rows := map[uint]*entity.StatutoryRecapRow{}
+	var order []uint
+	for _, l := range lines {
+		row := rows[l.SalaryID]
+		if row == nil {
+			m := byID[l.MemberID]
+			row = &entity.StatutoryRecapRow{SalaryID: l.SalaryID, MemberID: l.MemberID, MemberName: m.FullName, NPWP: m.NPWP,
+				PTKPStatus: normalizePTKP(m.PTKPStatus), Employee: map[string]float64{}, Employer: map[string]float64{}}
+			if salary, err := s.salaryRepo.FindByID(l.SalaryID); err == nil {
+				row.Gross = salary.GrossSalary
+				if row.Gross == 0 {
+					row.Gross = salary.Salary
+				}
+			}
+			rows[l.SalaryID] = row
+			order = append(order, l.SalaryID)
+		}
+		if l.Code == entity.DeductionPPh21 {
+			row.TaxableBase = l.Base
+		}
+		if l.Party == entity.PartyEmployee {
+			row.Employee[l.Code] += l.Amount
+			recap.TotalEmployee[l.Code] += l.Amount
+		} else {
+			row.Employer[l.Code] += l.Amount
+			recap.TotalEmployer[l.Code] += l.Amount
+		}
+		recap.TotalRemit += l.Amount
+	}
+	for _, id := range order {
+		recap.TotalGross += rows[id].Gross
+		recap.Rows = append(recap.Rows, *rows[id])
+	}
+	sort.SliceStable(recap.Rows, func(i, j int) bool { return recap.Rows[i].MemberName < recap.Rows[j].MemberName })
+	recap.TotalGross, recap.TotalRemit = round2(recap.TotalGross), round2(recap.TotalRemit)
+	return recap, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"testing"
+
+	"dashboardadminimb/internal/entity"
+)
+
+func TestTerRate(t *testing.T) {
+	tests := []struct {
+		name  string
+		ptkp  string
+		bruto float64
+		want  float64
+	}{
+		{name: "A batas 0%", ptkp: "TK/0", bruto: 5400000, want: 0},
+		{name: "A lewat batas 0%", ptkp: "TK/0", bruto: 5400001, want: 0.25},
+		{name: "A batas 2%", ptkp: "K/0", bruto: 10050000, want: 2},
+		{name: "A lewat batas 2%", ptkp: "TK/1", bruto: 10050001, want: 2.25},
+		{name: "A batas tertinggi", ptkp: "TK/0", bruto: 1400000000, want: 33},
+		{name: "A di atas semua batas", ptkp: "TK/0", bruto: 1400000001, want: 34},
+		{name: "B batas 0%", ptkp: "K/1", bruto: 6200000, want: 0},
+		{name: "B lewat batas 0%", ptkp: "TK/2", bruto: 6200001, want: 0.25},
+		{name: "B lewat batas 0,75%", ptkp: "K/2", bruto: 7300001, want: 1},
+		{name: "B di atas semua batas", ptkp: "TK/3", bruto: 1405000001, want: 34},
+		{name: "C batas 0%", ptkp: "K/3", bruto: 6600000, want: 0},
+		{name: "C lewat batas 0%", ptkp: "K/3", bruto: 6600001, want: 0.25},
+		{name: "C lewat batas 2%", ptkp: "K/3", bruto: 12050001, want: 3},
+		{name: "status huruf kecil", ptkp: " k/1 ", bruto: 6200001, want: 0.25},
+		{name: "status tidak dikenal = TK/0", ptkp: "X", bruto: 5400001, want: 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := terRate(tt.ptkp, tt.bruto); got != tt.want {
+				t.Fatalf("terRate(%q, %.0f) = %v, ingin %v", tt.ptkp, tt.bruto, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasal17(t *testing.T) {
+	tests := []struct {
+		pkp  float64
+		want float64
+	}{
+		{0, 0},
+		{-1000, 0},
+		{60000000, 3000000},
+		{60001000, 3000150},
+		{250000000, 31500000},
+		{500000000, 94000000},
+		{5000000000, 1444000000},
+		{5000001000, 1444000350},
+	}
+	for _, tt := range tests {
+		if got := pasal17(tt.pkp); got != tt.want {
+			t.Errorf("pasal17(%.0f) = %.0f, ingin %.0f", tt.pkp, got, tt.want)
+		}
+	}
+}
+
+// lineAmount jumlah baris potongan dengan kode dan pihak tertentu; ok false jika baris tidak ada.
+func lineAmount(lines []entity.SalaryDeduction, code, party string) (base, amount float64, ok bool) {
+	for _, l := range lines {
+		if l.Code == code && l.Party == party {
+			return l.Base, l.Amount, true
+		}
+	}
+	return 0, 0, false
+}
+
+func TestComputeStatutoryBPJSCaps(t *testing.T) {
+	setting := entity.DefaultPayrollTaxSetting(1)
+	setting.BPJSTKEnabled, setting.BPJSKesEnabled = true, true
+	member := &entity.Member{PTKPStatus: "TK/0", BPJSEmployment: true, BPJSHealth: true}
+
+	tests := []struct {
+		name       string
+		gross      float64
+		code       string
+		party      string
+		wantBase   float64
+		wantAmount float64
+	}{
+		{name: "JHT tanpa batas", gross: 20000000, code: entity.DeductionJHT, party: entity.PartyEmployee, wantBase: 20000000, wantAmount: 400000},
+		{name: "JP pekerja dibatasi", gross: 20000000, code: entity.DeductionJP, party: entity.PartyEmployee, wantBase: 10547400, wantAmount: 105474},
+		{name: "JP perusahaan dibatasi", gross: 20000000, code: entity.DeductionJP, party: entity.PartyEmployer, wantBase: 10547400, wantAmount: 210948},
+		{name: "JP di bawah batas", gross: 5000000, code: entity.DeductionJP, party: entity.PartyEmployee, wantBase: 5000000, wantAmount: 50000},
+		{name: "Kesehatan pekerja dibatasi", gross: 20000000, code: entity.DeductionBPJSKes, party: entity.PartyEmployee, wantBase: 12000000, wantAmount: 120000},
+		{name: "Kesehatan perusahaan dibatasi", gross: 20000000, code: entity.DeductionBPJSKes, party: entity.PartyEmployer, wantBase: 12000000, wantAmount: 480000},
+		{name: "Kesehatan tepat batas", gross: 12000000, code: entity.DeductionBPJSKes, party: entity.PartyEmployee, wantBase: 12000000, wantAmount: 120000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := computeStatutory(setting, member, tt.gross, "2025-03", nil)
+			base, amount, ok := lineAmount(lines, tt.code, tt.party)
+			if !ok {
+				t.Fatalf("baris %s/%s tidak ada", tt.code, tt.party)
+			}
+			if base != tt.wantBase || amount != tt.wantAmount {
+				t.Fatalf("base = %.0f, amount = %.0f, ingin %.0f dan %.0f", base, amount, tt.wantBase, tt.wantAmount)
+			}
+		})
+	}
+
+	t.Run("peserta nonaktif dilewati", func(t *testing.T) {
+		lines := computeStatutory(setting, &entity.Member{PTKPStatus: "TK/0"}, 20000000, "2025-03", nil)
+		if len(lines) != 0 {
+			t.Fatalf("lines = %+v, ingin kosong", lines)
+		}
+	})
+	t.Run("gaji nol", func(t *testing.T) {
+		if lines := computeStatutory(setting, member, 0, "2025-03", nil); lines != nil {
+			t.Fatalf("lines = %+v, ingin nil", lines)
+		}
+	})
+}
+
+// priorPPh21 sebelas bulan Januari–November dengan bruto dan PPh 21 yang sama.
+func priorPPh21(bruto, amount float64) []entity.SalaryDeduction {
+	lines := make([]entity.SalaryDeduction, 0, 11)
+	for i := 0; i < 11; i++ {
+		lines = append(lines, entity.SalaryDeduction{Code: entity.DeductionPPh21, Party: entity.PartyEmployee, Base: bruto, Amount: amount})
+	}
+	return lines
+}
+
+func TestComputeStatutoryPPh21(t *testing.T) {
+	setting := entity.DefaultPayrollTaxSetting(1)
+	setting.PPh21Enabled = true
+	member := &entity.Member{PTKPStatus: "TK/0"}
+	withJHT := priorPPh21(10000000, 200000)
+	for i := 0; i < 11; i++ {
+		withJHT = append(withJHT, entity.SalaryDeduction{Code: entity.DeductionJHT, Party: entity.PartyEmployee, Amount: 200000},
+			entity.SalaryDeduction{Code: entity.DeductionJHT, Party: entity.PartyEmployer, Amount: 370000})
+	}
+
+	tests := []struct {
+		name       string
+		month      string
+		prior      []entity.SalaryDeduction
+		wantRate   float64
+		wantAmount float64
+	}{
+		// 10 jt masuk TER A 2%.
+		{name: "bulan biasa pakai TER", month: "2025-11", prior: priorPPh21(10000000, 200000), wantRate: 2, wantAmount: 200000},
+		// Setahun 120 jt - biaya jabatan 6 jt - PTKP 54 jt = PKP 60 jt → 3 jt, dikurangi 2,2 jt yang sudah dipotong.
+		{name: "Desember true-up", month: "2025-12", prior: priorPPh21(10000000, 200000), wantRate: 8, wantAmount: 800000},
+		// Iuran JHT pekerja 2,2 jt mengurangi PKP menjadi 57,8 jt → 2,89 jt; bagian perusahaan diabaikan.
+		{name: "Desember dikurangi iuran pekerja", month: "2025-12", prior: withJHT, wantRate: 6.9, wantAmount: 690000},
+		{name: "Desember lebih potong tidak negatif", month: "2025-12", prior: priorPPh21(10000000, 500000), wantRate: 0, wantAmount: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := computeStatutory(setting, member, 10000000, tt.month, tt.prior)
+			if len(lines) != 1 {
+				t.Fatalf("lines = %+v, ingin satu baris PPh 21", lines)
+			}
+			l := lines[0]
+			if l.Code != entity.DeductionPPh21 || l.Base != 10000000 {
+				t.Fatalf("baris = %+v, ingin PPh 21 dengan base 10000000", l)
+			}
+			if l.Rate != tt.wantRate || l.Amount != tt.wantAmount {
+				t.Fatalf("rate = %v, amount = %.0f, ingin %v dan %.0f", l.Rate, l.Amount, tt.wantRate, tt.wantAmount)
+			}
+		})
+	}
+}
@@ -30,7 +30,10 @@ func NewMySQLDB(config *config.Config) (*gorm.DB, error) {
 		&entity.User{},
 		&entity.Member{},
 		&entity.Project{},
+		&entity.ReportDaily{},
+		&entity.DailyReportImage{},
 		&entity.ProjectShareLink{},
+		&entity.ProjectMilestone{},
 		&entity.Salary{},
 		// Multi-tenancy: finance tables also need user_id columns.
 		&entity.Finance{},
@@ -43,7 +46,26 @@ func NewMySQLDB(config *config.Config) (*gorm.DB, error) {
 		&entity.InvoiceItem{},
 		&entity.Customer{},
 		&entity.Equipment{},
+		&entity.EquipmentAssignment{},
+		&entity.EquipmentMeterReading{},
+		&entity.MaintenancePlan{},
+		&entity.ServiceRecord{},
+		&entity.EquipmentDocument{},
+		&entity.MemberDocument{},
+		&entity.MemberContract{},
+		&entity.MemberEmploymentEvent{},
+		&entity.FuelLog{},
+		&entity.PayrollRun{},
+		&entity.MemberAdvance{},
+		&entity.AdvanceRepayment{},
+		&entity.PayrollTaxSetting{},
+		&entity.SalaryDeduction{},
+		&entity.Attendance{},
+		&entity.StockLocation{},
+		&entity.StockMovement{},
 		&entity.ItemTemplate{},
+		&entity.PromptTemplate{},
+		&entity.ExtractionLog{},
 	)
 
 	return db, nil
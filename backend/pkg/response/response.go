@@ -17,3 +17,12 @@ func Error(c echo.Context, code int, err error) error {
 		"message": err.Error(),
 	})
 }
+
+// ErrorWithData seperti Error tapi menyertakan data (mis. versi terbaru saat 409 conflict).
+func ErrorWithData(c echo.Context, code int, err error, data interface{}) error {
+	return c.JSON(code, map[string]interface{}{
+		"status":  code,
+		"message": err.Error(),
+		"data":    data,
+	})
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAdvanceRoutes(e *echo.Echo, cfg config.Config, advanceService service.AdvanceService, activityService service.ActivityService) {
+	handler := http.NewAdvanceHandler(advanceService, activityService)
+	g := e.Group("/api/advances")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.List)
+	g.GET("/balances", handler.Balances)
+	g.GET("/members/:memberId/history", handler.History)
+	g.POST("", handler.Create)
+	g.GET("/:id", handler.Get)
+	g.PUT("/:id", handler.Update)
+	g.DELETE("/:id", handler.Delete)
+	g.POST("/:id/repayments", handler.Repay)
+}
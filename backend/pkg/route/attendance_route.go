@@ -0,0 +1,24 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAttendanceRoutes(e *echo.Echo, cfg config.Config, attendanceService service.AttendanceService, activityService service.ActivityService) {
+	handler := http.NewAttendanceHandler(attendanceService, activityService)
+	g := e.Group("/api/attendance")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.List)
+	g.GET("/matrix", handler.Matrix)
+	g.POST("", handler.Create)
+	g.POST("/bulk", handler.Bulk)
+	g.POST("/approve", handler.Approve)
+	g.GET("/:id", handler.Get)
+	g.PUT("/:id", handler.Update)
+	g.DELETE("/:id", handler.Delete)
+}
@@ -1,13 +1,16 @@
 package route
 
 import (
+	"dashboardadminimb/config"
 	"dashboardadminimb/internal/http"
+	"dashboardadminimb/pkg/middleware"
 
 	"github.com/labstack/echo/v4"
 )
 
-func SetupDailyReportRoutes(e *echo.Echo, handler *http.DailyReportHandler) {
+func SetupDailyReportRoutes(e *echo.Echo, handler *http.DailyReportHandler, cfg config.Config) {
 	dailyReports := e.Group("/api/daily-reports")
+	dailyReports.Use(middleware.AdminAuth(cfg))
 
 	// Daily report routes
 	dailyReports.POST("", handler.CreateDailyReport)
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterEmploymentRoutes(e *echo.Echo, cfg config.Config, employmentService service.EmploymentService) {
+	handler := http.NewEmploymentHandler(employmentService)
+	g := e.Group("/api/members/:id/employment")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.History)
+	g.GET("/terms", handler.Terms)
+	g.POST("/contracts", handler.CreateContract)
+	g.PUT("/contracts/:contractId", handler.UpdateContract)
+	g.DELETE("/contracts/:contractId", handler.DeleteContract)
+	g.POST("/changes", handler.RecordChange)
+	g.DELETE("/events/:eventId", handler.DeleteEvent)
+}
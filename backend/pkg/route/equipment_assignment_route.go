@@ -0,0 +1,21 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterEquipmentAssignmentRoutes(e *echo.Echo, cfg config.Config, assignmentService service.EquipmentAssignmentService) {
+	handler := http.NewEquipmentAssignmentHandler(assignmentService)
+	g := e.Group("/api/equipment-assignments")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.List)
+	g.GET("/deployed", handler.Deployed)
+	g.POST("", handler.Create)
+	g.PUT("/:id", handler.Update)
+	g.DELETE("/:id", handler.Delete)
+}
@@ -9,13 +9,35 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterEquipmentRoutes(e *echo.Echo, cfg config.Config, equipmentService service.EquipmentService) {
+func RegisterEquipmentRoutes(e *echo.Echo, cfg config.Config, equipmentService service.EquipmentService, utilizationService service.EquipmentUtilizationService, maintenanceService service.EquipmentMaintenanceService, documentService service.EquipmentDocumentService) {
 	handler := http.NewEquipmentHandler(equipmentService)
+	utilizationHandler := http.NewEquipmentUtilizationHandler(utilizationService)
+	maintenanceHandler := http.NewEquipmentMaintenanceHandler(maintenanceService)
+	documentHandler := http.NewEquipmentDocumentHandler(documentService)
 	g := e.Group("/api/equipment")
 	g.Use(middleware.AdminAuth(cfg))
 	g.GET("", handler.List)
+	g.GET("/utilization", utilizationHandler.Ranking)
+	g.GET("/:id/utilization", utilizationHandler.GetByEquipment)
 	g.GET("/:id", handler.GetByID)
 	g.POST("", handler.Create)
 	g.PUT("/:id", handler.Update)
 	g.DELETE("/:id", handler.Delete)
+
+	g.GET("/maintenance/due", maintenanceHandler.Due)
+	g.GET("/:id/meter-readings", maintenanceHandler.ListReadings)
+	g.POST("/:id/meter-readings", maintenanceHandler.AddReading)
+	g.GET("/:id/maintenance-plans", maintenanceHandler.ListPlans)
+	g.POST("/:id/maintenance-plans", maintenanceHandler.CreatePlan)
+	g.PUT("/:id/maintenance-plans/:planId", maintenanceHandler.UpdatePlan)
+	g.DELETE("/:id/maintenance-plans/:planId", maintenanceHandler.DeletePlan)
+	g.GET("/:id/service-records", maintenanceHandler.ListRecords)
+	g.POST("/:id/service-records", maintenanceHandler.CreateRecord)
+	g.DELETE("/:id/service-records/:recordId", maintenanceHandler.DeleteRecord)
+
+	g.GET("/documents/compliance", documentHandler.Compliance)
+	g.GET("/:id/documents", documentHandler.List)
+	g.POST("/:id/documents", documentHandler.Create)
+	g.PUT("/:id/documents/:docId", documentHandler.Update)
+	g.DELETE("/:id/documents/:docId", documentHandler.Delete)
 }
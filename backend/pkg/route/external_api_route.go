@@ -8,8 +8,8 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterExternalAPIRoutes(e *echo.Echo, tokenService service.IntegrationAPITokenService, projectService service.ProjectService, financeService service.FinanceService, memberService service.MemberService) {
-	h := http.NewExternalAPIHandler(projectService, financeService, memberService)
+func RegisterExternalAPIRoutes(e *echo.Echo, tokenService service.IntegrationAPITokenService, projectService service.ProjectService, financeService service.FinanceService, memberService service.MemberService, dailyReportService service.DailyReportService) {
+	h := http.NewExternalAPIHandler(projectService, financeService, memberService, dailyReportService)
 	g := e.Group("/api/external")
 
 	g.GET("/projects", h.GetProjects, middleware.IntegrationTokenAuth(tokenService, "projects"))
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterFuelRoutes(e *echo.Echo, cfg config.Config, fuelService service.FuelService) {
+	handler := http.NewFuelHandler(fuelService)
+	g := e.Group("/api/fuel-logs")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.List)
+	g.GET("/consumption", handler.Consumption)
+	g.POST("", handler.Create)
+	g.PUT("/:id", handler.Update)
+	g.DELETE("/:id", handler.Delete)
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterInventoryStockRoutes(e *echo.Echo, cfg config.Config, stockService service.InventoryStockService, activityService service.ActivityService) {
+	handler := http.NewInventoryStockHandler(stockService, activityService)
+	g := e.Group("/api/inventory")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("/locations", handler.ListLocations)
+	g.POST("/locations", handler.CreateLocation)
+	g.PUT("/locations/:id", handler.UpdateLocation)
+	g.DELETE("/locations/:id", handler.DeleteLocation)
+	g.GET("/movements", handler.ListMovements)
+	g.POST("/movements", handler.CreateMovement)
+	g.DELETE("/movements/:id", handler.DeleteMovement)
+	g.GET("/stock", handler.OnHand)
+	g.GET("/data/:id/movements", handler.ItemHistory)
+	g.PUT("/categories/:id/stock-settings", handler.ConfigureCategory)
+}
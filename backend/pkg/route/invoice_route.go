@@ -9,9 +9,10 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterInvoiceRoutes(e *echo.Echo, cfg config.Config, templateService service.InvoiceTemplateService, invoiceService service.InvoiceService) {
+func RegisterInvoiceRoutes(e *echo.Echo, cfg config.Config, templateService service.InvoiceTemplateService, invoiceService service.InvoiceService, promptTemplateService service.PromptTemplateService) {
 	templateHandler := http.NewInvoiceTemplateHandler(templateService)
-	invoiceHandler := http.NewInvoiceHandler(invoiceService, cfg)
+	invoiceHandler := http.NewInvoiceHandler(invoiceService, promptTemplateService, cfg)
+	promptHandler := http.NewPromptTemplateHandler(promptTemplateService)
 
 	g := e.Group("/api/invoices")
 	g.Use(middleware.AdminAuth(cfg))
@@ -23,6 +24,15 @@ func RegisterInvoiceRoutes(e *echo.Echo, cfg config.Config, templateService serv
 	g.PUT("/templates/:id", templateHandler.Update)
 	g.DELETE("/templates/:id", templateHandler.Delete)
 
+	// Prompt ekstraksi (versi per use case/provider) + log ekstraksi
+	g.GET("/prompts", promptHandler.List)
+	g.GET("/prompts/extraction-logs", promptHandler.ExtractionLogs)
+	g.GET("/prompts/:id", promptHandler.GetByID)
+	g.POST("/prompts", promptHandler.Create)
+	g.PUT("/prompts/:id/activate", promptHandler.Activate)
+	g.POST("/prompts/:id/render", promptHandler.Render)
+	g.DELETE("/prompts/:id", promptHandler.Delete)
+
 	// Invoices
 	g.GET("", invoiceHandler.GetAllWithPagination)
 	g.GET("/customer-suggestions", invoiceHandler.GetCustomerSuggestions)
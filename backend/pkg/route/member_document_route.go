@@ -0,0 +1,22 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterMemberDocumentRoutes(e *echo.Echo, cfg config.Config, documentService service.MemberDocumentService) {
+	handler := http.NewMemberDocumentHandler(documentService)
+	g := e.Group("/api/member-documents")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.List)
+	g.GET("/expiring", handler.Expiring)
+	g.POST("", handler.Create)
+	g.GET("/:id", handler.Get)
+	g.PUT("/:id", handler.Update)
+	g.DELETE("/:id", handler.Delete)
+}
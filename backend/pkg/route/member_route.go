@@ -9,8 +9,8 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterMemberRoutes(e *echo.Echo, memberService service.MemberService, salaryService service.SalaryService, config config.Config, DetailService service.SalaryDetailService, kasbonService service.KasbonService, activityService service.ActivityService) {
-	handler := http.NewMemberHandler(memberService, salaryService, "uploads", activityService)
+func RegisterMemberRoutes(e *echo.Echo, memberService service.MemberService, salaryService service.SalaryService, config config.Config, DetailService service.SalaryDetailService, kasbonService service.KasbonService, activityService service.ActivityService, documentService service.MemberDocumentService) {
+	handler := http.NewMemberHandler(memberService, salaryService, documentService, "uploads", activityService)
 	salaryHandler := http.NewSalaryHandler(salaryService, memberService, config.UploadDir, DetailService, activityService)
 	kasbonHandler := http.NewKasbonHandler(kasbonService, salaryService, activityService)
 	activityHandler := http.NewActivityHandler(activityService)
@@ -59,6 +59,7 @@ func RegisterMemberRoutes(e *echo.Echo, memberService service.MemberService, sal
 
 	// Salary Routes
 	h.GET("/:id", salaryHandler.GetSalaryByID)
+	h.POST("/:id/recalculate", salaryHandler.RecalculateSalary)
 
 	// Salary Document Routes
 	h.POST("/:id/documents", salaryHandler.UploadDocuments)
@@ -0,0 +1,22 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterPayrollRoutes(e *echo.Echo, cfg config.Config, payrollService service.PayrollService, activityService service.ActivityService) {
+	handler := http.NewPayrollHandler(payrollService, activityService)
+	g := e.Group("/api/payroll-runs")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("", handler.List)
+	g.POST("", handler.Create)
+	g.GET("/:id", handler.Get)
+	g.POST("/:id/refresh", handler.Refresh)
+	g.POST("/:id/finalize", handler.Finalize)
+	g.DELETE("/:id", handler.Delete)
+}
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterPayslipRoutes(e *echo.Echo, cfg config.Config, payslipService service.PayslipService, activityService service.ActivityService) {
+	handler := http.NewPayslipHandler(payslipService, activityService)
+	g := e.Group("/api/salaries")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("/payslips/export", handler.Export)
+	g.GET("/:id/payslip", handler.Download)
+	g.POST("/:id/payslip", handler.Store)
+}
@@ -0,0 +1,22 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterProjectMilestoneRoutes(e *echo.Echo, milestoneService service.ProjectMilestoneService, config config.Config) {
+	handler := http.NewProjectMilestoneHandler(milestoneService)
+	g := e.Group("/api/projects/:id/milestones")
+	g.Use(middleware.AdminAuth(config))
+
+	g.GET("", handler.List)
+	g.POST("", handler.Create)
+	g.PUT("/:milestoneId", handler.Update)
+	g.DELETE("/:milestoneId", handler.Delete)
+	g.POST("/:milestoneId/invoice", handler.CreateInvoice)
+}
@@ -9,8 +9,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterProjectRoutes(e *echo.Echo, projectService service.ProjectService, config config.Config, activityService service.ActivityService) {
+func RegisterProjectRoutes(e *echo.Echo, projectService service.ProjectService, config config.Config, activityService service.ActivityService, projectReportService service.ProjectReportService) {
 	handler := http.NewProjectHandler(projectService, activityService)
+	reportHandler := http.NewProjectReportHandler(projectReportService)
 	g := e.Group("/api/projects")
 	g.Use(middleware.AdminAuth(config))
 
@@ -21,4 +22,11 @@ func RegisterProjectRoutes(e *echo.Echo, projectService service.ProjectService,
 	g.GET("/count", handler.GetProjectCount)
 	g.PUT("/:id", handler.UpdateProject)
 	g.DELETE("/:id", handler.DeleteProject)
+	g.PATCH("/:id/reports/daily", handler.MergeDailyReports)
+
+	// Rekap dihitung dari laporan harian
+	g.GET("/:id/reports/weekly", reportHandler.GetWeekly)
+	g.GET("/:id/reports/monthly", reportHandler.GetMonthly)
+	g.GET("/:id/reports/s-curve", reportHandler.GetSCurve)
+	g.GET("/:id/earned-value", reportHandler.GetEarnedValue)
 }
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterStatisticsRoutes(e *echo.Echo, financeService service.FinanceService, memberService service.MemberService, projectService service.ProjectService, projectReportService service.ProjectReportService, config config.Config) {
+	handler := http.NewStatisticsHandler(financeService, memberService, projectService, projectReportService)
+	g := e.Group("/api/statistics")
+	g.Use(middleware.AdminAuth(config))
+
+	// Ringkasan dashboard termasuk earned value per proyek
+	g.GET("/dashboard", handler.GetDashboardStats)
+}
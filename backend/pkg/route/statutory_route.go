@@ -0,0 +1,20 @@
+package route
+
+import (
+	"dashboardadminimb/config"
+	"dashboardadminimb/internal/http"
+	"dashboardadminimb/internal/service"
+	"dashboardadminimb/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterStatutoryRoutes(e *echo.Echo, cfg config.Config, statutoryService service.StatutoryService) {
+	handler := http.NewStatutoryHandler(statutoryService)
+	g := e.Group("/api/statutory")
+	g.Use(middleware.AdminAuth(cfg))
+	g.GET("/settings", handler.GetSetting)
+	g.PUT("/settings", handler.SaveSetting)
+	g.GET("/recap", handler.Recap)
+	g.GET("/salaries/:id", handler.Lines)
+}
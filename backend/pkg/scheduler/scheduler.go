@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// Every menjalankan job di goroutine terpisah: sekali saat start, lalu setiap interval.
+// Error dan panic dicatat ke log tanpa menghentikan server.
+func Every(name string, interval time.Duration, job func(now time.Time) error) {
+	go func() {
+		run := func(now time.Time) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("scheduler %s: panic: %v", name, r)
+				}
+			}()
+			if err := job(now); err != nil {
+				log.Printf("scheduler %s: %v", name, err)
+			}
+		}
+		run(time.Now())
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			run(now)
+		}
+	}()
+}
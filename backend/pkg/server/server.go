@@ -9,11 +9,14 @@ import (
 	"dashboardadminimb/pkg/database"
 	appmiddleware "dashboardadminimb/pkg/middleware"
 	"dashboardadminimb/pkg/route"
+	"dashboardadminimb/pkg/scheduler"
 	nethttp "net/http"
 	"os"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"gorm.io/gorm"
 )
 
 func StartServer() {
@@ -26,7 +29,7 @@ func StartServer() {
 		AllowOrigins: []string{
 			"*",
 		},
-		AllowMethods: []string{nethttp.MethodGet, nethttp.MethodPost, nethttp.MethodPut, nethttp.MethodDelete, nethttp.MethodOptions},
+		AllowMethods: []string{nethttp.MethodGet, nethttp.MethodPost, nethttp.MethodPut, nethttp.MethodPatch, nethttp.MethodDelete, nethttp.MethodOptions},
 		AllowHeaders: []string{
 			echo.HeaderOrigin,
 			echo.HeaderContentType,
@@ -34,7 +37,9 @@ func StartServer() {
 			echo.HeaderAuthorization,
 			"X-Integration-Token",
 			"X-Requested-With",
+			"If-Match",
 		},
+		ExposeHeaders:    []string{"ETag"},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours
 	}))
@@ -64,35 +69,46 @@ func StartServer() {
 
 	// Inisialisasi service lainnya
 	projectRepo := repository.NewProjectRepository(db)
-	projectService := service.NewProjectService(projectRepo)
+	dailyReportRepo := repository.NewDailyReportRepository(db)
+	customerRepo := repository.NewCustomerRepository(db)
+	projectService := service.NewProjectService(projectRepo, dailyReportRepo, customerRepo)
+	dailyReportService := service.NewDailyReportService(dailyReportRepo)
 
 	projectExpenseRepo := repository.NewProjectExpenseRepository(db)
+	projectReportService := service.NewProjectReportService(projectRepo, dailyReportRepo, projectExpenseRepo)
 	projectIncomeRepo := repository.NewProjectIncomeRepository(db)
 	projectExpenseService := service.NewProjectExpenseService(projectExpenseRepo, projectIncomeRepo)
 	projectIncomeService := service.NewProjectIncomeService(projectIncomeRepo)
 
 	memberRepo := repository.NewMemberRepository(db)
-	memberService := service.NewMemberService(memberRepo)
+	employmentService := service.NewEmploymentService(repository.NewEmploymentRepository(db), memberRepo)
+	memberService := service.NewMemberService(memberRepo, employmentService)
 
 	activityRepo := repository.NewActivityRepository(db)
 	activityService := service.NewActivityService(activityRepo)
 
 	kasbonRepo := repository.NewKasbonRepository(db)
 	kasbonService := service.NewKasbonService(kasbonRepo)
-
-	salaryDetailRepo := repository.NewSalaryDetailRepository(db)
-	salaryDetailService := service.NewSalaryDetailService(salaryDetailRepo)
+	advanceRepo := repository.NewAdvanceRepository(db)
+	advanceService := service.NewAdvanceService(advanceRepo, memberRepo)
 
 	salaryRepo := repository.NewSalaryRepository(db)
-	salaryService := service.NewSalaryService(salaryRepo, memberRepo, salaryDetailService, kasbonService)
+	salaryDetailRepo := repository.NewSalaryDetailRepository(db)
+	salaryDetailService := service.NewSalaryDetailService(salaryDetailRepo, salaryRepo, employmentService)
 
 	equipmentRepo := repository.NewEquipmentRepository(db)
+	statutoryService := service.NewStatutoryService(repository.NewStatutoryRepository(db), salaryRepo, memberRepo)
+	memberDocumentService := service.NewMemberDocumentService(repository.NewMemberDocumentRepository(db), memberRepo, activityService)
+	attendanceService := service.NewAttendanceService(repository.NewAttendanceRepository(db), memberRepo, salaryRepo, salaryDetailRepo, equipmentRepo, projectService, memberDocumentService, employmentService)
+	salaryService := service.NewSalaryService(salaryRepo, memberRepo, salaryDetailService, kasbonService, advanceService, statutoryService, attendanceService)
+
 	financeRepo := repository.NewFinanceRepository(db)
 	financeService := service.NewFinanceService(financeRepo, equipmentRepo)
 	equipmentService := service.NewEquipmentService(equipmentRepo, financeRepo)
 
 	// Registrasi route
-	route.RegisterProjectRoutes(e, projectService, cfg, activityService)
+	route.RegisterProjectRoutes(e, projectService, cfg, activityService, projectReportService)
+	route.SetupDailyReportRoutes(e, internalhttp.NewDailyReportHandler(dailyReportService, projectService), cfg)
 	route.RegisterProjectExpenseRoutes(e, projectExpenseService, activityService, financeService, projectService, cfg)
 	route.RegisterProjectIncomeRoutes(e, projectIncomeService, activityService, financeService, projectService, cfg)
 
@@ -111,14 +127,42 @@ func StartServer() {
 	publicGroup.GET("/shared/:token", projectShareLinkHandler.GetSharedProject)
 	publicGroup.PUT("/shared/:token", projectShareLinkHandler.UpdateSharedSettings)
 	publicGroup.PUT("/shared/:token/reports", projectShareLinkHandler.UpdateSharedReports)
-
-	route.RegisterMemberRoutes(e, memberService, salaryService, cfg, salaryDetailService, kasbonService, activityService) // Perbaiki typo
+	publicGroup.PATCH("/shared/:token/reports/daily", projectShareLinkHandler.MergeSharedDailyReports)
+
+	route.RegisterMemberRoutes(e, memberService, salaryService, cfg, salaryDetailService, kasbonService, activityService, memberDocumentService) // Perbaiki typo
+	route.RegisterMemberDocumentRoutes(e, cfg, memberDocumentService)
+	route.RegisterEmploymentRoutes(e, cfg, employmentService)
+	route.RegisterAdvanceRoutes(e, cfg, advanceService, activityService)
+	payrollRepo := repository.NewPayrollRepository(db)
+	// Finalize payroll menyinkronkan gaji dan memfinalisasi run dalam satu transaksi: dependensi yang menulis/membaca
+	// gaji, detail, kasbon dan statutory dibangun ulang di atas tx.
+	payrollInTx := func(tx *gorm.DB) service.PayrollService {
+		txMemberRepo := repository.NewMemberRepository(tx)
+		txSalaryRepo := repository.NewSalaryRepository(tx)
+		txDetailRepo := repository.NewSalaryDetailRepository(tx)
+		return service.NewPayrollService(repository.NewPayrollRepository(tx), txSalaryRepo, txMemberRepo,
+			service.NewSalaryDetailService(txDetailRepo, txSalaryRepo, employmentService),
+			service.NewKasbonService(repository.NewKasbonRepository(tx)),
+			service.NewAdvanceService(repository.NewAdvanceRepository(tx), txMemberRepo),
+			service.NewStatutoryService(repository.NewStatutoryRepository(tx), txSalaryRepo, txMemberRepo),
+			service.NewAttendanceService(repository.NewAttendanceRepository(tx), txMemberRepo, txSalaryRepo, txDetailRepo, equipmentRepo, projectService, memberDocumentService, employmentService),
+			nil)
+	}
+	payrollService := service.NewPayrollService(payrollRepo, salaryRepo, memberRepo, salaryDetailService, kasbonService, advanceService, statutoryService, attendanceService, payrollInTx)
+	route.RegisterPayrollRoutes(e, cfg, payrollService, activityService)
+	payslipService := service.NewPayslipService(salaryRepo, memberRepo, userRepo, advanceRepo, salaryService, salaryDetailService, kasbonService, statutoryService, cfg.UploadDir)
+	route.RegisterPayslipRoutes(e, cfg, payslipService, activityService)
+	route.RegisterStatutoryRoutes(e, cfg, statutoryService)
+	route.RegisterAttendanceRoutes(e, cfg, attendanceService, activityService)
 	financeCategoryRepo := repository.NewFinanceCategoryRepository(db)
 	financeCategoryService := service.NewFinanceCategoryService(financeCategoryRepo)
 
 	inventoryRepo := repository.NewInventoryRepository(db)
-	inventoryService := service.NewInventoryService(inventoryRepo)
+	inventoryStockRepo := repository.NewInventoryStockRepository(db)
+	inventoryService := service.NewInventoryService(inventoryRepo, inventoryStockRepo)
+	inventoryStockService := service.NewInventoryStockService(inventoryStockRepo, inventoryRepo, projectService)
 	route.RegisterInventoryRoutes(e, inventoryService, cfg.UploadDir, cfg.BaseURL, cfg, activityService)
+	route.RegisterInventoryStockRoutes(e, cfg, inventoryStockService, activityService)
 
 	route.RegisterFinanceRoutes(e, financeService, cfg, activityService, financeCategoryService, projectIncomeService, projectExpenseService)
 
@@ -136,13 +180,29 @@ func StartServer() {
 	}
 	invoiceRepo := repository.NewInvoiceRepository(db)
 	invoiceService := service.NewInvoiceService(invoiceRepo)
-	route.RegisterInvoiceRoutes(e, cfg, invoiceTemplateService, invoiceService)
+	promptTemplateRepo := repository.NewPromptTemplateRepository(db)
+	promptTemplateService := service.NewPromptTemplateService(promptTemplateRepo)
+	route.RegisterInvoiceRoutes(e, cfg, invoiceTemplateService, invoiceService, promptTemplateService)
 
-	customerRepo := repository.NewCustomerRepository(db)
 	customerService := service.NewCustomerService(customerRepo)
 	route.RegisterCustomerRoutes(e, cfg, customerService)
 
-	route.RegisterEquipmentRoutes(e, cfg, equipmentService)
+	projectMilestoneRepo := repository.NewProjectMilestoneRepository(db)
+	projectMilestoneService := service.NewProjectMilestoneService(projectMilestoneRepo, projectService, customerRepo, invoiceTemplateRepo)
+	route.RegisterProjectMilestoneRoutes(e, projectMilestoneService, cfg)
+
+	equipmentAssignmentRepo := repository.NewEquipmentAssignmentRepository(db)
+	equipmentUtilizationService := service.NewEquipmentUtilizationService(equipmentRepo, equipmentAssignmentRepo, invoiceRepo, financeRepo, dailyReportRepo)
+	equipmentMaintenanceRepo := repository.NewEquipmentMaintenanceRepository(db)
+	equipmentMaintenanceService := service.NewEquipmentMaintenanceService(equipmentMaintenanceRepo, equipmentRepo, financeRepo, activityService)
+	equipmentDocumentRepo := repository.NewEquipmentDocumentRepository(db)
+	equipmentDocumentService := service.NewEquipmentDocumentService(equipmentDocumentRepo, equipmentRepo, activityService)
+	route.RegisterEquipmentRoutes(e, cfg, equipmentService, equipmentUtilizationService, equipmentMaintenanceService, equipmentDocumentService)
+	equipmentAssignmentService := service.NewEquipmentAssignmentService(equipmentAssignmentRepo, equipmentRepo, projectService, memberDocumentService)
+	route.RegisterEquipmentAssignmentRoutes(e, cfg, equipmentAssignmentService)
+	fuelLogRepo := repository.NewFuelLogRepository(db)
+	fuelService := service.NewFuelService(fuelLogRepo, equipmentRepo, memberRepo, financeRepo, invoiceRepo, equipmentMaintenanceRepo, projectService)
+	route.RegisterFuelRoutes(e, cfg, fuelService)
 
 	itemTemplateRepo := repository.NewItemTemplateRepository(db)
 	itemTemplateService := service.NewItemTemplateService(itemTemplateRepo)
@@ -151,8 +211,9 @@ func StartServer() {
 	route.RegisterRoutes(e, userService, passwordResetService, cfg)
 	route.RegisterApiKeyRoutes(e, apiKeyService, cfg)
 	route.RegisterIntegrationAPITokenRoutes(e, integrationTokenService, cfg)
-	route.RegisterExternalAPIRoutes(e, integrationTokenService, projectService, financeService, memberService)
+	route.RegisterExternalAPIRoutes(e, integrationTokenService, projectService, financeService, memberService, dailyReportService)
 	route.RegisterActivityRoutes(e, activityService, cfg)
+	route.RegisterStatisticsRoutes(e, financeService, memberService, projectService, projectReportService, cfg)
 
 	// Generic file upload endpoint (finance attachments, etc.)
 	uploadHandler := internalhttp.NewUploadHandler(cfg.UploadDir, cfg.BaseURL)
@@ -163,5 +224,24 @@ func StartServer() {
 	// Serve uploaded files (logos, etc.) publicly
 	e.Static("/uploads", cfg.UploadDir)
 
+	// Job latar belakang: servis alat yang terlambat serta dokumen alat dan anggota yang hampir habis dicatat sebagai aktivitas.
+	scheduler.Every("maintenance-overdue", time.Hour, func(now time.Time) error {
+		_, err := equipmentMaintenanceService.CheckOverdue(now)
+		return err
+	})
+	scheduler.Every("equipment-document-expiry", 24*time.Hour, func(now time.Time) error {
+		_, err := equipmentDocumentService.CheckExpiring(now)
+		return err
+	})
+	scheduler.Every("member-document-expiry", 24*time.Hour, func(now time.Time) error {
+		_, err := memberDocumentService.CheckExpiring(now)
+		return err
+	})
+	// Perubahan jabatan bertanggal ke depan diterapkan ke Member.Role saat tanggalnya tiba.
+	scheduler.Every("member-employment-terms", 24*time.Hour, func(now time.Time) error {
+		_, err := employmentService.ApplyDue(now)
+		return err
+	})
+
 	e.Logger.Fatal(e.Start(":" + cfg.Port))
 }
@@ -61,12 +61,13 @@ export const projectShareLinksAPI = {
     const res: any = await axios.put(`${API_BASE}/public/projects/shared/${token}?edit_token=${encodeURIComponent(editToken)}`, { settings });
     return res.data?.data as { settings: ShareProjectSettings };
   },
-  /** Simpan laporan (daily/weekly/monthly). editToken opsional jika allowEdit=true di settings link. */
-  updateSharedReports: async (token: string, editToken: string | undefined, reports: Project['reports']) => {
+  /** Simpan laporan (daily/weekly/monthly). editToken opsional jika allowEdit=true di settings link.
+   *  version = versi proyek terakhir dibaca; server menolak (409) jika proyek sudah diubah orang lain. */
+  updateSharedReports: async (token: string, editToken: string | undefined, reports: Project['reports'], version?: number) => {
     const query = editToken ? `?edit_token=${encodeURIComponent(editToken)}` : '';
     const res: any = await axios.put(
       `${API_BASE}/public/projects/shared/${encodeURIComponent(token)}/reports${query}`,
-      { reports }
+      { reports, version }
     );
     return res.data?.data?.project as Project;
   },
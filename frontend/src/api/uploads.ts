@@ -5,12 +5,13 @@ const API_BASE = API_BASE_URL || '';
 
 export const uploadsAPI = {
   /**
-   * Upload a file for finance attachments.
+   * Upload a file for finance attachments (or another allowed folder, e.g. 'equipment-documents').
    * Returns the public URL of the uploaded file.
    */
-  uploadFile: async (file: File): Promise<{ url: string; filename: string }> => {
+  uploadFile: async (file: File, folder?: 'finance' | 'equipment-documents'): Promise<{ url: string; filename: string }> => {
     const formData = new FormData();
     formData.append('file', file);
+    if (folder) formData.append('folder', folder);
     const res: any = await axios.post(`${API_BASE}/api/uploads/file`, formData, {
       headers: { 'Content-Type': 'multipart/form-data' },
     });
@@ -3,7 +3,7 @@ import { FiX, FiPlus, FiMinus, FiUsers, FiRefreshCw, FiClock, FiChevronLeft, FiC
 import { activitiesAPI, PaginationParams, FrontendPagination } from '../api/activities';
 
 interface Activity {
-  type: 'income' | 'expense' | 'member' | 'project' | 'update';
+  type: 'income' | 'expense' | 'member' | 'project' | 'update' | 'maintenance' | 'compliance';
   title: string;
   description: string;
   timestamp: string;
@@ -44,7 +44,7 @@ interface MonthlyComparison {
 }
 
 interface Activity {
-  type: 'income' | 'expense' | 'member' | 'project' | 'update';
+  type: 'income' | 'expense' | 'member' | 'project' | 'update' | 'maintenance' | 'compliance';
   title: string;
   description: string;
   timestamp: string;
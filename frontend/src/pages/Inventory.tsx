@@ -1,14 +1,11 @@
 import React, { useState, useEffect } from 'react';
-import { InventoryCategory, InventoryData } from '../types/BasicTypes';
+import { InventoryCategory, InventoryData, TableHeader } from '../types/BasicTypes';
 import InventoryPDFExporter from '../component/InventoryPDFExporter'
 import inventoryAPI from '../api/Inventory';
 
-interface TableHeader {
-  id: string;
-  name: string;
-  type: 'string' | 'integer' | 'float' | 'image';
-  optional: boolean;
-}
+// Tipe kolom lama tetap ditampilkan dengan tipe barunya
+const headerType = (header: TableHeader) =>
+  header.type === 'string' ? 'text' : header.type === 'integer' || header.type === 'float' ? 'number' : header.type;
 
 // API sudah diimpor dari modul inventory
 
@@ -61,7 +58,7 @@ const Inventory: React.FC<{ isCollapsed: boolean }> = ({ isCollapsed }) => {
     const newHeader: TableHeader = {
       id: `col-${Date.now()}`,
       name: 'Kolom Baru',
-      type: 'string',
+      type: 'text',
       optional: false
     };
     setNewHeaders([...newHeaders, newHeader]);
@@ -504,18 +501,45 @@ const Inventory: React.FC<{ isCollapsed: boolean }> = ({ isCollapsed }) => {
                     />
                     <select
                       className="w-full mb-2"
-                      value={header.type}
+                      value={headerType(header)}
                       onChange={(e) => {
                         const updated = [...newHeaders];
                         updated[index].type = e.target.value as any;
                         setNewHeaders(updated);
                       }}
                     >
-                      <option value="string">String</option>
-                      <option value="integer">Integer</option>
-                      <option value="float">Float</option>
+                      <option value="text">Teks</option>
+                      <option value="number">Angka</option>
+                      <option value="currency">Rupiah</option>
+                      <option value="date">Tanggal</option>
+                      <option value="enum">Pilihan</option>
                       <option value="image">Image</option>
                     </select>
+                    {headerType(header) === 'enum' && (
+                      <input
+                        type="text"
+                        placeholder="Pilihan, pisahkan dengan koma"
+                        className="w-full mb-2"
+                        value={(header.options || []).join(',')}
+                        onChange={(e) => {
+                          const updated = [...newHeaders];
+                          updated[index].options = e.target.value.split(',');
+                          setNewHeaders(updated);
+                        }}
+                      />
+                    )}
+                    <label className="flex items-center gap-2">
+                      <input
+                        type="checkbox"
+                        checked={!!header.unique}
+                        onChange={(e) => {
+                          const updated = [...newHeaders];
+                          updated[index].unique = e.target.checked;
+                          setNewHeaders(updated);
+                        }}
+                      />
+                      Unik
+                    </label>
                     <label className="flex items-center gap-2">
                       <input
                         type="checkbox"
@@ -523,6 +547,7 @@ const Inventory: React.FC<{ isCollapsed: boolean }> = ({ isCollapsed }) => {
                         onChange={(e) => {
                           const updated = [...newHeaders];
                           updated[index].optional = e.target.checked;
+                          updated[index].required = !e.target.checked;
                           setNewHeaders(updated);
                         }}
                       />
@@ -610,9 +635,34 @@ const Inventory: React.FC<{ isCollapsed: boolean }> = ({ isCollapsed }) => {
                         ))}
                       </div>
                     </div>
+                  ) : headerType(header) === 'enum' ? (
+                    <select
+                      className="w-full p-2 border rounded"
+                      value={
+                        editingData
+                          ? (editingData.values && editingData.values[header.id]) ?? ''
+                          : newDataValues[header.id] || ''
+                      }
+                      onChange={(e) => {
+                        if (editingData) {
+                          setEditingData({
+                            ...editingData,
+                            values: { ...(editingData.values ?? {}), [header.id]: e.target.value }
+                          });
+                        } else {
+                          setNewDataValues({ ...newDataValues, [header.id]: e.target.value });
+                        }
+                      }}
+                      required={!header.optional}
+                    >
+                      <option value="">-</option>
+                      {(header.options || []).map(option => (
+                        <option key={option} value={option}>{option}</option>
+                      ))}
+                    </select>
                   ) : (
                     <input
-                      type={header.type === 'integer' || header.type === 'float' ? 'number' : 'text'}
+                      type={headerType(header) === 'number' || headerType(header) === 'currency' ? 'number' : headerType(header) === 'date' ? 'date' : 'text'}
                       className="w-full p-2 border rounded"
                       value={
                         editingData
@@ -640,7 +690,7 @@ const Inventory: React.FC<{ isCollapsed: boolean }> = ({ isCollapsed }) => {
                         }
                       }}
                       required={!header.optional}
-                      step={header.type === 'float' ? '0.01' : undefined}
+                      step={header.integer || header.type === 'integer' ? undefined : '0.01'}
                     />
                   )}
                 </div>
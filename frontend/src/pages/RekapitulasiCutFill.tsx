@@ -329,7 +329,7 @@ const RekapitulasiCutFill: React.FC<Props> = ({
     if (!canEditReports || !shareToken || !project) return;
     setSavingShared(true);
     try {
-      const updated = await projectShareLinksAPI.updateSharedReports(shareToken, editToken || undefined, project.reports);
+      const updated = await projectShareLinksAPI.updateSharedReports(shareToken, editToken || undefined, project.reports, project.version);
       onEmbeddedProjectChange!(updated);
 
       if (withSync) {
@@ -360,7 +360,8 @@ const RekapitulasiCutFill: React.FC<Props> = ({
     if (isEmbedded || !project) return;
     setSavingLocal(true);
     try {
-      await projectsAPI.updateProject(project.id, project);
+      const saved = await projectsAPI.updateProject(project.id, project);
+      setLocalProject(saved);
 
       if (withSync) {
         const apiKey = getSmartNotaApiKey() || '';
@@ -585,9 +585,9 @@ const Reports: React.FC<ReportsProps> = ({ isCollapsed }) => {
   const handleSaveProject = async (updatedProject: Project) => {
     console.log(updatedProject)
     try {
-      await projectsAPI.updateProject(updatedProject.id, updatedProject);
+      const saved = await projectsAPI.updateProject(updatedProject.id, updatedProject);
       setProjects(prev =>
-        prev.map(p => p.id === updatedProject.id ? updatedProject : p)
+        prev.map(p => p.id === saved.id ? saved : p)
       );
       setEditingProject(null);
     } catch (err) {
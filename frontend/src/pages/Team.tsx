@@ -706,6 +706,8 @@ const Team: React.FC<TeamProps> = ({ isCollapsed }) => {
         month: newSalaryMonth,
         salary: newSalaryAmount,
         loan: newLoanAmount,
+        // Potongan di luar kasbon disimpan terpisah agar tidak hilang saat gaji dihitung ulang
+        loan_adjustment: Math.max(0, newLoanAmount - (salaryToUpdate.kasbons || []).reduce((sum, k) => sum + k.jumlah, 0)),
         net_salary: newSalaryAmount - newLoanAmount,
         gross_salary: newSalaryAmount,
         status: newStatus
@@ -817,6 +819,7 @@ const Team: React.FC<TeamProps> = ({ isCollapsed }) => {
         month: newSalaryMonth,
         salary: newSalaryAmount,
         loan: newLoanAmount,
+        loan_adjustment: newLoanAmount,
         net_salary: newSalaryAmount - newLoanAmount,
         gross_salary: newSalaryAmount,
         status: newStatus
@@ -1961,10 +1964,9 @@ const Team: React.FC<TeamProps> = ({ isCollapsed }) => {
                       <form onSubmit={handleNewSalarySubmit} className="mt-4 space-y-4">
                         {/* Input Nama Bulan */}
                         <div>
-                          <label className="block text-sm font-medium text-gray-700 mb-1">Nama Bulan</label>
+                          <label className="block text-sm font-medium text-gray-700 mb-1">Bulan</label>
                           <input
-                            type="text"
-                            placeholder="Masukkan nama bulan"
+                            type="month"
                             // value={newSalaryMonth}
                             onChange={(e) => setNewSalaryMonth(e.target.value)}
                             className="w-full px-4 py-2 border border-gray-300 rounded-lg"
@@ -2074,10 +2076,9 @@ const Team: React.FC<TeamProps> = ({ isCollapsed }) => {
                       <form onSubmit={handleUpdateSalary} className="mt-4 space-y-4">
                         {/* Input Nama Bulan */}
                         <div>
-                          <label className="block text-sm font-medium text-gray-700 mb-1">Nama Bulan</label>
+                          <label className="block text-sm font-medium text-gray-700 mb-1">Bulan</label>
                           <input
-                            type="text"
-                            placeholder="Masukkan nama bulan"
+                            type="month"
                             value={newSalaryMonth}
                             onChange={(e) => setNewSalaryMonth(e.target.value)}
                             className="w-full px-4 py-2 border border-gray-300 rounded-lg"
@@ -1,8 +1,15 @@
+// Skema kolom inventori; 'string' | 'integer' | 'float' adalah tipe lama (dinormalisasi backend ke text/number).
 export interface TableHeader {
   id: string;
   name: string;
-  type: 'string' | 'integer' | 'float' | 'image';
+  type: 'text' | 'number' | 'date' | 'enum' | 'currency' | 'image' | 'string' | 'integer' | 'float';
   optional: boolean;
+  required?: boolean;
+  unique?: boolean;
+  default?: string | number | null;
+  options?: string[];     // pilihan untuk enum
+  integer?: boolean;      // number tanpa desimal
+  renamed_from?: string;  // saat update: id kolom lama yang nilainya dipindah
 }
 
 export interface TableRow {
@@ -24,6 +31,7 @@ export interface SalaryRecord {
   month: string;
   salary: number;
   loan: number;
+  loan_adjustment?: number; // potongan manual di luar kasbon
   net_salary: number;
   gross_salary: number;
   status: string;
@@ -41,6 +49,8 @@ export interface SalaryDetail {
   jam_trip: number;       // 🟢 Dari jamTrip -> jam_trip
   harga_per_jam: number;  // 🟢 Dari hargaPerJam -> harga_per_jam
   keterangan: string;
+  absensi_id?: number | null; // terisi = dibuat dari absensi, tidak bisa diedit langsung
+  rate_source?: 'manual' | 'contract'; // contract = tarif kontrak, diperbarui saat gaji dihitung ulang
 }
 
 export interface Kasbon {
@@ -64,6 +74,81 @@ export interface Member {
   deactivationReason?: string;
   deactivatedAt?: string;
   totalSalary?: number;  // Total gaji yang sudah dibayarkan
+  ptkpStatus?: string;   // TK/0..TK/3, K/0..K/3 untuk PPh 21
+  npwp?: string;
+  bpjsKetenagakerjaan?: boolean;
+  bpjsKesehatan?: boolean;
+}
+
+// Dokumen anggota bertipe dari /api/member-documents (snake_case)
+export interface MemberDocument {
+  id: number;
+  member_id: string;
+  doc_type: 'ktp' | 'sim_b2' | 'sio' | 'mcu' | 'lainnya';
+  number: string;
+  issue_date: string | null;
+  expiry_date: string | null; // null = tidak kedaluwarsa
+  file_url: string;
+  notes: string;
+  days_left: number | null;
+  status: 'valid' | 'no_expiry' | 'expiring_30' | 'expiring_14' | 'expiring_7' | 'expired';
+}
+
+export interface MemberDocumentAlert {
+  member_id: string;
+  member_name: string;
+  role: string;
+  expired: MemberDocument[];
+  expiring: MemberDocument[];
+}
+
+// Peringatan lisensi operator pada penempatan alat / absensi (tidak memblokir)
+export interface LicenceWarning {
+  member_id: string;
+  member_name: string;
+  doc_type: string;
+  problem: 'missing' | 'expired' | 'expires_during';
+  expiry_date?: string;
+  message: string;
+}
+
+// Kontrak kerja & riwayat kepegawaian dari /api/members/:id/employment (snake_case)
+export interface MemberContract {
+  id: number;
+  member_id: string;
+  contract_type: 'pkwt' | 'pkwtt';
+  number: string;
+  start_date: string;
+  end_date: string | null; // wajib untuk PKWT
+  role: string;
+  hourly_rate: number;
+  trip_rate: number;
+  file_url: string;
+  notes: string;
+  status: 'active' | 'upcoming' | 'ended';
+}
+
+export interface MemberEmploymentEvent {
+  id: number;
+  member_id: string;
+  event_type: 'joined' | 'contract' | 'change' | 'deactivated' | 'reactivated';
+  effective_date: string;
+  role: string;
+  hourly_rate: number;
+  trip_rate: number;
+  contract_id: number | null;
+  notes: string;
+}
+
+export interface EmploymentTerms {
+  member_id: string;
+  date: string;
+  role: string;
+  hourly_rate: number;
+  trip_rate: number;
+  active: boolean;
+  contract_id: number | null;
+  contract_type?: string;
 }
 
 export interface DailyReportImage {
@@ -148,6 +233,8 @@ export interface Project {
   unitPrice: number;
   totalVolume: number;
   unit: string;
+  /** Versi optimistic concurrency; kirim balik saat update (409 jika sudah diubah orang lain). */
+  version?: number;
   reports: {
     daily: DailyReport[];
     weekly: WeeklyReport[];
@@ -166,6 +253,8 @@ export interface InventoryCategory {
   description: string;
   headers: TableHeader[];
   data: InventoryData[];
+  quantity_header?: string; // id header jumlah stok; nilainya dihitung dari mutasi stok
+  unit_header?: string;     // id header satuan
 }
 
 export interface InventoryData {
@@ -175,6 +264,51 @@ export interface InventoryData {
   images: string[];
 }
 
+// Buku stok inventori dari /api/inventory/{locations,movements,stock} (snake_case)
+export interface StockLocation {
+  id: number;
+  name: string;
+  kind: 'warehouse' | 'site';
+  project_id: number | null;
+  notes: string;
+}
+
+export interface StockMovement {
+  id: number;
+  item_id: string;
+  category_id: string;
+  movement_type: 'receipt' | 'issue' | 'transfer' | 'adjustment';
+  date: string;
+  quantity: number; // adjustment boleh negatif
+  unit: string;
+  from_location_id: number | null;
+  to_location_id: number | null;
+  project_id: number | null;
+  reference: string;
+  notes: string;
+  from_location_name?: string;
+  to_location_name?: string;
+  project_name?: string;
+  balance?: number; // saldo total item setelah mutasi (riwayat per item)
+}
+
+export interface StockBalance {
+  item_id: string;
+  location_id: number;
+  location_name: string;
+  quantity: number;
+  date?: string; // saat mutasi ditolak: tanggal saldo pertama kali negatif
+}
+
+export interface ItemStock {
+  item_id: string;
+  category_id: string;
+  name: string;
+  unit: string;
+  total: number;
+  locations: StockBalance[];
+}
+
 export interface FinanceEntry {
   id: number;
   tanggal: string;
@@ -6,6 +6,9 @@ export interface Equipment {
   license_plate?: string;
   price_per_day?: number;
   price_per_hour?: number;
+  /** Harga beli, dasar perhitungan payback */
+  purchase_price?: number;
+  purchase_date?: string;
   created_at?: string;
   updated_at?: string;
   /** Total pemasukan (Finance) yang ditautkan ke alat ini, seumur hidup data */
@@ -20,4 +23,6 @@ export interface CreateEquipmentRequest {
   license_plate?: string;
   price_per_day?: number;
   price_per_hour?: number;
+  purchase_price?: number;
+  purchase_date?: string;
 }